@@ -19,6 +19,8 @@
 package api
 
 import (
+	"context"
+	"io/ioutil"
 	"os"
 	"os/signal"
 	"syscall"
@@ -27,9 +29,12 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/api/envs"
 	"github.com/lastbackend/lastbackend/pkg/api/http"
 	"github.com/lastbackend/lastbackend/pkg/api/runtime"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
+	"github.com/lastbackend/lastbackend/pkg/util/pki"
 	"github.com/spf13/viper"
 )
 
@@ -50,6 +55,12 @@ func Daemon() bool {
 	envs.Get().SetStorage(stg)
 	envs.Get().SetCache(cache.NewCache())
 
+	ca, err := authority(stg)
+	if err != nil {
+		log.Fatalf("Cannot initialize certificate authority: %v", err)
+	}
+	envs.Get().SetCA(ca)
+
 	runtime.New().Run()
 
 	go func() {
@@ -59,8 +70,18 @@ func Daemon() bool {
 		opts.KeyFile = viper.GetString("api.tls.key")
 		opts.CaFile = viper.GetString("api.tls.ca")
 
+		if !opts.Insecure {
+			// Agents client certificates are signed by cluster authority,
+			// only this authority is trusted for client certificates authentication
+			opts.ClientCAs = ca.Pool()
+		}
+
 		types.SecretAccessToken = viper.GetString("token")
 
+		middleware.SetAuthenticator(func(ctx context.Context, token string) (*types.Account, error) {
+			return distribution.NewAccountModel(ctx, envs.Get().GetStorage()).Authenticate(token)
+		})
+
 		if err := http.Listen(viper.GetString("api.host"), viper.GetInt("api.port"), opts); err != nil {
 			log.Fatalf("Http server start error: %v", err)
 		}
@@ -85,3 +106,57 @@ func Daemon() bool {
 
 	return true
 }
+
+// authority loads certificate authority from files if configured,
+// otherwise authority is restored from storage or created on first start
+func authority(stg storage.Storage) (*pki.CA, error) {
+
+	if viper.IsSet("api.ca.cert") && viper.IsSet("api.ca.key") {
+
+		cert, err := ioutil.ReadFile(viper.GetString("api.ca.cert"))
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := ioutil.ReadFile(viper.GetString("api.ca.key"))
+		if err != nil {
+			return nil, err
+		}
+
+		return pki.LoadCA(cert, key)
+	}
+
+	cm := distribution.NewCertificateModel(context.Background(), stg)
+
+	item, err := cm.GetAuthority()
+	if err != nil {
+		return nil, err
+	}
+
+	if item != nil {
+		return pki.LoadCA(item.Certificate, item.Key)
+	}
+
+	ca, err := pki.NewCA(viper.GetString("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	item = new(types.CertificateAuthority)
+	item.Certificate = ca.Certificate()
+	if item.Key, err = ca.Key(); err != nil {
+		return nil, err
+	}
+
+	if err := cm.CreateAuthority(item); err != nil {
+
+		// Authority can be created by other API instance
+		if item, err := cm.GetAuthority(); err == nil && item != nil {
+			return pki.LoadCA(item.Certificate, item.Key)
+		}
+
+		return nil, err
+	}
+
+	return ca, nil
+}
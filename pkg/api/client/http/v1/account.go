@@ -0,0 +1,192 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	rv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
+	vv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/util/http/request"
+)
+
+type AccountClient struct {
+	client *request.RESTClient
+	name   string
+}
+
+func (ac *AccountClient) Create(ctx context.Context, opts *rv1.AccountCreateOptions) (*vv1.Account, error) {
+
+	body, err := opts.ToJson()
+	if err != nil {
+		return nil, err
+	}
+
+	var s *vv1.Account
+	var e *errors.Http
+
+	err = ac.client.Post("/account").
+		AddHeader("Content-Type", "application/json").
+		Body(body).
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+func (ac *AccountClient) List(ctx context.Context) (*vv1.AccountList, error) {
+
+	var s *vv1.AccountList
+	var e *errors.Http
+
+	err := ac.client.Get("/account").
+		AddHeader("Content-Type", "application/json").
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	if s == nil {
+		list := make(vv1.AccountList, 0)
+		s = &list
+	}
+
+	return s, nil
+}
+
+func (ac *AccountClient) Get(ctx context.Context) (*vv1.Account, error) {
+
+	var s *vv1.Account
+	var e *errors.Http
+
+	err := ac.client.Get(fmt.Sprintf("/account/%s", ac.name)).
+		AddHeader("Content-Type", "application/json").
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+func (ac *AccountClient) Update(ctx context.Context, opts *rv1.AccountUpdateOptions) (*vv1.Account, error) {
+
+	body, err := opts.ToJson()
+	if err != nil {
+		return nil, err
+	}
+
+	var s *vv1.Account
+	var e *errors.Http
+
+	err = ac.client.Put(fmt.Sprintf("/account/%s", ac.name)).
+		AddHeader("Content-Type", "application/json").
+		Body(body).
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+func (ac *AccountClient) Remove(ctx context.Context, opts *rv1.AccountRemoveOptions) error {
+
+	req := ac.client.Delete(fmt.Sprintf("/account/%s", ac.name)).
+		AddHeader("Content-Type", "application/json")
+
+	if opts != nil {
+		if opts.Force {
+			req.Param("force", strconv.FormatBool(opts.Force))
+		}
+	}
+
+	var e *errors.Http
+
+	if err := req.JSON(nil, &e); err != nil {
+		return err
+	}
+	if e != nil {
+		return errors.New(e.Message)
+	}
+
+	return nil
+}
+
+func (ac *AccountClient) IssueToken(ctx context.Context) (*vv1.AccountToken, error) {
+
+	var s *vv1.AccountToken
+	var e *errors.Http
+
+	err := ac.client.Post(fmt.Sprintf("/account/%s/token", ac.name)).
+		AddHeader("Content-Type", "application/json").
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+func (ac *AccountClient) RevokeToken(ctx context.Context, id string) error {
+
+	var e *errors.Http
+
+	err := ac.client.Delete(fmt.Sprintf("/account/%s/token/%s", ac.name, id)).
+		AddHeader("Content-Type", "application/json").
+		JSON(nil, &e)
+
+	if err != nil {
+		return err
+	}
+	if e != nil {
+		return errors.New(e.Message)
+	}
+
+	return nil
+}
+
+func newAccountClient(client *request.RESTClient, name string) *AccountClient {
+	return &AccountClient{client: client, name: name}
+}
@@ -0,0 +1,127 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	rv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
+	vv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/util/http/request"
+)
+
+type AutoscalerClient struct {
+	client *request.RESTClient
+
+	namespace string
+	service   string
+}
+
+func (ac *AutoscalerClient) Create(ctx context.Context, opts *rv1.AutoscalerOptions) (*vv1.Autoscaler, error) {
+
+	body, err := opts.ToJson()
+	if err != nil {
+		return nil, err
+	}
+
+	var s *vv1.Autoscaler
+	var e *errors.Http
+
+	err = ac.client.Post(fmt.Sprintf("/namespace/%s/service/%s/autoscaler", ac.namespace, ac.service)).
+		AddHeader("Content-Type", "application/json").
+		Body(body).
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+func (ac *AutoscalerClient) Get(ctx context.Context) (*vv1.Autoscaler, error) {
+
+	var s *vv1.Autoscaler
+	var e *errors.Http
+
+	err := ac.client.Get(fmt.Sprintf("/namespace/%s/service/%s/autoscaler", ac.namespace, ac.service)).
+		AddHeader("Content-Type", "application/json").
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+func (ac *AutoscalerClient) Update(ctx context.Context, opts *rv1.AutoscalerOptions) (*vv1.Autoscaler, error) {
+
+	body, err := opts.ToJson()
+	if err != nil {
+		return nil, err
+	}
+
+	var s *vv1.Autoscaler
+	var e *errors.Http
+
+	err = ac.client.Put(fmt.Sprintf("/namespace/%s/service/%s/autoscaler", ac.namespace, ac.service)).
+		AddHeader("Content-Type", "application/json").
+		Body(body).
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+func (ac *AutoscalerClient) Remove(ctx context.Context) error {
+
+	var e *errors.Http
+
+	err := ac.client.Delete(fmt.Sprintf("/namespace/%s/service/%s/autoscaler", ac.namespace, ac.service)).
+		AddHeader("Content-Type", "application/json").
+		JSON(nil, &e)
+
+	if err != nil {
+		return err
+	}
+	if e != nil {
+		return errors.New(e.Message)
+	}
+
+	return nil
+}
+
+func newAutoscalerClient(client *request.RESTClient, namespace, service string) *AutoscalerClient {
+	return &AutoscalerClient{client: client, namespace: namespace, service: service}
+}
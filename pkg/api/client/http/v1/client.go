@@ -31,6 +31,21 @@ func New(req *request.RESTClient) *Client {
 	return &Client{client: req}
 }
 
+func (s *Client) Account(args ...string) types.AccountClientV1 {
+	name := ""
+	// Get any parameters passed to us out of the args variable into "real"
+	// variables we created for them.
+	for i := range args {
+		switch i {
+		case 0: // name
+			name = args[0]
+		default:
+			panic("Wrong parameters count: (is allowed from 0 to 1)")
+		}
+	}
+	return newAccountClient(s.client, name)
+}
+
 func (s *Client) Cluster() types.ClusterClientV1 {
 	return newClusterClient(s.client)
 }
@@ -22,6 +22,7 @@ import (
 	"context"
 
 	"github.com/lastbackend/lastbackend/pkg/api/client/types"
+	rv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
 	vv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
 	"github.com/lastbackend/lastbackend/pkg/util/http/request"
@@ -95,6 +96,100 @@ func (cc *ClusterClient) Get(ctx context.Context) (*vv1.Cluster, error) {
 	return s, nil
 }
 
+func (cc *ClusterClient) IPAM(ctx context.Context) (*vv1.IPAM, error) {
+
+	var s *vv1.IPAM
+	var e *errors.Http
+
+	err := cc.client.Get("/cluster/ipam").
+		AddHeader("Content-Type", "application/json").
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+func (cc *ClusterClient) JoinToken(ctx context.Context, opts *rv1.CertificateJoinTokenCreateOptions) (*vv1.CertificateJoinToken, error) {
+
+	body, err := opts.ToJson()
+	if err != nil {
+		return nil, err
+	}
+
+	var s *vv1.CertificateJoinToken
+	var e *errors.Http
+
+	err = cc.client.Post("/cluster/join").
+		AddHeader("Content-Type", "application/json").
+		Body(body).
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+func (cc *ClusterClient) Join(ctx context.Context, opts *rv1.CertificateJoinOptions) (*vv1.Certificate, error) {
+
+	body, err := opts.ToJson()
+	if err != nil {
+		return nil, err
+	}
+
+	var s *vv1.Certificate
+	var e *errors.Http
+
+	err = cc.client.Post("/cluster/certificate").
+		AddHeader("Content-Type", "application/json").
+		Body(body).
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+func (cc *ClusterClient) RenewCertificate(ctx context.Context, opts *rv1.CertificateRenewOptions) (*vv1.Certificate, error) {
+
+	body, err := opts.ToJson()
+	if err != nil {
+		return nil, err
+	}
+
+	var s *vv1.Certificate
+	var e *errors.Http
+
+	err = cc.client.Put("/cluster/certificate").
+		AddHeader("Content-Type", "application/json").
+		Body(body).
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
 func newClusterClient(req *request.RESTClient) *ClusterClient {
 	return &ClusterClient{client: req}
 }
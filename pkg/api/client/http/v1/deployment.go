@@ -57,7 +57,7 @@ func (dc *DeploymentClient) List(ctx context.Context) (*vv1.DeploymentList, erro
 	var s *vv1.DeploymentList
 	var e *errors.Http
 
-	err := dc.client.Get(fmt.Sprintf("/namespace/%s/service/%s/deploymet", dc.namespace, dc.service)).
+	err := dc.client.Get(fmt.Sprintf("/namespace/%s/service/%s/deployment", dc.namespace, dc.service)).
 		AddHeader("Content-Type", "application/json").
 		JSON(&s, &e)
 
@@ -81,7 +81,7 @@ func (dc *DeploymentClient) Get(ctx context.Context) (*vv1.Deployment, error) {
 	var s *vv1.Deployment
 	var e *errors.Http
 
-	err := dc.client.Get(fmt.Sprintf("/namespace/%s/service/%s/deploymet/%s", dc.namespace, dc.service, dc.name)).
+	err := dc.client.Get(fmt.Sprintf("/namespace/%s/service/%s/deployment/%s", dc.namespace, dc.service, dc.name)).
 		AddHeader("Content-Type", "application/json").
 		JSON(&s, &e)
 
@@ -120,6 +120,131 @@ func (dc *DeploymentClient) Update(ctx context.Context, opts *rv1.DeploymentUpda
 	return s, nil
 }
 
+// History returns service deployment revisions with spec changes
+func (dc *DeploymentClient) History(ctx context.Context) (*vv1.DeploymentRevisionList, error) {
+
+	var s *vv1.DeploymentRevisionList
+	var e *errors.Http
+
+	err := dc.client.Get(fmt.Sprintf("/namespace/%s/service/%s/history", dc.namespace, dc.service)).
+		AddHeader("Content-Type", "application/json").
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	if s == nil {
+		list := make(vv1.DeploymentRevisionList, 0)
+		s = &list
+	}
+
+	return s, nil
+}
+
+// Rollback rolls service back to deployment spec
+func (dc *DeploymentClient) Rollback(ctx context.Context) (*vv1.Service, error) {
+
+	var s *vv1.Service
+	var e *errors.Http
+
+	err := dc.client.Post(fmt.Sprintf("/namespace/%s/service/%s/deployment/%s/rollback", dc.namespace, dc.service, dc.name)).
+		AddHeader("Content-Type", "application/json").
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+// Pause pauses deployment rollout
+func (dc *DeploymentClient) Pause(ctx context.Context) (*vv1.Deployment, error) {
+
+	var s *vv1.Deployment
+	var e *errors.Http
+
+	err := dc.client.Post(fmt.Sprintf("/namespace/%s/service/%s/deployment/%s/pause", dc.namespace, dc.service, dc.name)).
+		AddHeader("Content-Type", "application/json").
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+// Resume resumes paused deployment rollout
+func (dc *DeploymentClient) Resume(ctx context.Context) (*vv1.Deployment, error) {
+
+	var s *vv1.Deployment
+	var e *errors.Http
+
+	err := dc.client.Post(fmt.Sprintf("/namespace/%s/service/%s/deployment/%s/resume", dc.namespace, dc.service, dc.name)).
+		AddHeader("Content-Type", "application/json").
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+// Promote promotes canary or blue/green deployment release
+func (dc *DeploymentClient) Promote(ctx context.Context) (*vv1.Deployment, error) {
+
+	var s *vv1.Deployment
+	var e *errors.Http
+
+	err := dc.client.Post(fmt.Sprintf("/namespace/%s/service/%s/deployment/%s/promote", dc.namespace, dc.service, dc.name)).
+		AddHeader("Content-Type", "application/json").
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+// Abort aborts canary or blue/green deployment release
+func (dc *DeploymentClient) Abort(ctx context.Context) (*vv1.Deployment, error) {
+
+	var s *vv1.Deployment
+	var e *errors.Http
+
+	err := dc.client.Post(fmt.Sprintf("/namespace/%s/service/%s/deployment/%s/abort", dc.namespace, dc.service, dc.name)).
+		AddHeader("Content-Type", "application/json").
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
 func newDeploymentClient(client *request.RESTClient, namespace, service, name string) *DeploymentClient {
 	return &DeploymentClient{client: client, namespace: namespace, service: service, name: name}
 }
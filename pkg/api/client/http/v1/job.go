@@ -0,0 +1,169 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	rv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
+	vv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/util/http/request"
+)
+
+type JobClient struct {
+	client *request.RESTClient
+
+	namespace string
+	name      string
+}
+
+func (jc *JobClient) Create(ctx context.Context, opts *rv1.JobManifest) (*vv1.Job, error) {
+
+	body, err := opts.ToJson()
+	if err != nil {
+		return nil, err
+	}
+
+	var s *vv1.Job
+	var e *errors.Http
+
+	err = jc.client.Post(fmt.Sprintf("/namespace/%s/job", jc.namespace)).
+		AddHeader("Content-Type", "application/json").
+		Body(body).
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+func (jc *JobClient) List(ctx context.Context) (*vv1.JobList, error) {
+
+	var s *vv1.JobList
+	var e *errors.Http
+
+	err := jc.client.Get(fmt.Sprintf("/namespace/%s/job", jc.namespace)).
+		AddHeader("Content-Type", "application/json").
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	if s == nil {
+		list := make(vv1.JobList, 0)
+		s = &list
+	}
+
+	return s, nil
+}
+
+func (jc *JobClient) Get(ctx context.Context) (*vv1.Job, error) {
+
+	var s *vv1.Job
+	var e *errors.Http
+
+	err := jc.client.Get(fmt.Sprintf("/namespace/%s/job/%s", jc.namespace, jc.name)).
+		AddHeader("Content-Type", "application/json").
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+func (jc *JobClient) Update(ctx context.Context, opts *rv1.JobManifest) (*vv1.Job, error) {
+
+	body, err := opts.ToJson()
+	if err != nil {
+		return nil, err
+	}
+
+	var s *vv1.Job
+	var e *errors.Http
+
+	err = jc.client.Put(fmt.Sprintf("/namespace/%s/job/%s", jc.namespace, jc.name)).
+		AddHeader("Content-Type", "application/json").
+		Body(body).
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
+func (jc *JobClient) Remove(ctx context.Context) error {
+
+	var e *errors.Http
+
+	err := jc.client.Delete(fmt.Sprintf("/namespace/%s/job/%s", jc.namespace, jc.name)).
+		AddHeader("Content-Type", "application/json").
+		JSON(nil, &e)
+
+	if err != nil {
+		return err
+	}
+	if e != nil {
+		return errors.New(e.Message)
+	}
+
+	return nil
+}
+
+func (jc *JobClient) Logs(ctx context.Context, opts *rv1.JobLogsOptions) (io.ReadCloser, error) {
+
+	res := jc.client.Get(fmt.Sprintf("/namespace/%s/job/%s/logs", jc.namespace, jc.name))
+
+	if opts != nil {
+		res.Param("pod", opts.Pod)
+		res.Param("container", opts.Container)
+
+		if opts.Follow {
+			res.Param("follow", strconv.FormatBool(opts.Follow))
+		}
+	}
+
+	return res.Stream()
+}
+
+func newJobClient(client *request.RESTClient, namespace, name string) *JobClient {
+	return &JobClient{client: client, namespace: namespace, name: name}
+}
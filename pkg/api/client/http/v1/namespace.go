@@ -111,6 +111,36 @@ func (nc *NamespaceClient) Volume(args ...string) types.VolumeClientV1 {
 	return newVolumeClient(nc.client, nc.name, name)
 }
 
+func (nc *NamespaceClient) Job(args ...string) types.JobClientV1 {
+	name := ""
+	// Get any parameters passed to us out of the args variable into "real"
+	// variables we created for them.
+	for i := range args {
+		switch i {
+		case 0: // hostname
+			name = args[0]
+		default:
+			panic("Wrong parameter count: (is allowed from 0 to 1)")
+		}
+	}
+	return newJobClient(nc.client, nc.name, name)
+}
+
+func (nc *NamespaceClient) CronJob(args ...string) types.CronJobClientV1 {
+	name := ""
+	// Get any parameters passed to us out of the args variable into "real"
+	// variables we created for them.
+	for i := range args {
+		switch i {
+		case 0: // hostname
+			name = args[0]
+		default:
+			panic("Wrong parameter count: (is allowed from 0 to 1)")
+		}
+	}
+	return newCronJobClient(nc.client, nc.name, name)
+}
+
 func (nc *NamespaceClient) List(ctx context.Context) (*vv1.NamespaceList, error) {
 
 	var s *vv1.NamespaceList
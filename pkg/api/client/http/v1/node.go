@@ -139,6 +139,44 @@ func (nc NodeClient) Remove(ctx context.Context, opts *rv1.NodeRemoveOptions) er
 	return nil
 }
 
+func (nc NodeClient) Cordon(ctx context.Context) (*vv1.Node, error) {
+	return nc.setSpec(ctx, "cordon", nil)
+}
+
+func (nc NodeClient) Uncordon(ctx context.Context) (*vv1.Node, error) {
+	return nc.setSpec(ctx, "uncordon", nil)
+}
+
+func (nc NodeClient) Drain(ctx context.Context) (*vv1.Node, error) {
+	return nc.setSpec(ctx, "drain", nil)
+}
+
+func (nc NodeClient) SetTaints(ctx context.Context, opts *rv1.NodeTaintsOptions) (*vv1.Node, error) {
+	return nc.setSpec(ctx, "taints", []byte(opts.ToJson()))
+}
+
+func (nc NodeClient) setSpec(ctx context.Context, action string, body []byte) (*vv1.Node, error) {
+
+	var s *vv1.Node
+	var e *errors.Http
+
+	req := nc.client.Put(fmt.Sprintf("/cluster/node/%s/%s", nc.hostname, action)).
+		AddHeader("Content-Type", "application/json")
+
+	if body != nil {
+		req.Body(body)
+	}
+
+	if err := req.JSON(&s, &e); err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
 func newNodeClient(req *request.RESTClient, hostname string) *NodeClient {
 	return &NodeClient{client: req, hostname: hostname}
 }
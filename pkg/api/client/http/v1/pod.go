@@ -22,12 +22,16 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"strconv"
 
 	rv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
 	vv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/util/console"
 	"github.com/lastbackend/lastbackend/pkg/util/http/request"
+	"github.com/lastbackend/lastbackend/pkg/util/portforward"
 )
 
 type PodClient struct {
@@ -99,6 +103,121 @@ func (pc *PodClient) Logs(ctx context.Context, opts *rv1.PodLogsOptions) (io.Rea
 	return res.Stream()
 }
 
+func (pc *PodClient) Exec(ctx context.Context, opts *rv1.PodExecOptions, streams *types.ContainerStreams) (int, error) {
+
+	if streams == nil {
+		streams = new(types.ContainerStreams)
+	}
+
+	res := pc.client.Get(fmt.Sprintf("/namespace/%s/service/%s/exec", pc.namespace, pc.service))
+	res.Param("deployment", pc.deployment)
+	res.Param("pod", pc.name)
+	res.Param("container", opts.Container)
+	res.Param("tty", strconv.FormatBool(opts.TTY))
+	res.Param("stdin", strconv.FormatBool(opts.Stdin && streams.Stdin != nil))
+
+	for _, c := range opts.Command {
+		res.Param("command", c)
+	}
+
+	conn, err := res.Websocket()
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	status, err := console.Stream(conn, streams)
+	if err != nil {
+		return 0, err
+	}
+	if status.Message != "" {
+		return status.Code, errors.New(status.Message)
+	}
+
+	return status.Code, nil
+}
+
+func (pc *PodClient) Attach(ctx context.Context, opts *rv1.PodAttachOptions, streams *types.ContainerStreams) error {
+
+	if streams == nil {
+		streams = new(types.ContainerStreams)
+	}
+
+	res := pc.client.Get(fmt.Sprintf("/namespace/%s/service/%s/attach", pc.namespace, pc.service))
+	res.Param("deployment", pc.deployment)
+	res.Param("pod", pc.name)
+	res.Param("container", opts.Container)
+	res.Param("stdin", strconv.FormatBool(opts.Stdin && streams.Stdin != nil))
+
+	conn, err := res.Websocket()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	status, err := console.Stream(conn, streams)
+	if err != nil {
+		return err
+	}
+	if status.Message != "" {
+		return errors.New(status.Message)
+	}
+
+	return nil
+}
+
+// PortForward listens local ports and forwards connections to pod ports until context is done
+func (pc *PodClient) PortForward(ctx context.Context, opts *rv1.PodPortForwardOptions) error {
+
+	if opts == nil || len(opts.Ports) == 0 {
+		return errors.New("ports are not provided")
+	}
+
+	address := opts.Address
+	if address == "" {
+		address = "127.0.0.1"
+	}
+
+	res := pc.client.Get(fmt.Sprintf("/namespace/%s/service/%s/portforward", pc.namespace, pc.service))
+	res.Param("deployment", pc.deployment)
+	res.Param("pod", pc.name)
+
+	listeners := make([]net.Listener, 0)
+	defer func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}()
+
+	for _, p := range opts.Ports {
+		l, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(int(p.Local))))
+		if err != nil {
+			return err
+		}
+		listeners = append(listeners, l)
+		res.Param("port", strconv.Itoa(int(p.Remote)))
+	}
+
+	conn, err := res.Websocket()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := portforward.NewClient(conn)
+
+	for i, p := range opts.Ports {
+		go client.Listen(ctx, listeners[i], p.Remote)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-client.Done():
+		return client.Err()
+	}
+}
+
 func newPodClient(client *request.RESTClient, namespace, service, deployment, name string) *PodClient {
 	return &PodClient{client: client, namespace: namespace, service: service, deployment: deployment, name: name}
 }
@@ -53,6 +53,10 @@ func (sc *ServiceClient) Deployment(args ...string) types.DeploymentClientV1 {
 	return newDeploymentClient(sc.client, sc.namespace, sc.name, name)
 }
 
+func (sc *ServiceClient) Autoscaler() types.AutoscalerClientV1 {
+	return newAutoscalerClient(sc.client, sc.namespace, sc.name)
+}
+
 func (sc *ServiceClient) Create(ctx context.Context, opts *rv1.ServiceManifest) (*vv1.Service, error) {
 
 	body, err := opts.ToJson()
@@ -186,6 +190,25 @@ func (sc *ServiceClient) Logs(ctx context.Context, opts *rv1.ServiceLogsOptions)
 	return res.Stream()
 }
 
+func (sc *ServiceClient) Metrics(ctx context.Context) (*vv1.ServiceMetrics, error) {
+
+	var s *vv1.ServiceMetrics
+	var e *errors.Http
+
+	err := sc.client.Get(fmt.Sprintf("/namespace/%s/service/%s/metrics", sc.namespace, sc.name)).
+		AddHeader("Content-Type", "application/json").
+		JSON(&s, &e)
+
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return nil, errors.New(e.Message)
+	}
+
+	return s, nil
+}
+
 func newServiceClient(client *request.RESTClient, namespace, name string) *ServiceClient {
 	return &ServiceClient{client: client, namespace: namespace, name: name}
 }
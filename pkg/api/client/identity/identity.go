@@ -0,0 +1,247 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package identity
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/api/client"
+	"github.com/lastbackend/lastbackend/pkg/api/client/config"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/pki"
+)
+
+const (
+	logPrefix = "identity"
+	logLevel  = 3
+
+	// retryInterval is used when certificate rotation failed
+	retryInterval = time.Minute
+)
+
+// Options describes agent identity and the way to obtain client certificate
+type Options struct {
+	// API endpoint
+	Endpoint string
+	// Agent kind: node, ingress or discovery
+	Kind string
+	// Agent name, used as certificate common name
+	Name string
+	// One-time join token, required only for the first start
+	Token string
+	// Directory to store issued certificate and key
+	Dir string
+	// Trusted root certificates for API server
+	CAFile string
+}
+
+func (o Options) certFile() string {
+	return filepath.Join(o.Dir, fmt.Sprintf("%s.pem", o.Kind))
+}
+
+func (o Options) keyFile() string {
+	return filepath.Join(o.Dir, fmt.Sprintf("%s-key.pem", o.Kind))
+}
+
+func (o Options) caFile() string {
+	return filepath.Join(o.Dir, "ca.pem")
+}
+
+// TLS returns client TLS config based on issued certificate
+func (o Options) TLS() *config.TLSConfig {
+	cfg := client.NewTLSConfig()
+	cfg.CertFile = o.certFile()
+	cfg.KeyFile = o.keyFile()
+	cfg.CAFile = o.CAFile
+	return cfg
+}
+
+// Bootstrap returns TLS config with agent client certificate.
+// Previously issued certificate is reused while it is valid,
+// otherwise new certificate is requested with the join token
+func Bootstrap(ctx context.Context, opts Options) (*config.TLSConfig, error) {
+
+	if cert, err := load(opts); err == nil && time.Now().Before(cert.NotAfter) {
+		log.V(logLevel).Debugf("%s:bootstrap:> use certificate issued for %s", logPrefix, cert.Subject.CommonName)
+		return opts.TLS(), nil
+	}
+
+	if opts.Token == "" {
+		return nil, fmt.Errorf("join token is required to issue %s certificate", opts.Kind)
+	}
+
+	cfg := client.NewConfig()
+	if opts.CAFile != "" {
+		cfg.TLS = client.NewTLSConfig()
+		cfg.TLS.CAFile = opts.CAFile
+	}
+
+	cl, err := client.New(client.ClientHTTP, opts.Endpoint, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	key, csr, err := newCSR(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(request.CertificateJoinOptions)
+	req.Token = opts.Token
+	req.Kind = opts.Kind
+	req.Name = opts.Name
+	req.CSR = string(csr)
+
+	cert, err := cl.V1().Cluster().Join(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := save(opts, key, []byte(cert.Certificate), []byte(cert.CA)); err != nil {
+		return nil, err
+	}
+
+	log.V(logLevel).Debugf("%s:bootstrap:> certificate issued, expires at %s", logPrefix, cert.Expires)
+
+	return opts.TLS(), nil
+}
+
+// Rotate renews client certificate before it expires.
+// New certificate is requested with current one and stored in place,
+// so clients reload it on the next connection
+func Rotate(ctx context.Context, opts Options) {
+
+	for {
+
+		var wait = retryInterval
+
+		cert, err := load(opts)
+		if err != nil {
+			log.Errorf("%s:rotate:> load certificate err: %s", logPrefix, err.Error())
+		} else if at := pki.RenewAt(cert); time.Now().Before(at) {
+			wait = time.Until(at)
+		} else if err := renew(ctx, opts); err != nil {
+			log.Errorf("%s:rotate:> renew certificate err: %s", logPrefix, err.Error())
+		} else {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+func renew(ctx context.Context, opts Options) error {
+
+	cfg := client.NewConfig()
+	cfg.TLS = opts.TLS()
+
+	cl, err := client.New(client.ClientHTTP, opts.Endpoint, cfg)
+	if err != nil {
+		return err
+	}
+
+	key, csr, err := newCSR(opts)
+	if err != nil {
+		return err
+	}
+
+	req := new(request.CertificateRenewOptions)
+	req.CSR = string(csr)
+
+	cert, err := cl.V1().Cluster().RenewCertificate(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	log.V(logLevel).Debugf("%s:rotate:> certificate renewed, expires at %s", logPrefix, cert.Expires)
+
+	return save(opts, key, []byte(cert.Certificate), []byte(cert.CA))
+}
+
+func newCSR(opts Options) ([]byte, []byte, error) {
+
+	key, err := pki.GenerateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	csr, err := pki.NewCSR(key, opts.Kind, opts.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pem, err := pki.EncodeKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem, csr, nil
+}
+
+func load(opts Options) (*x509.Certificate, error) {
+
+	if _, err := os.Stat(opts.keyFile()); err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(opts.certFile())
+	if err != nil {
+		return nil, err
+	}
+
+	return pki.ParseCertificate(data)
+}
+
+func save(opts Options, key, cert, ca []byte) error {
+
+	if err := os.MkdirAll(opts.Dir, 0700); err != nil {
+		return err
+	}
+
+	// key should be written first: certificate change triggers key pair reload
+	if err := write(opts.keyFile(), key); err != nil {
+		return err
+	}
+	if err := write(opts.caFile(), ca); err != nil {
+		return err
+	}
+
+	return write(opts.certFile(), cert)
+}
+
+func write(path string, data []byte) error {
+
+	tmp := fmt.Sprintf("%s.tmp", path)
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -24,18 +24,34 @@ import (
 
 	rv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
 	vv1 "github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 )
 
 type ClientV1 interface {
+	Account(args ...string) AccountClientV1
 	Cluster() ClusterClientV1
 	Namespace(args ...string) NamespaceClientV1
 }
 
+type AccountClientV1 interface {
+	Create(ctx context.Context, opts *rv1.AccountCreateOptions) (*vv1.Account, error)
+	List(ctx context.Context) (*vv1.AccountList, error)
+	Get(ctx context.Context) (*vv1.Account, error)
+	Update(ctx context.Context, opts *rv1.AccountUpdateOptions) (*vv1.Account, error)
+	Remove(ctx context.Context, opts *rv1.AccountRemoveOptions) error
+	IssueToken(ctx context.Context) (*vv1.AccountToken, error)
+	RevokeToken(ctx context.Context, id string) error
+}
+
 type ClusterClientV1 interface {
 	Node(args ...string) NodeClientV1
 	Ingress(args ...string) IngressClientV1
 	Discovery(args ...string) DiscoveryClientV1
 	Get(ctx context.Context) (*vv1.Cluster, error)
+	IPAM(ctx context.Context) (*vv1.IPAM, error)
+	JoinToken(ctx context.Context, opts *rv1.CertificateJoinTokenCreateOptions) (*vv1.CertificateJoinToken, error)
+	Join(ctx context.Context, opts *rv1.CertificateJoinOptions) (*vv1.Certificate, error)
+	RenewCertificate(ctx context.Context, opts *rv1.CertificateRenewOptions) (*vv1.Certificate, error)
 }
 
 type NodeClientV1 interface {
@@ -44,6 +60,10 @@ type NodeClientV1 interface {
 	Get(ctx context.Context) (*vv1.Node, error)
 	SetStatus(ctx context.Context, opts *rv1.NodeStatusOptions) (*vv1.NodeManifest, error)
 	Remove(ctx context.Context, opts *rv1.NodeRemoveOptions) error
+	Cordon(ctx context.Context) (*vv1.Node, error)
+	Uncordon(ctx context.Context) (*vv1.Node, error)
+	Drain(ctx context.Context) (*vv1.Node, error)
+	SetTaints(ctx context.Context, opts *rv1.NodeTaintsOptions) (*vv1.Node, error)
 }
 
 type DiscoveryClientV1 interface {
@@ -66,6 +86,8 @@ type NamespaceClientV1 interface {
 	Service(args ...string) ServiceClientV1
 	Route(args ...string) RouteClientV1
 	Volume(args ...string) VolumeClientV1
+	Job(args ...string) JobClientV1
+	CronJob(args ...string) CronJobClientV1
 	Create(ctx context.Context, opts *rv1.NamespaceCreateOptions) (*vv1.Namespace, error)
 	List(ctx context.Context) (*vv1.NamespaceList, error)
 	Get(ctx context.Context) (*vv1.Namespace, error)
@@ -75,12 +97,14 @@ type NamespaceClientV1 interface {
 
 type ServiceClientV1 interface {
 	Deployment(args ...string) DeploymentClientV1
+	Autoscaler() AutoscalerClientV1
 	Create(ctx context.Context, opts *rv1.ServiceManifest) (*vv1.Service, error)
 	List(ctx context.Context) (*vv1.ServiceList, error)
 	Get(ctx context.Context) (*vv1.Service, error)
 	Update(ctx context.Context, opts *rv1.ServiceManifest) (*vv1.Service, error)
 	Remove(ctx context.Context, opts *rv1.ServiceRemoveOptions) error
 	Logs(ctx context.Context, opts *rv1.ServiceLogsOptions) (io.ReadCloser, error)
+	Metrics(ctx context.Context) (*vv1.ServiceMetrics, error)
 }
 
 type DeploymentClientV1 interface {
@@ -89,12 +113,45 @@ type DeploymentClientV1 interface {
 	List(ctx context.Context) (*vv1.DeploymentList, error)
 	Get(ctx context.Context) (*vv1.Deployment, error)
 	Update(ctx context.Context, opts *rv1.DeploymentUpdateOptions) (*vv1.Deployment, error)
+	History(ctx context.Context) (*vv1.DeploymentRevisionList, error)
+	Rollback(ctx context.Context) (*vv1.Service, error)
+	Pause(ctx context.Context) (*vv1.Deployment, error)
+	Resume(ctx context.Context) (*vv1.Deployment, error)
+	Promote(ctx context.Context) (*vv1.Deployment, error)
+	Abort(ctx context.Context) (*vv1.Deployment, error)
+}
+
+type AutoscalerClientV1 interface {
+	Create(ctx context.Context, opts *rv1.AutoscalerOptions) (*vv1.Autoscaler, error)
+	Get(ctx context.Context) (*vv1.Autoscaler, error)
+	Update(ctx context.Context, opts *rv1.AutoscalerOptions) (*vv1.Autoscaler, error)
+	Remove(ctx context.Context) error
+}
+
+type JobClientV1 interface {
+	Create(ctx context.Context, opts *rv1.JobManifest) (*vv1.Job, error)
+	List(ctx context.Context) (*vv1.JobList, error)
+	Get(ctx context.Context) (*vv1.Job, error)
+	Update(ctx context.Context, opts *rv1.JobManifest) (*vv1.Job, error)
+	Remove(ctx context.Context) error
+	Logs(ctx context.Context, opts *rv1.JobLogsOptions) (io.ReadCloser, error)
+}
+
+type CronJobClientV1 interface {
+	Create(ctx context.Context, opts *rv1.CronJobManifest) (*vv1.CronJob, error)
+	List(ctx context.Context) (*vv1.CronJobList, error)
+	Get(ctx context.Context) (*vv1.CronJob, error)
+	Update(ctx context.Context, opts *rv1.CronJobManifest) (*vv1.CronJob, error)
+	Remove(ctx context.Context) error
 }
 
 type PodClientV1 interface {
 	List(ctx context.Context) (*vv1.PodList, error)
 	Get(ctx context.Context) (*vv1.Pod, error)
 	Logs(ctx context.Context, opts *rv1.PodLogsOptions) (io.ReadCloser, error)
+	Exec(ctx context.Context, opts *rv1.PodExecOptions, streams *types.ContainerStreams) (int, error)
+	Attach(ctx context.Context, opts *rv1.PodAttachOptions, streams *types.ContainerStreams) error
+	PortForward(ctx context.Context, opts *rv1.PodPortForwardOptions) error
 }
 
 type EventsClientV1 interface {
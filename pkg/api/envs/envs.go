@@ -21,6 +21,7 @@ package envs
 import (
 	"github.com/lastbackend/lastbackend/pkg/api/cache"
 	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/lastbackend/lastbackend/pkg/util/pki"
 )
 
 var e Env
@@ -28,6 +29,7 @@ var e Env
 type Env struct {
 	storage storage.Storage
 	cache   *cache.Cache
+	ca      *pki.CA
 }
 
 func Get() *Env {
@@ -49,3 +51,11 @@ func (c *Env) SetCache(ch *cache.Cache) {
 func (c *Env) GetCache() *cache.Cache {
 	return c.cache
 }
+
+func (c *Env) SetCA(ca *pki.CA) {
+	c.ca = ca
+}
+
+func (c *Env) GetCA() *pki.CA {
+	return c.ca
+}
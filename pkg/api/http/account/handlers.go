@@ -0,0 +1,482 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package account
+
+import (
+	"net/http"
+
+	"github.com/lastbackend/lastbackend/pkg/api/envs"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/http/utils"
+)
+
+const (
+	logLevel  = 2
+	logPrefix = "api:handler:account"
+)
+
+func AccountListH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /account account accountList
+	//
+	// Shows a list of accounts
+	//
+	// ---
+	// produces:
+	// - application/json
+	// responses:
+	//   '200':
+	//     description: Account list response
+	//     schema:
+	//       "$ref": "#/definitions/views_account_list"
+	//   '500':
+	//     description: Internal server error
+
+	log.V(logLevel).Debugf("%s:list:> get accounts list", logPrefix)
+
+	var am = distribution.NewAccountModel(r.Context(), envs.Get().GetStorage())
+
+	items, err := am.List()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> get accounts list err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Account().NewList(items).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:list:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func AccountInfoH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /account/{account} account accountInfo
+	//
+	// Shows an info about account
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: account
+	//     in: path
+	//     description: account name
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Account response
+	//     schema:
+	//       "$ref": "#/definitions/views_account"
+	//   '404':
+	//     description: Account not found
+	//   '500':
+	//     description: Internal server error
+
+	aid := utils.Vars(r)["account"]
+
+	log.V(logLevel).Debugf("%s:info:> get account `%s`", logPrefix, aid)
+
+	account, e := fetchAccount(r, aid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	response, err := v1.View().Account().New(account).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:info:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:info:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func AccountCreateH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation POST /account account accountCreate
+	//
+	// Create new account
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: body
+	//     in: body
+	//     required: true
+	//     schema:
+	//       "$ref": "#/definitions/request_account_create"
+	// responses:
+	//   '200':
+	//     description: Account was successfully created
+	//     schema:
+	//       "$ref": "#/definitions/views_account"
+	//   '400':
+	//     description: Name is already in use
+	//   '500':
+	//     description: Internal server error
+
+	log.V(logLevel).Debugf("%s:create:> create account", logPrefix)
+
+	var (
+		am   = distribution.NewAccountModel(r.Context(), envs.Get().GetStorage())
+		opts = v1.Request().Account().CreateOptions()
+	)
+
+	// request body struct
+	if e := opts.DecodeAndValidate(r.Body); e != nil {
+		log.V(logLevel).Errorf("%s:create:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
+	item, err := am.Get(opts.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> get account err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if item != nil || opts.Name == types.AccountRoot {
+		log.V(logLevel).Warnf("%s:create:> name `%s` not unique", logPrefix, opts.Name)
+		errors.New("account").NotUnique("name").Http(w)
+		return
+	}
+
+	account := new(types.Account)
+	account.Meta.Name = opts.Name
+	account.Meta.Description = opts.Description
+	account.Spec.Kind = opts.Kind
+	account.Spec.Roles = opts.Roles
+
+	account, err = am.Create(account)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> create account err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Account().New(account).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:create:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func AccountUpdateH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation PUT /account/{account} account accountUpdate
+	//
+	// Update account description and roles
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: account
+	//     in: path
+	//     description: account name
+	//     required: true
+	//     type: string
+	//   - name: body
+	//     in: body
+	//     required: true
+	//     schema:
+	//       "$ref": "#/definitions/request_account_update"
+	// responses:
+	//   '200':
+	//     description: Account was successfully updated
+	//     schema:
+	//       "$ref": "#/definitions/views_account"
+	//   '400':
+	//     description: Bad parameter
+	//   '404':
+	//     description: Account not found
+	//   '500':
+	//     description: Internal server error
+
+	aid := utils.Vars(r)["account"]
+
+	log.V(logLevel).Debugf("%s:update:> update account `%s`", logPrefix, aid)
+
+	var (
+		am   = distribution.NewAccountModel(r.Context(), envs.Get().GetStorage())
+		opts = v1.Request().Account().UpdateOptions()
+	)
+
+	// request body struct
+	if e := opts.DecodeAndValidate(r.Body); e != nil {
+		log.V(logLevel).Errorf("%s:update:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
+	account, e := fetchAccount(r, aid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	if opts.Description != nil {
+		account.Meta.Description = *opts.Description
+	}
+
+	if opts.Roles != nil {
+		if account.Agent() && len(opts.Roles) > 0 {
+			log.V(logLevel).Warnf("%s:update:> agent account `%s` can not have roles", logPrefix, aid)
+			errors.New("account").BadParameter("roles").Http(w)
+			return
+		}
+		account.Spec.Roles = opts.Roles
+	}
+
+	account, err := am.Update(account)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:update:> update account err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Account().New(account).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:update:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:update:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func AccountRemoveH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation DELETE /account/{account} account accountRemove
+	//
+	// Remove account with all issued tokens
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: account
+	//     in: path
+	//     description: account name
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Account was successfully removed
+	//   '404':
+	//     description: Account not found
+	//   '500':
+	//     description: Internal server error
+
+	aid := utils.Vars(r)["account"]
+
+	log.V(logLevel).Debugf("%s:remove:> remove account `%s`", logPrefix, aid)
+
+	var am = distribution.NewAccountModel(r.Context(), envs.Get().GetStorage())
+
+	account, e := fetchAccount(r, aid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	if err := am.Remove(account); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> remove account `%s` err: %s", logPrefix, aid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte{}); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func AccountTokenIssueH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation POST /account/{account}/token account accountTokenIssue
+	//
+	// Issue new account token, token value is shown only in this response
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: account
+	//     in: path
+	//     description: account name
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Token was successfully issued
+	//     schema:
+	//       "$ref": "#/definitions/views_account_token"
+	//   '404':
+	//     description: Account not found
+	//   '500':
+	//     description: Internal server error
+
+	aid := utils.Vars(r)["account"]
+
+	log.V(logLevel).Debugf("%s:token:issue:> issue token for account `%s`", logPrefix, aid)
+
+	var am = distribution.NewAccountModel(r.Context(), envs.Get().GetStorage())
+
+	account, e := fetchAccount(r, aid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	token, value, err := account.IssueToken()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:token:issue:> issue token err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if _, err := am.Update(account); err != nil {
+		log.V(logLevel).Errorf("%s:token:issue:> update account err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Account().NewToken(token, value).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:token:issue:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:token:issue:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func AccountTokenRevokeH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation DELETE /account/{account}/token/{token} account accountTokenRevoke
+	//
+	// Revoke account token
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: account
+	//     in: path
+	//     description: account name
+	//     required: true
+	//     type: string
+	//   - name: token
+	//     in: path
+	//     description: token id
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Token was successfully revoked
+	//   '404':
+	//     description: Account or token not found
+	//   '500':
+	//     description: Internal server error
+
+	aid := utils.Vars(r)["account"]
+	tid := utils.Vars(r)["token"]
+
+	log.V(logLevel).Debugf("%s:token:revoke:> revoke token `%s` of account `%s`", logPrefix, tid, aid)
+
+	var am = distribution.NewAccountModel(r.Context(), envs.Get().GetStorage())
+
+	account, e := fetchAccount(r, aid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	if !account.RevokeToken(tid) {
+		log.V(logLevel).Warnf("%s:token:revoke:> token `%s` not found", logPrefix, tid)
+		errors.New("token").NotFound().Http(w)
+		return
+	}
+
+	if _, err := am.Update(account); err != nil {
+		log.V(logLevel).Errorf("%s:token:revoke:> update account err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte{}); err != nil {
+		log.V(logLevel).Errorf("%s:token:revoke:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func fetchAccount(r *http.Request, name string) (*types.Account, *errors.Err) {
+
+	var am = distribution.NewAccountModel(r.Context(), envs.Get().GetStorage())
+
+	account, err := am.Get(name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:fetch:> get account `%s` err: %s", logPrefix, name, err.Error())
+		return nil, errors.New("account").Unknown(err)
+	}
+	if account == nil {
+		log.V(logLevel).Warnf("%s:fetch:> account `%s` not found", logPrefix, name)
+		return nil, errors.New("account").NotFound()
+	}
+
+	return account, nil
+}
@@ -0,0 +1,218 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package account_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/lastbackend/lastbackend/pkg/api/envs"
+	"github.com/lastbackend/lastbackend/pkg/api/http/account"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// Testing AccountCreateH handler
+func TestAccountCreate(t *testing.T) {
+
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+
+	a1 := getAccountAsset("demo", types.AccountKindUser)
+
+	tests := []struct {
+		name         string
+		data         *request.AccountCreateOptions
+		want         *types.Account
+		wantErr      bool
+		err          string
+		expectedCode int
+	}{
+		{
+			name:         "checking create account if name already exists",
+			data:         &request.AccountCreateOptions{Name: a1.Meta.Name, Kind: types.AccountKindUser},
+			wantErr:      true,
+			err:          "{\"code\":400,\"status\":\"Not Unique\",\"message\":\"Name is already in use\"}",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking create account with unknown kind",
+			data:         &request.AccountCreateOptions{Name: "robot", Kind: "unknown"},
+			wantErr:      true,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad kind parameter\"}",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking create agent account with roles",
+			data:         &request.AccountCreateOptions{Name: "node1", Kind: types.AccountKindNode, Roles: map[string]string{"demo": types.RoleAdmin}},
+			wantErr:      true,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad roles parameter\"}",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "checking create account successfully",
+			data: &request.AccountCreateOptions{Name: "robot", Kind: types.AccountKindService,
+				Roles: map[string]string{"demo": types.RoleDeveloper}},
+			want:         getAccountAsset("robot", types.AccountKindService),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Account(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range tests {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			err := stg.Put(context.Background(), stg.Collection().Account(), stg.Key().Account(a1.Meta.Name), a1, nil)
+			assert.NoError(t, err)
+
+			body, err := tc.data.ToJson()
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("POST", "/account", strings.NewReader(string(body)))
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/account", account.AccountCreateH)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			if !assert.Equal(t, tc.expectedCode, res.Code, "status code not equal") {
+				return
+			}
+
+			b, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+
+			if tc.wantErr {
+				assert.Equal(t, tc.err, string(b), "incorrect status code")
+				return
+			}
+
+			a := new(views.Account)
+			err = json.Unmarshal(b, &a)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.want.Meta.Name, a.Meta.Name, "name not equal")
+			assert.Equal(t, tc.want.Spec.Kind, a.Spec.Kind, "kind not equal")
+			assert.Equal(t, types.RoleDeveloper, a.Spec.Roles["demo"], "role not equal")
+		})
+	}
+}
+
+// Testing AccountTokenIssueH and AccountTokenRevokeH handlers
+func TestAccountToken(t *testing.T) {
+
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+
+	var (
+		ctx = context.Background()
+		am  = distribution.NewAccountModel(ctx, stg)
+		a1  = getAccountAsset("demo", types.AccountKindUser)
+	)
+
+	err := stg.Del(ctx, stg.Collection().Account(), types.EmptyString)
+	assert.NoError(t, err)
+	defer stg.Del(ctx, stg.Collection().Account(), types.EmptyString)
+
+	err = stg.Put(ctx, stg.Collection().Account(), stg.Key().Account(a1.Meta.Name), a1, nil)
+	assert.NoError(t, err)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/account/{account}/token", account.AccountTokenIssueH).Methods(http.MethodPost)
+	r.HandleFunc("/account/{account}/token/{token}", account.AccountTokenRevokeH).Methods(http.MethodDelete)
+
+	// Issue token for unknown account
+	req, err := http.NewRequest("POST", "/account/unknown/token", nil)
+	assert.NoError(t, err)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusNotFound, res.Code, "status code not equal")
+
+	// Issue token
+	req, err = http.NewRequest("POST", "/account/demo/token", nil)
+	assert.NoError(t, err)
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	if !assert.Equal(t, http.StatusOK, res.Code, "status code not equal") {
+		return
+	}
+
+	token := new(views.AccountToken)
+	err = json.NewDecoder(res.Body).Decode(token)
+	assert.NoError(t, err)
+
+	item, err := am.Authenticate(token.Token)
+	assert.NoError(t, err)
+	if assert.NotNil(t, item, "token should be valid") {
+		assert.Equal(t, a1.Meta.Name, item.Meta.Name)
+	}
+
+	// Token secret should not be stored
+	item, err = am.Get(a1.Meta.Name)
+	assert.NoError(t, err)
+	assert.NotContains(t, item.Spec.Tokens[token.ID].Hash, strings.Split(token.Token, ":")[2])
+
+	// Revoke token
+	req, err = http.NewRequest("DELETE", fmt.Sprintf("/account/demo/token/%s", token.ID), nil)
+	assert.NoError(t, err)
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code, "status code not equal")
+
+	item, err = am.Authenticate(token.Token)
+	assert.NoError(t, err)
+	assert.Nil(t, item, "revoked token should be invalid")
+
+	// Revoke unknown token
+	req, err = http.NewRequest("DELETE", fmt.Sprintf("/account/demo/token/%s", token.ID), nil)
+	assert.NoError(t, err)
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusNotFound, res.Code, "status code not equal")
+}
+
+func getAccountAsset(name, kind string) *types.Account {
+	var a = types.Account{}
+	a.Meta.Name = name
+	a.Spec.Kind = kind
+	a.Spec.Roles = make(map[string]string)
+	a.Spec.Tokens = make(map[string]*types.AccountToken)
+	return &a
+}
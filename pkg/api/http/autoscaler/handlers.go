@@ -0,0 +1,525 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package autoscaler
+
+import (
+	"net/http"
+
+	"github.com/lastbackend/lastbackend/pkg/api/envs"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/http/utils"
+)
+
+const (
+	logLevel  = 2
+	logPrefix = "api:handler:autoscaler"
+)
+
+func AutoscalerListH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /namespace/{namespace}/autoscaler autoscaler autoscalerList
+	//
+	// Shows a list of services autoscalers in namespace
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Autoscaler list response
+	//     schema:
+	//       "$ref": "#/definitions/views_autoscaler_list"
+	//   '404':
+	//     description: Namespace not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["namespace"]
+
+	log.V(logLevel).Debugf("%s:list:> get autoscalers list in namespace `%s`", logPrefix, nid)
+
+	var (
+		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		am  = distribution.NewAutoscalerModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, err := nsm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:list:> namespace %s not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	al, err := am.List(ns.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> get autoscalers list err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Autoscaler().NewList(al).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:list:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func AutoscalerInfoH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /namespace/{namespace}/service/{service}/autoscaler autoscaler autoscalerInfo
+	//
+	// Shows service autoscaler with current utilisation and desired replicas
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: service
+	//     in: path
+	//     description: name of the service
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Autoscaler response
+	//     schema:
+	//       "$ref": "#/definitions/views_autoscaler"
+	//   '404':
+	//     description: Namespace not found / Service not found / Autoscaler not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["namespace"]
+	sid := utils.Vars(r)["service"]
+
+	log.V(logLevel).Debugf("%s:info:> get autoscaler of service `%s/%s`", logPrefix, nid, sid)
+
+	var (
+		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		sm  = distribution.NewServiceModel(r.Context(), envs.Get().GetStorage())
+		am  = distribution.NewAutoscalerModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, err := nsm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:info:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:info:> namespace %s not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	svc, err := sm.Get(ns.Meta.Name, sid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:info:> get service `%s` in namespace `%s` err: %s", logPrefix, sid, ns.Meta.Name, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if svc == nil {
+		log.V(logLevel).Warnf("%s:info:> service `%s` in namespace `%s` not found", logPrefix, sid, ns.Meta.Name)
+		errors.New("service").NotFound().Http(w)
+		return
+	}
+
+	as, err := am.Get(svc.Meta.Namespace, svc.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:info:> get autoscaler of service `%s` err: %s", logPrefix, svc.SelfLink(), err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if as == nil {
+		log.V(logLevel).Warnf("%s:info:> autoscaler of service `%s` not found", logPrefix, svc.SelfLink())
+		errors.New("autoscaler").NotFound().Http(w)
+		return
+	}
+
+	response, err := v1.View().Autoscaler().New(as).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:info:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:info:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func AutoscalerCreateH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation POST /namespace/{namespace}/service/{service}/autoscaler autoscaler autoscalerCreate
+	//
+	// Creates service autoscaler
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: service
+	//     in: path
+	//     description: name of the service
+	//     required: true
+	//     type: string
+	//   - name: body
+	//     in: body
+	//     required: true
+	//     schema:
+	//       "$ref": "#/definitions/request_autoscaler"
+	// responses:
+	//   '200':
+	//     description: Autoscaler was successfully created
+	//     schema:
+	//       "$ref": "#/definitions/views_autoscaler"
+	//   '400':
+	//     description: Bad request
+	//   '404':
+	//     description: Namespace not found / Service not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["namespace"]
+	sid := utils.Vars(r)["service"]
+
+	log.V(logLevel).Debugf("%s:create:> create autoscaler for service `%s/%s`", logPrefix, nid, sid)
+
+	var (
+		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		sm  = distribution.NewServiceModel(r.Context(), envs.Get().GetStorage())
+		am  = distribution.NewAutoscalerModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	// request body struct
+	opts := v1.Request().Autoscaler().Options()
+	if e := opts.DecodeAndValidate(r.Body); e != nil {
+		log.V(logLevel).Errorf("%s:create:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
+	ns, err := nsm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:create:> namespace %s not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	svc, err := sm.Get(ns.Meta.Name, sid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> get service `%s` in namespace `%s` err: %s", logPrefix, sid, ns.Meta.Name, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if svc == nil {
+		log.V(logLevel).Warnf("%s:create:> service `%s` in namespace `%s` not found", logPrefix, sid, ns.Meta.Name)
+		errors.New("service").NotFound().Http(w)
+		return
+	}
+
+	if svc.Status.State == types.StateDestroy || svc.Status.State == types.StateDestroyed {
+		log.V(logLevel).Warnf("%s:create:> service `%s` is destroyed", logPrefix, svc.SelfLink())
+		errors.New("service").BadRequest("Service is destroyed").Http(w)
+		return
+	}
+
+	if svc.Spec.Daemon() {
+		log.V(logLevel).Warnf("%s:create:> service `%s` runs in daemon mode", logPrefix, svc.SelfLink())
+		errors.New("service").BadRequest("Daemon service replicas are set by nodes count").Http(w)
+		return
+	}
+
+	item, err := am.Get(svc.Meta.Namespace, svc.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> get autoscaler of service `%s` err: %s", logPrefix, svc.SelfLink(), err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if item != nil {
+		log.V(logLevel).Warnf("%s:create:> autoscaler of service `%s` already exists", logPrefix, svc.SelfLink())
+		errors.New("autoscaler").NotUnique("service").Http(w)
+		return
+	}
+
+	as := new(types.Autoscaler)
+	opts.SetAutoscalerSpec(as)
+
+	as, err = am.Create(svc, as)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> create autoscaler err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Autoscaler().New(as).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:create:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func AutoscalerUpdateH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation PUT /namespace/{namespace}/service/{service}/autoscaler autoscaler autoscalerUpdate
+	//
+	// Updates service autoscaler
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: service
+	//     in: path
+	//     description: name of the service
+	//     required: true
+	//     type: string
+	//   - name: body
+	//     in: body
+	//     required: true
+	//     schema:
+	//       "$ref": "#/definitions/request_autoscaler"
+	// responses:
+	//   '200':
+	//     description: Autoscaler was successfully updated
+	//     schema:
+	//       "$ref": "#/definitions/views_autoscaler"
+	//   '400':
+	//     description: Bad request
+	//   '404':
+	//     description: Namespace not found / Service not found / Autoscaler not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["namespace"]
+	sid := utils.Vars(r)["service"]
+
+	log.V(logLevel).Debugf("%s:update:> update autoscaler of service `%s/%s`", logPrefix, nid, sid)
+
+	var (
+		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		sm  = distribution.NewServiceModel(r.Context(), envs.Get().GetStorage())
+		am  = distribution.NewAutoscalerModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	// request body struct
+	opts := v1.Request().Autoscaler().Options()
+	if e := opts.DecodeAndValidate(r.Body); e != nil {
+		log.V(logLevel).Errorf("%s:update:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
+	ns, err := nsm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:update:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:update:> namespace %s not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	svc, err := sm.Get(ns.Meta.Name, sid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:update:> get service `%s` in namespace `%s` err: %s", logPrefix, sid, ns.Meta.Name, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if svc == nil {
+		log.V(logLevel).Warnf("%s:update:> service `%s` in namespace `%s` not found", logPrefix, sid, ns.Meta.Name)
+		errors.New("service").NotFound().Http(w)
+		return
+	}
+
+	as, err := am.Get(svc.Meta.Namespace, svc.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:update:> get autoscaler of service `%s` err: %s", logPrefix, svc.SelfLink(), err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if as == nil {
+		log.V(logLevel).Warnf("%s:update:> autoscaler of service `%s` not found", logPrefix, svc.SelfLink())
+		errors.New("autoscaler").NotFound().Http(w)
+		return
+	}
+
+	opts.SetAutoscalerSpec(as)
+
+	as, err = am.Update(as)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:update:> update autoscaler err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Autoscaler().New(as).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:update:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:update:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func AutoscalerRemoveH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation DELETE /namespace/{namespace}/service/{service}/autoscaler autoscaler autoscalerRemove
+	//
+	// Removes service autoscaler, service keeps current replicas
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: service
+	//     in: path
+	//     description: name of the service
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Autoscaler was successfully removed
+	//   '404':
+	//     description: Namespace not found / Service not found / Autoscaler not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["namespace"]
+	sid := utils.Vars(r)["service"]
+
+	log.V(logLevel).Debugf("%s:remove:> remove autoscaler of service `%s/%s`", logPrefix, nid, sid)
+
+	var (
+		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		sm  = distribution.NewServiceModel(r.Context(), envs.Get().GetStorage())
+		am  = distribution.NewAutoscalerModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, err := nsm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:remove:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:remove:> namespace %s not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	svc, err := sm.Get(ns.Meta.Name, sid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:remove:> get service `%s` in namespace `%s` err: %s", logPrefix, sid, ns.Meta.Name, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if svc == nil {
+		log.V(logLevel).Warnf("%s:remove:> service `%s` in namespace `%s` not found", logPrefix, sid, ns.Meta.Name)
+		errors.New("service").NotFound().Http(w)
+		return
+	}
+
+	as, err := am.Get(svc.Meta.Namespace, svc.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:remove:> get autoscaler of service `%s` err: %s", logPrefix, svc.SelfLink(), err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if as == nil {
+		log.V(logLevel).Warnf("%s:remove:> autoscaler of service `%s` not found", logPrefix, svc.SelfLink())
+		errors.New("autoscaler").NotFound().Http(w)
+		return
+	}
+
+	if err := am.Remove(as); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> remove autoscaler err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte{}); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
@@ -0,0 +1,310 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package autoscaler_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/lastbackend/lastbackend/pkg/api/envs"
+	"github.com/lastbackend/lastbackend/pkg/api/http/autoscaler"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// Testing AutoscalerCreateH handler
+func TestAutoscalerCreate(t *testing.T) {
+
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+
+	ns1 := getNamespaceAsset("demo")
+	s1 := getServiceAsset(ns1.Meta.Name, "demo")
+	s1.Spec.Replicas = 2
+	s2 := getServiceAsset(ns1.Meta.Name, "test")
+
+	a2 := getAutoscalerAsset(s2)
+
+	opts := func(min, max, cpu int) *request.AutoscalerOptions {
+		o := new(request.AutoscalerOptions)
+		o.MinReplicas = min
+		o.MaxReplicas = max
+		o.Target.CPU = cpu
+		return o
+	}
+
+	tests := []struct {
+		name         string
+		service      *types.Service
+		data         *request.AutoscalerOptions
+		err          string
+		wantErr      bool
+		expectedCode int
+	}{
+		{
+			name:         "checking create autoscaler with min replicas greater than max",
+			service:      s1,
+			data:         opts(5, 2, 50),
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad min_replicas parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking create autoscaler without target",
+			service:      s1,
+			data:         opts(1, 5, 0),
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad target parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking create autoscaler if service not exists",
+			service:      getServiceAsset(ns1.Meta.Name, "unknown"),
+			data:         opts(1, 5, 50),
+			err:          "{\"code\":404,\"status\":\"Not Found\",\"message\":\"Service not found\"}",
+			wantErr:      true,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "checking create autoscaler if already exists",
+			service:      s2,
+			data:         opts(1, 5, 50),
+			err:          "{\"code\":400,\"status\":\"Not Unique\",\"message\":\"Service is already in use\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking create autoscaler successfully",
+			service:      s1,
+			data:         opts(1, 5, 50),
+			wantErr:      false,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Service(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Autoscaler(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range tests {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			err := stg.Put(context.Background(), stg.Collection().Namespace(), stg.Key().Namespace(ns1.Meta.Name), ns1, nil)
+			assert.NoError(t, err)
+
+			for _, s := range []*types.Service{s1, s2} {
+				err = stg.Put(context.Background(), stg.Collection().Service(), stg.Key().Service(s.Meta.Namespace, s.Meta.Name), s, nil)
+				assert.NoError(t, err)
+			}
+
+			err = stg.Put(context.Background(), stg.Collection().Autoscaler(), stg.Key().Autoscaler(a2.Meta.Namespace, a2.Meta.Service), a2, nil)
+			assert.NoError(t, err)
+
+			body, err := tc.data.ToJson()
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("POST", fmt.Sprintf("/namespace/%s/service/%s/autoscaler", ns1.Meta.Name, tc.service.Meta.Name), strings.NewReader(string(body)))
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/namespace/{namespace}/service/{service}/autoscaler", autoscaler.AutoscalerCreateH)
+
+			setRequestVars(r, req)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			if !assert.Equal(t, tc.expectedCode, res.Code, "status code not equal") {
+				return
+			}
+
+			data, e := ioutil.ReadAll(res.Body)
+			assert.NoError(t, e)
+
+			if tc.wantErr {
+				assert.Equal(t, tc.err, string(data), "incorrect status code")
+				return
+			}
+
+			v := new(views.Autoscaler)
+			err = json.Unmarshal(data, v)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.service.Meta.Name, v.Meta.Service, "service not equal")
+			assert.Equal(t, tc.data.MaxReplicas, v.Spec.MaxReplicas, "max replicas not equal")
+			assert.Equal(t, tc.data.Target.CPU, v.Spec.Target.CPU, "cpu target not equal")
+			assert.Equal(t, tc.service.Spec.Replicas, v.Status.Replicas, "replicas not equal")
+
+			a := new(types.Autoscaler)
+			err = stg.Get(context.Background(), stg.Collection().Autoscaler(), stg.Key().Autoscaler(ns1.Meta.Name, tc.service.Meta.Name), a, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.data.MaxReplicas, a.Spec.MaxReplicas, "stored max replicas not equal")
+		})
+	}
+}
+
+// Testing AutoscalerRemoveH handler
+func TestAutoscalerRemove(t *testing.T) {
+
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+
+	ns1 := getNamespaceAsset("demo")
+	s1 := getServiceAsset(ns1.Meta.Name, "demo")
+	s2 := getServiceAsset(ns1.Meta.Name, "test")
+
+	a1 := getAutoscalerAsset(s1)
+
+	tests := []struct {
+		name         string
+		service      *types.Service
+		err          string
+		wantErr      bool
+		expectedCode int
+	}{
+		{
+			name:         "checking remove autoscaler if not exists",
+			service:      s2,
+			err:          "{\"code\":404,\"status\":\"Not Found\",\"message\":\"Autoscaler not found\"}",
+			wantErr:      true,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "checking remove autoscaler successfully",
+			service:      s1,
+			wantErr:      false,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Service(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Autoscaler(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range tests {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			err := stg.Put(context.Background(), stg.Collection().Namespace(), stg.Key().Namespace(ns1.Meta.Name), ns1, nil)
+			assert.NoError(t, err)
+
+			for _, s := range []*types.Service{s1, s2} {
+				err = stg.Put(context.Background(), stg.Collection().Service(), stg.Key().Service(s.Meta.Namespace, s.Meta.Name), s, nil)
+				assert.NoError(t, err)
+			}
+
+			err = stg.Put(context.Background(), stg.Collection().Autoscaler(), stg.Key().Autoscaler(a1.Meta.Namespace, a1.Meta.Service), a1, nil)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("DELETE", fmt.Sprintf("/namespace/%s/service/%s/autoscaler", ns1.Meta.Name, tc.service.Meta.Name), nil)
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/namespace/{namespace}/service/{service}/autoscaler", autoscaler.AutoscalerRemoveH)
+
+			setRequestVars(r, req)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			if !assert.Equal(t, tc.expectedCode, res.Code, "status code not equal") {
+				return
+			}
+
+			data, e := ioutil.ReadAll(res.Body)
+			assert.NoError(t, e)
+
+			if tc.wantErr {
+				assert.Equal(t, tc.err, string(data), "incorrect status code")
+				return
+			}
+
+			a := new(types.Autoscaler)
+			err = stg.Get(context.Background(), stg.Collection().Autoscaler(), stg.Key().Autoscaler(ns1.Meta.Name, tc.service.Meta.Name), a, nil)
+			assert.Error(t, err, "autoscaler should be removed")
+		})
+	}
+}
+
+func getNamespaceAsset(name string) *types.Namespace {
+	var n = types.Namespace{}
+	n.Meta.SetDefault()
+	n.Meta.Name = name
+	return &n
+}
+
+func getServiceAsset(namespace, name string) *types.Service {
+	var s = types.Service{}
+	s.Meta.SetDefault()
+	s.Meta.Namespace = namespace
+	s.Meta.Name = name
+	s.Status.State = types.StateReady
+	return &s
+}
+
+func getAutoscalerAsset(svc *types.Service) *types.Autoscaler {
+	var a = types.Autoscaler{}
+	a.Meta.SetDefault()
+	a.Meta.Name = svc.Meta.Name
+	a.Meta.Namespace = svc.Meta.Namespace
+	a.Meta.Service = svc.Meta.Name
+	a.Spec.MinReplicas = 1
+	a.Spec.MaxReplicas = 3
+	a.Spec.Target.CPU = 60
+	return &a
+}
+
+func setRequestVars(r *mux.Router, req *http.Request) {
+	var match mux.RouteMatch
+	// Take the request and match it
+	r.Match(req, &match)
+	// Push the variable onto the context
+	req = mux.SetURLVars(req, match.Vars)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
 	"github.com/spf13/viper"
 )
 
@@ -83,3 +84,313 @@ func ClusterInfoH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func ClusterIPAMH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /cluster/ipam cluster clusterIPAM
+	//
+	// Shows IP address pools usage and leases
+	//
+	// ---
+	// produces:
+	// - application/json
+	// responses:
+	//   '200':
+	//     description: IPAM response
+	//     schema:
+	//       "$ref": "#/definitions/views_ipam"
+	//   '500':
+	//     description: Internal server error
+
+	log.V(logLevel).Debugf("%s:ipam:> get ipam", logPrefix)
+
+	var im = distribution.NewIPAMModel(r.Context(), envs.Get().GetStorage())
+
+	state, err := im.Get()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:ipam:> get ipam err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().IPAM().New(state).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:ipam:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:ipam:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func ClusterJoinTokenCreateH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation POST /cluster/join cluster clusterJoinTokenCreate
+	//
+	// Create one-time token used by agent to receive client certificate
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: body
+	//     in: body
+	//     required: true
+	//     schema:
+	//       "$ref": "#/definitions/request_certificate_join_token_create"
+	// responses:
+	//   '200':
+	//     description: Join token was successfully created
+	//     schema:
+	//       "$ref": "#/definitions/views_certificate_join_token"
+	//   '400':
+	//     description: Bad parameter
+	//   '500':
+	//     description: Internal server error
+
+	log.V(logLevel).Debugf("%s:join:> create join token", logPrefix)
+
+	var (
+		cm   = distribution.NewCertificateModel(r.Context(), envs.Get().GetStorage())
+		opts = v1.Request().Certificate().JoinTokenCreateOptions()
+	)
+
+	if e := opts.DecodeAndValidate(r.Body); e != nil {
+		log.V(logLevel).Errorf("%s:join:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
+	token, value, err := types.NewJoinToken(opts.Kind, opts.Name, opts.Duration())
+	if err != nil {
+		log.V(logLevel).Errorf("%s:join:> create join token err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if err := cm.CreateJoinToken(token); err != nil {
+		log.V(logLevel).Errorf("%s:join:> save join token err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Certificate().NewJoinToken(token, value).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:join:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:join:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func ClusterCertificateJoinH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation POST /cluster/certificate cluster clusterCertificateJoin
+	//
+	// Issue agent client certificate by one-time join token
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: body
+	//     in: body
+	//     required: true
+	//     schema:
+	//       "$ref": "#/definitions/request_certificate_join"
+	// responses:
+	//   '200':
+	//     description: Certificate was successfully issued
+	//     schema:
+	//       "$ref": "#/definitions/views_certificate"
+	//   '400':
+	//     description: Bad parameter
+	//   '401':
+	//     description: Invalid join token
+	//   '403':
+	//     description: Join token is issued for other agent or account with other kind already exists
+	//   '500':
+	//     description: Internal server error
+
+	log.V(logLevel).Debugf("%s:certificate:join:> issue certificate by join token", logPrefix)
+
+	var (
+		cm   = distribution.NewCertificateModel(r.Context(), envs.Get().GetStorage())
+		am   = distribution.NewAccountModel(r.Context(), envs.Get().GetStorage())
+		opts = v1.Request().Certificate().JoinOptions()
+	)
+
+	if e := opts.DecodeAndValidate(r.Body); e != nil {
+		log.V(logLevel).Errorf("%s:certificate:join:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
+	id, secret, ok := types.ParseJoinToken(opts.Token)
+	if !ok {
+		log.V(logLevel).Warnf("%s:certificate:join:> invalid join token format", logPrefix)
+		errors.HTTP.Unauthorized(w)
+		return
+	}
+
+	token, err := cm.GetJoinToken(id)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:certificate:join:> get join token err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if token == nil || !token.Verify(secret) {
+		log.V(logLevel).Warnf("%s:certificate:join:> join token `%s` not found", logPrefix, id)
+		errors.HTTP.Unauthorized(w)
+		return
+	}
+
+	if token.Expired() {
+		log.V(logLevel).Warnf("%s:certificate:join:> join token `%s` expired", logPrefix, id)
+		errors.HTTP.Unauthorized(w)
+		return
+	}
+
+	// Join token is bound to agent, so leaked token can not be used by other agent
+	if !token.Allowed(opts.Kind, opts.Name) {
+		log.V(logLevel).Warnf("%s:certificate:join:> join token `%s` is not issued for %s `%s`", logPrefix, id, opts.Kind, opts.Name)
+		errors.HTTP.Forbidden(w)
+		return
+	}
+
+	// Join token can be used only once
+	if err := cm.RemoveJoinToken(token); err != nil {
+		log.V(logLevel).Errorf("%s:certificate:join:> remove join token err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	account, err := am.Get(opts.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:certificate:join:> get account err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if account != nil && account.Spec.Kind != token.Kind {
+		log.V(logLevel).Warnf("%s:certificate:join:> account `%s` kind is not %s", logPrefix, opts.Name, token.Kind)
+		errors.HTTP.Forbidden(w)
+		return
+	}
+
+	if account == nil {
+		account = new(types.Account)
+		account.Meta.Name = opts.Name
+		account.Spec.Kind = token.Kind
+
+		if _, err := am.Create(account); err != nil {
+			log.V(logLevel).Errorf("%s:certificate:join:> create account err: %s", logPrefix, err.Error())
+			errors.HTTP.InternalServerError(w)
+			return
+		}
+	}
+
+	issueCertificate(w, account, opts.CSR)
+}
+
+func ClusterCertificateRenewH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation PUT /cluster/certificate cluster clusterCertificateRenew
+	//
+	// Rotate agent client certificate, request should be authenticated by current client certificate
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: body
+	//     in: body
+	//     required: true
+	//     schema:
+	//       "$ref": "#/definitions/request_certificate_renew"
+	// responses:
+	//   '200':
+	//     description: Certificate was successfully issued
+	//     schema:
+	//       "$ref": "#/definitions/views_certificate"
+	//   '400':
+	//     description: Bad parameter
+	//   '403':
+	//     description: Request is not authenticated by agent certificate
+	//   '500':
+	//     description: Internal server error
+
+	log.V(logLevel).Debugf("%s:certificate:renew:> renew certificate", logPrefix)
+
+	var (
+		am   = distribution.NewAccountModel(r.Context(), envs.Get().GetStorage())
+		opts = v1.Request().Certificate().RenewOptions()
+	)
+
+	identity := middleware.Account(r)
+	if identity == nil || !identity.Agent() || !middleware.Certified(r) {
+		log.V(logLevel).Warnf("%s:certificate:renew:> request is not authenticated by agent certificate", logPrefix)
+		errors.HTTP.Forbidden(w)
+		return
+	}
+
+	if e := opts.DecodeAndValidate(r.Body); e != nil {
+		log.V(logLevel).Errorf("%s:certificate:renew:> validation incoming data err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
+	// Removed account can not rotate certificate
+	account, err := am.Get(identity.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:certificate:renew:> get account err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if account == nil || account.Spec.Kind != identity.Spec.Kind {
+		log.V(logLevel).Warnf("%s:certificate:renew:> account `%s` not found", logPrefix, identity.Meta.Name)
+		errors.HTTP.Forbidden(w)
+		return
+	}
+
+	issueCertificate(w, account, opts.CSR)
+}
+
+func issueCertificate(w http.ResponseWriter, account *types.Account, csr string) {
+
+	ca := envs.Get().GetCA()
+	if ca == nil {
+		log.V(logLevel).Errorf("%s:certificate:> certificate authority is not initialized", logPrefix)
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	cert, expires, err := ca.Sign([]byte(csr), account.Spec.Kind, account.Meta.Name, viper.GetDuration("api.ca.ttl"))
+	if err != nil {
+		log.V(logLevel).Errorf("%s:certificate:> sign certificate err: %s", logPrefix, err.Error())
+		errors.New("certificate").BadParameter("csr").Http(w)
+		return
+	}
+
+	response, err := v1.View().Certificate().New(cert, ca.Certificate(), expires).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:certificate:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:certificate:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
@@ -24,14 +24,19 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/lastbackend/lastbackend/pkg/api/envs"
 	"github.com/lastbackend/lastbackend/pkg/api/http/cluster"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
 	"github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/lastbackend/lastbackend/pkg/util/pki"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -138,6 +143,268 @@ func TestClusterInfo(t *testing.T) {
 	}
 }
 
+// Testing ClusterIPAMH handler
+func TestClusterIPAM(t *testing.T) {
+
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+
+	state := types.NewIPAM()
+	state.Pools[types.IPAMPoolDefault] = &types.IPAMPool{
+		Name:   types.IPAMPoolDefault,
+		CIDR:   "10.0.0.0/30",
+		Family: types.IPAMFamilyIPv4,
+		Status: types.IPAMPoolStatus{Total: 2, Available: 1, Reserved: 1},
+	}
+	state.Leases["10.0.0.1"] = &types.IPAMLease{IP: "10.0.0.1", Pool: types.IPAMPoolDefault, Owner: "ns:svc"}
+
+	tests := []struct {
+		name         string
+		state        *types.IPAM
+		pools        int
+		leases       int
+		expectedCode int
+	}{
+		{
+			name:         "checking get ipam without state",
+			state:        nil,
+			pools:        0,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "checking success get ipam",
+			state:        state,
+			pools:        1,
+			leases:       1,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().System(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range tests {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			if tc.state != nil {
+				err := distribution.NewIPAMModel(context.Background(), stg).Set(tc.state)
+				assert.NoError(t, err)
+			}
+
+			req, err := http.NewRequest("GET", "/cluster/ipam", nil)
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/cluster/ipam", cluster.ClusterIPAMH)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			if !assert.Equal(t, tc.expectedCode, res.Code, "status code not equal") {
+				return
+			}
+
+			body, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+
+			i := new(views.IPAM)
+			err = json.Unmarshal(body, &i)
+			assert.NoError(t, err)
+
+			if !assert.Len(t, i.Pools, tc.pools, "pools count not equal") || tc.pools == 0 {
+				return
+			}
+
+			assert.Equal(t, uint64(1), i.Pools[0].Status.Available, "available not equal")
+			assert.Len(t, i.Pools[0].Leases, tc.leases, "leases count not equal")
+		})
+	}
+}
+
+func TestClusterCertificateJoin(t *testing.T) {
+
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+
+	ca, err := pki.NewCA("test")
+	assert.NoError(t, err)
+	envs.Get().SetCA(ca)
+
+	cm := distribution.NewCertificateModel(context.Background(), stg)
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().System(), types.EmptyString)
+		assert.NoError(t, err)
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Account(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	clear()
+	defer clear()
+
+	token := func(kind, name string, expired bool) string {
+		j, value, err := types.NewJoinToken(kind, name, time.Hour)
+		assert.NoError(t, err)
+		if expired {
+			j.Expires = time.Now().Add(-time.Minute)
+		}
+		assert.NoError(t, cm.CreateJoinToken(j))
+		return value
+	}
+
+	user := new(types.Account)
+	user.Meta.Name = "user"
+	user.Spec.Kind = types.AccountKindUser
+	_, err = distribution.NewAccountModel(context.Background(), stg).Create(user)
+	assert.NoError(t, err)
+
+	key, err := pki.GenerateKey()
+	assert.NoError(t, err)
+	csr, err := pki.NewCSR(key, types.AccountKindNode, "node1")
+	assert.NoError(t, err)
+
+	valid := token(types.AccountKindNode, "node1", false)
+	expired := token(types.AccountKindNode, "node1", true)
+	bound := token(types.AccountKindNode, "node3", false)
+
+	tests := []struct {
+		name         string
+		token        string
+		kind         string
+		account      string
+		csr          string
+		expectedCode int
+	}{
+		{
+			name:         "checking certificate issued by join token",
+			token:        valid,
+			kind:         types.AccountKindNode,
+			account:      "node1",
+			csr:          string(csr),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "checking join token can not be reused",
+			token:        valid,
+			kind:         types.AccountKindNode,
+			account:      "node1",
+			csr:          string(csr),
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "checking expired join token",
+			token:        expired,
+			kind:         types.AccountKindNode,
+			account:      "node1",
+			csr:          string(csr),
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "checking unknown join token",
+			token:        "unknown:token",
+			kind:         types.AccountKindNode,
+			account:      "node1",
+			csr:          string(csr),
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "checking join token issued for other agent name",
+			token:        bound,
+			kind:         types.AccountKindNode,
+			account:      "node4",
+			csr:          string(csr),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "checking join token issued for other agent kind",
+			token:        bound,
+			kind:         types.AccountKindIngress,
+			account:      "node3",
+			csr:          string(csr),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "checking join token is not consumed by other agent",
+			token:        bound,
+			kind:         types.AccountKindNode,
+			account:      "node3",
+			csr:          string(csr),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "checking account kind mismatch",
+			token:        token(types.AccountKindNode, user.Meta.Name, false),
+			kind:         types.AccountKindNode,
+			account:      user.Meta.Name,
+			csr:          string(csr),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "checking invalid certificate request",
+			token:        token(types.AccountKindNode, "node2", false),
+			kind:         types.AccountKindNode,
+			account:      "node2",
+			csr:          "csr",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			opts := new(request.CertificateJoinOptions)
+			opts.Token = tc.token
+			opts.Kind = tc.kind
+			opts.Name = tc.account
+			opts.CSR = tc.csr
+
+			buf, err := opts.ToJson()
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("POST", "/cluster/certificate", strings.NewReader(string(buf)))
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/cluster/certificate", cluster.ClusterCertificateJoinH)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			if !assert.Equal(t, tc.expectedCode, res.Code, "status code not equal") || tc.expectedCode != http.StatusOK {
+				return
+			}
+
+			body, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+
+			c := new(views.Certificate)
+			err = json.Unmarshal(body, &c)
+			assert.NoError(t, err)
+
+			cert, err := pki.ParseCertificate([]byte(c.Certificate))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			kind, name := pki.Identity(cert)
+			assert.Equal(t, types.AccountKindNode, kind, "kind not equal")
+			assert.Equal(t, tc.account, name, "name not equal")
+			assert.Equal(t, string(ca.Certificate()), c.CA, "ca not equal")
+
+			account, err := distribution.NewAccountModel(context.Background(), stg).Get(tc.account)
+			assert.NoError(t, err)
+			assert.NotNil(t, account, "agent account not created")
+		})
+	}
+}
+
 func getClusterAsset(memory int64) *types.Cluster {
 	var c = types.Cluster{}
 	c.Status.Capacity.Memory = memory
@@ -19,11 +19,18 @@
 package cluster
 
 import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/http"
 	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
 )
 
 var Routes = []http.Route{
 	// Cluster handlers
-	{Path: "/cluster", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: ClusterInfoH},
+	{Path: "/cluster", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: ClusterInfoH},
+	{Path: "/cluster/ipam", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: ClusterIPAMH},
+
+	// Agents certificates handlers
+	{Path: "/cluster/join", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin)}, Handler: ClusterJoinTokenCreateH},
+	{Path: "/cluster/certificate", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Context}, Handler: ClusterCertificateJoinH},
+	{Path: "/cluster/certificate", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.EmptyString, types.AccountKindNode, types.AccountKindIngress, types.AccountKindDiscovery)}, Handler: ClusterCertificateRenewH},
 }
@@ -19,15 +19,16 @@
 package config
 
 import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/http"
 	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
 )
 
 var Routes = []http.Route{
 	// Route handlers
-	{Path: "/namespace/{namespace}/config", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authenticate}, Handler: ConfigCreateH},
-	{Path: "/namespace/{namespace}/config", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: ConfigListH},
-	{Path: "/namespace/{namespace}/config/{config}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: ConfigGetH},
-	{Path: "/namespace/{namespace}/config/{config}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: ConfigUpdateH},
-	{Path: "/namespace/{namespace}/config/{config}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authenticate}, Handler: ConfigRemoveH},
+	{Path: "/namespace/{namespace}/config", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: ConfigCreateH},
+	{Path: "/namespace/{namespace}/config", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: ConfigListH},
+	{Path: "/namespace/{namespace}/config/{config}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: ConfigGetH},
+	{Path: "/namespace/{namespace}/config/{config}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: ConfigUpdateH},
+	{Path: "/namespace/{namespace}/config/{config}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: ConfigRemoveH},
 }
@@ -0,0 +1,464 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package cronjob
+
+import (
+	"net/http"
+
+	"github.com/lastbackend/lastbackend/pkg/api/envs"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/http/utils"
+)
+
+const (
+	logLevel  = 2
+	logPrefix = "api:handler:cronjob"
+)
+
+func CronJobListH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /namespace/{namespace}/cronjob cronjob cronjobList
+	//
+	// Shows a list of cron jobs in namespace
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Cron job list response
+	//     schema:
+	//       "$ref": "#/definitions/views_cronjob_list"
+	//   '404':
+	//     description: Namespace not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["namespace"]
+
+	log.V(logLevel).Debugf("%s:list:> get cron jobs list in namespace `%s`", logPrefix, nid)
+
+	var (
+		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		cm  = distribution.NewCronJobModel(r.Context(), envs.Get().GetStorage())
+		jm  = distribution.NewJobModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, err := nsm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:list:> namespace %s not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	cl, err := cm.ListByNamespace(ns.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> get cron jobs list err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	jl, err := jm.ListByNamespace(ns.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> get jobs list err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().CronJob().NewList(cl, jl).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:list:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func CronJobInfoH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /namespace/{namespace}/cronjob/{cronjob} cronjob cronjobInfo
+	//
+	// Shows cron job schedule status with running and finished jobs
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: cronjob
+	//     in: path
+	//     description: name of the cron job
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Cron job response
+	//     schema:
+	//       "$ref": "#/definitions/views_cronjob"
+	//   '404':
+	//     description: Namespace not found / Cron job not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["namespace"]
+	cid := utils.Vars(r)["cronjob"]
+
+	log.V(logLevel).Debugf("%s:info:> get cron job `%s/%s`", logPrefix, nid, cid)
+
+	var (
+		jm = distribution.NewJobModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, cj, e := fetchCronJob(r, nid, cid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	jl, err := jm.ListByCronJob(ns.Meta.Name, cj.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:info:> get cron job jobs list err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().CronJob().New(cj, jl).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:info:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:info:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func CronJobCreateH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation POST /namespace/{namespace}/cronjob cronjob cronjobCreate
+	//
+	// Create new cron job
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: body
+	//     in: body
+	//     required: true
+	//     schema:
+	//       "$ref": "#/definitions/request_cronjob"
+	// responses:
+	//   '200':
+	//     description: Cron job was successfully created
+	//     schema:
+	//       "$ref": "#/definitions/views_cronjob"
+	//   '400':
+	//     description: Bad request / Name is already in use
+	//   '404':
+	//     description: Namespace not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["namespace"]
+
+	log.V(logLevel).Debugf("%s:create:> create cron job in namespace `%s`", logPrefix, nid)
+
+	var (
+		nm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		cm = distribution.NewCronJobModel(r.Context(), envs.Get().GetStorage())
+
+		opts = v1.Request().CronJob().Manifest()
+	)
+
+	// request body struct
+	if err := opts.DecodeAndValidate(r.Body); err != nil {
+		log.V(logLevel).Errorf("%s:create:> validation incoming data err: %s", logPrefix, err.Err())
+		err.Http(w)
+		return
+	}
+
+	if opts.Meta.Name == nil {
+		errors.New("cronjob").BadParameter("name").Http(w)
+		return
+	}
+
+	if opts.Spec.Schedule == nil {
+		errors.New("cronjob").BadParameter("schedule").Http(w)
+		return
+	}
+
+	if opts.Spec.Job == nil || opts.Spec.Job.Template == nil || len(opts.Spec.Job.Template.Containers) == 0 {
+		errors.New("cronjob").BadParameter("spec").Http(w)
+		return
+	}
+
+	ns, err := nm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:create:> namespace %s not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	item, err := cm.Get(ns.Meta.Name, *opts.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> get cron job `%s` in namespace `%s` err: %s", logPrefix, *opts.Meta.Name, ns.Meta.Name, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if item != nil {
+		log.V(logLevel).Warnf("%s:create:> cron job name `%s` in namespace `%s` not unique", logPrefix, *opts.Meta.Name, ns.Meta.Name)
+		errors.New("cronjob").NotUnique("name").Http(w)
+		return
+	}
+
+	cj := new(types.CronJob)
+	cj.Spec.SetDefault()
+	opts.SetCronJobMeta(cj)
+	opts.SetCronJobSpec(cj)
+
+	cj, err = cm.Create(ns, cj)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> create cron job err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().CronJob().New(cj, nil).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:create:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func CronJobUpdateH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation PUT /namespace/{namespace}/cronjob/{cronjob} cronjob cronjobUpdate
+	//
+	// Update cron job schedule and policies, job template changes are applied to next scheduled jobs
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: cronjob
+	//     in: path
+	//     description: name of the cron job
+	//     required: true
+	//     type: string
+	//   - name: body
+	//     in: body
+	//     required: true
+	//     schema:
+	//       "$ref": "#/definitions/request_cronjob"
+	// responses:
+	//   '200':
+	//     description: Cron job was successfully updated
+	//     schema:
+	//       "$ref": "#/definitions/views_cronjob"
+	//   '400':
+	//     description: Bad request
+	//   '404':
+	//     description: Namespace not found / Cron job not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["namespace"]
+	cid := utils.Vars(r)["cronjob"]
+
+	log.V(logLevel).Debugf("%s:update:> update cron job `%s/%s`", logPrefix, nid, cid)
+
+	var (
+		cm = distribution.NewCronJobModel(r.Context(), envs.Get().GetStorage())
+
+		opts = v1.Request().CronJob().Manifest()
+	)
+
+	// request body struct
+	if err := opts.DecodeAndValidate(r.Body); err != nil {
+		log.V(logLevel).Errorf("%s:update:> validation incoming data err: %s", logPrefix, err.Err())
+		err.Http(w)
+		return
+	}
+
+	_, cj, e := fetchCronJob(r, nid, cid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	opts.SetCronJobMeta(cj)
+	opts.SetCronJobSpec(cj)
+
+	cj, err := cm.Update(cj)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:update:> update cron job err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().CronJob().New(cj, nil).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:update:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:update:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func CronJobRemoveH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation DELETE /namespace/{namespace}/cronjob/{cronjob} cronjob cronjobRemove
+	//
+	// Remove cron job with all created jobs
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: cronjob
+	//     in: path
+	//     description: name of the cron job
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Cron job was successfully removed
+	//   '404':
+	//     description: Namespace not found / Cron job not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["namespace"]
+	cid := utils.Vars(r)["cronjob"]
+
+	log.V(logLevel).Debugf("%s:remove:> remove cron job `%s/%s`", logPrefix, nid, cid)
+
+	var (
+		cm = distribution.NewCronJobModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	_, cj, e := fetchCronJob(r, nid, cid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	if _, err := cm.Destroy(cj); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> destroy cron job err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte{}); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+// fetchCronJob returns namespace and cron job, http error is returned if any of them is not found
+func fetchCronJob(r *http.Request, nid, cid string) (*types.Namespace, *types.CronJob, *errors.Err) {
+
+	var (
+		nm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		cm = distribution.NewCronJobModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, err := nm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:fetch:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		return nil, nil, errors.New("namespace").Unknown(err)
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:fetch:> namespace %s not found", logPrefix, nid)
+		return nil, nil, errors.New("namespace").NotFound()
+	}
+
+	cj, err := cm.Get(ns.Meta.Name, cid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:fetch:> get cron job %s:%s err: %s", logPrefix, nid, cid, err.Error())
+		return nil, nil, errors.New("cronjob").Unknown(err)
+	}
+	if cj == nil {
+		log.V(logLevel).Warnf("%s:fetch:> cron job %s:%s not found", logPrefix, nid, cid)
+		return nil, nil, errors.New("cronjob").NotFound()
+	}
+
+	return ns, cj, nil
+}
@@ -0,0 +1,317 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package cronjob_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/lastbackend/lastbackend/pkg/api/envs"
+	"github.com/lastbackend/lastbackend/pkg/api/http/cronjob"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// Testing CronJobCreateH handler
+func TestCronJobCreate(t *testing.T) {
+
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+
+	ns1 := getNamespaceAsset("demo")
+	c1 := getCronJobAsset(ns1.Meta.Name, "demo")
+
+	manifest := func(name, schedule string) *request.CronJobManifest {
+		mf := new(request.CronJobManifest)
+		mf.Meta.Name = &name
+		mf.Spec.Schedule = &schedule
+		mf.Spec.Job = new(request.JobManifestSpec)
+		mf.Spec.Job.Template = new(request.ManifestSpecTemplate)
+		mf.Spec.Job.Template.Containers = append(mf.Spec.Job.Template.Containers, request.ManifestSpecTemplateContainer{
+			Name:  "redis",
+			Image: request.ManifestSpecTemplateContainerImage{Name: "redis"},
+		})
+		return mf
+	}
+
+	m1 := manifest("fresh", "*/5 * * * *")
+	tz, concurrency := "Europe/Amsterdam", types.CronJobConcurrencyForbid
+	m1.Spec.TimeZone = &tz
+	m1.Spec.Concurrency = &concurrency
+
+	m2 := manifest("fresh", "61 * * * *")
+
+	m3 := manifest("fresh", "@daily")
+	unknown := "Mars/Olympus"
+	m3.Spec.TimeZone = &unknown
+
+	m4 := manifest("fresh", "@daily")
+	policy := "queue"
+	m4.Spec.Concurrency = &policy
+
+	m5 := manifest("fresh", "@daily")
+	m5.Spec.Job = nil
+
+	m6 := manifest(strings.Repeat("a", types.CronJobNameMaxLength+1), "@daily")
+
+	tests := []struct {
+		name         string
+		data         *request.CronJobManifest
+		err          string
+		wantErr      bool
+		expectedCode int
+	}{
+		{
+			name:         "checking create cron job with invalid schedule",
+			data:         m2,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad schedule parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking create cron job with unknown time zone",
+			data:         m3,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad timezone parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking create cron job with unknown concurrency policy",
+			data:         m4,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad concurrency parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking create cron job without job template",
+			data:         m5,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad spec parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking create cron job with too long name",
+			data:         m6,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad name parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking create cron job if name already exists",
+			data:         manifest(c1.Meta.Name, "@daily"),
+			err:          "{\"code\":400,\"status\":\"Not Unique\",\"message\":\"Name is already in use\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking create cron job successfully",
+			data:         m1,
+			wantErr:      false,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().CronJob(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range tests {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			err := stg.Put(context.Background(), stg.Collection().Namespace(), stg.Key().Namespace(ns1.Meta.Name), ns1, nil)
+			assert.NoError(t, err)
+
+			err = stg.Put(context.Background(), stg.Collection().CronJob(), stg.Key().CronJob(c1.Meta.Namespace, c1.Meta.Name), c1, nil)
+			assert.NoError(t, err)
+
+			buf, err := tc.data.ToJson()
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("POST", fmt.Sprintf("/namespace/%s/cronjob", ns1.Meta.Name), strings.NewReader(string(buf)))
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/namespace/{namespace}/cronjob", cronjob.CronJobCreateH)
+
+			setRequestVars(r, req)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			if !assert.Equal(t, tc.expectedCode, res.Code, "status code not equal") {
+				return
+			}
+
+			body, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+
+			if tc.wantErr {
+				assert.Equal(t, tc.err, string(body), "incorrect status code")
+				return
+			}
+
+			v := new(views.CronJob)
+			err = json.Unmarshal(body, v)
+			assert.NoError(t, err)
+
+			c := new(types.CronJob)
+			err = stg.Get(context.Background(), stg.Collection().CronJob(), stg.Key().CronJob(ns1.Meta.Name, *tc.data.Meta.Name), c, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, *tc.data.Meta.Name, v.Meta.Name, "name mismatch")
+			assert.Equal(t, *tc.data.Spec.Schedule, c.Spec.Schedule, "schedule mismatch")
+			assert.Equal(t, tz, c.Spec.TimeZone, "time zone mismatch")
+			assert.Equal(t, concurrency, c.Spec.Concurrency, "concurrency mismatch")
+			assert.Equal(t, types.CronJobDefaultSucceededHistory, c.Spec.History.Succeeded, "succeeded history mismatch")
+			assert.Equal(t, types.CronJobDefaultFailedHistory, c.Spec.History.Failed, "failed history mismatch")
+			assert.Equal(t, 1, c.Spec.Job.Completions, "job completions mismatch")
+			assert.Len(t, c.Spec.Job.Template.Containers, 1, "containers count mismatch")
+		})
+	}
+}
+
+// Testing CronJobRemoveH handler
+func TestCronJobRemove(t *testing.T) {
+
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+
+	ns1 := getNamespaceAsset("demo")
+	c1 := getCronJobAsset(ns1.Meta.Name, "demo")
+	c2 := getCronJobAsset(ns1.Meta.Name, "test")
+
+	tests := []struct {
+		name         string
+		cronjob      *types.CronJob
+		err          string
+		wantErr      bool
+		expectedCode int
+	}{
+		{
+			name:         "checking remove cron job if not exists",
+			cronjob:      c2,
+			err:          "{\"code\":404,\"status\":\"Not Found\",\"message\":\"Cronjob not found\"}",
+			wantErr:      true,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "checking remove cron job successfully",
+			cronjob:      c1,
+			wantErr:      false,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().CronJob(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range tests {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			err := stg.Put(context.Background(), stg.Collection().Namespace(), stg.Key().Namespace(ns1.Meta.Name), ns1, nil)
+			assert.NoError(t, err)
+
+			err = stg.Put(context.Background(), stg.Collection().CronJob(), stg.Key().CronJob(c1.Meta.Namespace, c1.Meta.Name), c1, nil)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("DELETE", fmt.Sprintf("/namespace/%s/cronjob/%s", ns1.Meta.Name, tc.cronjob.Meta.Name), nil)
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/namespace/{namespace}/cronjob/{cronjob}", cronjob.CronJobRemoveH)
+
+			setRequestVars(r, req)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			if !assert.Equal(t, tc.expectedCode, res.Code, "status code not equal") {
+				return
+			}
+
+			body, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+
+			if tc.wantErr {
+				assert.Equal(t, tc.err, string(body), "incorrect status code")
+				return
+			}
+
+			c := new(types.CronJob)
+			err = stg.Get(context.Background(), stg.Collection().CronJob(), stg.Key().CronJob(ns1.Meta.Name, tc.cronjob.Meta.Name), c, nil)
+			assert.NoError(t, err)
+			assert.True(t, c.Spec.State.Destroy, "cron job should be marked for destroy")
+		})
+	}
+}
+
+func getNamespaceAsset(name string) *types.Namespace {
+	var n = types.Namespace{}
+	n.Meta.SetDefault()
+	n.Meta.Name = name
+	return &n
+}
+
+func getCronJobAsset(namespace, name string) *types.CronJob {
+	var c = types.CronJob{}
+	c.Meta.SetDefault()
+	c.Meta.Namespace = namespace
+	c.Meta.Name = name
+	c.Spec.SetDefault()
+	c.Spec.Schedule = "@daily"
+	c.Status.State = types.StateReady
+	return &c
+}
+
+func setRequestVars(r *mux.Router, req *http.Request) {
+	var match mux.RouteMatch
+	// Take the request and match it
+	r.Match(req, &match)
+	// Push the variable onto the context
+	req = mux.SetURLVars(req, match.Vars)
+}
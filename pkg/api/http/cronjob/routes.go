@@ -0,0 +1,33 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package cronjob
+
+import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/util/http"
+	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
+)
+
+var Routes = []http.Route{
+	{Path: "/namespace/{namespace}/cronjob", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: CronJobCreateH},
+	{Path: "/namespace/{namespace}/cronjob", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: CronJobListH},
+	{Path: "/namespace/{namespace}/cronjob/{cronjob}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: CronJobInfoH},
+	{Path: "/namespace/{namespace}/cronjob/{cronjob}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: CronJobUpdateH},
+	{Path: "/namespace/{namespace}/cronjob/{cronjob}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: CronJobRemoveH},
+}
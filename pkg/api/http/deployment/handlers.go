@@ -20,6 +20,7 @@ package deployment
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/lastbackend/lastbackend/pkg/api/envs"
 	"github.com/lastbackend/lastbackend/pkg/api/types/v1"
@@ -347,3 +348,710 @@ func DeploymentUpdateH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func DeploymentHistoryH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /namespace/{namespace}/service/{service}/history deployment deploymentHistory
+	//
+	// Shows a list of service deployment revisions with spec changes
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: service
+	//     in: path
+	//     description: name of the service
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Deployment revision list response
+	//     schema:
+	//       "$ref": "#/definitions/views_deployment_revision_list"
+	//   '404':
+	//     description: Namespace not found / Service not found
+	//   '500':
+	//     description: Internal server error
+
+	sid := utils.Vars(r)["service"]
+	nid := utils.Vars(r)["namespace"]
+
+	log.V(logLevel).Debugf("%s:history:> get deployments history for `%s/%s`", logPrefix, nid, sid)
+
+	var (
+		sm  = distribution.NewServiceModel(r.Context(), envs.Get().GetStorage())
+		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		dm  = distribution.NewDeploymentModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, err := nsm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:history:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:history:> namespace %s not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	srv, err := sm.Get(ns.Meta.Name, sid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:history:> get service `%s` err: %s", logPrefix, sid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if srv == nil {
+		log.V(logLevel).Warnf("%s:history:> service `%s` not found", logPrefix, sid)
+		errors.New("service").NotFound().Http(w)
+		return
+	}
+
+	dl, err := dm.ListByService(srv.Meta.Namespace, srv.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:history:> get deployment list by service `%s` err: %s", logPrefix, srv.Meta.Name, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Deployment().NewRevisionList(srv, dl).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:history:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:history:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func DeploymentRollbackH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation POST /namespace/{namespace}/service/{service}/deployment/{deployment}/rollback deployment deploymentRollback
+	//
+	// Rolls service back to deployment spec, new deployment is created with deployment spec
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: service
+	//     in: path
+	//     description: name of the service
+	//     required: true
+	//     type: string
+	//   - name: deployment
+	//     in: path
+	//     description: name of the deployment
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Service rollback was successfully started
+	//     schema:
+	//       "$ref": "#/definitions/views_service"
+	//   '400':
+	//     description: Deployment is current service revision / Namespace quota exceeded
+	//   '404':
+	//     description: Namespace not found / Service not found / Deployment not found
+	//   '500':
+	//     description: Internal server error
+
+	sid := utils.Vars(r)["service"]
+	nid := utils.Vars(r)["namespace"]
+	did := utils.Vars(r)["deployment"]
+
+	log.V(logLevel).Debugf("%s:rollback:> rollback deployment `%s` in service `%s/%s`", logPrefix, did, nid, sid)
+
+	var (
+		sm  = distribution.NewServiceModel(r.Context(), envs.Get().GetStorage())
+		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		dm  = distribution.NewDeploymentModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, err := nsm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:rollback:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:rollback:> namespace %s not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	srv, err := sm.Get(ns.Meta.Name, sid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:rollback:> get service `%s` err: %s", logPrefix, sid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if srv == nil {
+		log.V(logLevel).Warnf("%s:rollback:> service `%s` not found", logPrefix, sid)
+		errors.New("service").NotFound().Http(w)
+		return
+	}
+
+	d, err := dm.Get(srv.Meta.Namespace, srv.Meta.Name, did)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:rollback:> get deployment by name `%s` err: %s", logPrefix, did, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if d == nil {
+		log.V(logLevel).Warnf("%s:rollback:> deployment `%s` not found", logPrefix, did)
+		errors.New("deployment").NotFound().Http(w)
+		return
+	}
+
+	if d.Spec.Template.Updated.Equal(srv.Spec.Template.Updated) {
+		log.V(logLevel).Warnf("%s:rollback:> deployment `%s` is current service revision", logPrefix, did)
+		errors.New("deployment").BadRequest("Deployment is current service revision").Http(w)
+		return
+	}
+
+	prev := srv.QuotaUsage()
+
+	srv.Spec.Template = d.Spec.Template
+	srv.Spec.Template.Updated = time.Now()
+	srv.Status.State = types.StateProvision
+
+	used, err := nsm.Usage(ns)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:rollback:> get namespace resources usage err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if err := ns.Spec.Quotas.Admit(*used, prev, srv.QuotaUsage()); err != nil {
+		log.V(logLevel).Warnf("%s:rollback:> namespace `%s` quotas check err: %s", logPrefix, ns.Meta.Name, err.Error())
+		errors.New("namespace").BadRequest(err.Error()).Http(w)
+		return
+	}
+
+	srv, err = sm.Update(srv)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:rollback:> update service err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if err := nsm.UpdateResources(ns); err != nil {
+		log.V(logLevel).Errorf("%s:rollback:> update namespace resources err: %s", logPrefix, err.Error())
+	}
+
+	response, err := v1.View().Service().NewWithDeployment(srv, nil, nil).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:rollback:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:rollback:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func DeploymentPauseH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation POST /namespace/{namespace}/service/{service}/deployment/{deployment}/pause deployment deploymentPause
+	//
+	// Pauses deployment rollout
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: service
+	//     in: path
+	//     description: name of the service
+	//     required: true
+	//     type: string
+	//   - name: deployment
+	//     in: path
+	//     description: name of the deployment
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Deployment rollout was successfully paused
+	//     schema:
+	//       "$ref": "#/definitions/views_deployment"
+	//   '400':
+	//     description: Deployment is destroyed
+	//   '404':
+	//     description: Namespace not found / Service not found / Deployment not found
+	//   '500':
+	//     description: Internal server error
+
+	sid := utils.Vars(r)["service"]
+	nid := utils.Vars(r)["namespace"]
+	did := utils.Vars(r)["deployment"]
+
+	log.V(logLevel).Debugf("%s:pause:> pause deployment `%s` in service `%s/%s`", logPrefix, did, nid, sid)
+
+	var (
+		sm  = distribution.NewServiceModel(r.Context(), envs.Get().GetStorage())
+		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		dm  = distribution.NewDeploymentModel(r.Context(), envs.Get().GetStorage())
+		pdm = distribution.NewPodModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, err := nsm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:pause:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:pause:> namespace %s not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	srv, err := sm.Get(ns.Meta.Name, sid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:pause:> get service `%s` err: %s", logPrefix, sid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if srv == nil {
+		log.V(logLevel).Warnf("%s:pause:> service `%s` not found", logPrefix, sid)
+		errors.New("service").NotFound().Http(w)
+		return
+	}
+
+	d, err := dm.Get(srv.Meta.Namespace, srv.Meta.Name, did)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:pause:> get deployment by name `%s` err: %s", logPrefix, did, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if d == nil {
+		log.V(logLevel).Warnf("%s:pause:> deployment `%s` not found", logPrefix, did)
+		errors.New("deployment").NotFound().Http(w)
+		return
+	}
+
+	switch d.Status.State {
+	case types.StateDestroy, types.StateDestroyed:
+		log.V(logLevel).Warnf("%s:pause:> deployment `%s` is destroyed", logPrefix, did)
+		errors.New("deployment").BadRequest("Deployment is destroyed").Http(w)
+		return
+	}
+
+	if err := dm.Pause(d); err != nil {
+		log.V(logLevel).Errorf("%s:pause:> pause deployment err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	pl, err := pdm.ListByDeployment(srv.Meta.Namespace, srv.Meta.Name, d.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:pause:> get pod list by deployment `%s` err: %s", logPrefix, d.Meta.Name, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Deployment().New(d, pl).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:pause:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:pause:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func DeploymentResumeH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation POST /namespace/{namespace}/service/{service}/deployment/{deployment}/resume deployment deploymentResume
+	//
+	// Resumes paused deployment rollout
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: service
+	//     in: path
+	//     description: name of the service
+	//     required: true
+	//     type: string
+	//   - name: deployment
+	//     in: path
+	//     description: name of the deployment
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Deployment rollout was successfully resumed
+	//     schema:
+	//       "$ref": "#/definitions/views_deployment"
+	//   '400':
+	//     description: Deployment is destroyed
+	//   '404':
+	//     description: Namespace not found / Service not found / Deployment not found
+	//   '500':
+	//     description: Internal server error
+
+	sid := utils.Vars(r)["service"]
+	nid := utils.Vars(r)["namespace"]
+	did := utils.Vars(r)["deployment"]
+
+	log.V(logLevel).Debugf("%s:resume:> resume deployment `%s` in service `%s/%s`", logPrefix, did, nid, sid)
+
+	var (
+		sm  = distribution.NewServiceModel(r.Context(), envs.Get().GetStorage())
+		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		dm  = distribution.NewDeploymentModel(r.Context(), envs.Get().GetStorage())
+		pdm = distribution.NewPodModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, err := nsm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:resume:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:resume:> namespace %s not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	srv, err := sm.Get(ns.Meta.Name, sid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:resume:> get service `%s` err: %s", logPrefix, sid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if srv == nil {
+		log.V(logLevel).Warnf("%s:resume:> service `%s` not found", logPrefix, sid)
+		errors.New("service").NotFound().Http(w)
+		return
+	}
+
+	d, err := dm.Get(srv.Meta.Namespace, srv.Meta.Name, did)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:resume:> get deployment by name `%s` err: %s", logPrefix, did, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if d == nil {
+		log.V(logLevel).Warnf("%s:resume:> deployment `%s` not found", logPrefix, did)
+		errors.New("deployment").NotFound().Http(w)
+		return
+	}
+
+	switch d.Status.State {
+	case types.StateDestroy, types.StateDestroyed:
+		log.V(logLevel).Warnf("%s:resume:> deployment `%s` is destroyed", logPrefix, did)
+		errors.New("deployment").BadRequest("Deployment is destroyed").Http(w)
+		return
+	}
+
+	if err := dm.Resume(d); err != nil {
+		log.V(logLevel).Errorf("%s:resume:> resume deployment err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	pl, err := pdm.ListByDeployment(srv.Meta.Namespace, srv.Meta.Name, d.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:resume:> get pod list by deployment `%s` err: %s", logPrefix, d.Meta.Name, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Deployment().New(d, pl).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:resume:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:resume:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func DeploymentPromoteH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation POST /namespace/{namespace}/service/{service}/deployment/{deployment}/promote deployment deploymentPromote
+	//
+	// Promotes canary or blue/green deployment to replace active deployment
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: service
+	//     in: path
+	//     description: name of the service
+	//     required: true
+	//     type: string
+	//   - name: deployment
+	//     in: path
+	//     description: name of the deployment
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Deployment was successfully promoted
+	//     schema:
+	//       "$ref": "#/definitions/views_deployment"
+	//   '400':
+	//     description: Deployment is destroyed / Deployment is aborted
+	//   '404':
+	//     description: Namespace not found / Service not found / Deployment not found
+	//   '500':
+	//     description: Internal server error
+
+	sid := utils.Vars(r)["service"]
+	nid := utils.Vars(r)["namespace"]
+	did := utils.Vars(r)["deployment"]
+
+	log.V(logLevel).Debugf("%s:promote:> promote deployment `%s` in service `%s/%s`", logPrefix, did, nid, sid)
+
+	var (
+		sm  = distribution.NewServiceModel(r.Context(), envs.Get().GetStorage())
+		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		dm  = distribution.NewDeploymentModel(r.Context(), envs.Get().GetStorage())
+		pdm = distribution.NewPodModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, err := nsm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:promote:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:promote:> namespace %s not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	srv, err := sm.Get(ns.Meta.Name, sid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:promote:> get service `%s` err: %s", logPrefix, sid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if srv == nil {
+		log.V(logLevel).Warnf("%s:promote:> service `%s` not found", logPrefix, sid)
+		errors.New("service").NotFound().Http(w)
+		return
+	}
+
+	d, err := dm.Get(srv.Meta.Namespace, srv.Meta.Name, did)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:promote:> get deployment by name `%s` err: %s", logPrefix, did, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if d == nil {
+		log.V(logLevel).Warnf("%s:promote:> deployment `%s` not found", logPrefix, did)
+		errors.New("deployment").NotFound().Http(w)
+		return
+	}
+
+	switch d.Status.State {
+	case types.StateDestroy, types.StateDestroyed:
+		log.V(logLevel).Warnf("%s:promote:> deployment `%s` is destroyed", logPrefix, did)
+		errors.New("deployment").BadRequest("Deployment is destroyed").Http(w)
+		return
+	}
+
+	if d.Spec.Aborted {
+		log.V(logLevel).Warnf("%s:promote:> deployment `%s` is aborted", logPrefix, did)
+		errors.New("deployment").BadRequest("Deployment is aborted").Http(w)
+		return
+	}
+
+	if err := dm.Promote(d); err != nil {
+		log.V(logLevel).Errorf("%s:promote:> promote deployment err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	pl, err := pdm.ListByDeployment(srv.Meta.Namespace, srv.Meta.Name, d.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:promote:> get pod list by deployment `%s` err: %s", logPrefix, d.Meta.Name, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Deployment().New(d, pl).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:promote:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:promote:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func DeploymentAbortH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation POST /namespace/{namespace}/service/{service}/deployment/{deployment}/abort deployment deploymentAbort
+	//
+	// Aborts deployment release and returns traffic to active deployment
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: service
+	//     in: path
+	//     description: name of the service
+	//     required: true
+	//     type: string
+	//   - name: deployment
+	//     in: path
+	//     description: name of the deployment
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Deployment release was successfully aborted
+	//     schema:
+	//       "$ref": "#/definitions/views_deployment"
+	//   '400':
+	//     description: Deployment is destroyed
+	//   '404':
+	//     description: Namespace not found / Service not found / Deployment not found
+	//   '500':
+	//     description: Internal server error
+
+	sid := utils.Vars(r)["service"]
+	nid := utils.Vars(r)["namespace"]
+	did := utils.Vars(r)["deployment"]
+
+	log.V(logLevel).Debugf("%s:abort:> abort deployment `%s` in service `%s/%s`", logPrefix, did, nid, sid)
+
+	var (
+		sm  = distribution.NewServiceModel(r.Context(), envs.Get().GetStorage())
+		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		dm  = distribution.NewDeploymentModel(r.Context(), envs.Get().GetStorage())
+		pdm = distribution.NewPodModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, err := nsm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:abort:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:abort:> namespace %s not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	srv, err := sm.Get(ns.Meta.Name, sid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:abort:> get service `%s` err: %s", logPrefix, sid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if srv == nil {
+		log.V(logLevel).Warnf("%s:abort:> service `%s` not found", logPrefix, sid)
+		errors.New("service").NotFound().Http(w)
+		return
+	}
+
+	d, err := dm.Get(srv.Meta.Namespace, srv.Meta.Name, did)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:abort:> get deployment by name `%s` err: %s", logPrefix, did, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if d == nil {
+		log.V(logLevel).Warnf("%s:abort:> deployment `%s` not found", logPrefix, did)
+		errors.New("deployment").NotFound().Http(w)
+		return
+	}
+
+	switch d.Status.State {
+	case types.StateDestroy, types.StateDestroyed:
+		log.V(logLevel).Warnf("%s:abort:> deployment `%s` is destroyed", logPrefix, did)
+		errors.New("deployment").BadRequest("Deployment is destroyed").Http(w)
+		return
+	}
+
+	if err := dm.Abort(d); err != nil {
+		log.V(logLevel).Errorf("%s:abort:> abort deployment err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	pl, err := pdm.ListByDeployment(srv.Meta.Namespace, srv.Meta.Name, d.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:abort:> get pod list by deployment `%s` err: %s", logPrefix, d.Meta.Name, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Deployment().New(d, pl).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:abort:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:abort:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/lastbackend/lastbackend/pkg/api/envs"
@@ -348,6 +349,134 @@ func TestDeploymentListHList(t *testing.T) {
 
 }
 
+// Testing DeploymentRollbackH handler
+func TestDeploymentRollback(t *testing.T) {
+
+	var ctx = context.Background()
+
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+
+	ns1 := getNamespaceAsset("demo", "")
+	ns1.Spec.Quotas.RAM = 512
+	s1 := getServiceAsset(ns1.Meta.Name, "demo", "")
+	s1.Spec.Replicas = 1
+	s1.Spec.Template.Updated = time.Now()
+	s1.Spec.Template.Containers = types.SpecTemplateContainers{{Name: "web", Image: types.SpecTemplateContainerImage{Name: "nginx:2"}}}
+
+	d1 := getDeploymentAsset(ns1.Meta.Name, s1.Meta.Name, "demo")
+	d1.Spec.Template = s1.Spec.Template
+
+	d2 := getDeploymentAsset(ns1.Meta.Name, s1.Meta.Name, "test")
+	d2.Spec.Template.Updated = s1.Spec.Template.Updated.Add(-time.Hour)
+	d2.Spec.Template.Containers = types.SpecTemplateContainers{{Name: "web", Image: types.SpecTemplateContainerImage{Name: "nginx:1"}}}
+
+	d3 := getDeploymentAsset(ns1.Meta.Name, s1.Meta.Name, "large")
+	d3.Spec.Template.Updated = s1.Spec.Template.Updated.Add(-2 * time.Hour)
+	d3.Spec.Template.Containers = types.SpecTemplateContainers{{Name: "web", Image: types.SpecTemplateContainerImage{Name: "nginx:0"}}}
+	d3.Spec.Template.Containers[0].Resources.Request.RAM = 1024
+
+	type args struct {
+		ctx        context.Context
+		namespace  *types.Namespace
+		service    *types.Service
+		deployment *types.Deployment
+	}
+
+	tests := []struct {
+		name         string
+		args         args
+		image        string
+		err          string
+		wantErr      bool
+		expectedCode int
+	}{
+		{
+			name:         "checking rollback to current revision",
+			args:         args{ctx, ns1, s1, d1},
+			err:          "{\"code\":400,\"status\":\"Bad Request\",\"message\":\"Deployment is current service revision\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking rollback if namespace quota exceeded",
+			args:         args{ctx, ns1, s1, d3},
+			err:          "{\"code\":400,\"status\":\"Bad Request\",\"message\":\"RAM quota exceeded\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking rollback successfully",
+			args:         args{ctx, ns1, s1, d2},
+			image:        "nginx:1",
+			wantErr:      false,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Service(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Deployment(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range tests {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			err := stg.Put(context.Background(), stg.Collection().Namespace(), stg.Key().Namespace(ns1.Meta.Name), ns1, nil)
+			assert.NoError(t, err)
+
+			err = stg.Put(context.Background(), stg.Collection().Service(), stg.Key().Service(s1.Meta.Namespace, s1.Meta.Name), s1, nil)
+			assert.NoError(t, err)
+
+			for _, d := range []*types.Deployment{d1, d2, d3} {
+				err = stg.Put(context.Background(), stg.Collection().Deployment(), stg.Key().Deployment(d.Meta.Namespace, d.Meta.Service, d.Meta.Name), d, nil)
+				assert.NoError(t, err)
+			}
+
+			req, err := http.NewRequest("POST", fmt.Sprintf("/namespace/%s/service/%s/deployment/%s/rollback", tc.args.namespace.Meta.Name, tc.args.service.Meta.Name, tc.args.deployment.Meta.Name), nil)
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/namespace/{namespace}/service/{service}/deployment/{deployment}/rollback", deployment.DeploymentRollbackH)
+
+			setRequestVars(r, req)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			if !assert.Equal(t, tc.expectedCode, res.Code, "status code not equal") {
+				return
+			}
+
+			body, e := ioutil.ReadAll(res.Body)
+			assert.NoError(t, e)
+
+			if tc.wantErr {
+				assert.Equal(t, tc.err, string(body), "incorrect status code")
+				return
+			}
+
+			svc := new(types.Service)
+			err = stg.Get(context.Background(), stg.Collection().Service(), stg.Key().Service(s1.Meta.Namespace, s1.Meta.Name), svc, nil)
+			assert.NoError(t, err)
+
+			assert.Equal(t, types.StateProvision, svc.Status.State, "service state not equal")
+			assert.Equal(t, tc.image, svc.Spec.Template.Containers[0].Image.Name, "image not equal")
+			assert.True(t, svc.Spec.Template.Updated.After(s1.Spec.Template.Updated), "template updated time")
+		})
+	}
+}
+
 func getNamespaceAsset(name, desc string) *types.Namespace {
 	var n = types.Namespace{}
 	n.Meta.SetDefault()
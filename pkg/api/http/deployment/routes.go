@@ -19,12 +19,19 @@
 package deployment
 
 import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/http"
 	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
 )
 
 var Routes = []http.Route{
-	{Path: "/namespace/{namespace}/service/{service}/deployment", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: DeploymentListH},
-	{Path: "/namespace/{namespace}/service/{service}/deployment/{deployment}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: DeploymentInfoH},
-	{Path: "/namespace/{namespace}/service/{service}/deployment/{deployment}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: DeploymentUpdateH},
+	{Path: "/namespace/{namespace}/service/{service}/history", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: DeploymentHistoryH},
+	{Path: "/namespace/{namespace}/service/{service}/deployment", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: DeploymentListH},
+	{Path: "/namespace/{namespace}/service/{service}/deployment/{deployment}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: DeploymentInfoH},
+	{Path: "/namespace/{namespace}/service/{service}/deployment/{deployment}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: DeploymentUpdateH},
+	{Path: "/namespace/{namespace}/service/{service}/deployment/{deployment}/rollback", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: DeploymentRollbackH},
+	{Path: "/namespace/{namespace}/service/{service}/deployment/{deployment}/pause", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: DeploymentPauseH},
+	{Path: "/namespace/{namespace}/service/{service}/deployment/{deployment}/resume", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: DeploymentResumeH},
+	{Path: "/namespace/{namespace}/service/{service}/deployment/{deployment}/promote", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: DeploymentPromoteH},
+	{Path: "/namespace/{namespace}/service/{service}/deployment/{deployment}/abort", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: DeploymentAbortH},
 }
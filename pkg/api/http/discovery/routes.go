@@ -19,14 +19,15 @@
 package discovery
 
 import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/http"
 	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
 )
 
 var Routes = []http.Route{
-	{Path: "/discovery", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: DiscoveryListH},
-	{Path: "/discovery/{discovery}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: DiscoveryInfoH},
-	{Path: "/discovery/{discovery}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: DiscoveryConnectH},
-	{Path: "/discovery/{discovery}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authenticate}, Handler: DiscoveryRemoveH},
-	{Path: "/discovery/{discovery}/status", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: DiscoverySetStatusH},
+	{Path: "/discovery", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: DiscoveryListH},
+	{Path: "/discovery/{discovery}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead, types.AccountKindDiscovery)}, Handler: DiscoveryInfoH},
+	{Path: "/discovery/{discovery}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin, types.AccountKindDiscovery)}, Handler: DiscoveryConnectH},
+	{Path: "/discovery/{discovery}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin)}, Handler: DiscoveryRemoveH},
+	{Path: "/discovery/{discovery}/status", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin, types.AccountKindDiscovery)}, Handler: DiscoverySetStatusH},
 }
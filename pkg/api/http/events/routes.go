@@ -19,11 +19,12 @@
 package events
 
 import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/http"
 	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
 )
 
 var Routes = []http.Route{
 	// Events handlers
-	{Path: "/events", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Context}, Handler: EventSubscribeH},
+	{Path: "/events", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: EventSubscribeH},
 }
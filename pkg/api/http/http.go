@@ -19,13 +19,20 @@
 package http
 
 import (
+	"crypto/x509"
+
 	"github.com/gorilla/mux"
+	"github.com/lastbackend/lastbackend/pkg/api/http/account"
+	"github.com/lastbackend/lastbackend/pkg/api/http/autoscaler"
 	"github.com/lastbackend/lastbackend/pkg/api/http/cluster"
 	"github.com/lastbackend/lastbackend/pkg/api/http/config"
+	"github.com/lastbackend/lastbackend/pkg/api/http/cronjob"
 	"github.com/lastbackend/lastbackend/pkg/api/http/deployment"
 	"github.com/lastbackend/lastbackend/pkg/api/http/discovery"
 	"github.com/lastbackend/lastbackend/pkg/api/http/events"
 	"github.com/lastbackend/lastbackend/pkg/api/http/ingress"
+	"github.com/lastbackend/lastbackend/pkg/api/http/job"
+	"github.com/lastbackend/lastbackend/pkg/api/http/metrics"
 	"github.com/lastbackend/lastbackend/pkg/api/http/namespace"
 	"github.com/lastbackend/lastbackend/pkg/api/http/node"
 	"github.com/lastbackend/lastbackend/pkg/api/http/route"
@@ -48,6 +55,9 @@ var Routes = make([]http.Route, 0)
 type HttpOpts struct {
 	Insecure bool
 
+	// Authorities used to verify agents client certificates
+	ClientCAs *x509.CertPool
+
 	CertFile string
 	KeyFile  string
 	CaFile   string
@@ -61,6 +71,9 @@ func AddRoutes(r ...[]http.Route) {
 
 func init() {
 
+	// Accounts
+	AddRoutes(account.Routes)
+
 	// Cluster
 	AddRoutes(cluster.Routes)
 	AddRoutes(node.Routes)
@@ -74,12 +87,18 @@ func init() {
 	AddRoutes(route.Routes)
 	AddRoutes(service.Routes)
 	AddRoutes(deployment.Routes)
+	AddRoutes(autoscaler.Routes)
+	AddRoutes(job.Routes)
+	AddRoutes(cronjob.Routes)
 	AddRoutes(volume.Routes)
 	AddRoutes(ingress.Routes)
 	AddRoutes(discovery.Routes)
 
 	// events
 	AddRoutes(events.Routes)
+
+	// metrics
+	AddRoutes(metrics.Routes)
 }
 
 func Listen(host string, port int, opts *HttpOpts) error {
@@ -109,6 +128,11 @@ func Listen(host string, port int, opts *HttpOpts) error {
 		return http.Listen(host, port, r)
 	}
 
+	if opts.ClientCAs != nil {
+		log.V(logLevel).Debugf("%s:> run http server with tls and client certificates", logPrefix)
+		return http.ListenWithClientCAs(host, port, opts.CertFile, opts.KeyFile, opts.ClientCAs, r)
+	}
+
 	log.V(logLevel).Debugf("%s:> run http server with tls", logPrefix)
 	return http.ListenWithTLS(host, port, opts.CaFile, opts.CertFile, opts.KeyFile, r)
 }
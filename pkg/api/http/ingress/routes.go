@@ -19,14 +19,15 @@
 package ingress
 
 import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/http"
 	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
 )
 
 var Routes = []http.Route{
-	{Path: "/ingress", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: IngressListH},
-	{Path: "/ingress/{ingress}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: IngressInfoH},
-	{Path: "/ingress/{ingress}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: IngressConnectH},
-	{Path: "/ingress/{ingress}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authenticate}, Handler: IngressRemoveH},
-	{Path: "/ingress/{ingress}/status", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: IngressSetStatusH},
+	{Path: "/ingress", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: IngressListH},
+	{Path: "/ingress/{ingress}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead, types.AccountKindIngress)}, Handler: IngressInfoH},
+	{Path: "/ingress/{ingress}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin, types.AccountKindIngress)}, Handler: IngressConnectH},
+	{Path: "/ingress/{ingress}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin)}, Handler: IngressRemoveH},
+	{Path: "/ingress/{ingress}/status", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin, types.AccountKindIngress)}, Handler: IngressSetStatusH},
 }
@@ -0,0 +1,655 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/lastbackend/lastbackend/pkg/api/envs"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/http/utils"
+)
+
+const (
+	logLevel    = 2
+	logPrefix   = "api:handler:job"
+	BUFFER_SIZE = 512
+)
+
+func JobListH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /namespace/{namespace}/job job jobList
+	//
+	// Shows a list of jobs in namespace
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Job list response
+	//     schema:
+	//       "$ref": "#/definitions/views_job_list"
+	//   '404':
+	//     description: Namespace not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["namespace"]
+
+	log.V(logLevel).Debugf("%s:list:> get jobs list in namespace `%s`", logPrefix, nid)
+
+	var (
+		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		jm  = distribution.NewJobModel(r.Context(), envs.Get().GetStorage())
+		pm  = distribution.NewPodModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, err := nsm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:list:> namespace %s not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	jl, err := jm.ListByNamespace(ns.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> get jobs list err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	pl, err := pm.ListByService(ns.Meta.Name, types.JobPodService)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> get job pods list err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Job().NewList(jl, pl).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:list:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func JobInfoH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /namespace/{namespace}/job/{job} job jobInfo
+	//
+	// Shows job status with finished and running pods
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: job
+	//     in: path
+	//     description: name of the job
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Job response
+	//     schema:
+	//       "$ref": "#/definitions/views_job"
+	//   '404':
+	//     description: Namespace not found / Job not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["namespace"]
+	jid := utils.Vars(r)["job"]
+
+	log.V(logLevel).Debugf("%s:info:> get job `%s/%s`", logPrefix, nid, jid)
+
+	var (
+		pm = distribution.NewPodModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, job, e := fetchJob(r, nid, jid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	pl, err := pm.ListByJob(ns.Meta.Name, job.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:info:> get job pods list err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Job().New(job, pl).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:info:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:info:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func JobCreateH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation POST /namespace/{namespace}/job job jobCreate
+	//
+	// Create new job
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: body
+	//     in: body
+	//     required: true
+	//     schema:
+	//       "$ref": "#/definitions/request_job"
+	// responses:
+	//   '200':
+	//     description: Job was successfully created
+	//     schema:
+	//       "$ref": "#/definitions/views_job"
+	//   '400':
+	//     description: Bad request / Name is already in use / Namespace quotas exceeded
+	//   '404':
+	//     description: Namespace not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["namespace"]
+
+	log.V(logLevel).Debugf("%s:create:> create job in namespace `%s`", logPrefix, nid)
+
+	var (
+		nm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		jm = distribution.NewJobModel(r.Context(), envs.Get().GetStorage())
+
+		opts = v1.Request().Job().Manifest()
+	)
+
+	// request body struct
+	if err := opts.DecodeAndValidate(r.Body); err != nil {
+		log.V(logLevel).Errorf("%s:create:> validation incoming data err: %s", logPrefix, err.Err())
+		err.Http(w)
+		return
+	}
+
+	if opts.Meta.Name == nil {
+		errors.New("job").BadParameter("name").Http(w)
+		return
+	}
+
+	if opts.Spec.Template == nil || len(opts.Spec.Template.Containers) == 0 {
+		errors.New("job").BadParameter("spec").Http(w)
+		return
+	}
+
+	ns, err := nm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:create:> namespace %s not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	item, err := jm.Get(ns.Meta.Name, *opts.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> get job `%s` in namespace `%s` err: %s", logPrefix, *opts.Meta.Name, ns.Meta.Name, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if item != nil {
+		log.V(logLevel).Warnf("%s:create:> job name `%s` in namespace `%s` not unique", logPrefix, *opts.Meta.Name, ns.Meta.Name)
+		errors.New("job").NotUnique("name").Http(w)
+		return
+	}
+
+	job := new(types.Job)
+	job.Spec.SetDefault()
+	opts.SetJobMeta(job)
+	opts.SetJobSpec(job)
+
+	used, err := nm.Usage(ns)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> get namespace resources usage err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if err := ns.Spec.Quotas.Admit(*used, types.NamespaceResources{}, job.QuotaUsage()); err != nil {
+		log.V(logLevel).Warnf("%s:create:> namespace `%s` quotas check err: %s", logPrefix, ns.Meta.Name, err.Error())
+		errors.New("namespace").BadRequest(err.Error()).Http(w)
+		return
+	}
+
+	job, err = jm.Create(ns, job)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> create job err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if err := nm.UpdateResources(ns); err != nil {
+		log.V(logLevel).Errorf("%s:create:> update namespace resources err: %s", logPrefix, err.Error())
+	}
+
+	response, err := v1.View().Job().New(job, nil).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:create:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func JobUpdateH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation PUT /namespace/{namespace}/job/{job} job jobUpdate
+	//
+	// Update job completions, parallelism, retries and deadline, pods template can not be changed
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: job
+	//     in: path
+	//     description: name of the job
+	//     required: true
+	//     type: string
+	//   - name: body
+	//     in: body
+	//     required: true
+	//     schema:
+	//       "$ref": "#/definitions/request_job"
+	// responses:
+	//   '200':
+	//     description: Job was successfully updated
+	//     schema:
+	//       "$ref": "#/definitions/views_job"
+	//   '400':
+	//     description: Bad request / Namespace quotas exceeded
+	//   '404':
+	//     description: Namespace not found / Job not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["namespace"]
+	jid := utils.Vars(r)["job"]
+
+	log.V(logLevel).Debugf("%s:update:> update job `%s/%s`", logPrefix, nid, jid)
+
+	var (
+		nm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		jm = distribution.NewJobModel(r.Context(), envs.Get().GetStorage())
+
+		opts = v1.Request().Job().Manifest()
+	)
+
+	// request body struct
+	if err := opts.DecodeAndValidate(r.Body); err != nil {
+		log.V(logLevel).Errorf("%s:update:> validation incoming data err: %s", logPrefix, err.Err())
+		err.Http(w)
+		return
+	}
+
+	ns, job, e := fetchJob(r, nid, jid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	prev := job.QuotaUsage()
+	opts.SetJobMeta(job)
+	opts.SetJobSpec(job)
+
+	used, err := nm.Usage(ns)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:update:> get namespace resources usage err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if err := ns.Spec.Quotas.Admit(*used, prev, job.QuotaUsage()); err != nil {
+		log.V(logLevel).Warnf("%s:update:> namespace `%s` quotas check err: %s", logPrefix, ns.Meta.Name, err.Error())
+		errors.New("namespace").BadRequest(err.Error()).Http(w)
+		return
+	}
+
+	job, err = jm.Update(job)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:update:> update job err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if err := nm.UpdateResources(ns); err != nil {
+		log.V(logLevel).Errorf("%s:update:> update namespace resources err: %s", logPrefix, err.Error())
+	}
+
+	response, err := v1.View().Job().New(job, nil).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:update:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:update:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func JobRemoveH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation DELETE /namespace/{namespace}/job/{job} job jobRemove
+	//
+	// Remove job with all its pods
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: job
+	//     in: path
+	//     description: name of the job
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Job was successfully removed
+	//   '404':
+	//     description: Namespace not found / Job not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["namespace"]
+	jid := utils.Vars(r)["job"]
+
+	log.V(logLevel).Debugf("%s:remove:> remove job `%s/%s`", logPrefix, nid, jid)
+
+	var (
+		nm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		jm = distribution.NewJobModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, job, e := fetchJob(r, nid, jid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	if _, err := jm.Destroy(job); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> destroy job err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if err := nm.UpdateResources(ns); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> update namespace resources err: %s", logPrefix, err.Error())
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte{}); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
+func JobLogsH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /namespace/{namespace}/job/{job}/logs job jobLogs
+	//
+	// Shows logs of the job pod container, logs of finished pods are available until job is removed
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: name of the namespace
+	//     required: true
+	//     type: string
+	//   - name: job
+	//     in: path
+	//     description: name of the job
+	//     required: true
+	//     type: string
+	//   - name: pod
+	//     in: query
+	//     description: pod id
+	//     required: true
+	//     type: string
+	//   - name: container
+	//     in: query
+	//     description: container id
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Job logs received
+	//   '404':
+	//     description: Namespace not found / Job not found / Pod not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["namespace"]
+	jid := utils.Vars(r)["job"]
+	pid := r.URL.Query().Get("pod")
+	cid := r.URL.Query().Get("container")
+
+	log.V(logLevel).Debugf("%s:logs:> get logs of job `%s/%s` pod `%s`", logPrefix, nid, jid, pid)
+
+	var (
+		pm = distribution.NewPodModel(r.Context(), envs.Get().GetStorage())
+		nm = distribution.NewNodeModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, job, e := fetchJob(r, nid, jid)
+	if e != nil {
+		e.Http(w)
+		return
+	}
+
+	pod, err := pm.Get(ns.Meta.Name, types.JobPodService, job.Meta.Name, pid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:logs:> get pod by name err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if pod == nil {
+		log.V(logLevel).Warnf("%s:logs:> pod `%s` not found", logPrefix, pid)
+		errors.New("pod").NotFound().Http(w)
+		return
+	}
+
+	node, err := nm.Get(pod.Meta.Node)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:logs:> get node by name err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if node == nil {
+		log.V(logLevel).Warnf("%s:logs:> node %s not found", logPrefix, pod.Meta.Node)
+		errors.New("node").NotFound().Http(w)
+		return
+	}
+
+	tlsConfig, err := node.Spec.Security.TLSConfig()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:logs:> node tls config err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s://%s/pod/%s/%s/logs", scheme, node.Endpoint(), pod.Meta.SelfLink, cid), nil)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:logs:> create http client err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", types.SecretAccessToken))
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+
+	res, err := client.Do(req)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:logs:> get pod logs err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	notify := w.(http.CloseNotifier).CloseNotify()
+	done := make(chan bool, 1)
+
+	go func() {
+		<-notify
+		log.V(logLevel).Debugf("%s:logs:> HTTP connection just closed.", logPrefix)
+		done <- true
+	}()
+
+	var buffer = make([]byte, BUFFER_SIZE)
+
+	for {
+		select {
+		case <-done:
+			res.Body.Close()
+			return
+		default:
+
+			n, err := res.Body.Read(buffer)
+			if err != nil {
+
+				if err == context.Canceled {
+					log.V(logLevel).Debug("Stream is canceled")
+					return
+				}
+
+				log.Errorf("Error read bytes from stream %s", err)
+				return
+			}
+
+			if _, err := w.Write(buffer[0:n]); err != nil {
+				log.Errorf("Error write bytes to stream %s", err)
+				return
+			}
+
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+		}
+	}
+}
+
+// fetchJob returns namespace and job, http error is returned if any of them is not found
+func fetchJob(r *http.Request, nid, jid string) (*types.Namespace, *types.Job, *errors.Err) {
+
+	var (
+		nm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		jm = distribution.NewJobModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, err := nm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:fetch:> get namespace %s err: %s", logPrefix, nid, err.Error())
+		return nil, nil, errors.New("namespace").Unknown(err)
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:fetch:> namespace %s not found", logPrefix, nid)
+		return nil, nil, errors.New("namespace").NotFound()
+	}
+
+	job, err := jm.Get(ns.Meta.Name, jid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:fetch:> get job %s:%s err: %s", logPrefix, nid, jid, err.Error())
+		return nil, nil, errors.New("job").Unknown(err)
+	}
+	if job == nil {
+		log.V(logLevel).Warnf("%s:fetch:> job %s:%s not found", logPrefix, nid, jid)
+		return nil, nil, errors.New("job").NotFound()
+	}
+
+	return ns, job, nil
+}
@@ -0,0 +1,450 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package job_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/lastbackend/lastbackend/pkg/api/envs"
+	"github.com/lastbackend/lastbackend/pkg/api/http/job"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1/request"
+	"github.com/lastbackend/lastbackend/pkg/api/types/v1/views"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// Testing JobCreateH handler
+func TestJobCreate(t *testing.T) {
+
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+
+	ns1 := getNamespaceAsset("demo")
+	ns2 := getNamespaceAsset("test")
+	ns2.Spec.Quotas.Pods = 1
+
+	j1 := getJobAsset(ns1.Meta.Name, "demo")
+
+	m1 := getJobManifest("fresh", "redis")
+	m1.Spec.Parallelism = intPtr(2)
+
+	m2 := getJobManifest("demo", "redis")
+
+	m3 := getJobManifest("fresh", "redis")
+	m3.Spec.Completions = intPtr(0)
+
+	m4 := getJobManifest("fresh", "redis")
+	m4.Spec.Template = nil
+
+	m5 := getJobManifest("fresh", "redis")
+	m5.Meta.Name = nil
+
+	tests := []struct {
+		name         string
+		namespace    *types.Namespace
+		data         *request.JobManifest
+		err          string
+		wantErr      bool
+		expectedCode int
+	}{
+		{
+			name:         "checking create job with zero completions",
+			namespace:    ns1,
+			data:         m3,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad completions parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking create job without name",
+			namespace:    ns1,
+			data:         m5,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad name parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking create job without template",
+			namespace:    ns1,
+			data:         m4,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad spec parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking create job if namespace not found",
+			namespace:    getNamespaceAsset("empty"),
+			data:         m1,
+			err:          "{\"code\":404,\"status\":\"Not Found\",\"message\":\"Namespace not found\"}",
+			wantErr:      true,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "checking create job if name already exists",
+			namespace:    ns1,
+			data:         m2,
+			err:          "{\"code\":400,\"status\":\"Not Unique\",\"message\":\"Name is already in use\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking create job if namespace pods quota exceeded",
+			namespace:    ns2,
+			data:         m1,
+			err:          "{\"code\":400,\"status\":\"Bad Request\",\"message\":\"Pods quota exceeded\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking create job successfully",
+			namespace:    ns1,
+			data:         m1,
+			wantErr:      false,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Job(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range tests {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			for _, n := range []*types.Namespace{ns1, ns2} {
+				err := stg.Put(context.Background(), stg.Collection().Namespace(), stg.Key().Namespace(n.Meta.Name), n, nil)
+				assert.NoError(t, err)
+			}
+
+			err := stg.Put(context.Background(), stg.Collection().Job(), stg.Key().Job(j1.Meta.Namespace, j1.Meta.Name), j1, nil)
+			assert.NoError(t, err)
+
+			buf, err := tc.data.ToJson()
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("POST", fmt.Sprintf("/namespace/%s/job", tc.namespace.Meta.Name), strings.NewReader(string(buf)))
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/namespace/{namespace}/job", job.JobCreateH)
+
+			setRequestVars(r, req)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			if !assert.Equal(t, tc.expectedCode, res.Code, "status code not equal") {
+				return
+			}
+
+			body, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+
+			if tc.wantErr {
+				assert.Equal(t, tc.err, string(body), "incorrect status code")
+				return
+			}
+
+			v := new(views.Job)
+			err = json.Unmarshal(body, v)
+			assert.NoError(t, err)
+
+			j := new(types.Job)
+			err = stg.Get(context.Background(), stg.Collection().Job(), stg.Key().Job(tc.namespace.Meta.Name, *tc.data.Meta.Name), j, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, *tc.data.Meta.Name, v.Meta.Name, "name mismatch")
+			assert.Equal(t, 1, j.Spec.Completions, "completions mismatch")
+			assert.Equal(t, 2, j.Spec.Parallelism, "parallelism mismatch")
+			assert.Equal(t, types.JobDefaultBackoffLimit, j.Spec.BackoffLimit, "backoff limit mismatch")
+			assert.Equal(t, types.StateCreated, j.Status.State, "state mismatch")
+			assert.Len(t, j.Spec.Template.Containers, 1, "containers count mismatch")
+		})
+	}
+}
+
+// Testing JobInfoH handler
+func TestJobInfo(t *testing.T) {
+
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+
+	ns1 := getNamespaceAsset("demo")
+	j1 := getJobAsset(ns1.Meta.Name, "demo")
+	j2 := getJobAsset(ns1.Meta.Name, "test")
+
+	p1 := getJobPodAsset(j1, "pod1")
+
+	tests := []struct {
+		name         string
+		namespace    string
+		job          string
+		err          string
+		wantErr      bool
+		expectedCode int
+	}{
+		{
+			name:         "checking get job if namespace not found",
+			namespace:    "empty",
+			job:          j1.Meta.Name,
+			err:          "{\"code\":404,\"status\":\"Not Found\",\"message\":\"Namespace not found\"}",
+			wantErr:      true,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "checking get job if not exists",
+			namespace:    ns1.Meta.Name,
+			job:          j2.Meta.Name,
+			err:          "{\"code\":404,\"status\":\"Not Found\",\"message\":\"Job not found\"}",
+			wantErr:      true,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "checking get job successfully",
+			namespace:    ns1.Meta.Name,
+			job:          j1.Meta.Name,
+			wantErr:      false,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Job(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Pod(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range tests {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			err := stg.Put(context.Background(), stg.Collection().Namespace(), stg.Key().Namespace(ns1.Meta.Name), ns1, nil)
+			assert.NoError(t, err)
+
+			err = stg.Put(context.Background(), stg.Collection().Job(), stg.Key().Job(j1.Meta.Namespace, j1.Meta.Name), j1, nil)
+			assert.NoError(t, err)
+
+			err = stg.Put(context.Background(), stg.Collection().Pod(), stg.Key().Pod(p1.Meta.Namespace, p1.Meta.Service, p1.Meta.Deployment, p1.Meta.Name), p1, nil)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("GET", fmt.Sprintf("/namespace/%s/job/%s", tc.namespace, tc.job), nil)
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/namespace/{namespace}/job/{job}", job.JobInfoH)
+
+			setRequestVars(r, req)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			if !assert.Equal(t, tc.expectedCode, res.Code, "status code not equal") {
+				return
+			}
+
+			body, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+
+			if tc.wantErr {
+				assert.Equal(t, tc.err, string(body), "incorrect status code")
+				return
+			}
+
+			v := new(views.Job)
+			err = json.Unmarshal(body, v)
+			assert.NoError(t, err)
+
+			assert.Equal(t, j1.Meta.Name, v.Meta.Name, "name mismatch")
+			assert.Len(t, v.Pods, 1, "pods count mismatch")
+			assert.Contains(t, v.Pods, p1.Meta.Name, "pod not found")
+		})
+	}
+}
+
+// Testing JobRemoveH handler
+func TestJobRemove(t *testing.T) {
+
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+
+	ns1 := getNamespaceAsset("demo")
+	j1 := getJobAsset(ns1.Meta.Name, "demo")
+	j2 := getJobAsset(ns1.Meta.Name, "test")
+
+	tests := []struct {
+		name         string
+		job          *types.Job
+		err          string
+		wantErr      bool
+		expectedCode int
+	}{
+		{
+			name:         "checking remove job if not exists",
+			job:          j2,
+			err:          "{\"code\":404,\"status\":\"Not Found\",\"message\":\"Job not found\"}",
+			wantErr:      true,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "checking remove job successfully",
+			job:          j1,
+			wantErr:      false,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Job(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range tests {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			err := stg.Put(context.Background(), stg.Collection().Namespace(), stg.Key().Namespace(ns1.Meta.Name), ns1, nil)
+			assert.NoError(t, err)
+
+			err = stg.Put(context.Background(), stg.Collection().Job(), stg.Key().Job(j1.Meta.Namespace, j1.Meta.Name), j1, nil)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("DELETE", fmt.Sprintf("/namespace/%s/job/%s", ns1.Meta.Name, tc.job.Meta.Name), nil)
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/namespace/{namespace}/job/{job}", job.JobRemoveH)
+
+			setRequestVars(r, req)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			if !assert.Equal(t, tc.expectedCode, res.Code, "status code not equal") {
+				return
+			}
+
+			body, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+
+			if tc.wantErr {
+				assert.Equal(t, tc.err, string(body), "incorrect status code")
+				return
+			}
+
+			j := new(types.Job)
+			err = stg.Get(context.Background(), stg.Collection().Job(), stg.Key().Job(ns1.Meta.Name, tc.job.Meta.Name), j, nil)
+			assert.NoError(t, err)
+			assert.True(t, j.Spec.State.Destroy, "job should be marked for destroy")
+		})
+	}
+}
+
+func getNamespaceAsset(name string) *types.Namespace {
+	var n = types.Namespace{}
+	n.Meta.SetDefault()
+	n.Meta.Name = name
+	return &n
+}
+
+func getJobAsset(namespace, name string) *types.Job {
+	var j = types.Job{}
+	j.Meta.SetDefault()
+	j.Meta.Namespace = namespace
+	j.Meta.Name = name
+	j.Spec.SetDefault()
+	j.Spec.Template.Containers = append(j.Spec.Template.Containers, &types.SpecTemplateContainer{
+		Name:  "demo",
+		Image: types.SpecTemplateContainerImage{Name: "redis"},
+	})
+	j.Status.State = types.StateProvision
+	return &j
+}
+
+func getJobPodAsset(j *types.Job, name string) *types.Pod {
+	var p = types.Pod{}
+	p.Meta.SetDefault()
+	p.Meta.Name = name
+	p.Meta.Namespace = j.Meta.Namespace
+	p.Meta.Service = types.JobPodService
+	p.Meta.Deployment = j.Meta.Name
+	p.Meta.Job = j.Meta.Name
+	p.SelfLink()
+	return &p
+}
+
+func getJobManifest(name, image string) *request.JobManifest {
+
+	var mf = new(request.JobManifest)
+
+	mf.Meta.Name = &name
+	mf.Spec.Template = new(request.ManifestSpecTemplate)
+	mf.Spec.Template.Containers = append(mf.Spec.Template.Containers, request.ManifestSpecTemplateContainer{
+		Name: image,
+		Image: request.ManifestSpecTemplateContainerImage{
+			Name: image,
+		},
+	})
+
+	return mf
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func setRequestVars(r *mux.Router, req *http.Request) {
+	var match mux.RouteMatch
+	// Take the request and match it
+	r.Match(req, &match)
+	// Push the variable onto the context
+	req = mux.SetURLVars(req, match.Vars)
+}
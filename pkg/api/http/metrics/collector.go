@@ -0,0 +1,77 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package metrics
+
+import (
+	"context"
+
+	"github.com/lastbackend/lastbackend/pkg/api/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/util/metrics"
+)
+
+func init() {
+	metrics.Register("api:service", ServiceCollector)
+}
+
+// ServiceCollector returns resources usage of services aggregated from running pods
+func ServiceCollector(ctx context.Context) ([]*metrics.Metric, error) {
+
+	pm := distribution.NewPodModel(ctx, envs.Get().GetStorage())
+
+	pl, err := pm.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		services = make(map[string]*types.PodUsage, 0)
+		pods     = make(map[string]int, 0)
+		labels   = make(map[string]metrics.Labels, 0)
+	)
+
+	for _, p := range pl.Items {
+
+		if !p.Status.Running {
+			continue
+		}
+
+		link := p.ServiceLink()
+		if _, ok := services[link]; !ok {
+			services[link] = new(types.PodUsage)
+			labels[link] = metrics.Labels{"namespace": p.Meta.Namespace, "service": p.Meta.Service}
+		}
+
+		services[link].Sum(p.Status.Usage)
+		pods[link]++
+	}
+
+	var (
+		count = metrics.NewGauge("lb_service_pods_running", "Number of running service pods.")
+		usage = metrics.NewUsageMetrics("lb_service", "service pods")
+	)
+
+	for link, u := range services {
+		count.Add(float64(pods[link]), labels[link])
+		usage.Add(*u, labels[link])
+	}
+
+	return append(usage.Metrics(), count), nil
+}
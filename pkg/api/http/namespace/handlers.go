@@ -25,7 +25,9 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/api/types/v1"
 	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
 	"github.com/lastbackend/lastbackend/pkg/util/http/utils"
 )
 
@@ -64,6 +66,17 @@ func NamespaceListH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Show only namespaces available for account
+	if account := middleware.Account(r); account != nil {
+		list := items.Items[:0]
+		for _, ns := range items.Items {
+			if account.Allowed(ns.Meta.Name, types.AccessRead) {
+				list = append(list, ns)
+			}
+		}
+		items.Items = list
+	}
+
 	response, err := v1.View().Namespace().NewList(items).ToJson()
 	if err != nil {
 		log.V(logLevel).Errorf("%s:list:> convert struct to json err: %s", logPrefix, err.Error())
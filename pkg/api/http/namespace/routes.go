@@ -19,15 +19,16 @@
 package namespace
 
 import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/http"
 	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
 )
 
 var Routes = []http.Route{
 	// Namespace handlers
-	{Path: "/namespace", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NamespaceListH},
-	{Path: "/namespace", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NamespaceCreateH},
-	{Path: "/namespace/{namespace}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NamespaceInfoH},
-	{Path: "/namespace/{namespace}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NamespaceUpdateH},
-	{Path: "/namespace/{namespace}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NamespaceRemoveH},
+	{Path: "/namespace", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.EmptyString)}, Handler: NamespaceListH},
+	{Path: "/namespace", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin)}, Handler: NamespaceCreateH},
+	{Path: "/namespace/{namespace}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: NamespaceInfoH},
+	{Path: "/namespace/{namespace}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin)}, Handler: NamespaceUpdateH},
+	{Path: "/namespace/{namespace}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin)}, Handler: NamespaceRemoveH},
 }
@@ -23,6 +23,7 @@ import (
 	"net/http"
 
 	"strings"
+	"time"
 
 	"github.com/lastbackend/lastbackend/pkg/api/envs"
 	"github.com/lastbackend/lastbackend/pkg/api/types/v1"
@@ -360,6 +361,7 @@ func NodeConnectH(w http.ResponseWriter, r *http.Request) {
 			nco.Status.State.CNI.Message = errors.ErrEntityExists
 		}
 
+		nco.Port = opts.Port
 		nco.Security.TLS = opts.TLS
 
 		if opts.SSL != nil {
@@ -399,6 +401,7 @@ func NodeConnectH(w http.ResponseWriter, r *http.Request) {
 	node.Meta.Set(ou)
 	node.Status.Capacity = opts.Status.Capacity
 
+	node.Spec.Port = opts.Port
 	node.Spec.Security.TLS = opts.TLS
 
 	if opts.SSL != nil {
@@ -499,7 +502,9 @@ func NodeSetStatusH(w http.ResponseWriter, r *http.Request) {
 	}
 
 	node.Status.State = opts.State
+	node.Status.State.Ready = true
 	node.Status.Online = true
+	node.Status.Heartbeat = time.Now()
 	node.Status.Capacity = opts.Resources.Capacity
 
 	if err := nm.Set(node); err != nil {
@@ -540,9 +545,11 @@ func NodeSetStatusH(w http.ResponseWriter, r *http.Request) {
 		pod.Status.Status = s.Status
 		pod.Status.Running = s.Running
 		pod.Status.Message = s.Message
+		pod.Status.Ready = s.Ready
 		pod.Status.Containers = s.Containers
 		pod.Status.Network = s.Network
 		pod.Status.Steps = s.Steps
+		pod.Status.Usage = s.Usage
 
 		if err := pm.Update(pod); err != nil {
 			log.V(logLevel).Errorf("%s:setpodstatus:> update pod err: %s", logPrefix, err.Error())
@@ -670,6 +677,207 @@ func NodeRemoveH(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func NodeCordonH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation PUT /cluster/node/{node}/cordon node nodeCordon
+	//
+	// Mark node as unschedulable, new pods are not placed on node
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: node
+	//     in: path
+	//     description: node id
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Successfully cordoned node
+	//     schema:
+	//       "$ref": "#/definitions/views_node"
+	//   '404':
+	//     description: Node not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["node"]
+
+	log.V(logLevel).Debugf("%s:cordon:> cordon node `%s`", logPrefix, nid)
+
+	setNodeSpec(w, r, nid, func(spec *types.NodeSpec) {
+		spec.Unschedulable = true
+	})
+}
+
+func NodeUncordonH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation PUT /cluster/node/{node}/uncordon node nodeUncordon
+	//
+	// Mark node as schedulable and stop node drain
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: node
+	//     in: path
+	//     description: node id
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Successfully uncordoned node
+	//     schema:
+	//       "$ref": "#/definitions/views_node"
+	//   '404':
+	//     description: Node not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["node"]
+
+	log.V(logLevel).Debugf("%s:uncordon:> uncordon node `%s`", logPrefix, nid)
+
+	setNodeSpec(w, r, nid, func(spec *types.NodeSpec) {
+		spec.Uncordon()
+	})
+}
+
+func NodeDrainH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation PUT /cluster/node/{node}/drain node nodeDrain
+	//
+	// Cordon node and move pods to other nodes.
+	// Pods are rescheduled by deployment controller with respect to service max unavailable replicas,
+	// pods with drain taint toleration are left on node
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: node
+	//     in: path
+	//     description: node id
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Successfully started node drain
+	//     schema:
+	//       "$ref": "#/definitions/views_node"
+	//   '404':
+	//     description: Node not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["node"]
+
+	log.V(logLevel).Debugf("%s:drain:> drain node `%s`", logPrefix, nid)
+
+	setNodeSpec(w, r, nid, func(spec *types.NodeSpec) {
+		spec.Drain()
+	})
+}
+
+func NodeSetTaintsH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation PUT /cluster/node/{node}/taints node nodeSetTaints
+	//
+	// Set node taints
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: node
+	//     in: path
+	//     description: node id
+	//     required: true
+	//     type: string
+	//   - name: body
+	//     in: body
+	//     required: true
+	//     schema:
+	//       "$ref": "#/definitions/request_node_taints"
+	// responses:
+	//   '200':
+	//     description: Successfully set node taints
+	//     schema:
+	//       "$ref": "#/definitions/views_node"
+	//   '400':
+	//     description: Bad request
+	//   '404':
+	//     description: Node not found
+	//   '500':
+	//     description: Internal server error
+
+	nid := utils.Vars(r)["node"]
+
+	log.V(logLevel).Debugf("%s:settaints:> set node `%s` taints", logPrefix, nid)
+
+	// request body struct
+	opts := new(request.NodeTaintsOptions)
+	if err := opts.DecodeAndValidate(r.Body); err != nil {
+		log.V(logLevel).Errorf("%s:settaints:> validation incoming data err: %s", logPrefix, err.Err())
+		err.Http(w)
+		return
+	}
+
+	setNodeSpec(w, r, nid, func(spec *types.NodeSpec) {
+
+		taints := make([]types.NodeTaint, 0)
+		for _, t := range spec.Taints {
+			if t.Key == types.NodeTaintDrain {
+				taints = append(taints, t)
+			}
+		}
+
+		spec.Taints = append(taints, opts.Taints...)
+	})
+}
+
+func setNodeSpec(w http.ResponseWriter, r *http.Request, nid string, update func(spec *types.NodeSpec)) {
+
+	var (
+		nm = distribution.NewNodeModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	n, err := nm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:setspec:> get node `%s` err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if n == nil {
+		log.V(logLevel).Warnf("%s:setspec:> node `%s` not found", logPrefix, nid)
+		errors.New("node").NotFound().Http(w)
+		return
+	}
+
+	update(&n.Spec)
+
+	if err := nm.Set(n); err != nil {
+		log.V(logLevel).Errorf("%s:setspec:> update node `%s` err: %s", logPrefix, nid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Node().New(n).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:setspec:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(response); err != nil {
+		log.Errorf("%s:setspec:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
 func getNodeSpec(ctx context.Context, n *types.Node) (*types.NodeManifest, error) {
 
 	var (
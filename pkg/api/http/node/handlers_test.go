@@ -714,6 +714,133 @@ func TestNodeSetStatusH(t *testing.T) {
 	}
 }
 
+func TestNodeSetSpecH(t *testing.T) {
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+	viper.Set("verbose", 0)
+
+	var (
+		ctx = context.Background()
+
+		n1 = getNodeAsset("test1", "", true)
+		n2 = getNodeAsset("test2", "", true)
+
+		taint  = types.NodeTaint{Key: "dedicated", Value: "db", Effect: types.NodeTaintEffectNoSchedule}
+		drain  = types.NodeTaint{Key: types.NodeTaintDrain, Effect: types.NodeTaintEffectNoExecute}
+		to     = v1.Request().Node().TaintsOptions()
+		tdrain = v1.Request().Node().TaintsOptions()
+	)
+
+	n1.Spec.Taints = []types.NodeTaint{drain}
+
+	to.Taints = []types.NodeTaint{taint}
+	tdrain.Taints = []types.NodeTaint{drain}
+
+	type args struct {
+		ctx    context.Context
+		node   string
+		action string
+	}
+
+	tests := []struct {
+		name          string
+		args          args
+		handler       func(http.ResponseWriter, *http.Request)
+		data          string
+		expectedCode  int
+		unschedulable bool
+		taints        []types.NodeTaint
+	}{
+		{
+			name:         "checking cordon node failed: not found",
+			args:         args{ctx, n2.Meta.Name, "cordon"},
+			handler:      node.NodeCordonH,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:          "checking cordon node successfully",
+			args:          args{ctx, n1.Meta.Name, "cordon"},
+			handler:       node.NodeCordonH,
+			expectedCode:  http.StatusOK,
+			unschedulable: true,
+			taints:        []types.NodeTaint{drain},
+		},
+		{
+			name:          "checking uncordon node removes drain taint",
+			args:          args{ctx, n1.Meta.Name, "uncordon"},
+			handler:       node.NodeUncordonH,
+			expectedCode:  http.StatusOK,
+			unschedulable: false,
+			taints:        []types.NodeTaint{},
+		},
+		{
+			name:          "checking drain node successfully",
+			args:          args{ctx, n1.Meta.Name, "drain"},
+			handler:       node.NodeDrainH,
+			expectedCode:  http.StatusOK,
+			unschedulable: true,
+			taints:        []types.NodeTaint{drain},
+		},
+		{
+			name:         "checking set node taints failed: drain taint is reserved",
+			args:         args{ctx, n1.Meta.Name, "taints"},
+			handler:      node.NodeSetTaintsH,
+			data:         tdrain.ToJson(),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:          "checking set node taints keeps drain taint",
+			args:          args{ctx, n1.Meta.Name, "taints"},
+			handler:       node.NodeSetTaintsH,
+			data:          to.ToJson(),
+			expectedCode:  http.StatusOK,
+			unschedulable: false,
+			taints:        []types.NodeTaint{drain, taint},
+		},
+	}
+
+	for _, tc := range tests {
+
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Node().Info(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = stg.Put(context.Background(), stg.Collection().Node().Info(), stg.Key().Node(n1.Meta.Name), &n1, nil)
+		assert.NoError(t, err)
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			req, err := http.NewRequest("PUT", fmt.Sprintf("/cluster/node/%s/%s", tc.args.node, tc.args.action), strings.NewReader(tc.data))
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc(fmt.Sprintf("/cluster/node/{node}/%s", tc.args.action), tc.handler)
+
+			setRequestVars(r, req)
+
+			// We create assert ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+			res := httptest.NewRecorder()
+
+			// Our handlers satisfy http.Handler, so we can call their ServeHTTP method
+			// directly and pass in our Request and ResponseRecorder.
+			r.ServeHTTP(res, req)
+
+			// Check the status code is what we expect.
+			assert.Equal(t, tc.expectedCode, res.Code, "status code not equal")
+
+			if tc.expectedCode == http.StatusOK {
+				got := new(types.Node)
+				err = envs.Get().GetStorage().Get(context.Background(), stg.Collection().Node().Info(), envs.Get().GetStorage().Key().Node(tc.args.node), got, nil)
+				assert.NoError(t, err)
+				if !assert.NotNil(t, got, "node should not be empty") {
+					return
+				}
+				assert.Equal(t, tc.unschedulable, got.Spec.Unschedulable, "unschedulable not equal")
+				assert.Equal(t, tc.taints, got.Spec.Taints, "taints not equal")
+			}
+		})
+	}
+}
+
 func setRequestVars(r *mux.Router, req *http.Request) {
 	var match mux.RouteMatch
 	// Take the request and match it
@@ -19,16 +19,21 @@
 package node
 
 import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/http"
 	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
 )
 
 var Routes = []http.Route{
-	{Path: "/cluster/node", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NodeListH},
-	{Path: "/cluster/node/{node}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NodeInfoH},
-	{Path: "/cluster/node/{node}/spec", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NodeGetSpecH},
-	{Path: "/cluster/node/{node}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NodeRemoveH},
-	{Path: "/cluster/node/{node}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NodeConnectH},
-	{Path: "/cluster/node/{node}/meta", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NodeSetMetaH},
-	{Path: "/cluster/node/{node}/status", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: NodeSetStatusH},
+	{Path: "/cluster/node", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: NodeListH},
+	{Path: "/cluster/node/{node}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead, types.AccountKindNode)}, Handler: NodeInfoH},
+	{Path: "/cluster/node/{node}/spec", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead, types.AccountKindNode)}, Handler: NodeGetSpecH},
+	{Path: "/cluster/node/{node}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin)}, Handler: NodeRemoveH},
+	{Path: "/cluster/node/{node}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin, types.AccountKindNode)}, Handler: NodeConnectH},
+	{Path: "/cluster/node/{node}/meta", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin, types.AccountKindNode)}, Handler: NodeSetMetaH},
+	{Path: "/cluster/node/{node}/status", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin, types.AccountKindNode)}, Handler: NodeSetStatusH},
+	{Path: "/cluster/node/{node}/cordon", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin)}, Handler: NodeCordonH},
+	{Path: "/cluster/node/{node}/uncordon", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin)}, Handler: NodeUncordonH},
+	{Path: "/cluster/node/{node}/drain", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin)}, Handler: NodeDrainH},
+	{Path: "/cluster/node/{node}/taints", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessAdmin)}, Handler: NodeSetTaintsH},
 }
@@ -19,6 +19,8 @@
 package route
 
 import (
+	"context"
+
 	"github.com/lastbackend/lastbackend/pkg/api/types/v1"
 
 	"net/http"
@@ -267,12 +269,35 @@ func RouteCreateH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if e := routeCheckTLS(r.Context(), rs); e != nil {
+		log.V(logLevel).Errorf("%s:> route tls check err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
+	used, err := nm.Usage(ns)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> get namespace resources usage err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if err := ns.Spec.Quotas.Admit(*used, types.NamespaceResources{}, rs.QuotaUsage()); err != nil {
+		log.V(logLevel).Warnf("%s:create:> namespace `%s` quotas check err: %s", logPrefix, ns.Meta.Name, err.Error())
+		errors.New("namespace").BadRequest(err.Error()).Http(w)
+		return
+	}
+
 	if _, err := rm.Create(ns, rs); err != nil {
 		log.V(logLevel).Errorf("%s:create:> create route err: %s", logPrefix, ns.Meta.Name, err.Error())
 		errors.HTTP.InternalServerError(w)
 		return
 	}
 
+	if err := nm.UpdateResources(ns); err != nil {
+		log.V(logLevel).Errorf("%s:create:> update namespace resources err: %s", logPrefix, err.Error())
+	}
+
 	response, err := v1.View().Route().New(rs).ToJson()
 	if err != nil {
 		log.V(logLevel).Errorf("%s:create:> convert struct to json err: %s", logPrefix, err.Error())
@@ -386,6 +411,12 @@ func RouteUpdateH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if e := routeCheckTLS(r.Context(), rs); e != nil {
+		log.V(logLevel).Errorf("%s:> route tls check err: %s", logPrefix, e.Err())
+		e.Http(w)
+		return
+	}
+
 	rs, err = rm.Update(rs)
 	if err != nil {
 		log.V(logLevel).Errorf("%s:update:> update route `%s` err: %s", logPrefix, ns.Meta.Name, err.Error())
@@ -476,9 +507,49 @@ func RouteRemoveH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := nsm.UpdateResources(ns); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> update namespace resources err: %s", logPrefix, err.Error())
+	}
+
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write([]byte{}); err != nil {
 		log.V(logLevel).Errorf("%s:remove:> write response err: %s", logPrefix, err.Error())
 		return
 	}
 }
+
+// routeCheckTLS checks route tls secret and sets certificate status
+func routeCheckTLS(ctx context.Context, rs *types.Route) *errors.Err {
+
+	rs.Status.TLS = types.RouteTLSStatus{}
+
+	if rs.Spec.TLS.Secret == types.EmptyString {
+		return nil
+	}
+
+	sm := distribution.NewSecretModel(ctx, envs.Get().GetStorage())
+
+	secret, err := sm.Get(rs.Meta.Namespace, rs.Spec.TLS.Secret)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:tls:> get secret err: %s", logPrefix, err.Error())
+		return errors.New("route").Unknown(err)
+	}
+
+	if secret == nil || secret.Spec.Type != types.KindSecretTLS {
+		return errors.New("route").BadParameter("tls.secret")
+	}
+
+	data, err := secret.DecodeSecretTLSData()
+	if err != nil {
+		return errors.New("route").BadParameter("tls.secret", err)
+	}
+
+	cert, err := data.Parse()
+	if err != nil {
+		return errors.New("route").BadParameter("tls.secret", err)
+	}
+
+	rs.Status.TLS.Set(cert.NotAfter)
+
+	return nil
+}
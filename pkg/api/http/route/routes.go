@@ -19,15 +19,16 @@
 package route
 
 import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/http"
 	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
 )
 
 var Routes = []http.Route{
 	// Route handlers
-	{Path: "/namespace/{namespace}/route", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authenticate}, Handler: RouteCreateH},
-	{Path: "/namespace/{namespace}/route", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: RouteListH},
-	{Path: "/namespace/{namespace}/route/{route}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: RouteInfoH},
-	{Path: "/namespace/{namespace}/route/{route}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: RouteUpdateH},
-	{Path: "/namespace/{namespace}/route/{route}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authenticate}, Handler: RouteRemoveH},
+	{Path: "/namespace/{namespace}/route", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: RouteCreateH},
+	{Path: "/namespace/{namespace}/route", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: RouteListH},
+	{Path: "/namespace/{namespace}/route/{route}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: RouteInfoH},
+	{Path: "/namespace/{namespace}/route/{route}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: RouteUpdateH},
+	{Path: "/namespace/{namespace}/route/{route}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: RouteRemoveH},
 }
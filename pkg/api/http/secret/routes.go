@@ -19,15 +19,16 @@
 package secret
 
 import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/http"
 	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
 )
 
 var Routes = []http.Route{
 	// Route handlers
-	{Path: "/namespace/{namespace}/secret", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authenticate}, Handler: SecretCreateH},
-	{Path: "/namespace/{namespace}/secret", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: SecretListH},
-	{Path: "/namespace/{namespace}/secret/{secret}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: SecretGetH},
-	{Path: "/namespace/{namespace}/secret/{secret}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: SecretUpdateH},
-	{Path: "/namespace/{namespace}/secret/{secret}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authenticate}, Handler: SecretRemoveH},
+	{Path: "/namespace/{namespace}/secret", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: SecretCreateH},
+	{Path: "/namespace/{namespace}/secret", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: SecretListH},
+	{Path: "/namespace/{namespace}/secret/{secret}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead, types.AccountKindNode)}, Handler: SecretGetH},
+	{Path: "/namespace/{namespace}/secret/{secret}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: SecretUpdateH},
+	{Path: "/namespace/{namespace}/secret/{secret}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: SecretRemoveH},
 }
@@ -21,16 +21,21 @@ package service
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/lastbackend/lastbackend/pkg/api/envs"
 	"github.com/lastbackend/lastbackend/pkg/api/types/v1"
 	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/console"
 	"github.com/lastbackend/lastbackend/pkg/util/http/utils"
 )
 
@@ -304,14 +309,35 @@ func ServiceCreateH(w http.ResponseWriter, r *http.Request) {
 	opts.SetServiceMeta(svc)
 	svc.Meta.Namespace = ns.Meta.Name
 	svc.Meta.Endpoint = fmt.Sprintf("%s.%s", strings.ToLower(svc.Meta.Name), ns.Meta.Endpoint)
+	svc.Spec.Strategy.History = types.SpecStrategyHistoryDefault
 
 	opts.SetServiceSpec(svc)
 
+	// volume claims create dedicated volumes for stateful service pods
+	if len(svc.Spec.VolumeClaims) > 0 && !svc.Spec.Stateful() {
+		log.V(logLevel).Warnf("%s:create:> volume claims are supported by stateful service only", logPrefix)
+		errors.New("service").BadParameter("volume_claims").Http(w)
+		return
+	}
+
 	if err := checkServiceVolumes(r.Context(), svc); err != nil {
 		log.V(logLevel).Errorf("%s:create:> create service err: %s", logPrefix, err.Error())
 		errors.HTTP.BadParameter(w, "volume templates")
 	}
 
+	used, err := nm.Usage(ns)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> get namespace resources usage err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if err := ns.Spec.Quotas.Admit(*used, types.NamespaceResources{}, svc.QuotaUsage()); err != nil {
+		log.V(logLevel).Warnf("%s:create:> namespace `%s` quotas check err: %s", logPrefix, ns.Meta.Name, err.Error())
+		errors.New("namespace").BadRequest(err.Error()).Http(w)
+		return
+	}
+
 	srv, err := sm.Create(ns, svc)
 	if err != nil {
 		log.V(logLevel).Errorf("%s:create:> create service err: %s", logPrefix, err.Error())
@@ -319,6 +345,10 @@ func ServiceCreateH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := nm.UpdateResources(ns); err != nil {
+		log.V(logLevel).Errorf("%s:create:> update namespace resources err: %s", logPrefix, err.Error())
+	}
+
 	response, err := v1.View().Service().NewWithDeployment(srv, nil, nil).ToJson()
 	if err != nil {
 		log.V(logLevel).Errorf("%s:create:> convert struct to json err: %s", logPrefix, err.Error())
@@ -411,6 +441,29 @@ func ServiceUpdateH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// service mode is set on create: daemon and stateful services are not switched to replicas and back
+	if opts.Spec.Mode != nil && ((*opts.Spec.Mode == types.ServiceModeDaemon) != svc.Spec.Daemon() ||
+		(*opts.Spec.Mode == types.ServiceModeStateful) != svc.Spec.Stateful()) {
+		log.V(logLevel).Warnf("%s:update:> service `%s` mode can not be changed", logPrefix, svc.SelfLink())
+		errors.New("service").BadParameter("mode").Http(w)
+		return
+	}
+
+	if svc.Spec.Ordered() && opts.Spec.Strategy != nil && !opts.Spec.Strategy.ValidOrdered() {
+		log.V(logLevel).Warnf("%s:update:> strategy is not supported by %s service `%s`", logPrefix, svc.Spec.Mode, svc.SelfLink())
+		errors.New("service").BadParameter("strategy").Http(w)
+		return
+	}
+
+	// volume claims are set on create: existing pod volumes are not resized or renamed
+	if opts.Spec.VolumeClaims != nil && !volumeClaimsEqual(opts.Spec.GetVolumeClaims(), svc.Spec.VolumeClaims) {
+		log.V(logLevel).Warnf("%s:update:> service `%s` volume claims can not be changed", logPrefix, svc.SelfLink())
+		errors.New("service").BadParameter("volume_claims").Http(w)
+		return
+	}
+
+	prev := svc.QuotaUsage()
+
 	opts.SetServiceMeta(svc)
 	svc.Meta.Endpoint = fmt.Sprintf("%s.%s", strings.ToLower(svc.Meta.Name), ns.Meta.Endpoint)
 	opts.SetServiceSpec(svc)
@@ -420,6 +473,19 @@ func ServiceUpdateH(w http.ResponseWriter, r *http.Request) {
 		errors.HTTP.BadParameter(w, "volume templates")
 	}
 
+	used, err := nm.Usage(ns)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:update:> get namespace resources usage err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if err := ns.Spec.Quotas.Admit(*used, prev, svc.QuotaUsage()); err != nil {
+		log.V(logLevel).Warnf("%s:update:> namespace `%s` quotas check err: %s", logPrefix, ns.Meta.Name, err.Error())
+		errors.New("namespace").BadRequest(err.Error()).Http(w)
+		return
+	}
+
 	srv, err := sm.Update(svc)
 	if err != nil {
 		log.V(logLevel).Errorf("%s:update:> update service err: %s", logPrefix, err.Error())
@@ -427,6 +493,10 @@ func ServiceUpdateH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := nm.UpdateResources(ns); err != nil {
+		log.V(logLevel).Errorf("%s:update:> update namespace resources err: %s", logPrefix, err.Error())
+	}
+
 	response, err := v1.View().Service().NewWithDeployment(srv, nil, nil).ToJson()
 	if err != nil {
 		log.V(logLevel).Errorf("%s:update:> convert struct to json err: %s", logPrefix, err.Error())
@@ -530,6 +600,10 @@ func ServiceRemoveH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := nsm.UpdateResources(ns); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> update namespace resources err: %s", logPrefix, err.Error())
+	}
+
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write([]byte{}); err != nil {
 		log.V(logLevel).Errorf("%s:remove:> write response err: %s", logPrefix, err.Error())
@@ -537,6 +611,92 @@ func ServiceRemoveH(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func ServiceMetricsH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /namespace/{namespace}/service/{service}/metrics service serviceMetrics
+	//
+	// Shows resources usage of the service pods
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: namespace id
+	//     required: true
+	//     type: string
+	//   - name: service
+	//     in: path
+	//     description: service id
+	//     required: true
+	//     type: string
+	// responses:
+	//   '200':
+	//     description: Service metrics response
+	//     schema:
+	//       "$ref": "#/definitions/views_service_metrics"
+	//   '404':
+	//     description: Namespace not found / Service not found
+	//   '500':
+	//     description: Internal server error
+
+	sid := utils.Vars(r)["service"]
+	nid := utils.Vars(r)["namespace"]
+
+	log.V(logLevel).Debugf("%s:metrics:> get service `%s` metrics in namespace `%s`", logPrefix, sid, nid)
+
+	var (
+		sm  = distribution.NewServiceModel(r.Context(), envs.Get().GetStorage())
+		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		pdm = distribution.NewPodModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, err := nsm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:metrics:> get namespace err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:metrics:> namespace `%s` not found", logPrefix, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	srv, err := sm.Get(ns.Meta.Name, sid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:metrics:> get service by name `%s` in namespace `%s` err: %s", logPrefix, sid, ns.Meta.Name, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if srv == nil {
+		log.V(logLevel).Warnf("%s:metrics:> service `%s` in namespace `%s` not found", logPrefix, sid, ns.Meta.Name)
+		errors.New("service").NotFound().Http(w)
+		return
+	}
+
+	pods, err := pdm.ListByService(srv.Meta.Namespace, srv.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:metrics:> get pod list by service id `%s` err: %s", logPrefix, srv.Meta.Name, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	response, err := v1.View().Service().NewMetrics(srv, pods).ToJson()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:metrics:> convert struct to json err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(response); err != nil {
+		log.V(logLevel).Errorf("%s:metrics:> write response err: %s", logPrefix, err.Error())
+		return
+	}
+}
+
 func ServiceLogsH(w http.ResponseWriter, r *http.Request) {
 
 	// swagger:operation GET /namespace/{namespace}/service/{service}/logs service serviceLogs
@@ -657,14 +817,30 @@ func ServiceLogsH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s:%d/pod/%s/%s/logs", node.Meta.InternalIP, 2969, pod.Meta.SelfLink, cid), nil)
+	tlsConfig, err := node.Spec.Security.TLSConfig()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:logs:> node tls config err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s://%s/pod/%s/%s/logs", scheme, node.Endpoint(), pod.Meta.SelfLink, cid), nil)
 	if err != nil {
 		log.V(logLevel).Errorf("%s:logs:> create http client err: %s", logPrefix, err.Error())
 		errors.HTTP.InternalServerError(w)
 		return
 	}
 
-	res, err := http.DefaultClient.Do(req)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", types.SecretAccessToken))
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+
+	res, err := client.Do(req)
 	if err != nil {
 		log.V(logLevel).Errorf("%s:logs:> get pod logs err: %s", logPrefix, err.Error())
 		errors.HTTP.InternalServerError(w)
@@ -729,6 +905,22 @@ func ServiceLogsH(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// volumeClaimsEqual checks that volume claims have same names and capacity
+func volumeClaimsEqual(a, b []types.ServiceVolumeClaim) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
 func checkServiceVolumes(ctx context.Context, svc *types.Service) error {
 
 	var (
@@ -739,7 +931,8 @@ func checkServiceVolumes(ctx context.Context, svc *types.Service) error {
 	var vc = make(map[string]string, 0)
 
 	for _, v := range svc.Spec.Template.Volumes {
-		if v.Volume.Name != types.EmptyString {
+		// stateful service volume claims are created for every pod by controller
+		if v.Volume.Name != types.EmptyString && !svc.Spec.VolumeClaim(v.Volume.Name) {
 			vc[v.Volume.Name] = v.Name
 		}
 	}
@@ -809,3 +1002,315 @@ func checkServiceVolumes(ctx context.Context, svc *types.Service) error {
 
 	return nil
 }
+
+func ServiceExecH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /namespace/{namespace}/service/{service}/exec service serviceExec
+	//
+	// Runs command in service pod container over websocket connection
+	//
+	// ---
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: namespace id
+	//     required: true
+	//     type: string
+	//   - name: service
+	//     in: path
+	//     description: service id
+	//     required: true
+	//     type: string
+	//   - name: deployment
+	//     in: query
+	//     description: deployment id
+	//     required: true
+	//     type: string
+	//   - name: pod
+	//     in: query
+	//     description: pod id
+	//     required: true
+	//     type: string
+	//   - name: container
+	//     in: query
+	//     description: container id or name
+	//     required: true
+	//     type: string
+	//   - name: command
+	//     in: query
+	//     description: command with arguments, repeated for each argument
+	//     required: true
+	//     type: string
+	//   - name: tty
+	//     in: query
+	//     description: allocate terminal
+	//     type: boolean
+	//   - name: stdin
+	//     in: query
+	//     description: attach stdin
+	//     type: boolean
+	// responses:
+	//   '101':
+	//     description: Switching protocols to websocket
+	//   '400':
+	//     description: Bad command parameter
+	//   '404':
+	//     description: Namespace not found / Service not found / Pod not found / Node not found
+	//   '500':
+	//     description: Internal server error
+
+	if len(r.URL.Query()["command"]) == 0 {
+		log.V(logLevel).Warnf("%s:exec:> command not provided", logPrefix)
+		errors.New("command").BadParameter("command").Http(w)
+		return
+	}
+
+	container := r.URL.Query().Get("container")
+	podStreamProxy(w, r, "exec", func(pod *types.Pod) string {
+		return fmt.Sprintf("/pod/%s/%s/exec", pod.SelfLink(), container)
+	}, "command", "tty", "stdin")
+}
+
+func ServiceAttachH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /namespace/{namespace}/service/{service}/attach service serviceAttach
+	//
+	// Attaches to service pod container main process over websocket connection
+	//
+	// ---
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: namespace id
+	//     required: true
+	//     type: string
+	//   - name: service
+	//     in: path
+	//     description: service id
+	//     required: true
+	//     type: string
+	//   - name: deployment
+	//     in: query
+	//     description: deployment id
+	//     required: true
+	//     type: string
+	//   - name: pod
+	//     in: query
+	//     description: pod id
+	//     required: true
+	//     type: string
+	//   - name: container
+	//     in: query
+	//     description: container id or name
+	//     required: true
+	//     type: string
+	//   - name: stdin
+	//     in: query
+	//     description: attach stdin
+	//     type: boolean
+	// responses:
+	//   '101':
+	//     description: Switching protocols to websocket
+	//   '404':
+	//     description: Namespace not found / Service not found / Pod not found / Node not found
+	//   '500':
+	//     description: Internal server error
+
+	container := r.URL.Query().Get("container")
+	podStreamProxy(w, r, "attach", func(pod *types.Pod) string {
+		return fmt.Sprintf("/pod/%s/%s/attach", pod.SelfLink(), container)
+	}, "stdin")
+}
+
+func ServicePortForwardH(w http.ResponseWriter, r *http.Request) {
+
+	// swagger:operation GET /namespace/{namespace}/service/{service}/portforward service servicePortForward
+	//
+	// Forwards service pod ports over websocket connection
+	//
+	// ---
+	// parameters:
+	//   - name: namespace
+	//     in: path
+	//     description: namespace id
+	//     required: true
+	//     type: string
+	//   - name: service
+	//     in: path
+	//     description: service id
+	//     required: true
+	//     type: string
+	//   - name: deployment
+	//     in: query
+	//     description: deployment id
+	//     required: true
+	//     type: string
+	//   - name: pod
+	//     in: query
+	//     description: pod id
+	//     required: true
+	//     type: string
+	//   - name: port
+	//     in: query
+	//     description: pod port, repeated for each forwarded port
+	//     required: true
+	//     type: integer
+	// responses:
+	//   '101':
+	//     description: Switching protocols to websocket
+	//   '400':
+	//     description: Bad port parameter
+	//   '404':
+	//     description: Namespace not found / Service not found / Pod not found / Node not found
+	//   '500':
+	//     description: Internal server error
+
+	ports := r.URL.Query()["port"]
+	if len(ports) == 0 {
+		log.V(logLevel).Warnf("%s:portforward:> ports not provided", logPrefix)
+		errors.New("port").BadParameter("port").Http(w)
+		return
+	}
+
+	for _, p := range ports {
+		if port, err := strconv.ParseUint(p, 10, 16); err != nil || port == 0 {
+			log.V(logLevel).Warnf("%s:portforward:> invalid port `%s`", logPrefix, p)
+			errors.New("port").BadParameter("port").Http(w)
+			return
+		}
+	}
+
+	podStreamProxy(w, r, "portforward", func(pod *types.Pod) string {
+		return fmt.Sprintf("/pod/%s/portforward", pod.SelfLink())
+	}, "port")
+}
+
+// podStreamProxy proxies websocket connection to pod node,
+// node path is built from pod and listed query params are passed to node
+func podStreamProxy(w http.ResponseWriter, r *http.Request, action string, path func(pod *types.Pod) string, params ...string) {
+
+	var (
+		nid   = utils.Vars(r)["namespace"]
+		sid   = utils.Vars(r)["service"]
+		query = r.URL.Query()
+		did   = query.Get("deployment")
+		pid   = query.Get("pod")
+	)
+
+	log.V(logLevel).Debugf("%s:%s:> %s service `%s` pod `%s` in namespace `%s`", logPrefix, action, action, sid, pid, nid)
+
+	var (
+		nsm = distribution.NewNamespaceModel(r.Context(), envs.Get().GetStorage())
+		sm  = distribution.NewServiceModel(r.Context(), envs.Get().GetStorage())
+		pm  = distribution.NewPodModel(r.Context(), envs.Get().GetStorage())
+		nm  = distribution.NewNodeModel(r.Context(), envs.Get().GetStorage())
+	)
+
+	ns, err := nsm.Get(nid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:%s:> get namespace err: %s", logPrefix, action, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if ns == nil {
+		log.V(logLevel).Warnf("%s:%s:> namespace `%s` not found", logPrefix, action, nid)
+		errors.New("namespace").NotFound().Http(w)
+		return
+	}
+
+	svc, err := sm.Get(ns.Meta.Name, sid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:%s:> get service by name `%s` err: %s", logPrefix, action, sid, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if svc == nil {
+		log.V(logLevel).Warnf("%s:%s:> service name `%s` in namespace `%s` not found", logPrefix, action, sid, ns.Meta.Name)
+		errors.New("service").NotFound().Http(w)
+		return
+	}
+
+	pod, err := pm.Get(ns.Meta.Name, svc.Meta.Name, did, pid)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:%s:> get pod by name err: %s", logPrefix, action, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if pod == nil {
+		log.V(logLevel).Warnf("%s:%s:> pod `%s` not found", logPrefix, action, pid)
+		errors.New("pod").NotFound().Http(w)
+		return
+	}
+
+	node, err := nm.Get(pod.Meta.Node)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:%s:> get node by name err: %s", logPrefix, action, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+	if node == nil {
+		log.V(logLevel).Warnf("%s:%s:> node %s not found", logPrefix, action, pod.Meta.Node)
+		errors.New("node").NotFound().Http(w)
+		return
+	}
+
+	values := url.Values{}
+	for _, k := range params {
+		if v, ok := query[k]; ok {
+			values[k] = v
+		}
+	}
+
+	tlsConfig, err := node.Spec.Security.TLSConfig()
+	if err != nil {
+		log.V(logLevel).Errorf("%s:%s:> node tls config err: %s", logPrefix, action, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	endpoint := url.URL{
+		Scheme:   "ws",
+		Host:     node.Endpoint(),
+		Path:     path(pod),
+		RawQuery: values.Encode(),
+	}
+
+	// Node agent API is served with TLS and verifies client certificate provided by node
+	dialer := *websocket.DefaultDialer
+	if tlsConfig != nil {
+		endpoint.Scheme = "wss"
+		dialer.TLSClientConfig = tlsConfig
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", fmt.Sprintf("Bearer %s", types.SecretAccessToken))
+
+	upstream, res, err := dialer.Dial(endpoint.String(), header)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:%s:> connect to node err: %s", logPrefix, action, err.Error())
+
+		if res == nil {
+			errors.HTTP.InternalServerError(w)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+
+		w.WriteHeader(res.StatusCode)
+		w.Write(body)
+		return
+	}
+	defer upstream.Close()
+
+	conn, err := console.Upgrade(w, r)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:%s:> websocket upgrade err: %s", logPrefix, action, err.Error())
+		return
+	}
+	defer conn.Close()
+
+	if err := console.Proxy(conn, upstream); err != nil {
+		log.V(logLevel).Debugf("%s:%s:> proxy connection closed: %s", logPrefix, action, err.Error())
+	}
+}
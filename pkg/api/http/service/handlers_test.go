@@ -171,6 +171,201 @@ func TestServiceInfo(t *testing.T) {
 
 }
 
+// Testing ServiceMetricsH handler
+func TestServiceMetrics(t *testing.T) {
+
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+
+	ns1 := getNamespaceAsset("demo", "")
+	s1 := getServiceAsset(ns1.Meta.Name, "demo", "")
+	s2 := getServiceAsset(ns1.Meta.Name, "test", "")
+
+	getPod := func(name string, running bool, cpu, ram int64) *types.Pod {
+		p := new(types.Pod)
+		p.Meta.SetDefault()
+		p.Meta.Namespace = s1.Meta.Namespace
+		p.Meta.Service = s1.Meta.Name
+		p.Meta.Deployment = "deployment"
+		p.Meta.Name = name
+		p.Status.Running = running
+		p.Status.Usage.CPU = cpu
+		p.Status.Usage.RAM = ram
+		p.Status.Usage.Network.RX = 100
+		return p
+	}
+
+	pods := []*types.Pod{
+		getPod("p1", true, 100, 64),
+		getPod("p2", true, 300, 128),
+		getPod("p3", false, 500, 256),
+	}
+
+	tests := []struct {
+		name         string
+		service      *types.Service
+		err          string
+		wantErr      bool
+		expectedCode int
+	}{
+		{
+			name:         "checking get service metrics if service not exists",
+			service:      s2,
+			err:          "{\"code\":404,\"status\":\"Not Found\",\"message\":\"Service not found\"}",
+			wantErr:      true,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "checking get service metrics successfully",
+			service:      s1,
+			wantErr:      false,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Service(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Pod(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range tests {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			err := stg.Put(context.Background(), stg.Collection().Namespace(), stg.Key().Namespace(ns1.Meta.Name), ns1, nil)
+			assert.NoError(t, err)
+
+			err = stg.Put(context.Background(), stg.Collection().Service(), stg.Key().Service(s1.Meta.Namespace, s1.Meta.Name), s1, nil)
+			assert.NoError(t, err)
+
+			for _, p := range pods {
+				err = stg.Put(context.Background(), stg.Collection().Pod(), stg.Key().Pod(p.Meta.Namespace, p.Meta.Service, p.Meta.Deployment, p.Meta.Name), p, nil)
+				assert.NoError(t, err)
+			}
+
+			req, err := http.NewRequest("GET", fmt.Sprintf("/namespace/%s/service/%s/metrics", ns1.Meta.Name, tc.service.Meta.Name), nil)
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/namespace/{namespace}/service/{service}/metrics", service.ServiceMetricsH)
+
+			setRequestVars(r, req)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			if !assert.Equal(t, tc.expectedCode, res.Code, "status code not equal") {
+				return
+			}
+
+			body, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+
+			if tc.wantErr {
+				assert.Equal(t, tc.err, string(body), "incorrect status code")
+				return
+			}
+
+			m := new(views.ServiceMetrics)
+			err = json.Unmarshal(body, m)
+			assert.NoError(t, err)
+
+			assert.Equal(t, 2, len(m.Pods), "running pods count not equal")
+			assert.Equal(t, int64(400), m.Usage.CPU, "cpu usage not equal")
+			assert.Equal(t, int64(192), m.Usage.RAM, "ram usage not equal")
+			assert.Equal(t, int64(200), m.Usage.Network.RX, "network usage not equal")
+			assert.Equal(t, int64(300), m.Pods["p2"].Usage.CPU, "pod cpu usage not equal")
+		})
+	}
+}
+
+// Testing ServicePortForwardH handler
+func TestServicePortForward(t *testing.T) {
+
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+
+	ns1 := getNamespaceAsset("demo", "")
+	s1 := getServiceAsset(ns1.Meta.Name, "demo", "")
+
+	tests := []struct {
+		name         string
+		query        string
+		err          string
+		expectedCode int
+	}{
+		{
+			name:         "checking port forward without ports",
+			query:        "deployment=d&pod=p",
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad port parameter\"}",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking port forward with invalid port",
+			query:        "deployment=d&pod=p&port=5432&port=70000",
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad port parameter\"}",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking port forward if pod not exists",
+			query:        "deployment=d&pod=p&port=5432",
+			err:          "{\"code\":404,\"status\":\"Not Found\",\"message\":\"Pod not found\"}",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	clear := func() {
+		err := envs.Get().GetStorage().Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+		assert.NoError(t, err)
+
+		err = envs.Get().GetStorage().Del(context.Background(), stg.Collection().Service(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range tests {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			clear()
+			defer clear()
+
+			err := stg.Put(context.Background(), stg.Collection().Namespace(), stg.Key().Namespace(ns1.Meta.Name), ns1, nil)
+			assert.NoError(t, err)
+
+			err = stg.Put(context.Background(), stg.Collection().Service(), stg.Key().Service(s1.Meta.Namespace, s1.Meta.Name), s1, nil)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest("GET", fmt.Sprintf("/namespace/%s/service/%s/portforward?%s", ns1.Meta.Name, s1.Meta.Name, tc.query), nil)
+			assert.NoError(t, err)
+
+			r := mux.NewRouter()
+			r.HandleFunc("/namespace/{namespace}/service/{service}/portforward", service.ServicePortForwardH)
+
+			setRequestVars(r, req)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			if !assert.Equal(t, tc.expectedCode, res.Code, "status code not equal") {
+				return
+			}
+
+			body, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.err, string(body), "incorrect status code")
+		})
+	}
+}
+
 // Testing ServiceListH handler
 func TestServiceList(t *testing.T) {
 
@@ -313,12 +508,18 @@ func TestServiceCreate(t *testing.T) {
 	envs.Get().SetStorage(stg)
 
 	ns1 := getNamespaceAsset("demo", "")
+	ns1.Spec.Quotas.CPU = 1024
 	ns2 := getNamespaceAsset("test", "")
 
 	s1 := getServiceAsset(ns1.Meta.Name, "demo", "")
+	s1.Spec.Template.Containers = append(s1.Spec.Template.Containers, &types.SpecTemplateContainer{})
+	s1.Spec.Template.Containers[0].Resources.Request.CPU = 256
 	s2 := getServiceAsset(ns1.Meta.Name, "test", "")
 	s3 := getServiceAsset(ns1.Meta.Name, "new_demo", "")
 
+	m1 := getServiceManifest("new_demo", "redis")
+	m1.Spec.Template.Containers[0].Role = types.ContainerRoleInit
+
 	type fields struct {
 		stg storage.Storage
 	}
@@ -371,13 +572,43 @@ func TestServiceCreate(t *testing.T) {
 			wantErr:      true,
 			expectedCode: http.StatusBadRequest,
 		},
+		{
+			name:         "check create service if cpu request exceeds limit",
+			args:         args{ctx, ns1, s3},
+			fields:       fields{stg},
+			handler:      service.ServiceCreateH,
+			data:         getServiceManifestWithCPU("new_demo", "redis", 1, 512, 256),
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad resources parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "check create service if namespace cpu quota exceeded",
+			args:         args{ctx, ns1, s3},
+			fields:       fields{stg},
+			handler:      service.ServiceCreateH,
+			data:         getServiceManifestWithCPU("new_demo", "redis", 2, 512, 0),
+			err:          "{\"code\":400,\"status\":\"Bad Request\",\"message\":\"CPU quota exceeded\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "check create service if template has init containers only",
+			args:         args{ctx, ns1, s3},
+			fields:       fields{stg},
+			handler:      service.ServiceCreateH,
+			data:         m1,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad role parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
 		// TODO: check another spec parameters
 		{
 			name:         "check create service success",
 			args:         args{ctx, ns1, s3},
 			fields:       fields{stg},
 			handler:      service.ServiceCreateH,
-			data:         getServiceManifest("new_demo", "redis"),
+			data:         getServiceManifestWithCPU("new_demo", "redis", 3, 256, 512),
 			want:         v1.View().Service().NewWithDeployment(s3, nil, nil),
 			wantErr:      false,
 			expectedCode: http.StatusOK,
@@ -468,6 +699,7 @@ func TestServiceUpdate(t *testing.T) {
 	envs.Get().SetStorage(stg)
 
 	ns1 := getNamespaceAsset("demo", "")
+	ns1.Spec.Quotas.CPU = 1024
 	ns2 := getNamespaceAsset("test", "")
 
 	s1 := getServiceAsset(ns1.Meta.Name, "demo", "")
@@ -487,6 +719,14 @@ func TestServiceUpdate(t *testing.T) {
 
 	m3.SetServiceSpec(s3)
 
+	m4 := getServiceManifest(s1.Meta.Name, "redis")
+	mode := types.ServiceModeDaemon
+	m4.Spec.Mode = &mode
+
+	m5 := getServiceManifest(s1.Meta.Name, "redis")
+	m5.Spec.VolumeClaims = []request.ServiceManifestSpecVolumeClaim{{Name: "data"}}
+	m5.Spec.VolumeClaims[0].Capacity.Storage = "1GB"
+
 	type fields struct {
 		stg storage.Storage
 	}
@@ -529,6 +769,36 @@ func TestServiceUpdate(t *testing.T) {
 			wantErr:      true,
 			expectedCode: http.StatusNotFound,
 		},
+		{
+			name:         "checking update service mode can not be changed",
+			fields:       fields{stg},
+			args:         args{ctx, ns1, s1},
+			handler:      service.ServiceUpdateH,
+			data:         m4,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad mode parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking update service volume claims can not be changed",
+			fields:       fields{stg},
+			args:         args{ctx, ns1, s1},
+			handler:      service.ServiceUpdateH,
+			data:         m5,
+			err:          "{\"code\":400,\"status\":\"Bad Parameter\",\"message\":\"Bad volume_claims parameter\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "checking update service if namespace cpu quota exceeded",
+			fields:       fields{stg},
+			args:         args{ctx, ns1, s1},
+			handler:      service.ServiceUpdateH,
+			data:         getServiceManifestWithCPU(s1.Meta.Name, "redis", 2, 768, 0),
+			err:          "{\"code\":400,\"status\":\"Bad Request\",\"message\":\"CPU quota exceeded\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
 		// TODO: check another spec parameters
 		{
 			name:         "check update service success",
@@ -932,3 +1202,11 @@ func getServiceManifest(name, image string) *request.ServiceManifest {
 	mf.Spec.Template.Volumes = append(mf.Spec.Template.Volumes, volume)
 	return mf
 }
+
+func getServiceManifestWithCPU(name, image string, replicas int, quota, limit int64) *request.ServiceManifest {
+	mf := getServiceManifest(name, image)
+	mf.Spec.Replicas = &replicas
+	mf.Spec.Template.Containers[0].Resources.Request.CPU = quota
+	mf.Spec.Template.Containers[0].Resources.Limits.CPU = limit
+	return mf
+}
@@ -19,15 +19,20 @@
 package service
 
 import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/http"
 	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
 )
 
 var Routes = []http.Route{
-	{Path: "/namespace/{namespace}/service", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authenticate}, Handler: ServiceCreateH},
-	{Path: "/namespace/{namespace}/service", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: ServiceListH},
-	{Path: "/namespace/{namespace}/service/{service}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: ServiceInfoH},
-	{Path: "/namespace/{namespace}/service/{service}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: ServiceUpdateH},
-	{Path: "/namespace/{namespace}/service/{service}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authenticate}, Handler: ServiceRemoveH},
-	{Path: "/namespace/{namespace}/service/{service}/logs", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: ServiceLogsH},
+	{Path: "/namespace/{namespace}/service", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: ServiceCreateH},
+	{Path: "/namespace/{namespace}/service", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: ServiceListH},
+	{Path: "/namespace/{namespace}/service/{service}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: ServiceInfoH},
+	{Path: "/namespace/{namespace}/service/{service}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: ServiceUpdateH},
+	{Path: "/namespace/{namespace}/service/{service}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: ServiceRemoveH},
+	{Path: "/namespace/{namespace}/service/{service}/logs", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: ServiceLogsH},
+	{Path: "/namespace/{namespace}/service/{service}/metrics", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: ServiceMetricsH},
+	{Path: "/namespace/{namespace}/service/{service}/exec", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: ServiceExecH},
+	{Path: "/namespace/{namespace}/service/{service}/attach", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: ServiceAttachH},
+	{Path: "/namespace/{namespace}/service/{service}/portforward", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: ServicePortForwardH},
 }
@@ -252,12 +252,29 @@ func VolumeCreateH(w http.ResponseWriter, r *http.Request) {
 	mf.SetVolumeMeta(rs)
 	mf.SetVolumeSpec(rs)
 
+	used, err := nm.Usage(ns)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:create:> get namespace resources usage err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if err := ns.Spec.Quotas.Admit(*used, types.NamespaceResources{}, rs.QuotaUsage()); err != nil {
+		log.V(logLevel).Warnf("%s:create:> namespace `%s` quotas check err: %s", logPrefix, ns.Meta.Name, err.Error())
+		errors.New("namespace").BadRequest(err.Error()).Http(w)
+		return
+	}
+
 	if _, err := rm.Create(ns, rs); err != nil {
 		log.V(logLevel).Errorf("%s:create:> create volume err: %s", logPrefix, ns.Meta.Name, err.Error())
 		errors.HTTP.InternalServerError(w)
 		return
 	}
 
+	if err := nm.UpdateResources(ns); err != nil {
+		log.V(logLevel).Errorf("%s:create:> update namespace resources err: %s", logPrefix, err.Error())
+	}
+
 	response, err := v1.View().Volume().New(rs).ToJson()
 	if err != nil {
 		log.V(logLevel).Errorf("%s:create:> convert struct to json err: %s", logPrefix, err.Error())
@@ -353,14 +370,33 @@ func VolumeUpdateH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	prev := rs.QuotaUsage()
+
 	mf.SetVolumeMeta(rs)
 	mf.SetVolumeSpec(rs)
 
+	used, err := nm.Usage(ns)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:update:> get namespace resources usage err: %s", logPrefix, err.Error())
+		errors.HTTP.InternalServerError(w)
+		return
+	}
+
+	if err := ns.Spec.Quotas.Admit(*used, prev, rs.QuotaUsage()); err != nil {
+		log.V(logLevel).Warnf("%s:update:> namespace `%s` quotas check err: %s", logPrefix, ns.Meta.Name, err.Error())
+		errors.New("namespace").BadRequest(err.Error()).Http(w)
+		return
+	}
+
 	if err = rm.Update(rs); err != nil {
 		log.V(logLevel).Errorf("%s:update:> update volume `%s` err: %s", logPrefix, ns.Meta.Name, err.Error())
 		errors.HTTP.InternalServerError(w)
 	}
 
+	if err := nm.UpdateResources(ns); err != nil {
+		log.V(logLevel).Errorf("%s:update:> update namespace resources err: %s", logPrefix, err.Error())
+	}
+
 	response, err := v1.View().Volume().New(rs).ToJson()
 	if err != nil {
 		log.V(logLevel).Errorf("%s:update:> convert struct to json err: %s", logPrefix, err.Error())
@@ -445,6 +481,10 @@ func VolumeRemoveH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := nsm.UpdateResources(ns); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> update namespace resources err: %s", logPrefix, err.Error())
+	}
+
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write([]byte{}); err != nil {
 		log.V(logLevel).Errorf("%s:remove:> write response err: %s", logPrefix, err.Error())
@@ -310,6 +310,8 @@ func TestVolumeCreate(t *testing.T) {
 
 	ns1 := getNamespaceAsset("demo", "")
 	ns2 := getNamespaceAsset("test", "")
+	ns3 := getNamespaceAsset("disabled", "")
+	ns3.Spec.Quotas.Disabled = true
 
 	sv1 := getServiceAsset(ns1.Meta.Name, "demo", "")
 
@@ -363,6 +365,16 @@ func TestVolumeCreate(t *testing.T) {
 			wantErr:      true,
 			expectedCode: http.StatusBadRequest,
 		},
+		{
+			name:         "check create volume if namespace is disabled",
+			args:         args{ctx, ns3},
+			fields:       fields{stg},
+			handler:      volume.VolumeCreateH,
+			data:         string(mf1),
+			err:          "{\"code\":400,\"status\":\"Bad Request\",\"message\":\"Namespace is disabled\"}",
+			wantErr:      true,
+			expectedCode: http.StatusBadRequest,
+		},
 		// TODO: need checking incoming data for validity
 		{
 			name:         "check create volume success",
@@ -396,6 +408,9 @@ func TestVolumeCreate(t *testing.T) {
 			err := tc.fields.stg.Put(context.Background(), stg.Collection().Namespace(), tc.fields.stg.Key().Namespace(ns1.Meta.Name), ns1, nil)
 			assert.NoError(t, err)
 
+			err = tc.fields.stg.Put(context.Background(), stg.Collection().Namespace(), tc.fields.stg.Key().Namespace(ns3.Meta.Name), ns3, nil)
+			assert.NoError(t, err)
+
 			err = tc.fields.stg.Put(context.Background(), stg.Collection().Service(), stg.Key().Service(sv1.Meta.Namespace, sv1.Meta.Name), sv1, nil)
 			assert.NoError(t, err)
 
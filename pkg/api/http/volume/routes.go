@@ -19,15 +19,16 @@
 package volume
 
 import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/http"
 	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
 )
 
 var Routes = []http.Route{
 	// Route handlers
-	{Path: "/namespace/{namespace}/volume", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authenticate}, Handler: VolumeCreateH},
-	{Path: "/namespace/{namespace}/volume", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: VolumeListH},
-	{Path: "/namespace/{namespace}/volume/{volume}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: VolumeInfoH},
-	{Path: "/namespace/{namespace}/volume/{volume}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authenticate}, Handler: VolumeUpdateH},
-	{Path: "/namespace/{namespace}/volume/{volume}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authenticate}, Handler: VolumeRemoveH},
+	{Path: "/namespace/{namespace}/volume", Method: http.MethodPost, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: VolumeCreateH},
+	{Path: "/namespace/{namespace}/volume", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: VolumeListH},
+	{Path: "/namespace/{namespace}/volume/{volume}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authorize(types.AccessRead)}, Handler: VolumeInfoH},
+	{Path: "/namespace/{namespace}/volume/{volume}", Method: http.MethodPut, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: VolumeUpdateH},
+	{Path: "/namespace/{namespace}/volume/{volume}", Method: http.MethodDelete, Middleware: []http.Middleware{middleware.Authorize(types.AccessWrite)}, Handler: VolumeRemoveH},
 }
@@ -20,12 +20,16 @@ package runtime
 
 import (
 	"context"
+	"errors"
 
 	"github.com/lastbackend/lastbackend/pkg/api/envs"
 	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
 )
 
+const logPrefix = "api:runtime"
+
 type Runtime struct {
 }
 
@@ -71,9 +75,7 @@ func (r *Runtime) Run() {
 	go r.routeWatch(ctx, &rl.System.Revision)
 
 	for _, i := range rl.Items {
-		m := new(types.RouteManifest)
-		m.Set(i)
-		c.Ingress().SetRouteManifest(i.SelfLink(), m)
+		c.Ingress().SetRouteManifest(i.SelfLink(), routeManifest(ctx, i))
 	}
 
 	dm := distribution.NewDiscoveryModel(ctx, envs.Get().GetStorage())
@@ -256,6 +258,10 @@ func (r *Runtime) secretWatch(ctx context.Context, rev *int64) {
 				}
 
 				c.Node().SetSecretManifest(w.Data.Meta.Name, sm)
+
+				if w.Data.Spec.Type == types.KindSecretTLS {
+					routeSecretSync(ctx, w.Data, w.IsActionRemove())
+				}
 			}
 		}
 	}()
@@ -428,8 +434,7 @@ func (r *Runtime) routeWatch(ctx context.Context, rev *int64) {
 					continue
 				}
 
-				m := new(types.RouteManifest)
-				m.Set(w.Data)
+				m := routeManifest(ctx, w.Data)
 
 				if w.IsActionRemove() {
 					m.State = types.StateDestroyed
@@ -442,3 +447,68 @@ func (r *Runtime) routeWatch(ctx context.Context, rev *int64) {
 
 	im.Watch(n, rev)
 }
+
+// routeManifest creates route manifest with certificate from route tls secret
+func routeManifest(ctx context.Context, route *types.Route) *types.RouteManifest {
+
+	m := new(types.RouteManifest)
+	m.Set(route)
+
+	if route.Spec.TLS.Secret == types.EmptyString {
+		return m
+	}
+
+	sm := distribution.NewSecretModel(ctx, envs.Get().GetStorage())
+
+	secret, err := sm.Get(route.Meta.Namespace, route.Spec.TLS.Secret)
+	if err != nil {
+		log.Errorf("%s:route:> get route `%s` secret err: %v", logPrefix, route.SelfLink(), err)
+		return m
+	}
+	if secret == nil {
+		log.Warnf("%s:route:> route `%s` secret not found", logPrefix, route.SelfLink())
+		return m
+	}
+
+	if err := m.SetTLS(route, secret); err != nil {
+		log.Errorf("%s:route:> set route `%s` certificate err: %v", logPrefix, route.SelfLink(), err)
+	}
+
+	return m
+}
+
+// routeSecretSync updates certificate status of routes using secret,
+// route update triggers route manifest update with new certificate
+func routeSecretSync(ctx context.Context, secret *types.Secret, removed bool) {
+
+	rm := distribution.NewRouteModel(ctx, envs.Get().GetStorage())
+
+	rl, err := rm.ListByNamespace(secret.Meta.Namespace)
+	if err != nil {
+		log.Errorf("%s:route:> list routes err: %v", logPrefix, err)
+		return
+	}
+
+	for _, route := range rl.Items {
+
+		if route.Spec.TLS.Secret != secret.Meta.Name {
+			continue
+		}
+
+		status := route.Status
+
+		if removed {
+			status.TLS.SetError(errors.New("secret not found"))
+		} else if data, err := secret.DecodeSecretTLSData(); err != nil {
+			status.TLS.SetError(err)
+		} else if cert, err := data.Parse(); err != nil {
+			status.TLS.SetError(err)
+		} else {
+			status.TLS.Set(cert.NotAfter)
+		}
+
+		if err := rm.SetStatus(route, &status); err != nil {
+			log.Errorf("%s:route:> set route `%s` status err: %v", logPrefix, route.SelfLink(), err)
+		}
+	}
+}
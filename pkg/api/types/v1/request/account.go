@@ -0,0 +1,48 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package request
+
+// AccountCreateOptions represents options for account creation
+//
+// swagger:model request_account_create
+type AccountCreateOptions struct {
+	// Account name, agent account name should match agent hostname
+	Name string `json:"name"`
+	// Account description
+	Description string `json:"description"`
+	// Account kind: user, service, node, ingress or discovery
+	Kind string `json:"kind"`
+	// Roles mapped by namespace name, "*" binds role to all namespaces and cluster resources
+	Roles map[string]string `json:"roles"`
+}
+
+// AccountUpdateOptions represents options for account update
+//
+// swagger:model request_account_update
+type AccountUpdateOptions struct {
+	// Account description
+	Description *string `json:"description"`
+	// Roles mapped by namespace name, replaces all account roles
+	Roles map[string]string `json:"roles"`
+}
+
+// swagger:ignore
+type AccountRemoveOptions struct {
+	Force bool `json:"force"`
+}
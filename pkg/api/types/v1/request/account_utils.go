@@ -0,0 +1,146 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package request
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/util/validator"
+)
+
+type AccountRequest struct{}
+
+func (AccountRequest) CreateOptions() *AccountCreateOptions {
+	return new(AccountCreateOptions)
+}
+
+func (a *AccountCreateOptions) Validate() *errors.Err {
+	switch true {
+	case len(a.Name) == 0:
+		return errors.New("account").BadParameter("name")
+	case !validator.IsNamespaceName(a.Name):
+		return errors.New("account").BadParameter("name")
+	case len(a.Description) > DEFAULT_DESCRIPTION_LIMIT:
+		return errors.New("account").BadParameter("description")
+	case !accountKindValid(a.Kind):
+		return errors.New("account").BadParameter("kind")
+	case !accountRolesValid(a.Roles):
+		return errors.New("account").BadParameter("roles")
+	case a.Kind != types.AccountKindUser && a.Kind != types.AccountKindService && len(a.Roles) > 0:
+		return errors.New("account").BadParameter("roles")
+	}
+	return nil
+}
+
+func (a *AccountCreateOptions) DecodeAndValidate(reader io.Reader) *errors.Err {
+
+	if reader == nil {
+		err := errors.New("data body can not be null")
+		return errors.New("account").IncorrectJSON(err)
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.New("account").Unknown(err)
+	}
+
+	err = json.Unmarshal(body, a)
+	if err != nil {
+		return errors.New("account").IncorrectJSON(err)
+	}
+
+	return a.Validate()
+}
+
+func (a *AccountCreateOptions) ToJson() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+func (AccountRequest) UpdateOptions() *AccountUpdateOptions {
+	return new(AccountUpdateOptions)
+}
+
+func (a *AccountUpdateOptions) Validate() *errors.Err {
+	switch true {
+	case a.Description != nil && len(*a.Description) > DEFAULT_DESCRIPTION_LIMIT:
+		return errors.New("account").BadParameter("description")
+	case !accountRolesValid(a.Roles):
+		return errors.New("account").BadParameter("roles")
+	}
+	return nil
+}
+
+func (a *AccountUpdateOptions) DecodeAndValidate(reader io.Reader) *errors.Err {
+
+	if reader == nil {
+		err := errors.New("data body can not be null")
+		return errors.New("account").IncorrectJSON(err)
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.New("account").Unknown(err)
+	}
+
+	err = json.Unmarshal(body, a)
+	if err != nil {
+		return errors.New("account").IncorrectJSON(err)
+	}
+
+	return a.Validate()
+}
+
+func (a *AccountUpdateOptions) ToJson() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+func (AccountRequest) RemoveOptions() *AccountRemoveOptions {
+	return new(AccountRemoveOptions)
+}
+
+func (a *AccountRemoveOptions) Validate() *errors.Err {
+	return nil
+}
+
+func accountKindValid(kind string) bool {
+	switch kind {
+	case types.AccountKindUser, types.AccountKindService,
+		types.AccountKindNode, types.AccountKindIngress, types.AccountKindDiscovery:
+		return true
+	}
+	return false
+}
+
+func accountRolesValid(roles map[string]string) bool {
+	for ns, role := range roles {
+		if ns == types.EmptyString {
+			return false
+		}
+		switch role {
+		case types.RoleViewer, types.RoleDeveloper, types.RoleAdmin:
+		default:
+			return false
+		}
+	}
+	return true
+}
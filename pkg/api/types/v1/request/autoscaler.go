@@ -0,0 +1,52 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package request
+
+// AutoscalerOptions represents service autoscaler options
+//
+// swagger:model request_autoscaler
+type AutoscalerOptions struct {
+	// Autoscaling is suspended
+	// required: false
+	Disabled bool `json:"disabled" yaml:"disabled"`
+	// Minimal service replicas, 1 by default
+	// required: false
+	MinReplicas int `json:"min_replicas" yaml:"min_replicas"`
+	// Maximal service replicas
+	// required: true
+	MaxReplicas int `json:"max_replicas" yaml:"max_replicas"`
+	// Target average utilisation in percent of pods resource requests
+	// required: true
+	Target AutoscalerTargetOptions `json:"target" yaml:"target"`
+	// Stabilisation windows in seconds: zero uses default window, negative disables window
+	// required: false
+	Window AutoscalerWindowOptions `json:"window" yaml:"window"`
+}
+
+// swagger:model request_autoscaler_target
+type AutoscalerTargetOptions struct {
+	CPU int `json:"cpu" yaml:"cpu"`
+	RAM int `json:"ram" yaml:"ram"`
+}
+
+// swagger:model request_autoscaler_window
+type AutoscalerWindowOptions struct {
+	Upscale   int `json:"upscale" yaml:"upscale"`
+	Downscale int `json:"downscale" yaml:"downscale"`
+}
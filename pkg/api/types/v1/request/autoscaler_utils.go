@@ -0,0 +1,85 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package request
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+type AutoscalerRequest struct{}
+
+func (AutoscalerRequest) Options() *AutoscalerOptions {
+	return new(AutoscalerOptions)
+}
+
+func (a *AutoscalerOptions) Validate() *errors.Err {
+	switch true {
+	case a.MinReplicas < 0:
+		return errors.New("autoscaler").BadParameter("min_replicas")
+	case a.MaxReplicas < 1:
+		return errors.New("autoscaler").BadParameter("max_replicas")
+	case a.MinReplicas > a.MaxReplicas:
+		return errors.New("autoscaler").BadParameter("min_replicas")
+	case a.Target.CPU < 0 || a.Target.RAM < 0:
+		return errors.New("autoscaler").BadParameter("target")
+	case a.Target.CPU == 0 && a.Target.RAM == 0:
+		return errors.New("autoscaler").BadParameter("target")
+	}
+	return nil
+}
+
+func (a *AutoscalerOptions) DecodeAndValidate(reader io.Reader) *errors.Err {
+
+	if reader == nil {
+		err := errors.New("data body can not be null")
+		return errors.New("autoscaler").IncorrectJSON(err)
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.New("autoscaler").Unknown(err)
+	}
+
+	err = json.Unmarshal(body, a)
+	if err != nil {
+		return errors.New("autoscaler").IncorrectJSON(err)
+	}
+
+	return a.Validate()
+}
+
+func (a *AutoscalerOptions) ToJson() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+// SetAutoscalerSpec sets autoscaler spec from options
+func (a *AutoscalerOptions) SetAutoscalerSpec(as *types.Autoscaler) {
+	as.Spec.Disabled = a.Disabled
+	as.Spec.MinReplicas = a.MinReplicas
+	as.Spec.MaxReplicas = a.MaxReplicas
+	as.Spec.Target.CPU = a.Target.CPU
+	as.Spec.Target.RAM = a.Target.RAM
+	as.Spec.Window.Upscale = a.Window.Upscale
+	as.Spec.Window.Downscale = a.Window.Downscale
+}
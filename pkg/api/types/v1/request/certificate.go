@@ -0,0 +1,53 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package request
+
+// CertificateJoinTokenCreateOptions represents options for join token creation
+//
+// swagger:model request_certificate_join_token_create
+type CertificateJoinTokenCreateOptions struct {
+	// Agent kind: node, ingress or discovery
+	Kind string `json:"kind"`
+	// Agent hostname allowed to join with token
+	Name string `json:"name"`
+	// Token lifetime, for example: 1h
+	TTL string `json:"ttl"`
+}
+
+// CertificateJoinOptions represents options to receive agent client certificate by join token
+//
+// swagger:model request_certificate_join
+type CertificateJoinOptions struct {
+	// One-time join token
+	Token string `json:"token"`
+	// Agent kind: node, ingress or discovery
+	Kind string `json:"kind"`
+	// Agent hostname
+	Name string `json:"name"`
+	// PEM-encoded certificate request
+	CSR string `json:"csr"`
+}
+
+// CertificateRenewOptions represents options to rotate agent client certificate
+//
+// swagger:model request_certificate_renew
+type CertificateRenewOptions struct {
+	// PEM-encoded certificate request
+	CSR string `json:"csr"`
+}
@@ -0,0 +1,159 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package request
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/util/validator"
+)
+
+type CertificateRequest struct{}
+
+func (CertificateRequest) JoinTokenCreateOptions() *CertificateJoinTokenCreateOptions {
+	return new(CertificateJoinTokenCreateOptions)
+}
+
+func (c *CertificateJoinTokenCreateOptions) Validate() *errors.Err {
+	switch true {
+	case c.Kind != types.AccountKindNode && c.Kind != types.AccountKindIngress && c.Kind != types.AccountKindDiscovery:
+		return errors.New("certificate").BadParameter("kind")
+	case len(c.Name) == 0 || !validator.IsNamespaceName(c.Name):
+		return errors.New("certificate").BadParameter("name")
+	case c.TTL != types.EmptyString && c.Duration() <= 0:
+		return errors.New("certificate").BadParameter("ttl")
+	}
+	return nil
+}
+
+// Duration returns token lifetime, zero if lifetime is not provided or invalid
+func (c *CertificateJoinTokenCreateOptions) Duration() time.Duration {
+	d, err := time.ParseDuration(c.TTL)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+func (c *CertificateJoinTokenCreateOptions) DecodeAndValidate(reader io.Reader) *errors.Err {
+
+	if reader == nil {
+		err := errors.New("data body can not be null")
+		return errors.New("certificate").IncorrectJSON(err)
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.New("certificate").Unknown(err)
+	}
+
+	err = json.Unmarshal(body, c)
+	if err != nil {
+		return errors.New("certificate").IncorrectJSON(err)
+	}
+
+	return c.Validate()
+}
+
+func (c *CertificateJoinTokenCreateOptions) ToJson() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (CertificateRequest) JoinOptions() *CertificateJoinOptions {
+	return new(CertificateJoinOptions)
+}
+
+func (c *CertificateJoinOptions) Validate() *errors.Err {
+	switch true {
+	case len(c.Token) == 0:
+		return errors.New("certificate").BadParameter("token")
+	case c.Kind != types.AccountKindNode && c.Kind != types.AccountKindIngress && c.Kind != types.AccountKindDiscovery:
+		return errors.New("certificate").BadParameter("kind")
+	case len(c.Name) == 0 || !validator.IsNamespaceName(c.Name):
+		return errors.New("certificate").BadParameter("name")
+	case len(c.CSR) == 0:
+		return errors.New("certificate").BadParameter("csr")
+	}
+	return nil
+}
+
+func (c *CertificateJoinOptions) DecodeAndValidate(reader io.Reader) *errors.Err {
+
+	if reader == nil {
+		err := errors.New("data body can not be null")
+		return errors.New("certificate").IncorrectJSON(err)
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.New("certificate").Unknown(err)
+	}
+
+	err = json.Unmarshal(body, c)
+	if err != nil {
+		return errors.New("certificate").IncorrectJSON(err)
+	}
+
+	return c.Validate()
+}
+
+func (c *CertificateJoinOptions) ToJson() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (CertificateRequest) RenewOptions() *CertificateRenewOptions {
+	return new(CertificateRenewOptions)
+}
+
+func (c *CertificateRenewOptions) Validate() *errors.Err {
+	switch true {
+	case len(c.CSR) == 0:
+		return errors.New("certificate").BadParameter("csr")
+	}
+	return nil
+}
+
+func (c *CertificateRenewOptions) DecodeAndValidate(reader io.Reader) *errors.Err {
+
+	if reader == nil {
+		err := errors.New("data body can not be null")
+		return errors.New("certificate").IncorrectJSON(err)
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.New("certificate").Unknown(err)
+	}
+
+	err = json.Unmarshal(body, c)
+	if err != nil {
+		return errors.New("certificate").IncorrectJSON(err)
+	}
+
+	return c.Validate()
+}
+
+func (c *CertificateRenewOptions) ToJson() ([]byte, error) {
+	return json.Marshal(c)
+}
@@ -0,0 +1,127 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package request
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"gopkg.in/yaml.v2"
+)
+
+// CronJobManifest represents jobs schedule manifest
+//
+// swagger:model request_cronjob
+type CronJobManifest struct {
+	Meta CronJobManifestMeta `json:"meta,omitempty" yaml:"meta,omitempty"`
+	Spec CronJobManifestSpec `json:"spec,omitempty" yaml:"spec,omitempty"`
+}
+
+type CronJobManifestMeta struct {
+	RuntimeMeta `yaml:",inline"`
+}
+
+// swagger:model request_cronjob_spec
+type CronJobManifestSpec struct {
+	// Cron expression, standard 5 fields format or @hourly, @daily, @weekly, @monthly, @yearly descriptors
+	Schedule *string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	// Time zone name schedule is evaluated in, UTC by default
+	TimeZone *string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	// Concurrency policy: allow, forbid or replace, allow by default
+	Concurrency *string `json:"concurrency,omitempty" yaml:"concurrency,omitempty"`
+	// Period in seconds missed schedule may be started after, zero value allows to start missed schedule at any time
+	StartingDeadline *int `json:"starting_deadline,omitempty" yaml:"starting_deadline,omitempty"`
+	// Finished jobs count kept for inspection
+	History *CronJobManifestHistory `json:"history,omitempty" yaml:"history,omitempty"`
+	// Job created by schedule
+	Job *JobManifestSpec `json:"job,omitempty" yaml:"job,omitempty"`
+}
+
+// swagger:model request_cronjob_history
+type CronJobManifestHistory struct {
+	// Successfully completed jobs count, 3 by default
+	Succeeded *int `json:"succeeded,omitempty" yaml:"succeeded,omitempty"`
+	// Failed jobs count, 1 by default
+	Failed *int `json:"failed,omitempty" yaml:"failed,omitempty"`
+}
+
+func (c *CronJobManifest) FromJson(data []byte) error {
+	return json.Unmarshal(data, c)
+}
+
+func (c *CronJobManifest) ToJson() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c *CronJobManifest) FromYaml(data []byte) error {
+	return yaml.Unmarshal(data, c)
+}
+
+func (c *CronJobManifest) ToYaml() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+func (c *CronJobManifest) SetCronJobMeta(cj *types.CronJob) {
+
+	if cj.Meta.Name == types.EmptyString {
+		cj.Meta.Name = *c.Meta.Name
+	}
+
+	if c.Meta.Description != nil {
+		cj.Meta.Description = *c.Meta.Description
+	}
+
+	if c.Meta.Labels != nil {
+		cj.Meta.Labels = c.Meta.Labels
+	}
+}
+
+// SetCronJobSpec sets cron job spec from manifest, job template changes are applied to next scheduled jobs
+func (c *CronJobManifest) SetCronJobSpec(cj *types.CronJob) {
+
+	if c.Spec.Schedule != nil {
+		cj.Spec.Schedule = *c.Spec.Schedule
+	}
+
+	if c.Spec.TimeZone != nil {
+		cj.Spec.TimeZone = *c.Spec.TimeZone
+	}
+
+	if c.Spec.Concurrency != nil {
+		cj.Spec.Concurrency = *c.Spec.Concurrency
+	}
+
+	if c.Spec.StartingDeadline != nil {
+		cj.Spec.StartingDeadline = *c.Spec.StartingDeadline
+	}
+
+	if c.Spec.History != nil {
+		if c.Spec.History.Succeeded != nil {
+			cj.Spec.History.Succeeded = *c.Spec.History.Succeeded
+		}
+		if c.Spec.History.Failed != nil {
+			cj.Spec.History.Failed = *c.Spec.History.Failed
+		}
+	}
+
+	if c.Spec.Job != nil {
+		c.Spec.Job.SetSpec(&cj.Spec.Job, time.Now())
+	}
+}
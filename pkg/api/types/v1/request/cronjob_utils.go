@@ -0,0 +1,100 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package request
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/util/cron"
+	"github.com/lastbackend/lastbackend/pkg/util/validator"
+)
+
+type CronJobRequest struct{}
+
+func (CronJobRequest) Manifest() *CronJobManifest {
+	return new(CronJobManifest)
+}
+
+func (c *CronJobManifest) Validate() *errors.Err {
+	switch true {
+	case c.Meta.Name != nil && (!validator.IsServiceName(*c.Meta.Name) || len(*c.Meta.Name) > types.CronJobNameMaxLength):
+		return errors.New("cronjob").BadParameter("name")
+	case c.Meta.Description != nil && len(*c.Meta.Description) > DEFAULT_DESCRIPTION_LIMIT:
+		return errors.New("cronjob").BadParameter("description")
+	case c.Spec.Schedule != nil && !validSchedule(*c.Spec.Schedule):
+		return errors.New("cronjob").BadParameter("schedule")
+	case c.Spec.TimeZone != nil && !validTimeZone(*c.Spec.TimeZone):
+		return errors.New("cronjob").BadParameter("timezone")
+	case c.Spec.Concurrency != nil && !validConcurrency(*c.Spec.Concurrency):
+		return errors.New("cronjob").BadParameter("concurrency")
+	case c.Spec.StartingDeadline != nil && *c.Spec.StartingDeadline < 0:
+		return errors.New("cronjob").BadParameter("starting_deadline")
+	case c.Spec.History != nil && c.Spec.History.Succeeded != nil && *c.Spec.History.Succeeded < 0:
+		return errors.New("cronjob").BadParameter("history")
+	case c.Spec.History != nil && c.Spec.History.Failed != nil && *c.Spec.History.Failed < 0:
+		return errors.New("cronjob").BadParameter("history")
+	case c.Spec.Job != nil:
+		return c.Spec.Job.validate("cronjob")
+	}
+
+	return nil
+}
+
+func (c *CronJobManifest) DecodeAndValidate(reader io.Reader) *errors.Err {
+
+	if reader == nil {
+		err := errors.New("data body can not be null")
+		return errors.New("cronjob").IncorrectJSON(err)
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.New("cronjob").Unknown(err)
+	}
+
+	err = json.Unmarshal(body, c)
+	if err != nil {
+		return errors.New("cronjob").IncorrectJSON(err)
+	}
+
+	return c.Validate()
+}
+
+func validConcurrency(policy string) bool {
+	switch policy {
+	case types.CronJobConcurrencyAllow, types.CronJobConcurrencyForbid, types.CronJobConcurrencyReplace:
+		return true
+	}
+	return false
+}
+
+func validSchedule(schedule string) bool {
+	_, err := cron.Parse(schedule)
+	return err == nil
+}
+
+func validTimeZone(tz string) bool {
+	_, err := time.LoadLocation(tz)
+	return err == nil
+}
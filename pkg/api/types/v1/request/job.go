@@ -0,0 +1,135 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package request
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"gopkg.in/yaml.v2"
+)
+
+// JobManifest represents run to completion job manifest
+//
+// swagger:model request_job
+type JobManifest struct {
+	Meta JobManifestMeta `json:"meta,omitempty" yaml:"meta,omitempty"`
+	Spec JobManifestSpec `json:"spec,omitempty" yaml:"spec,omitempty"`
+}
+
+type JobManifestMeta struct {
+	RuntimeMeta `yaml:",inline"`
+}
+
+// swagger:model request_job_spec
+type JobManifestSpec struct {
+	// Pods count which should complete successfully, 1 by default
+	Completions *int `json:"completions,omitempty" yaml:"completions,omitempty"`
+	// Max pods count running at the same time, 1 by default
+	Parallelism *int `json:"parallelism,omitempty" yaml:"parallelism,omitempty"`
+	// Failed pods count allowed before job is marked as failed
+	BackoffLimit *int `json:"backoff_limit,omitempty" yaml:"backoff_limit,omitempty"`
+	// Period in seconds job may be active, zero value disables deadline
+	ActiveDeadline *int                  `json:"active_deadline,omitempty" yaml:"active_deadline,omitempty"`
+	Selector       *ManifestSpecSelector `json:"selector,omitempty" yaml:"selector,omitempty"`
+	// Pods template, it is applied on job creation only
+	Template *ManifestSpecTemplate `json:"template,omitempty" yaml:"template,omitempty"`
+}
+
+func (j *JobManifest) FromJson(data []byte) error {
+	return json.Unmarshal(data, j)
+}
+
+func (j *JobManifest) ToJson() ([]byte, error) {
+	return json.Marshal(j)
+}
+
+func (j *JobManifest) FromYaml(data []byte) error {
+	return yaml.Unmarshal(data, j)
+}
+
+func (j *JobManifest) ToYaml() ([]byte, error) {
+	return yaml.Marshal(j)
+}
+
+func (j *JobManifest) SetJobMeta(job *types.Job) {
+
+	if job.Meta.Name == types.EmptyString {
+		job.Meta.Name = *j.Meta.Name
+	}
+
+	if j.Meta.Description != nil {
+		job.Meta.Description = *j.Meta.Description
+	}
+
+	if j.Meta.Labels != nil {
+		job.Meta.Labels = j.Meta.Labels
+	}
+}
+
+// SetJobSpec sets job spec from manifest, pods template is set only for new job
+func (j *JobManifest) SetJobSpec(job *types.Job) {
+
+	spec := j.Spec
+
+	// pods template can not be changed for created job
+	if len(job.Spec.Template.Containers) != 0 {
+		spec.Template = nil
+	}
+
+	spec.SetSpec(&job.Spec, job.Meta.Created)
+}
+
+// SetSpec sets job spec options provided in manifest
+func (s *JobManifestSpec) SetSpec(spec *types.JobSpec, updated time.Time) {
+
+	if s.Completions != nil {
+		spec.Completions = *s.Completions
+	}
+
+	if s.Parallelism != nil {
+		spec.Parallelism = *s.Parallelism
+	}
+
+	if s.BackoffLimit != nil {
+		spec.BackoffLimit = *s.BackoffLimit
+	}
+
+	if s.ActiveDeadline != nil {
+		spec.ActiveDeadline = *s.ActiveDeadline
+	}
+
+	if s.Selector != nil {
+		spec.Selector = s.Selector.GetSpec()
+	}
+
+	if s.Template != nil {
+		spec.Template = s.Template.GetSpec()
+		spec.Template.Updated = updated
+	}
+}
+
+// swagger:ignore
+// swagger:model request_job_logs
+type JobLogsOptions struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Follow    bool   `json:"follow"`
+}
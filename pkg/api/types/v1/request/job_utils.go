@@ -0,0 +1,99 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package request
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/util/validator"
+)
+
+type JobRequest struct{}
+
+func (JobRequest) Manifest() *JobManifest {
+	return new(JobManifest)
+}
+
+func (j *JobManifest) Validate() *errors.Err {
+	switch true {
+	case j.Meta.Name != nil && !validator.IsServiceName(*j.Meta.Name):
+		return errors.New("job").BadParameter("name")
+	case j.Meta.Description != nil && len(*j.Meta.Description) > DEFAULT_DESCRIPTION_LIMIT:
+		return errors.New("job").BadParameter("description")
+	}
+
+	return j.Spec.validate("job")
+}
+
+// validate checks job spec options, kind is used in error messages
+func (s *JobManifestSpec) validate(kind string) *errors.Err {
+	switch true {
+	case s.Completions != nil && *s.Completions < 1:
+		return errors.New(kind).BadParameter("completions")
+	case s.Parallelism != nil && *s.Parallelism < 1:
+		return errors.New(kind).BadParameter("parallelism")
+	case s.BackoffLimit != nil && *s.BackoffLimit < 0:
+		return errors.New(kind).BadParameter("backoff_limit")
+	case s.ActiveDeadline != nil && *s.ActiveDeadline < 0:
+		return errors.New(kind).BadParameter("active_deadline")
+	case s.Selector != nil && !s.Selector.ValidPlacement():
+		return errors.New(kind).BadParameter("selector")
+	case s.Template != nil && len(s.Template.Containers) != 0 && !s.Template.ValidRoles():
+		return errors.New(kind).BadParameter("role")
+	case s.Template != nil:
+		for _, container := range s.Template.Containers {
+			if len(container.Image.Name) == 0 {
+				return errors.New(kind).BadParameter("image")
+			}
+			// job pod is completed when containers exit, sidecar is not stopped before pod removal
+			if container.Role == types.ContainerRoleSidecar {
+				return errors.New(kind).BadParameter("role")
+			}
+			if !container.Resources.Valid() {
+				return errors.New(kind).BadParameter("resources")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (j *JobManifest) DecodeAndValidate(reader io.Reader) *errors.Err {
+
+	if reader == nil {
+		err := errors.New("data body can not be null")
+		return errors.New("job").IncorrectJSON(err)
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.New("job").Unknown(err)
+	}
+
+	err = json.Unmarshal(body, j)
+	if err != nil {
+		return errors.New("job").IncorrectJSON(err)
+	}
+
+	return j.Validate()
+}
@@ -25,17 +25,61 @@ import (
 )
 
 type ManifestSpecSelector struct {
-	Node   string            `json:"node,omitempty" yaml:"node,omitempty"`
-	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Node         string                           `json:"node,omitempty" yaml:"node,omitempty"`
+	Labels       map[string]string                `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Affinity     []ManifestSpecSelectorAffinity   `json:"affinity,omitempty" yaml:"affinity,omitempty"`
+	AntiAffinity []ManifestSpecSelectorAffinity   `json:"anti_affinity,omitempty" yaml:"anti_affinity,omitempty"`
+	Spread       []ManifestSpecSelectorSpread     `json:"spread,omitempty" yaml:"spread,omitempty"`
+	Tolerations  []ManifestSpecSelectorToleration `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
+}
+
+type ManifestSpecSelectorAffinity struct {
+	Service  string `json:"service,omitempty" yaml:"service,omitempty"`
+	Topology string `json:"topology,omitempty" yaml:"topology,omitempty"`
+}
+
+type ManifestSpecSelectorSpread struct {
+	Topology string `json:"topology,omitempty" yaml:"topology,omitempty"`
+	MaxSkew  int    `json:"max_skew,omitempty" yaml:"max_skew,omitempty"`
+}
+
+type ManifestSpecSelectorToleration struct {
+	Key      string `json:"key,omitempty" yaml:"key,omitempty"`
+	Operator string `json:"operator,omitempty" yaml:"operator,omitempty"`
+	Value    string `json:"value,omitempty" yaml:"value,omitempty"`
+	Effect   string `json:"effect,omitempty" yaml:"effect,omitempty"`
 }
 
 type ManifestSpecNetwork struct {
 	IP    *string  `json:"ip,omitempty" yaml:"ip,omitempty"`
+	Pool  *string  `json:"pool,omitempty" yaml:"pool,omitempty"`
 	Ports []string `json:"ports,omitempty" yaml:"ports,omitempty"`
 }
 
 type ManifestSpecStrategy struct {
 	Type *string `json:"type,omitempty" yaml:"type,omitempty"`
+	// Rolling update options
+	Rolling *ManifestSpecStrategyRolling `json:"rolling,omitempty" yaml:"rolling,omitempty"`
+	// Rollout deadline in seconds, rollout is marked as failed after it
+	Deadline *int `json:"deadline,omitempty" yaml:"deadline,omitempty"`
+	// Canary release options
+	Canary *ManifestSpecStrategyCanary `json:"canary,omitempty" yaml:"canary,omitempty"`
+	// Count of previous deployments kept for rollback
+	History *int `json:"history,omitempty" yaml:"history,omitempty"`
+}
+
+type ManifestSpecStrategyRolling struct {
+	// Max replicas count created above desired replicas count
+	MaxSurge int `json:"max_surge" yaml:"max_surge"`
+	// Max replicas count unavailable during update
+	MaxUnavailable int `json:"max_unavailable" yaml:"max_unavailable"`
+}
+
+type ManifestSpecStrategyCanary struct {
+	// Canary pods count
+	Replicas int `json:"replicas" yaml:"replicas"`
+	// Percent of traffic sent to canary pods
+	Weight int `json:"weight" yaml:"weight"`
 }
 
 type ManifestSpecTemplate struct {
@@ -45,6 +89,7 @@ type ManifestSpecTemplate struct {
 
 type ManifestSpecTemplateContainer struct {
 	Name          string                                 `json:"name,omitempty" yaml:"name,omitempty"`
+	Role          string                                 `json:"role,omitempty" yaml:"role,omitempty"`
 	Command       string                                 `json:"command,omitempty" yaml:"command,omitempty"`
 	Workdir       string                                 `json:"workdir,omitempty" yaml:"workdir,omitempty"`
 	Entrypoint    string                                 `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`
@@ -55,6 +100,44 @@ type ManifestSpecTemplateContainer struct {
 	Image         ManifestSpecTemplateContainerImage     `json:"image,omitempty" yaml:"image,omitempty"`
 	Resources     ManifestSpecTemplateContainerResources `json:"resources,omitempty" yaml:"resources,omitempty"`
 	RestartPolicy ManifestSpecTemplateRestartPolicy      `json:"restart,omitempty" yaml:"restart,omitempty"`
+	Probes        ManifestSpecTemplateContainerProbes    `json:"probes,omitempty" yaml:"probes,omitempty"`
+}
+
+type ManifestSpecTemplateContainerProbes struct {
+	// Liveness probe: container is restarted according to restart policy on failure
+	Live *ManifestSpecTemplateContainerProbe `json:"live,omitempty" yaml:"live,omitempty"`
+	// Readiness probe: container receives traffic only after probe succeeded
+	Ready *ManifestSpecTemplateContainerProbe `json:"ready,omitempty" yaml:"ready,omitempty"`
+}
+
+type ManifestSpecTemplateContainerProbe struct {
+	// Command executed in container, zero exit code is success
+	Exec []string `json:"exec,omitempty" yaml:"exec,omitempty"`
+	// Container port accepting connections
+	Socket *ManifestSpecTemplateContainerProbeSocket `json:"socket,omitempty" yaml:"socket,omitempty"`
+	// HTTP GET request to container, 2xx-3xx status is success
+	HTTP *ManifestSpecTemplateContainerProbeHTTP `json:"http,omitempty" yaml:"http,omitempty"`
+	// Delay before first probe in seconds
+	InitialDelay int `json:"initial_delay,omitempty" yaml:"initial_delay,omitempty"`
+	// Probe timeout in seconds
+	Timeout int `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// Probe period in seconds
+	Period int `json:"period,omitempty" yaml:"period,omitempty"`
+	// Consecutive successes to treat probe as passed
+	ThresholdSuccess int `json:"threshold_success,omitempty" yaml:"threshold_success,omitempty"`
+	// Consecutive failures to treat probe as failed
+	ThresholdFailure int `json:"threshold_failure,omitempty" yaml:"threshold_failure,omitempty"`
+}
+
+type ManifestSpecTemplateContainerProbeSocket struct {
+	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	Port     int    `json:"port,omitempty" yaml:"port,omitempty"`
+}
+
+type ManifestSpecTemplateContainerProbeHTTP struct {
+	Path    string            `json:"path,omitempty" yaml:"path,omitempty"`
+	Port    int               `json:"port,omitempty" yaml:"port,omitempty"`
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
 }
 
 type ManifestSpecTemplateContainerEnv struct {
@@ -102,6 +185,24 @@ type ManifestSpecTemplateContainerResource struct {
 	RAM int64 `json:"ram,omitempty" yaml:"ram,omitempty"`
 }
 
+// Valid checks that resources are not negative and requests do not exceed limits
+func (m ManifestSpecTemplateContainerResources) Valid() bool {
+
+	if m.Request.CPU < 0 || m.Request.RAM < 0 || m.Limits.CPU < 0 || m.Limits.RAM < 0 {
+		return false
+	}
+
+	if m.Limits.CPU > 0 && m.Request.CPU > m.Limits.CPU {
+		return false
+	}
+
+	if m.Limits.RAM > 0 && m.Request.RAM > m.Limits.RAM {
+		return false
+	}
+
+	return true
+}
+
 type ManifestSpecTemplateVolume struct {
 	// Template volume name
 	Name string `json:"name,omitempty" yaml:"name,omitempty"`
@@ -156,10 +257,176 @@ func (m ManifestSpecSelector) GetSpec() types.SpecSelector {
 
 	s.Node = m.Node
 	s.Labels = m.Labels
+	s.Affinity = m.GetAffinitySpec(m.Affinity)
+	s.AntiAffinity = m.GetAffinitySpec(m.AntiAffinity)
+
+	for _, r := range m.Spread {
+		s.Spread = append(s.Spread, types.SpecSelectorSpread{
+			Topology: r.Topology,
+			MaxSkew:  r.MaxSkew,
+		})
+	}
+
+	for _, t := range m.Tolerations {
+		s.Tolerations = append(s.Tolerations, types.SpecSelectorToleration{
+			Key:      t.Key,
+			Operator: t.Operator,
+			Value:    t.Value,
+			Effect:   t.Effect,
+		})
+	}
+
+	return s
+}
+
+func (m ManifestSpecSelector) GetAffinitySpec(rules []ManifestSpecSelectorAffinity) []types.SpecSelectorAffinity {
+
+	var s []types.SpecSelectorAffinity
+
+	for _, r := range rules {
+		s = append(s, types.SpecSelectorAffinity{
+			Service:  r.Service,
+			Topology: r.Topology,
+		})
+	}
 
 	return s
 }
 
+// ValidPlacement checks that spread rules have topology and skew is not negative
+// and tolerations have known operator and effect
+func (m ManifestSpecSelector) ValidPlacement() bool {
+	for _, r := range m.Spread {
+		if r.Topology == types.EmptyString || r.MaxSkew < 0 {
+			return false
+		}
+	}
+
+	for _, t := range m.Tolerations {
+		switch t.Operator {
+		case types.EmptyString, types.SpecSelectorTolerationOperatorEqual:
+			if t.Key == types.EmptyString {
+				return false
+			}
+		case types.SpecSelectorTolerationOperatorExists:
+			if t.Value != types.EmptyString {
+				return false
+			}
+		default:
+			return false
+		}
+
+		switch t.Effect {
+		case types.EmptyString, types.NodeTaintEffectNoSchedule, types.NodeTaintEffectNoExecute:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// EqualPlacement checks if pods placement rules are the same as in spec
+func (m ManifestSpecSelector) EqualPlacement(spec types.SpecSelector) bool {
+
+	var s = m.GetSpec()
+
+	if len(s.Affinity) != len(spec.Affinity) ||
+		len(s.AntiAffinity) != len(spec.AntiAffinity) ||
+		len(s.Spread) != len(spec.Spread) ||
+		len(s.Tolerations) != len(spec.Tolerations) {
+		return false
+	}
+
+	for i := range s.Affinity {
+		if s.Affinity[i] != spec.Affinity[i] {
+			return false
+		}
+	}
+
+	for i := range s.AntiAffinity {
+		if s.AntiAffinity[i] != spec.AntiAffinity[i] {
+			return false
+		}
+	}
+
+	for i := range s.Spread {
+		if s.Spread[i] != spec.Spread[i] {
+			return false
+		}
+	}
+
+	for i := range s.Tolerations {
+		if s.Tolerations[i] != spec.Tolerations[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Valid checks strategy type, canary weight percent and that rolling options, deadline and history are not negative
+func (m ManifestSpecStrategy) Valid() bool {
+
+	if m.Type != nil {
+		switch *m.Type {
+		case types.EmptyString, types.SpecStrategyTypeRolling, types.SpecStrategyTypeRecreate,
+			types.SpecStrategyTypeCanary, types.SpecStrategyTypeBlueGreen:
+		default:
+			return false
+		}
+	}
+
+	if m.Rolling != nil && (m.Rolling.MaxSurge < 0 || m.Rolling.MaxUnavailable < 0) {
+		return false
+	}
+
+	if m.Canary != nil && (m.Canary.Replicas < 0 || m.Canary.Weight < 0 || m.Canary.Weight > 100) {
+		return false
+	}
+
+	if m.History != nil && *m.History < 0 {
+		return false
+	}
+
+	return m.Deadline == nil || *m.Deadline >= 0
+}
+
+// ValidOrdered checks that strategy can be used by daemon and stateful services:
+// their pods are replaced by rolling update one pod at a time
+func (m ManifestSpecStrategy) ValidOrdered() bool {
+
+	if m.Type == nil {
+		return true
+	}
+
+	switch *m.Type {
+	case types.SpecStrategyTypeCanary, types.SpecStrategyTypeBlueGreen:
+		return false
+	}
+
+	return true
+}
+
+// ValidRoles checks that containers roles are known and template has app container:
+// pod can not consist of init containers and sidecars only
+func (m ManifestSpecTemplate) ValidRoles() bool {
+
+	var app bool
+
+	for _, c := range m.Containers {
+		switch c.Role {
+		case types.ContainerRoleInit, types.ContainerRoleSidecar:
+		case types.EmptyString, types.ContainerRolePrimary, types.ContainerRoleSlave:
+			app = true
+		default:
+			return false
+		}
+	}
+
+	return app
+}
+
 func (m ManifestSpecTemplate) GetSpec() types.SpecTemplate {
 	var s = types.SpecTemplate{}
 
@@ -214,6 +481,7 @@ func (m ManifestSpecTemplateVolume) GetSpec() types.SpecTemplateVolume {
 func (m ManifestSpecTemplateContainer) GetSpec() types.SpecTemplateContainer {
 	s := types.SpecTemplateContainer{}
 	s.Name = m.Name
+	s.Role = m.Role
 
 	s.RestartPolicy.Policy = m.RestartPolicy.Policy
 	s.RestartPolicy.Attempt = m.RestartPolicy.Attempt
@@ -262,5 +530,74 @@ func (m ManifestSpecTemplateContainer) GetSpec() types.SpecTemplateContainer {
 		})
 	}
 
+	if m.Probes.Live != nil {
+		s.Probes.LiveProbe = m.Probes.Live.GetSpec()
+	}
+
+	if m.Probes.Ready != nil {
+		s.Probes.ReadProbe = m.Probes.Ready.GetSpec()
+	}
+
 	return s
 }
+
+func (m ManifestSpecTemplateContainerProbe) GetSpec() types.SpecTemplateContainerProbe {
+	s := types.SpecTemplateContainerProbe{}
+
+	s.Exec.Command = m.Exec
+
+	if m.Socket != nil {
+		s.Socket.Protocol = m.Socket.Protocol
+		s.Socket.Port = m.Socket.Port
+	}
+
+	if m.HTTP != nil {
+		s.HTTP.Path = m.HTTP.Path
+		s.HTTP.Port = m.HTTP.Port
+		s.HTTP.Headers = m.HTTP.Headers
+	}
+
+	s.InitialDelaySeconds = m.InitialDelay
+	s.TimeoutSeconds = m.Timeout
+	s.PeriodSeconds = m.Period
+	s.ThresholdSuccess = m.ThresholdSuccess
+	s.ThresholdFailure = m.ThresholdFailure
+
+	return s
+}
+
+// Valid checks that probe has exactly one check with valid port and non negative timings
+func (m ManifestSpecTemplateContainerProbe) Valid() bool {
+
+	var checks int
+
+	if len(m.Exec) > 0 {
+		checks++
+	}
+
+	if m.Socket != nil {
+		if m.Socket.Port <= 0 || m.Socket.Port > 65535 {
+			return false
+		}
+		switch strings.ToLower(m.Socket.Protocol) {
+		case types.EmptyString, "tcp", "udp":
+		default:
+			return false
+		}
+		checks++
+	}
+
+	if m.HTTP != nil {
+		if m.HTTP.Port <= 0 || m.HTTP.Port > 65535 {
+			return false
+		}
+		checks++
+	}
+
+	if checks != 1 {
+		return false
+	}
+
+	return m.InitialDelay >= 0 && m.Timeout >= 0 && m.Period >= 0 &&
+		m.ThresholdSuccess >= 0 && m.ThresholdFailure >= 0
+}
@@ -42,5 +42,9 @@ type NamespaceRemoveOptions struct {
 type NamespaceQuotasOptions struct {
 	Disabled bool  `json:"disabled"`
 	RAM      int64 `json:"ram"`
+	CPU      int64 `json:"cpu"`
+	Pods     int   `json:"pods"`
+	Volumes  int   `json:"volumes"`
+	Storage  int64 `json:"storage"`
 	Routes   int   `json:"routes"`
 }
@@ -45,7 +45,8 @@ func (n *NamespaceCreateOptions) Validate() *errors.Err {
 		return errors.New("namespace").BadParameter("name")
 	case len(n.Description) > DEFAULT_DESCRIPTION_LIMIT:
 		return errors.New("namespace").BadParameter("description")
-		// TODO: check quotas data
+	case n.Quotas != nil && !n.Quotas.Valid():
+		return errors.New("namespace").BadParameter("quotas")
 	}
 	return nil
 }
@@ -80,6 +81,10 @@ func (n *NamespaceCreateOptions) DecodeAndValidate(reader io.Reader) (*types.Nam
 		opts.Quotas = new(types.NamespaceQuotasOptions)
 		opts.Quotas.Routes = n.Quotas.Routes
 		opts.Quotas.RAM = n.Quotas.RAM
+		opts.Quotas.CPU = n.Quotas.CPU
+		opts.Quotas.Pods = n.Quotas.Pods
+		opts.Quotas.Volumes = n.Quotas.Volumes
+		opts.Quotas.Storage = n.Quotas.Storage
 		opts.Quotas.Disabled = n.Quotas.Disabled
 	}
 
@@ -98,7 +103,8 @@ func (n *NamespaceUpdateOptions) Validate() *errors.Err {
 	switch true {
 	case n.Description != nil && len(*n.Description) > DEFAULT_DESCRIPTION_LIMIT:
 		return errors.New("namespace").BadParameter("description")
-		// TODO: check quotas data
+	case n.Quotas != nil && !n.Quotas.Valid():
+		return errors.New("namespace").BadParameter("quotas")
 	}
 	return nil
 }
@@ -131,6 +137,10 @@ func (n *NamespaceUpdateOptions) DecodeAndValidate(reader io.Reader) (*types.Nam
 		opts.Quotas = new(types.NamespaceQuotasOptions)
 		opts.Quotas.Routes = n.Quotas.Routes
 		opts.Quotas.RAM = n.Quotas.RAM
+		opts.Quotas.CPU = n.Quotas.CPU
+		opts.Quotas.Pods = n.Quotas.Pods
+		opts.Quotas.Volumes = n.Quotas.Volumes
+		opts.Quotas.Storage = n.Quotas.Storage
 		opts.Quotas.Disabled = n.Quotas.Disabled
 	}
 
@@ -152,3 +162,9 @@ func (n *NamespaceRemoveOptions) Validate() *errors.Err {
 func (n *NamespaceRemoveOptions) ToJson() ([]byte, error) {
 	return json.Marshal(n)
 }
+
+// Valid checks that quotas limits are not negative
+func (q *NamespaceQuotasOptions) Valid() bool {
+	return q.RAM >= 0 && q.CPU >= 0 && q.Pods >= 0 &&
+		q.Volumes >= 0 && q.Storage >= 0 && q.Routes >= 0
+}
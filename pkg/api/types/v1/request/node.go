@@ -30,6 +30,7 @@ type NodeConnectOptions struct {
 	Info    types.NodeInfo     `json:"info"`
 	Status  types.NodeStatus   `json:"status"`
 	Network types.NetworkState `json:"network"`
+	Port    uint16             `json:"port"`
 	TLS     bool               `json:"tls"`
 	SSL     *SSL               `json:"ssl"`
 }
@@ -70,12 +71,16 @@ type NodePodStatusOptions struct {
 	Running bool `json:"running" yaml:"running"`
 	// Pod state message
 	Message string `json:"message" yaml:"message"`
+	// Pod ready to serve traffic
+	Ready bool `json:"ready" yaml:"ready"`
 	// Pod steps
 	Steps types.PodSteps `json:"steps" yaml:"steps"`
 	// Pod network
 	Network types.PodNetwork `json:"network" yaml:"network"`
 	// Pod containers
 	Containers map[string]*types.PodContainer `json:"containers" yaml:"containers"`
+	// Pod resources usage
+	Usage types.PodUsage `json:"usage" yaml:"usage"`
 }
 
 // swagger:model request_node_volume_status
@@ -94,6 +99,12 @@ type NodeRouteStatusOptions struct {
 	Message string `json:"message" yaml:"message"`
 }
 
+// swagger:model request_node_taints
+type NodeTaintsOptions struct {
+	// Node taints, drain taint is managed by drain and uncordon requests
+	Taints []types.NodeTaint `json:"taints"`
+}
+
 // swagger:ignore
 // swagger:model request_node_remove
 type NodeRemoveOptions struct {
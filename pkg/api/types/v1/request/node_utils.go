@@ -24,6 +24,7 @@ import (
 	"io/ioutil"
 
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 )
 
 type NodeRequest struct{}
@@ -229,6 +230,49 @@ func (n *NodeMetaOptions) DecodeAndValidate(reader io.Reader) *errors.Err {
 	return n.Validate()
 }
 
+func (NodeRequest) TaintsOptions() *NodeTaintsOptions {
+	return new(NodeTaintsOptions)
+}
+
+func (s *NodeTaintsOptions) ToJson() string {
+	buf, _ := json.Marshal(s)
+	return string(buf)
+}
+
+func (n *NodeTaintsOptions) Validate() *errors.Err {
+	for _, t := range n.Taints {
+		switch true {
+		case t.Key == types.EmptyString:
+			return errors.New("node").BadParameter("key")
+		case t.Key == types.NodeTaintDrain:
+			return errors.New("node").BadParameter("key")
+		case t.Effect != types.NodeTaintEffectNoSchedule && t.Effect != types.NodeTaintEffectNoExecute:
+			return errors.New("node").BadParameter("effect")
+		}
+	}
+	return nil
+}
+
+func (n *NodeTaintsOptions) DecodeAndValidate(reader io.Reader) *errors.Err {
+
+	if reader == nil {
+		err := errors.New("data body can not be null")
+		return errors.New("node").IncorrectJSON(err)
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.New("node").Unknown(err)
+	}
+
+	err = json.Unmarshal(body, n)
+	if err != nil {
+		return errors.New("node").IncorrectJSON(err)
+	}
+
+	return n.Validate()
+}
+
 func (NodeRequest) RemoveOptions() *NodeRemoveOptions {
 	return new(NodeRemoveOptions)
 }
@@ -84,6 +84,14 @@ func (s *PodManifest) SetPodSpec(pod *types.Pod) {
 			pod.Spec.Selector.Labels = s.Spec.Selector.Labels
 		}
 
+		if !s.Spec.Selector.EqualPlacement(pod.Spec.Selector) {
+			spec := s.Spec.Selector.GetSpec()
+			pod.Spec.Selector.Affinity = spec.Affinity
+			pod.Spec.Selector.AntiAffinity = spec.AntiAffinity
+			pod.Spec.Selector.Spread = spec.Spread
+			pod.Spec.Selector.Tolerations = spec.Tolerations
+		}
+
 	}
 
 	if s.Spec.Template != nil {
@@ -402,3 +410,26 @@ type PodLogsOptions struct {
 	Container string `json:"container"`
 	Follow    bool   `json:"follow"`
 }
+
+type PodExecOptions struct {
+	Container string   `json:"container"`
+	Command   []string `json:"command"`
+	TTY       bool     `json:"tty"`
+	Stdin     bool     `json:"stdin"`
+}
+
+type PodAttachOptions struct {
+	Container string `json:"container"`
+	Stdin     bool   `json:"stdin"`
+}
+
+type PodPortForwardOptions struct {
+	// Local address to listen, 127.0.0.1 is used by default
+	Address string               `json:"address"`
+	Ports   []PodPortForwardPort `json:"ports"`
+}
+
+type PodPortForwardPort struct {
+	Local  uint16 `json:"local"`
+	Remote uint16 `json:"remote"`
+}
@@ -36,9 +36,18 @@ type RouteManifestMeta struct {
 // swagger:model request_route_create
 type RouteManifestSpec struct {
 	Port     uint16                         `json:"port" yaml:"port"`
+	TLS      *RouteManifestSpecTLSOption    `json:"tls,omitempty" yaml:"tls,omitempty"`
 	Rules    []RouteManifestSpecRulesOption `json:"rules" yaml:"rules"`
 }
 
+// swagger:model request_route_tls
+type RouteManifestSpecTLSOption struct {
+	// Name of secret with certificate and key
+	Secret string `json:"secret" yaml:"secret"`
+	// Redirect http requests to https
+	Redirect bool `json:"redirect" yaml:"redirect"`
+}
+
 // swagger:ignore
 // swagger:model request_route_remove
 type RouteRemoveOptions struct {
@@ -95,6 +104,13 @@ func (r *RouteManifest) SetRouteSpec(route *types.Route, svc *types.ServiceList)
 		route.Spec.Port = r.Spec.Port
 	}
 
+	route.Spec.TLS = types.RouteTLS{}
+	if r.Spec.TLS != nil {
+		route.Spec.TLS.Secret = r.Spec.TLS.Secret
+		route.Spec.TLS.Redirect = r.Spec.TLS.Redirect
+	}
+	route.Spec.Security = route.Spec.TLS.Secret != types.EmptyString
+
 	route.Spec.Rules = make([]types.RouteRule, 0)
 	for _, rs := range r.Spec.Rules {
 
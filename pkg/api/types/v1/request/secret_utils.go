@@ -24,6 +24,7 @@ import (
 	"io/ioutil"
 
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 )
 
 type SecretRequest struct{}
@@ -33,6 +34,27 @@ func (SecretRequest) Manifest() *SecretManifest {
 }
 
 func (v *SecretManifest) Validate() *errors.Err {
+
+	if v.Spec.Type != types.KindSecretTLS {
+		return nil
+	}
+
+	data := types.SecretTLSData{
+		Certificate: v.Spec.Data[types.SecretTLSCertificateKey],
+		Key:         v.Spec.Data[types.SecretTLSPrivateKey],
+	}
+
+	switch true {
+	case len(data.Certificate) == 0:
+		return errors.New("secret").BadParameter(types.SecretTLSCertificateKey)
+	case len(data.Key) == 0:
+		return errors.New("secret").BadParameter(types.SecretTLSPrivateKey)
+	}
+
+	if _, err := data.Parse(); err != nil {
+		return errors.New("secret").BadParameter(types.SecretTLSCertificateKey, err)
+	}
+
 	return nil
 }
 
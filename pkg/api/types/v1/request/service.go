@@ -21,6 +21,7 @@ package request
 import (
 	"encoding/json"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/util/resource"
 	"gopkg.in/yaml.v2"
 	"strconv"
 	"strings"
@@ -37,11 +38,21 @@ type ServiceManifestMeta struct {
 }
 
 type ServiceManifestSpec struct {
+	// Service mode: replicated, daemon or stateful
+	Mode     *string               `json:"mode,omitempty" yaml:"mode,omitempty"`
 	Replicas *int                  `json:"replicas,omitempty" yaml:"replicas,omitempty"`
 	Selector *ManifestSpecSelector `json:"selector,omitempty" yaml:"selector,omitempty"`
 	Network  *ManifestSpecNetwork  `json:"network,omitempty" yaml:"network,omitempty"`
 	Strategy *ManifestSpecStrategy `json:"strategy,omitempty" yaml:"strategy,omitempty"`
 	Template *ManifestSpecTemplate `json:"template,omitempty" yaml:"template,omitempty"`
+	// Stateful service volume claims
+	VolumeClaims []ServiceManifestSpecVolumeClaim `json:"volume_claims,omitempty" yaml:"volume_claims,omitempty"`
+}
+
+type ServiceManifestSpecVolumeClaim struct {
+	// Claim name used as persistent volume name in template volumes
+	Name     string                     `json:"name" yaml:"name"`
+	Capacity VolumeManifestSpecCapacity `json:"capacity" yaml:"capacity"`
 }
 
 func (s *ServiceManifest) FromJson(data []byte) error {
@@ -76,6 +87,20 @@ func (s *ServiceManifest) SetServiceMeta(svc *types.Service) {
 
 }
 
+// GetVolumeClaims returns stateful service volume claims spec
+func (s ServiceManifestSpec) GetVolumeClaims() []types.ServiceVolumeClaim {
+
+	claims := make([]types.ServiceVolumeClaim, 0)
+
+	for _, c := range s.VolumeClaims {
+		claim := types.ServiceVolumeClaim{Name: c.Name}
+		claim.Capacity.Storage, _ = resource.DecodeResource(c.Capacity.Storage)
+		claims = append(claims, claim)
+	}
+
+	return claims
+}
+
 func (s *ServiceManifest) SetServiceSpec(svc *types.Service) {
 
 	tn := svc.Spec.Network.Updated
@@ -93,16 +118,28 @@ func (s *ServiceManifest) SetServiceSpec(svc *types.Service) {
 		}
 	}()
 
+	if s.Spec.Mode != nil {
+		svc.Spec.Mode = *s.Spec.Mode
+	}
+
 	if s.Spec.Replicas != nil {
 		svc.Spec.Replicas = *s.Spec.Replicas
 	}
 
+	if s.Spec.VolumeClaims != nil {
+		svc.Spec.VolumeClaims = s.Spec.GetVolumeClaims()
+	}
+
 	if s.Spec.Network != nil {
 
 		if s.Spec.Network.IP != nil {
 			svc.Spec.Network.IP = *s.Spec.Network.IP
 		}
 
+		if s.Spec.Network.Pool != nil {
+			svc.Spec.Network.Pool = *s.Spec.Network.Pool
+		}
+
 		if len(s.Spec.Network.Ports) > 0 {
 
 			svc.Spec.Network.Ports = make(map[uint16]string, 0)
@@ -158,12 +195,39 @@ func (s *ServiceManifest) SetServiceSpec(svc *types.Service) {
 			svc.Spec.Selector.Updated = time.Now()
 		}
 
+		if !s.Spec.Selector.EqualPlacement(svc.Spec.Selector) {
+			spec := s.Spec.Selector.GetSpec()
+			svc.Spec.Selector.Affinity = spec.Affinity
+			svc.Spec.Selector.AntiAffinity = spec.AntiAffinity
+			svc.Spec.Selector.Spread = spec.Spread
+			svc.Spec.Selector.Tolerations = spec.Tolerations
+			svc.Spec.Selector.Updated = time.Now()
+		}
+
 	}
 
 	if s.Spec.Strategy != nil {
 		if s.Spec.Strategy.Type != nil {
 			svc.Spec.Strategy.Type = *s.Spec.Strategy.Type
 		}
+
+		if s.Spec.Strategy.Rolling != nil {
+			svc.Spec.Strategy.RollingOptions.MaxSurge = s.Spec.Strategy.Rolling.MaxSurge
+			svc.Spec.Strategy.RollingOptions.MaxUnavailable = s.Spec.Strategy.Rolling.MaxUnavailable
+		}
+
+		if s.Spec.Strategy.Deadline != nil {
+			svc.Spec.Strategy.Deadline = *s.Spec.Strategy.Deadline
+		}
+
+		if s.Spec.Strategy.Canary != nil {
+			svc.Spec.Strategy.CanaryOptions.Replicas = s.Spec.Strategy.Canary.Replicas
+			svc.Spec.Strategy.CanaryOptions.Weight = s.Spec.Strategy.Canary.Weight
+		}
+
+		if s.Spec.Strategy.History != nil {
+			svc.Spec.Strategy.History = *s.Spec.Strategy.History
+		}
 	}
 
 	if s.Spec.Template != nil {
@@ -191,6 +255,11 @@ func (s *ServiceManifest) SetServiceSpec(svc *types.Service) {
 				svc.Spec.Template.Updated = time.Now()
 			}
 
+			if spec.Role != c.Role {
+				spec.Role = c.Role
+				svc.Spec.Template.Updated = time.Now()
+			}
+
 			if spec.Image.Name != c.Image.Name {
 				spec.Image.Name = c.Image.Name
 				svc.Spec.Template.Updated = time.Now()
@@ -293,6 +362,12 @@ func (s *ServiceManifest) SetServiceSpec(svc *types.Service) {
 				svc.Spec.Template.Updated = time.Now()
 			}
 
+			probes := c.GetSpec().Probes
+			if !spec.Probes.LiveProbe.Equal(probes.LiveProbe) || !spec.Probes.ReadProbe.Equal(probes.ReadProbe) {
+				spec.Probes = probes
+				svc.Spec.Template.Updated = time.Now()
+			}
+
 			for _, v := range c.Volumes {
 
 				var f = false
@@ -24,6 +24,8 @@ import (
 	"io/ioutil"
 
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/util/resource"
 	"github.com/lastbackend/lastbackend/pkg/util/validator"
 )
 
@@ -39,19 +41,71 @@ func (s *ServiceManifest) Validate() *errors.Err {
 		return errors.New("service").BadParameter("name")
 	case s.Meta.Description != nil && len(*s.Meta.Description) > DEFAULT_DESCRIPTION_LIMIT:
 		return errors.New("service").BadParameter("description")
+	case s.Spec.Selector != nil && !s.Spec.Selector.ValidPlacement():
+		return errors.New("service").BadParameter("selector")
+	case s.Spec.Mode != nil && !validServiceMode(*s.Spec.Mode):
+		return errors.New("service").BadParameter("mode")
+	case s.Spec.Strategy != nil && !s.Spec.Strategy.Valid():
+		return errors.New("service").BadParameter("strategy")
+	case s.Spec.Mode != nil && (*s.Spec.Mode == types.ServiceModeDaemon || *s.Spec.Mode == types.ServiceModeStateful) &&
+		s.Spec.Strategy != nil && !s.Spec.Strategy.ValidOrdered():
+		return errors.New("service").BadParameter("strategy")
+	case !validServiceVolumeClaims(s.Spec.VolumeClaims):
+		return errors.New("service").BadParameter("volume_claims")
 	case len(s.Spec.Template.Containers) == 0:
 		return errors.New("service").BadParameter("spec")
+	case !s.Spec.Template.ValidRoles():
+		return errors.New("service").BadParameter("role")
 	case len(s.Spec.Template.Containers) != 0:
 		for _, container := range s.Spec.Template.Containers {
 			if len(container.Image.Name) == 0 {
 				return errors.New("service").BadParameter("image")
 			}
+			if !container.Resources.Valid() {
+				return errors.New("service").BadParameter("resources")
+			}
+			if container.Probes.Live != nil && !container.Probes.Live.Valid() {
+				return errors.New("service").BadParameter("probes")
+			}
+			if container.Probes.Ready != nil && !container.Probes.Ready.Valid() {
+				return errors.New("service").BadParameter("probes")
+			}
 		}
 	}
 
 	return nil
 }
 
+// validServiceMode checks that service mode is supported
+func validServiceMode(mode string) bool {
+	switch mode {
+	case types.EmptyString, types.ServiceModeReplicated, types.ServiceModeDaemon, types.ServiceModeStateful:
+		return true
+	}
+	return false
+}
+
+// validServiceVolumeClaims checks that claims have unique names and storage capacity
+func validServiceVolumeClaims(claims []ServiceManifestSpecVolumeClaim) bool {
+
+	var names = make(map[string]bool)
+
+	for _, c := range claims {
+
+		if c.Name == types.EmptyString || names[c.Name] {
+			return false
+		}
+		names[c.Name] = true
+
+		storage, err := resource.DecodeResource(c.Capacity.Storage)
+		if err != nil || storage <= 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (s *ServiceManifest) DecodeAndValidate(reader io.Reader) *errors.Err {
 
 	if reader == nil {
@@ -32,8 +32,13 @@ const (
 )
 
 type IRequest interface {
+	Account() *AccountRequest
+	Autoscaler() *AutoscalerRequest
+	Certificate() *CertificateRequest
 	Cluster() *ClusterRequest
+	CronJob() *CronJobRequest
 	Deployment() *DeploymentRequest
+	Job() *JobRequest
 	Namespace() *NamespaceRequest
 	Node() *NodeRequest
 	Endpoint() *EndpointRequest
@@ -48,12 +53,28 @@ type IRequest interface {
 
 type Request struct{}
 
+func (Request) Account() *AccountRequest {
+	return new(AccountRequest)
+}
+func (Request) Autoscaler() *AutoscalerRequest {
+	return new(AutoscalerRequest)
+}
+func (Request) Certificate() *CertificateRequest {
+	return new(CertificateRequest)
+}
+
 func (Request) Cluster() *ClusterRequest {
 	return new(ClusterRequest)
 }
+func (Request) CronJob() *CronJobRequest {
+	return new(CronJobRequest)
+}
 func (Request) Deployment() *DeploymentRequest {
 	return new(DeploymentRequest)
 }
+func (Request) Job() *JobRequest {
+	return new(JobRequest)
+}
 func (Request) Namespace() *NamespaceRequest {
 	return new(NamespaceRequest)
 }
@@ -0,0 +1,60 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import (
+	"time"
+)
+
+// swagger:model views_account
+type Account struct {
+	Meta AccountMeta `json:"meta"`
+	Spec AccountSpec `json:"spec"`
+}
+
+// swagger:model views_account_meta
+type AccountMeta struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	SelfLink    string    `json:"self_link"`
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+}
+
+// swagger:model views_account_spec
+type AccountSpec struct {
+	Kind   string            `json:"kind"`
+	Roles  map[string]string `json:"roles"`
+	Tokens AccountTokenList  `json:"tokens"`
+}
+
+// AccountToken represents issued token, token value is shown only once on issue
+//
+// swagger:model views_account_token
+type AccountToken struct {
+	ID      string    `json:"id"`
+	Token   string    `json:"token,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// swagger:model views_account_token_list
+type AccountTokenList []*AccountToken
+
+// swagger:model views_account_list
+type AccountList []*Account
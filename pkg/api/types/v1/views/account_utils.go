@@ -0,0 +1,102 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+type AccountView struct{}
+
+func (av *AccountView) New(obj *types.Account) *Account {
+	a := Account{}
+	a.Meta = a.ToMeta(obj.Meta)
+	a.Spec = a.ToSpec(obj.Spec)
+	return &a
+}
+
+func (a *Account) ToMeta(obj types.AccountMeta) AccountMeta {
+	meta := AccountMeta{}
+	meta.Name = obj.Name
+	meta.Description = obj.Description
+	meta.SelfLink = obj.SelfLink
+	meta.Created = obj.Created
+	meta.Updated = obj.Updated
+	return meta
+}
+
+func (a *Account) ToSpec(obj types.AccountSpec) AccountSpec {
+
+	spec := AccountSpec{}
+	spec.Kind = obj.Kind
+	spec.Roles = make(map[string]string, 0)
+	spec.Tokens = make(AccountTokenList, 0)
+
+	for ns, role := range obj.Roles {
+		spec.Roles[ns] = role
+	}
+
+	for _, t := range obj.Tokens {
+		spec.Tokens = append(spec.Tokens, &AccountToken{ID: t.ID, Created: t.Created})
+	}
+
+	sort.Slice(spec.Tokens, func(i, j int) bool {
+		return spec.Tokens[i].Created.Before(spec.Tokens[j].Created)
+	})
+
+	return spec
+}
+
+func (a *Account) ToJson() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+func (av *AccountView) NewList(obj *types.AccountList) *AccountList {
+	if obj == nil {
+		return nil
+	}
+
+	al := make(AccountList, 0)
+	for _, v := range obj.Items {
+		al = append(al, av.New(v))
+	}
+	return &al
+}
+
+func (al *AccountList) ToJson() ([]byte, error) {
+	if al == nil {
+		al = &AccountList{}
+	}
+	return json.Marshal(al)
+}
+
+func (av *AccountView) NewToken(obj *types.AccountToken, token string) *AccountToken {
+	return &AccountToken{
+		ID:      obj.ID,
+		Token:   token,
+		Created: obj.Created,
+	}
+}
+
+func (t *AccountToken) ToJson() ([]byte, error) {
+	return json.Marshal(t)
+}
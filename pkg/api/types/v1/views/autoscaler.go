@@ -0,0 +1,77 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import "time"
+
+// swagger:model views_autoscaler
+type Autoscaler struct {
+	Meta   AutoscalerMeta   `json:"meta"`
+	Spec   AutoscalerSpec   `json:"spec"`
+	Status AutoscalerStatus `json:"status"`
+}
+
+// swagger:model views_autoscaler_meta
+type AutoscalerMeta struct {
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace"`
+	Service   string    `json:"service"`
+	SelfLink  string    `json:"self_link"`
+	Updated   time.Time `json:"updated"`
+	Created   time.Time `json:"created"`
+}
+
+// swagger:model views_autoscaler_spec
+type AutoscalerSpec struct {
+	Disabled    bool             `json:"disabled"`
+	MinReplicas int              `json:"min_replicas"`
+	MaxReplicas int              `json:"max_replicas"`
+	Target      AutoscalerTarget `json:"target"`
+	Window      AutoscalerWindow `json:"window"`
+}
+
+// swagger:model views_autoscaler_target
+type AutoscalerTarget struct {
+	CPU int `json:"cpu"`
+	RAM int `json:"ram"`
+}
+
+// swagger:model views_autoscaler_window
+type AutoscalerWindow struct {
+	Upscale   int `json:"upscale"`
+	Downscale int `json:"downscale"`
+}
+
+// swagger:model views_autoscaler_status
+type AutoscalerStatus struct {
+	Replicas int             `json:"replicas"`
+	Desired  int             `json:"desired"`
+	Usage    AutoscalerUsage `json:"usage"`
+	Message  string          `json:"message"`
+	Scaled   time.Time       `json:"scaled"`
+}
+
+// swagger:model views_autoscaler_usage
+type AutoscalerUsage struct {
+	CPU int `json:"cpu"`
+	RAM int `json:"ram"`
+}
+
+// swagger:model views_autoscaler_list
+type AutoscalerList []*Autoscaler
@@ -0,0 +1,92 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import (
+	"encoding/json"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+type AutoscalerView struct{}
+
+func (av *AutoscalerView) New(obj *types.Autoscaler) *Autoscaler {
+	a := Autoscaler{}
+	a.Meta = a.ToMeta(obj.Meta)
+	a.Spec = a.ToSpec(obj.Spec)
+	a.Status = a.ToStatus(obj.Status)
+	return &a
+}
+
+func (a *Autoscaler) ToJson() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+func (a *Autoscaler) ToMeta(obj types.AutoscalerMeta) AutoscalerMeta {
+	meta := AutoscalerMeta{}
+	meta.Name = obj.Name
+	meta.Namespace = obj.Namespace
+	meta.Service = obj.Service
+	meta.SelfLink = obj.SelfLink
+	meta.Updated = obj.Updated
+	meta.Created = obj.Created
+	return meta
+}
+
+func (a *Autoscaler) ToSpec(obj types.AutoscalerSpec) AutoscalerSpec {
+	spec := AutoscalerSpec{}
+	spec.Disabled = obj.Disabled
+	spec.MinReplicas = obj.MinReplicas
+	spec.MaxReplicas = obj.MaxReplicas
+	spec.Target.CPU = obj.Target.CPU
+	spec.Target.RAM = obj.Target.RAM
+	spec.Window.Upscale = obj.Window.Upscale
+	spec.Window.Downscale = obj.Window.Downscale
+	return spec
+}
+
+func (a *Autoscaler) ToStatus(obj types.AutoscalerStatus) AutoscalerStatus {
+	status := AutoscalerStatus{}
+	status.Replicas = obj.Replicas
+	status.Desired = obj.Desired
+	status.Usage.CPU = obj.Usage.CPU
+	status.Usage.RAM = obj.Usage.RAM
+	status.Message = obj.Message
+	status.Scaled = obj.Scaled
+	return status
+}
+
+func (av AutoscalerView) NewList(obj *types.AutoscalerList) *AutoscalerList {
+	if obj == nil {
+		return nil
+	}
+
+	al := make(AutoscalerList, 0)
+	for _, v := range obj.Items {
+		al = append(al, av.New(v))
+	}
+	return &al
+}
+
+func (al *AutoscalerList) ToJson() ([]byte, error) {
+	if al == nil {
+		al = &AutoscalerList{}
+	}
+	return json.Marshal(al)
+}
@@ -0,0 +1,42 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import "time"
+
+// CertificateJoinToken represents created join token, token value is shown only once
+//
+// swagger:model views_certificate_join_token
+type CertificateJoinToken struct {
+	Token   string    `json:"token"`
+	Kind    string    `json:"kind"`
+	Name    string    `json:"name"`
+	Expires time.Time `json:"expires"`
+}
+
+// Certificate represents issued agent client certificate
+//
+// swagger:model views_certificate
+type Certificate struct {
+	// PEM-encoded client certificate
+	Certificate string `json:"certificate"`
+	// PEM-encoded cluster authority certificate
+	CA      string    `json:"ca"`
+	Expires time.Time `json:"expires"`
+}
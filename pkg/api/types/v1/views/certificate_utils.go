@@ -0,0 +1,53 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+type CertificateView struct{}
+
+func (cv *CertificateView) NewJoinToken(obj *types.JoinToken, token string) *CertificateJoinToken {
+	return &CertificateJoinToken{
+		Token:   token,
+		Kind:    obj.Kind,
+		Name:    obj.Name,
+		Expires: obj.Expires,
+	}
+}
+
+func (t *CertificateJoinToken) ToJson() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+func (cv *CertificateView) New(cert, ca []byte, expires time.Time) *Certificate {
+	return &Certificate{
+		Certificate: string(cert),
+		CA:          string(ca),
+		Expires:     expires,
+	}
+}
+
+func (c *Certificate) ToJson() ([]byte, error) {
+	return json.Marshal(c)
+}
@@ -213,6 +213,7 @@ func (cv *ContainerView) NewPodContainer(c *types.PodContainer) PodContainer {
 		ID:      c.ID,
 		Pod:     c.Pod,
 		Name:    c.Name,
+		Role:    c.Role,
 		Ready:   c.Ready,
 	}
 
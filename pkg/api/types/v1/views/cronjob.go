@@ -0,0 +1,69 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import (
+	"time"
+)
+
+// swagger:model views_cronjob
+type CronJob struct {
+	Meta   CronJobMeta   `json:"meta"`
+	Spec   CronJobSpec   `json:"spec"`
+	Status CronJobStatus `json:"status"`
+	Jobs   JobList       `json:"jobs"`
+}
+
+// swagger:model views_cronjob_meta
+type CronJobMeta struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Namespace   string            `json:"namespace"`
+	SelfLink    string            `json:"self_link"`
+	Labels      map[string]string `json:"labels"`
+	Updated     time.Time         `json:"updated"`
+	Created     time.Time         `json:"created"`
+}
+
+// swagger:model views_cronjob_spec
+type CronJobSpec struct {
+	Schedule         string             `json:"schedule"`
+	TimeZone         string             `json:"timezone"`
+	Concurrency      string             `json:"concurrency"`
+	StartingDeadline int                `json:"starting_deadline"`
+	History          CronJobSpecHistory `json:"history"`
+	Job              JobSpec            `json:"job"`
+}
+
+// swagger:model views_cronjob_spec_history
+type CronJobSpecHistory struct {
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+// swagger:model views_cronjob_status
+type CronJobStatus struct {
+	State        string    `json:"state"`
+	Message      string    `json:"message"`
+	Active       []string  `json:"active"`
+	LastSchedule time.Time `json:"last_schedule"`
+}
+
+// swagger:model views_cronjob_list
+type CronJobList []*CronJob
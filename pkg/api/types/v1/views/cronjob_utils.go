@@ -0,0 +1,112 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import (
+	"encoding/json"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+type CronJobView struct{}
+
+func (cv *CronJobView) New(obj *types.CronJob, jl *types.JobList) *CronJob {
+	c := CronJob{}
+	c.Meta = c.ToMeta(obj.Meta)
+	c.Spec = c.ToSpec(obj.Spec)
+	c.Status = c.ToStatus(obj.Status)
+	c.Jobs = c.ToJobs(jl)
+	return &c
+}
+
+func (c *CronJob) ToJson() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c *CronJob) ToMeta(obj types.CronJobMeta) CronJobMeta {
+	meta := CronJobMeta{}
+	meta.Name = obj.Name
+	meta.Description = obj.Description
+	meta.Namespace = obj.Namespace
+	meta.SelfLink = obj.SelfLink
+	meta.Labels = obj.Labels
+	meta.Updated = obj.Updated
+	meta.Created = obj.Created
+	return meta
+}
+
+func (c *CronJob) ToSpec(obj types.CronJobSpec) CronJobSpec {
+	return CronJobSpec{
+		Schedule:         obj.Schedule,
+		TimeZone:         obj.TimeZone,
+		Concurrency:      obj.Concurrency,
+		StartingDeadline: obj.StartingDeadline,
+		History: CronJobSpecHistory{
+			Succeeded: obj.History.Succeeded,
+			Failed:    obj.History.Failed,
+		},
+		Job: new(Job).ToSpec(obj.Job),
+	}
+}
+
+func (c *CronJob) ToStatus(obj types.CronJobStatus) CronJobStatus {
+	status := CronJobStatus{
+		State:        obj.State,
+		Message:      obj.Message,
+		Active:       obj.Active,
+		LastSchedule: obj.LastSchedule,
+	}
+	if status.Active == nil {
+		status.Active = make([]string, 0)
+	}
+	return status
+}
+
+// ToJobs returns jobs created by cron job schedule, job pods are not included
+func (c *CronJob) ToJobs(obj *types.JobList) JobList {
+	jobs := make(JobList, 0)
+	if obj == nil {
+		return jobs
+	}
+	for _, j := range obj.Items {
+		if j.Meta.CronJob == c.Meta.Name {
+			jobs = append(jobs, new(JobView).New(j, nil))
+		}
+	}
+	return jobs
+}
+
+func (cv CronJobView) NewList(obj *types.CronJobList, jl *types.JobList) *CronJobList {
+	if obj == nil {
+		return nil
+	}
+
+	cl := make(CronJobList, 0)
+	for _, v := range obj.Items {
+		cl = append(cl, cv.New(v, jl))
+	}
+	return &cl
+}
+
+func (cl *CronJobList) ToJson() ([]byte, error) {
+	if cl == nil {
+		cl = &CronJobList{}
+	}
+	return json.Marshal(cl)
+}
@@ -97,8 +97,34 @@ type DeploymentSpec struct {
 	Replicas int                `json:"replicas"`
 	Selector types.SpecSelector `json:"selector"`
 	Template types.SpecTemplate `json:"template"`
+	Paused   bool               `json:"paused"`
+	Promoted bool               `json:"promoted"`
+	Aborted  bool               `json:"aborted"`
 }
 
+// DeploymentRevision is a deployment version with spec changes against previous version
+//
+// swagger:model views_deployment_revision
+type DeploymentRevision struct {
+	// Deployment version
+	Version int `json:"version"`
+	// Deployment name
+	Deployment string `json:"deployment"`
+	// Deployment state
+	State string `json:"state"`
+	// Deployment spec is current service spec
+	Active bool `json:"active"`
+	// Deployment creation time
+	Created time.Time `json:"created"`
+	// Spec changes against previous version
+	Changes []string `json:"changes"`
+}
+
+// DeploymentRevisionList is a list of deployment revisions ordered from latest version
+//
+// swagger:model views_deployment_revision_list
+type DeploymentRevisionList []*DeploymentRevision
+
 // DeploymentStatusInfo is an info about deployment status
 //
 // swagger:model views_deployment_status
@@ -20,6 +20,9 @@ package views
 
 import (
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
 
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 )
@@ -70,6 +73,9 @@ func (di *Deployment) ToSpec(obj types.DeploymentSpec) DeploymentSpec {
 		Selector: obj.Selector,
 		Replicas: obj.Replicas,
 		Template: obj.Template,
+		Paused:   obj.Paused,
+		Promoted: obj.Promoted,
+		Aborted:  obj.Aborted,
 	}
 
 	return spec
@@ -103,3 +109,115 @@ func (dv *DeploymentView) NewList(obj *types.DeploymentList, pods *types.PodList
 func (di *DeploymentList) ToJson() ([]byte, error) {
 	return json.Marshal(di)
 }
+
+func (dv *DeploymentView) NewRevisionList(svc *types.Service, obj *types.DeploymentList) *DeploymentRevisionList {
+
+	items := make([]*types.Deployment, 0)
+	for _, d := range obj.Items {
+		items = append(items, d)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Meta.Version > items[j].Meta.Version
+	})
+
+	rl := make(DeploymentRevisionList, 0)
+	for i, d := range items {
+
+		r := new(DeploymentRevision)
+		r.Version = d.Meta.Version
+		r.Deployment = d.Meta.Name
+		r.State = d.Status.State
+		r.Created = d.Meta.Created
+		r.Active = d.Status.State != types.StateDestroyed && d.Spec.Template.Updated.Equal(svc.Spec.Template.Updated)
+		r.Changes = make([]string, 0)
+
+		if i+1 < len(items) {
+			r.Changes = deploymentTemplateDiff(items[i+1].Spec.Template, d.Spec.Template)
+		}
+
+		rl = append(rl, r)
+	}
+
+	return &rl
+}
+
+func (dl *DeploymentRevisionList) ToJson() ([]byte, error) {
+	return json.Marshal(dl)
+}
+
+// deploymentTemplateDiff returns list of human readable changes between spec templates
+func deploymentTemplateDiff(prev, next types.SpecTemplate) []string {
+
+	changes := make([]string, 0)
+
+	pc := make(map[string]*types.SpecTemplateContainer)
+	for _, c := range prev.Containers {
+		pc[c.Name] = c
+	}
+
+	for _, c := range next.Containers {
+
+		p, ok := pc[c.Name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("container %s: added", c.Name))
+			continue
+		}
+
+		if p.Image.Name != c.Image.Name {
+			changes = append(changes, fmt.Sprintf("container %s: image %s -> %s", c.Name, p.Image.Name, c.Image.Name))
+		}
+
+		var sections = []struct {
+			name       string
+			prev, next interface{}
+		}{
+			{"role", p.Role, c.Role},
+			{"exec", p.Exec, c.Exec},
+			{"env", p.EnvVars, c.EnvVars},
+			{"ports", p.Ports, c.Ports},
+			{"resources", p.Resources, c.Resources},
+			{"volumes", p.Volumes, c.Volumes},
+			{"probes", p.Probes, c.Probes},
+			{"restart policy", p.RestartPolicy, c.RestartPolicy},
+		}
+
+		for _, s := range sections {
+			if !reflect.DeepEqual(s.prev, s.next) {
+				changes = append(changes, fmt.Sprintf("container %s: %s changed", c.Name, s.name))
+			}
+		}
+
+		delete(pc, c.Name)
+	}
+
+	for _, c := range prev.Containers {
+		if _, ok := pc[c.Name]; ok {
+			changes = append(changes, fmt.Sprintf("container %s: removed", c.Name))
+		}
+	}
+
+	pv := make(map[string]*types.SpecTemplateVolume)
+	for _, v := range prev.Volumes {
+		pv[v.Name] = v
+	}
+
+	for _, v := range next.Volumes {
+		p, ok := pv[v.Name]
+		switch true {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("volume %s: added", v.Name))
+		case !reflect.DeepEqual(p, v):
+			changes = append(changes, fmt.Sprintf("volume %s: changed", v.Name))
+		}
+		delete(pv, v.Name)
+	}
+
+	for _, v := range prev.Volumes {
+		if _, ok := pv[v.Name]; ok {
+			changes = append(changes, fmt.Sprintf("volume %s: removed", v.Name))
+		}
+	}
+
+	return changes
+}
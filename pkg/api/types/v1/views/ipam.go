@@ -0,0 +1,67 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import "time"
+
+// IPAM represents IP address pools usage for api
+//
+// swagger:model views_ipam
+type IPAM struct {
+	Pools IPAMPoolList `json:"pools"`
+}
+
+// IPAMPool represents named IP addresses range for api
+//
+// swagger:model views_ipam_pool
+type IPAMPool struct {
+	Name   string         `json:"name"`
+	CIDR   string         `json:"cidr"`
+	Family string         `json:"family"`
+	Status IPAMPoolStatus `json:"status"`
+	Leases []*IPAMLease   `json:"leases"`
+}
+
+// IPAMPoolStatus represents pool addresses usage for api
+//
+// swagger:model views_ipam_pool_status
+type IPAMPoolStatus struct {
+	// total number of usable addresses
+	Total uint64 `json:"total"`
+
+	// number of available addresses
+	Available uint64 `json:"available"`
+
+	// number of reserved addresses
+	Reserved uint64 `json:"reserved"`
+}
+
+// IPAMLease represents leased address for api
+//
+// swagger:model views_ipam_lease
+type IPAMLease struct {
+	IP      string    `json:"ip"`
+	Owner   string    `json:"owner"`
+	Created time.Time `json:"created"`
+}
+
+// IPAMPoolList is a list of pools for api
+//
+// swagger:model views_ipam_pool_list
+type IPAMPoolList []*IPAMPool
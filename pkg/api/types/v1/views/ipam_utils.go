@@ -0,0 +1,77 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+type IPAMView struct{}
+
+func (iv *IPAMView) New(obj *types.IPAM) *IPAM {
+
+	i := IPAM{}
+	i.Pools = make(IPAMPoolList, 0)
+
+	if obj == nil {
+		return &i
+	}
+
+	pools := make(map[string]*IPAMPool, 0)
+	for name, p := range obj.Pools {
+		pools[name] = &IPAMPool{
+			Name:   p.Name,
+			CIDR:   p.CIDR,
+			Family: p.Family,
+			Status: IPAMPoolStatus{
+				Total:     p.Status.Total,
+				Available: p.Status.Available,
+				Reserved:  p.Status.Reserved,
+			},
+			Leases: make([]*IPAMLease, 0),
+		}
+		i.Pools = append(i.Pools, pools[name])
+	}
+
+	for _, l := range obj.Leases {
+		p, ok := pools[l.Pool]
+		if !ok {
+			continue
+		}
+		p.Leases = append(p.Leases, &IPAMLease{
+			IP:      l.IP,
+			Owner:   l.Owner,
+			Created: l.Created,
+		})
+	}
+
+	sort.Slice(i.Pools, func(a, b int) bool { return i.Pools[a].Name < i.Pools[b].Name })
+	for _, p := range i.Pools {
+		sort.Slice(p.Leases, func(a, b int) bool { return p.Leases[a].IP < p.Leases[b].IP })
+	}
+
+	return &i
+}
+
+func (i *IPAM) ToJson() ([]byte, error) {
+	return json.Marshal(i)
+}
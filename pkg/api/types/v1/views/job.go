@@ -0,0 +1,69 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import (
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+// swagger:model views_job
+type Job struct {
+	Meta   JobMeta        `json:"meta"`
+	Spec   JobSpec        `json:"spec"`
+	Status JobStatus      `json:"status"`
+	Pods   map[string]Pod `json:"pods"`
+}
+
+// swagger:model views_job_meta
+type JobMeta struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Namespace   string            `json:"namespace"`
+	SelfLink    string            `json:"self_link"`
+	CronJob     string            `json:"cronjob,omitempty"`
+	Labels      map[string]string `json:"labels"`
+	Updated     time.Time         `json:"updated"`
+	Created     time.Time         `json:"created"`
+}
+
+// swagger:model views_job_spec
+type JobSpec struct {
+	Completions    int                `json:"completions"`
+	Parallelism    int                `json:"parallelism"`
+	BackoffLimit   int                `json:"backoff_limit"`
+	ActiveDeadline int                `json:"active_deadline"`
+	Selector       types.SpecSelector `json:"selector"`
+	Template       types.SpecTemplate `json:"template"`
+}
+
+// swagger:model views_job_status
+type JobStatus struct {
+	State     string    `json:"state"`
+	Message   string    `json:"message"`
+	Active    int       `json:"active"`
+	Succeeded int       `json:"succeeded"`
+	Failed    int       `json:"failed"`
+	Started   time.Time `json:"started"`
+	Finished  time.Time `json:"finished"`
+}
+
+// swagger:model views_job_list
+type JobList []*Job
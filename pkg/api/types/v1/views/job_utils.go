@@ -0,0 +1,109 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package views
+
+import (
+	"encoding/json"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+type JobView struct{}
+
+func (jv *JobView) New(obj *types.Job, pl *types.PodList) *Job {
+	j := Job{}
+	j.Meta = j.ToMeta(obj.Meta)
+	j.Spec = j.ToSpec(obj.Spec)
+	j.Status = j.ToStatus(obj.Status)
+	j.Pods = j.ToPods(pl)
+	return &j
+}
+
+func (j *Job) ToJson() ([]byte, error) {
+	return json.Marshal(j)
+}
+
+func (j *Job) ToMeta(obj types.JobMeta) JobMeta {
+	meta := JobMeta{}
+	meta.Name = obj.Name
+	meta.Description = obj.Description
+	meta.Namespace = obj.Namespace
+	meta.SelfLink = obj.SelfLink
+	meta.CronJob = obj.CronJob
+	meta.Labels = obj.Labels
+	meta.Updated = obj.Updated
+	meta.Created = obj.Created
+	return meta
+}
+
+func (j *Job) ToSpec(obj types.JobSpec) JobSpec {
+	return JobSpec{
+		Completions:    obj.Completions,
+		Parallelism:    obj.Parallelism,
+		BackoffLimit:   obj.BackoffLimit,
+		ActiveDeadline: obj.ActiveDeadline,
+		Selector:       obj.Selector,
+		Template:       obj.Template,
+	}
+}
+
+func (j *Job) ToStatus(obj types.JobStatus) JobStatus {
+	return JobStatus{
+		State:     obj.State,
+		Message:   obj.Message,
+		Active:    obj.Active,
+		Succeeded: obj.Succeeded,
+		Failed:    obj.Failed,
+		Started:   obj.Started,
+		Finished:  obj.Finished,
+	}
+}
+
+func (j *Job) ToPods(obj *types.PodList) map[string]Pod {
+	pods := make(map[string]Pod, 0)
+	if obj == nil {
+		return pods
+	}
+	for _, p := range obj.Items {
+		if p.Meta.Job == j.Meta.Name {
+			pv := new(PodViewHelper)
+			pods[p.Meta.Name] = pv.New(p)
+		}
+	}
+	return pods
+}
+
+func (jv JobView) NewList(obj *types.JobList, pl *types.PodList) *JobList {
+	if obj == nil {
+		return nil
+	}
+
+	jl := make(JobList, 0)
+	for _, v := range obj.Items {
+		jl = append(jl, jv.New(v, pl))
+	}
+	return &jl
+}
+
+func (jl *JobList) ToJson() ([]byte, error) {
+	if jl == nil {
+		jl = &JobList{}
+	}
+	return json.Marshal(jl)
+}
@@ -19,17 +19,53 @@
 package views
 
 type ManifestSpecSelector struct {
-	Node   string            `json:"node,omitempty" yaml:"node,omitempty"`
-	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Node         string                           `json:"node,omitempty" yaml:"node,omitempty"`
+	Labels       map[string]string                `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Affinity     []ManifestSpecSelectorAffinity   `json:"affinity,omitempty" yaml:"affinity,omitempty"`
+	AntiAffinity []ManifestSpecSelectorAffinity   `json:"anti_affinity,omitempty" yaml:"anti_affinity,omitempty"`
+	Spread       []ManifestSpecSelectorSpread     `json:"spread,omitempty" yaml:"spread,omitempty"`
+	Tolerations  []ManifestSpecSelectorToleration `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
+}
+
+type ManifestSpecSelectorAffinity struct {
+	Service  string `json:"service,omitempty" yaml:"service,omitempty"`
+	Topology string `json:"topology,omitempty" yaml:"topology,omitempty"`
+}
+
+type ManifestSpecSelectorSpread struct {
+	Topology string `json:"topology,omitempty" yaml:"topology,omitempty"`
+	MaxSkew  int    `json:"max_skew,omitempty" yaml:"max_skew,omitempty"`
+}
+
+type ManifestSpecSelectorToleration struct {
+	Key      string `json:"key,omitempty" yaml:"key,omitempty"`
+	Operator string `json:"operator,omitempty" yaml:"operator,omitempty"`
+	Value    string `json:"value,omitempty" yaml:"value,omitempty"`
+	Effect   string `json:"effect,omitempty" yaml:"effect,omitempty"`
 }
 
 type ManifestSpecNetwork struct {
 	IP    string            `json:"ip,omitempty" yaml:"ip,omitempty"`
+	Pool  string            `json:"pool,omitempty" yaml:"pool,omitempty"`
 	Ports map[uint16]string `json:"ports,omitempty" yaml:"ports,omitempty"`
 }
 
 type ManifestSpecStrategy struct {
-	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	Type     string                       `json:"type,omitempty" yaml:"type,omitempty"`
+	Rolling  *ManifestSpecStrategyRolling `json:"rolling,omitempty" yaml:"rolling,omitempty"`
+	Deadline int                          `json:"deadline,omitempty" yaml:"deadline,omitempty"`
+	Canary   *ManifestSpecStrategyCanary  `json:"canary,omitempty" yaml:"canary,omitempty"`
+	History  int                          `json:"history,omitempty" yaml:"history,omitempty"`
+}
+
+type ManifestSpecStrategyCanary struct {
+	Replicas int `json:"replicas" yaml:"replicas"`
+	Weight   int `json:"weight" yaml:"weight"`
+}
+
+type ManifestSpecStrategyRolling struct {
+	MaxSurge       int `json:"max_surge" yaml:"max_surge"`
+	MaxUnavailable int `json:"max_unavailable" yaml:"max_unavailable"`
 }
 
 type ManifestSpecTemplate struct {
@@ -39,6 +75,7 @@ type ManifestSpecTemplate struct {
 
 type ManifestSpecTemplateContainer struct {
 	Name          string                                 `json:"name,omitempty" yaml:"name,omitempty"`
+	Role          string                                 `json:"role,omitempty" yaml:"role,omitempty"`
 	Command       string                                 `json:"command,omitempty" yaml:"command,omitempty"`
 	Workdir       string                                 `json:"workdir,omitempty" yaml:"workdir,omitempty"`
 	Entrypoint    string                                 `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`
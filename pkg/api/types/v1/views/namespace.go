@@ -53,14 +53,22 @@ type NamespaceEnvs []string
 
 // swagger:model views_namespace_resource
 type NamespaceResources struct {
-	RAM    int64 `json:"ram"`
-	Routes int   `json:"routes"`
+	RAM     int64 `json:"ram"`
+	CPU     int64 `json:"cpu"`
+	Pods    int   `json:"pods"`
+	Volumes int   `json:"volumes"`
+	Storage int64 `json:"storage"`
+	Routes  int   `json:"routes"`
 }
 
 // swagger:model views_namespace_quotas
 type NamespaceQuotas struct {
 	Disabled bool  `json:"disabled"`
 	RAM      int64 `json:"ram"`
+	CPU      int64 `json:"cpu"`
+	Pods     int   `json:"pods"`
+	Volumes  int   `json:"volumes"`
+	Storage  int64 `json:"storage"`
 	Routes   int   `json:"routes"`
 }
 
@@ -73,8 +73,12 @@ func (r *Namespace) ToEnv(obj types.NamespaceEnvs) NamespaceEnvs {
 
 func (r *Namespace) ToResources(obj types.NamespaceResources) NamespaceResources {
 	return NamespaceResources{
-		RAM:    obj.RAM,
-		Routes: obj.Routes,
+		RAM:     obj.RAM,
+		CPU:     obj.CPU,
+		Pods:    obj.Pods,
+		Volumes: obj.Volumes,
+		Storage: obj.Storage,
+		Routes:  obj.Routes,
 	}
 }
 
@@ -82,6 +86,10 @@ func (r *Namespace) ToQuotas(obj types.NamespaceQuotas) NamespaceQuotas {
 	return NamespaceQuotas{
 		Disabled: obj.Disabled,
 		RAM:      obj.RAM,
+		CPU:      obj.CPU,
+		Pods:     obj.Pods,
+		Volumes:  obj.Volumes,
+		Storage:  obj.Storage,
 		Routes:   obj.Routes,
 	}
 }
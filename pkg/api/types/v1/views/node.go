@@ -83,12 +83,23 @@ type NodeStatus struct {
 	Online    bool            `json:"online"`
 	Capacity  NodeResources   `json:"capacity"`
 	Allocated NodeResources   `json:"allocated"`
+	Heartbeat time.Time       `json:"heartbeat"`
 }
 
 // swagger:ignore
 // swagger:model types_node_spec
 type NodeSpec struct {
-	Security NodeSecurity `json:"security"`
+	Security      NodeSecurity `json:"security"`
+	Unschedulable bool         `json:"unschedulable"`
+	Taints        []NodeTaint  `json:"taints"`
+}
+
+// NodeTaint - node taint structure
+// swagger:model views_node_taint
+type NodeTaint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Effect string `json:"effect"`
 }
 
 type NodeSecurity struct {
@@ -53,6 +53,8 @@ func (nv *NodeView) ToNodeStatus(status types.NodeStatus) NodeStatus {
 	ns := NodeStatus{}
 
 	ns.Online = status.Online
+	ns.Heartbeat = status.Heartbeat
+	ns.State.Ready = status.State.Ready
 
 	ns.Capacity.Containers = status.Capacity.Containers
 	ns.Capacity.Pods = status.Capacity.Pods
@@ -92,6 +94,11 @@ func (nv *NodeView) ToNodeStatus(status types.NodeStatus) NodeStatus {
 func (nv *NodeView) ToNodeSpec(spec types.NodeSpec) NodeSpec {
 	ns := NodeSpec{}
 	ns.Security.TLS = spec.Security.TLS
+	ns.Unschedulable = spec.Unschedulable
+	ns.Taints = make([]NodeTaint, 0)
+	for _, t := range spec.Taints {
+		ns.Taints = append(ns.Taints, NodeTaint{Key: t.Key, Value: t.Value, Effect: t.Effect})
+	}
 	return ns
 }
 
@@ -98,6 +98,8 @@ type PodStatus struct {
 	State string `json:"state"`
 	// Pod state message
 	Message string `json:"message"`
+	// Pod ready to serve traffic
+	Ready bool `json:"ready"`
 	// Pod steps
 	Steps PodSteps `json:"steps"`
 	// Pod network
@@ -119,6 +121,8 @@ type PodContainer struct {
 	Pod string `json:"pod"`
 	// Pod container name
 	Name string `json:"name"`
+	// Pod container role
+	Role string `json:"role,omitempty"`
 	// Pod container state
 	State PodContainerState `json:"state"`
 	// Pod container ready
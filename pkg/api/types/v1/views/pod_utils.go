@@ -67,6 +67,7 @@ func (pv *Pod) toStatus(pod types.PodStatus) PodStatus {
 	var status = PodStatus{
 		State:   pod.State,
 		Message: pod.Message,
+		Ready:   pod.Ready,
 	}
 
 	status.Network.HostIP = pod.Network.HostIP
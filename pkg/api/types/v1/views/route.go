@@ -46,9 +46,16 @@ type RouteMeta struct {
 type RouteSpec struct {
 	Domain string       `json:"domain"`
 	Port   uint16       `json:"port"`
+	TLS    RouteTLS     `json:"tls"`
 	Rules  []*RouteRule `json:"rules"`
 }
 
+// swagger:model views_route_tls
+type RouteTLS struct {
+	Secret   string `json:"secret"`
+	Redirect bool   `json:"redirect"`
+}
+
 // swagger:model views_route_rule
 type RouteRule struct {
 	Service  string `json:"service"`
@@ -59,6 +66,14 @@ type RouteRule struct {
 
 // swagger:model views_route_status
 type RouteStatus struct {
-	State   string `json:"state"`
-	Message string `json:"message"`
+	State   string         `json:"state"`
+	Message string         `json:"message"`
+	TLS     RouteTLSStatus `json:"tls"`
+}
+
+// swagger:model views_route_tls_status
+type RouteTLSStatus struct {
+	State   string    `json:"state"`
+	Message string    `json:"message"`
+	Expires time.Time `json:"expires"`
 }
@@ -53,6 +53,8 @@ func (r *Route) ToSpec(obj types.RouteSpec) RouteSpec {
 	spec := RouteSpec{}
 	spec.Domain = obj.Domain
 	spec.Port = obj.Port
+	spec.TLS.Secret = obj.TLS.Secret
+	spec.TLS.Redirect = obj.TLS.Redirect
 	for _, rule := range obj.Rules {
 		spec.Rules = append(spec.Rules, &RouteRule{
 			Service:  rule.Service,
@@ -68,6 +70,9 @@ func (r *Route) ToStatus(obj types.RouteStatus) RouteStatus {
 	state := RouteStatus{}
 	state.State = obj.State
 	state.Message = obj.Message
+	state.TLS.State = obj.TLS.Current()
+	state.TLS.Message = obj.TLS.Message
+	state.TLS.Expires = obj.TLS.Expires
 	return state
 }
 
@@ -72,6 +72,45 @@ type ServiceStats struct {
 	Network int64 `json:"network"`
 }
 
+// ServiceMetrics - service resources usage aggregated from running pods
+// swagger:model views_service_metrics
+type ServiceMetrics struct {
+	Namespace string                       `json:"namespace"`
+	Service   string                       `json:"service"`
+	Usage     ServiceUsage                 `json:"usage"`
+	Pods      map[string]ServicePodMetrics `json:"pods"`
+}
+
+// swagger:model views_service_pod_metrics
+type ServicePodMetrics struct {
+	Deployment string       `json:"deployment"`
+	Node       string       `json:"node"`
+	Usage      ServiceUsage `json:"usage"`
+}
+
+// swagger:model views_service_usage
+type ServiceUsage struct {
+	// CPU usage in millicores
+	CPU int64 `json:"cpu"`
+	// RAM usage in MB
+	RAM     int64               `json:"ram"`
+	Network ServiceUsageNetwork `json:"network"`
+	Disk    ServiceUsageDisk    `json:"disk"`
+	Updated time.Time           `json:"updated"`
+}
+
+// swagger:model views_service_usage_network
+type ServiceUsageNetwork struct {
+	RX int64 `json:"rx"`
+	TX int64 `json:"tx"`
+}
+
+// swagger:model views_service_usage_disk
+type ServiceUsageDisk struct {
+	Read  int64 `json:"read"`
+	Write int64 `json:"write"`
+}
+
 // swagger:model views_service_status
 type ServiceStatus struct {
 	State   string `json:"state"`
@@ -88,11 +127,20 @@ type ServiceDeployment struct {
 
 // swagger:model views_service_spec
 type ServiceSpec struct {
+	Mode     string               `json:"mode,omitempty" yaml:"mode,omitempty"`
 	Selector ManifestSpecSelector `json:"selector,omitempty" yaml:"selector,omitempty"`
 	Replicas int                  `json:"replicas,omitempty" yaml:"replicas,omitempty"`
 	Network  ManifestSpecNetwork  `json:"network,omitempty" yaml:"network,omitempty"`
 	Strategy ManifestSpecStrategy `json:"strategy,omitempty" yaml:"strategy,omitempty"`
 	Template ManifestSpecTemplate `json:"template,omitempty" yaml:"template,omitempty"`
+	// Stateful service volume claims
+	VolumeClaims []ServiceSpecVolumeClaim `json:"volume_claims,omitempty" yaml:"volume_claims,omitempty"`
+}
+
+// swagger:model views_service_spec_volume_claim
+type ServiceSpecVolumeClaim struct {
+	Name     string             `json:"name" yaml:"name"`
+	Capacity VolumeSpecCapacity `json:"capacity" yaml:"capacity"`
 }
 
 type ServiceTemplateSpec struct {
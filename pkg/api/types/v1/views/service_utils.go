@@ -26,6 +26,7 @@ import (
 	"strings"
 
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/util/resource"
 )
 
 type ServiceView struct{}
@@ -85,6 +86,7 @@ func (sv *Service) ToStatus(obj types.ServiceStatus) ServiceStatus {
 func (sv *Service) ToSpec(obj types.ServiceSpec) ServiceSpec {
 
 	var spec = ServiceSpec{
+		Mode:     obj.Mode,
 		Replicas: obj.Replicas,
 		Template: ManifestSpecTemplate{
 			Containers: make([]ManifestSpecTemplateContainer, 0),
@@ -96,17 +98,38 @@ func (sv *Service) ToSpec(obj types.ServiceSpec) ServiceSpec {
 		},
 		Network: ManifestSpecNetwork{
 			IP:    obj.Network.IP,
+			Pool:  obj.Network.Pool,
 			Ports: obj.Network.Ports,
 		},
 		Strategy: ManifestSpecStrategy{
 			Type: obj.Strategy.Type,
+			Rolling: &ManifestSpecStrategyRolling{
+				MaxSurge:       obj.Strategy.RollingOptions.MaxSurge,
+				MaxUnavailable: obj.Strategy.RollingOptions.MaxUnavailable,
+			},
+			Deadline: obj.Strategy.Deadline,
+			History:  obj.Strategy.History,
 		},
 	}
 
+	if obj.Strategy.Canary() {
+		spec.Strategy.Canary = &ManifestSpecStrategyCanary{
+			Replicas: obj.Strategy.CanaryOptions.Replicas,
+			Weight:   obj.Strategy.CanaryOptions.Weight,
+		}
+	}
+
+	for _, c := range obj.VolumeClaims {
+		claim := ServiceSpecVolumeClaim{Name: c.Name}
+		claim.Capacity.Storage = resource.EncodeResource(c.Capacity.Storage)
+		spec.VolumeClaims = append(spec.VolumeClaims, claim)
+	}
+
 	for _, s := range obj.Template.Containers {
 
 		c := ManifestSpecTemplateContainer{
 			Name:       s.Name,
+			Role:       s.Role,
 			Command:    strings.Join(s.Exec.Command, " "),
 			Workdir:    s.Exec.Workdir,
 			Args:       s.Exec.Args,
@@ -186,6 +209,36 @@ func (sv *Service) ToSpec(obj types.ServiceSpec) ServiceSpec {
 		spec.Template.Volumes = append(spec.Template.Volumes, v)
 	}
 
+	for _, r := range obj.Selector.Affinity {
+		spec.Selector.Affinity = append(spec.Selector.Affinity, ManifestSpecSelectorAffinity{
+			Service:  r.Service,
+			Topology: r.Topology,
+		})
+	}
+
+	for _, r := range obj.Selector.AntiAffinity {
+		spec.Selector.AntiAffinity = append(spec.Selector.AntiAffinity, ManifestSpecSelectorAffinity{
+			Service:  r.Service,
+			Topology: r.Topology,
+		})
+	}
+
+	for _, r := range obj.Selector.Spread {
+		spec.Selector.Spread = append(spec.Selector.Spread, ManifestSpecSelectorSpread{
+			Topology: r.Topology,
+			MaxSkew:  r.MaxSkew,
+		})
+	}
+
+	for _, t := range obj.Selector.Tolerations {
+		spec.Selector.Tolerations = append(spec.Selector.Tolerations, ManifestSpecSelectorToleration{
+			Key:      t.Key,
+			Operator: t.Operator,
+			Value:    t.Value,
+			Effect:   t.Effect,
+		})
+	}
+
 	return spec
 }
 
@@ -217,8 +270,18 @@ func (sv Service) ToRequestManifest() *request.ServiceManifest {
 		sm.Meta.Labels = sv.Meta.Labels
 	}
 
+	if sv.Spec.Mode != types.EmptyString {
+		sm.Spec.Mode = &sv.Spec.Mode
+	}
+
 	sm.Spec.Replicas = &sv.Spec.Replicas
 
+	for _, c := range sv.Spec.VolumeClaims {
+		claim := request.ServiceManifestSpecVolumeClaim{Name: c.Name}
+		claim.Capacity.Storage = c.Capacity.Storage
+		sm.Spec.VolumeClaims = append(sm.Spec.VolumeClaims, claim)
+	}
+
 	sm.Spec.Selector = new(request.ManifestSpecSelector)
 	sm.Spec.Selector.Node = sv.Spec.Selector.Node
 	sm.Spec.Selector.Labels = sv.Spec.Selector.Labels
@@ -226,11 +289,56 @@ func (sv Service) ToRequestManifest() *request.ServiceManifest {
 		sm.Spec.Selector.Labels = make(map[string]string, 0)
 	}
 
+	for _, r := range sv.Spec.Selector.Affinity {
+		sm.Spec.Selector.Affinity = append(sm.Spec.Selector.Affinity, request.ManifestSpecSelectorAffinity{
+			Service:  r.Service,
+			Topology: r.Topology,
+		})
+	}
+
+	for _, r := range sv.Spec.Selector.AntiAffinity {
+		sm.Spec.Selector.AntiAffinity = append(sm.Spec.Selector.AntiAffinity, request.ManifestSpecSelectorAffinity{
+			Service:  r.Service,
+			Topology: r.Topology,
+		})
+	}
+
+	for _, r := range sv.Spec.Selector.Spread {
+		sm.Spec.Selector.Spread = append(sm.Spec.Selector.Spread, request.ManifestSpecSelectorSpread{
+			Topology: r.Topology,
+			MaxSkew:  r.MaxSkew,
+		})
+	}
+
+	for _, t := range sv.Spec.Selector.Tolerations {
+		sm.Spec.Selector.Tolerations = append(sm.Spec.Selector.Tolerations, request.ManifestSpecSelectorToleration{
+			Key:      t.Key,
+			Operator: t.Operator,
+			Value:    t.Value,
+			Effect:   t.Effect,
+		})
+	}
+
 	sm.Spec.Strategy = new(request.ManifestSpecStrategy)
 	sm.Spec.Strategy.Type = &sv.Spec.Strategy.Type
+	if sv.Spec.Strategy.Rolling != nil {
+		sm.Spec.Strategy.Rolling = &request.ManifestSpecStrategyRolling{
+			MaxSurge:       sv.Spec.Strategy.Rolling.MaxSurge,
+			MaxUnavailable: sv.Spec.Strategy.Rolling.MaxUnavailable,
+		}
+	}
+	sm.Spec.Strategy.Deadline = &sv.Spec.Strategy.Deadline
+	sm.Spec.Strategy.History = &sv.Spec.Strategy.History
+	if sv.Spec.Strategy.Canary != nil {
+		sm.Spec.Strategy.Canary = &request.ManifestSpecStrategyCanary{
+			Replicas: sv.Spec.Strategy.Canary.Replicas,
+			Weight:   sv.Spec.Strategy.Canary.Weight,
+		}
+	}
 
 	sm.Spec.Network = new(request.ManifestSpecNetwork)
 	sm.Spec.Network.IP = &sv.Spec.Network.IP
+	sm.Spec.Network.Pool = &sv.Spec.Network.Pool
 	sm.Spec.Network.Ports = make([]string, 0)
 
 	if sv.Spec.Network.Ports != nil {
@@ -278,6 +386,7 @@ func (sv Service) ToRequestManifest() *request.ServiceManifest {
 
 			data := request.ManifestSpecTemplateContainer{
 				Name:       v.Name,
+				Role:       v.Role,
 				Command:    v.Command,
 				Workdir:    v.Workdir,
 				Entrypoint: v.Entrypoint,
@@ -351,6 +460,58 @@ func (sv *ServiceView) NewList(obj *types.ServiceList, d *types.DeploymentList,
 	return &s
 }
 
+// ***************************************************
+// SERVICE METRICS MODEL
+// ***************************************************
+
+func (sv *ServiceView) NewMetrics(srv *types.Service, pl *types.PodList) *ServiceMetrics {
+	m := new(ServiceMetrics)
+	m.Namespace = srv.Meta.Namespace
+	m.Service = srv.Meta.Name
+	m.Pods = make(map[string]ServicePodMetrics, 0)
+
+	if pl == nil {
+		return m
+	}
+
+	var usage types.PodUsage
+	for _, p := range pl.Items {
+		if p.Meta.Service != srv.Meta.Name || !p.Status.Running {
+			continue
+		}
+
+		usage.Sum(p.Status.Usage)
+		m.Pods[p.Meta.Name] = ServicePodMetrics{
+			Deployment: p.Meta.Deployment,
+			Node:       p.Meta.Node,
+			Usage:      m.ToUsage(p.Status.Usage),
+		}
+	}
+
+	m.Usage = m.ToUsage(usage)
+	return m
+}
+
+func (sm *ServiceMetrics) ToUsage(obj types.PodUsage) ServiceUsage {
+	return ServiceUsage{
+		CPU: obj.CPU,
+		RAM: obj.RAM,
+		Network: ServiceUsageNetwork{
+			RX: obj.Network.RX,
+			TX: obj.Network.TX,
+		},
+		Disk: ServiceUsageDisk{
+			Read:  obj.Disk.Read,
+			Write: obj.Disk.Write,
+		},
+		Updated: obj.Updated,
+	}
+}
+
+func (sm *ServiceMetrics) ToJson() ([]byte, error) {
+	return json.Marshal(sm)
+}
+
 func (sv *ServiceList) ToJson() ([]byte, error) {
 	return json.Marshal(sv)
 }
@@ -21,10 +21,14 @@ package views
 const logLevel = 5
 
 type IView interface {
+	Account() *AccountView
+	Autoscaler() *AutoscalerView
+	Certificate() *CertificateView
 	Cluster() *ClusterView
 	Node() *NodeView
 	Ingress() *IngressView
 	Discovery() *DiscoveryView
+	IPAM() *IPAMView
 
 	Namespace() *NamespaceView
 	Route() *RouteView
@@ -32,6 +36,8 @@ type IView interface {
 	Secret() *SecretView
 	Config() *ConfigView
 	Deployment() *DeploymentView
+	Job() *JobView
+	CronJob() *CronJobView
 	Endpoint() *EndpointView
 	Pod() *Pod
 	Container() *ContainerView
@@ -40,6 +46,16 @@ type IView interface {
 
 type View struct{}
 
+func (View) Account() *AccountView {
+	return new(AccountView)
+}
+func (View) Autoscaler() *AutoscalerView {
+	return new(AutoscalerView)
+}
+func (View) Certificate() *CertificateView {
+	return new(CertificateView)
+}
+
 func (View) Cluster() *ClusterView {
 	return new(ClusterView)
 }
@@ -52,6 +68,9 @@ func (View) Ingress() *IngressView {
 func (View) Discovery() *DiscoveryView {
 	return new(DiscoveryView)
 }
+func (View) IPAM() *IPAMView {
+	return new(IPAMView)
+}
 
 func (View) Namespace() *NamespaceView {
 	return new(NamespaceView)
@@ -71,6 +90,12 @@ func (View) Config() *ConfigView {
 func (View) Deployment() *DeploymentView {
 	return new(DeploymentView)
 }
+func (View) Job() *JobView {
+	return new(JobView)
+}
+func (View) CronJob() *CronJobView {
+	return new(CronJobView)
+}
 func (View) Pod() *Pod {
 	return new(Pod)
 }
@@ -29,8 +29,12 @@ import (
 	"os"
 
 	"github.com/lastbackend/lastbackend/pkg/controller/ipam"
+	"github.com/lastbackend/lastbackend/pkg/controller/lifecycle"
 	"github.com/lastbackend/lastbackend/pkg/controller/runtime"
+	"github.com/lastbackend/lastbackend/pkg/controller/scheduler"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/lastbackend/lastbackend/pkg/util/metrics"
 	"github.com/spf13/viper"
 )
 
@@ -51,16 +55,39 @@ func Daemon() bool {
 	}
 	env.SetStorage(stg)
 
-	ipm, err := ipam.New(viper.GetString("service.cidr"))
+	pools := viper.GetStringMapString("ipam.pools")
+	if cidr := viper.GetString("service.cidr"); cidr != "" {
+		if _, ok := pools[types.IPAMPoolDefault]; !ok {
+			pools[types.IPAMPoolDefault] = cidr
+		}
+	}
+
+	ipm, err := ipam.New(pools)
 	if err != nil {
 		log.Fatalf("Cannot initialize ipam service: %s", err.Error())
 	}
 	env.SetIPAM(ipm)
 
+	sch, err := scheduler.New(viper.GetString("scheduler.strategy"))
+	if err != nil {
+		log.Fatalf("Cannot initialize scheduler: %s", err.Error())
+	}
+	env.SetScheduler(sch)
+
+	env.SetLifecycle(lifecycle.New(viper.GetDuration("lifecycle.grace_period"), viper.GetDuration("lifecycle.eviction_timeout")))
+
 	// Initialize Runtime
 	r := runtime.NewRuntime(context.Background())
 	r.Loop()
 
+	if viper.IsSet("metrics.port") {
+		go func() {
+			if err := metrics.Listen(viper.GetString("metrics.host"), viper.GetInt("metrics.port")); err != nil {
+				log.Errorf("controller:metrics: server err: %s", err.Error())
+			}
+		}()
+	}
+
 	// Handle SIGINT and SIGTERM.
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 
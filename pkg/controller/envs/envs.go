@@ -20,14 +20,18 @@ package envs
 
 import (
 	"github.com/lastbackend/lastbackend/pkg/controller/ipam/ipam"
+	"github.com/lastbackend/lastbackend/pkg/controller/lifecycle"
+	"github.com/lastbackend/lastbackend/pkg/controller/scheduler"
 	"github.com/lastbackend/lastbackend/pkg/storage"
 )
 
 var e Env
 
 type Env struct {
-	storage storage.Storage
-	ipam    ipam.IPAM
+	storage   storage.Storage
+	ipam      ipam.IPAM
+	scheduler *scheduler.Scheduler
+	lifecycle *lifecycle.Lifecycle
 }
 
 func Get() *Env {
@@ -49,3 +53,19 @@ func (c *Env) SetIPAM(ipam ipam.IPAM) {
 func (c *Env) GetIPAM() ipam.IPAM {
 	return c.ipam
 }
+
+func (c *Env) SetScheduler(s *scheduler.Scheduler) {
+	c.scheduler = s
+}
+
+func (c *Env) GetScheduler() *scheduler.Scheduler {
+	return c.scheduler
+}
+
+func (c *Env) SetLifecycle(l *lifecycle.Lifecycle) {
+	c.lifecycle = l
+}
+
+func (c *Env) GetLifecycle() *lifecycle.Lifecycle {
+	return c.lifecycle
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/controller/ipam/local"
 )
 
-func New(cidr string) (ipam.IPAM, error) {
-	return local.New(cidr)
+// New returns IPAM with pools CIDRs mapped by name
+func New(pools map[string]string) (ipam.IPAM, error) {
+	return local.New(pools)
 }
@@ -20,11 +20,17 @@ package ipam
 
 import (
 	"net"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 )
 
 type IPAM interface {
-	Lease() (*net.IP, error)
+	// Lease IP address from named pool for owner, default pool is used if pool is empty
+	Lease(pool, owner string) (*net.IP, error)
+	// Release IP address
 	Release(ip *net.IP) error
-	Available() int
-	Reserved() int
+	// Reconcile leases with IP addresses in use mapped to owners
+	Reconcile(owners map[string]string) error
+	// Pools returns pools with addresses usage
+	Pools() map[string]*types.IPAMPool
 }
@@ -21,9 +21,14 @@ package local
 import (
 	"context"
 	"net"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/storage"
 )
@@ -31,46 +36,52 @@ import (
 const (
 	logIPAMPrefix         = "controller:ipam:>"
 	IPAMLeaseNotAvailable = "IPAMLeaseNotAvailable"
+	IPAMPoolNotFound      = "IPAMPoolNotFound"
 	defaultCIDR           = "172.17.0.0/16"
+
+	// ReconcileGrace - time after lease creation when lease is not released by reconciler,
+	// address can be leased but not stored in owner object yet
+	ReconcileGrace = time.Minute
+
+	// legacyKey - storage key of leased addresses list used before pools were introduced
+	legacyKey = "ipam"
 )
 
 // IPAM - IP address management
 type IPAM struct {
-	leased    map[string]bool
-	released  map[string]bool
-	available int
-	reserved  int
-	storage   storage.Storage
+	lock    sync.Mutex
+	pools   map[string]*pool
+	leases  map[string]*types.IPAMLease
+	storage storage.Storage
 }
 
-// Lease IP from range
-func (i *IPAM) Lease() (*net.IP, error) {
+// Lease IP from named pool
+func (i *IPAM) Lease(name, owner string) (*net.IP, error) {
 
-	var (
-		lease string
-	)
+	i.lock.Lock()
+	defer i.lock.Unlock()
 
-	// Check available leases count
-	if i.available == 0 {
-		return nil, errors.New(IPAMLeaseNotAvailable)
+	if name == types.EmptyString {
+		name = types.IPAMPoolDefault
 	}
 
-	// Get first map element
-	for i := range i.released {
-		lease = i
-		break
+	p, ok := i.pools[name]
+	if !ok {
+		return nil, errors.New(IPAMPoolNotFound)
 	}
 
-	// Mark lease IP as leased
-	delete(i.released, lease)
-	i.available--
-	// Find new lease for next reservation
-
-	// Decrease available count
-	i.leased[lease] = true
-	i.reserved++
+	ip, err := p.lease(i.leases)
+	if err != nil {
+		return nil, err
+	}
 
-	ip := net.ParseIP(lease)
+	i.leases[ip.String()] = &types.IPAMLease{
+		IP:      ip.String(),
+		Pool:    p.name,
+		Owner:   owner,
+		Created: time.Now(),
+	}
+	p.reserved++
 
 	if err := i.save(); err != nil {
 		return nil, err
@@ -79,101 +90,224 @@ func (i *IPAM) Lease() (*net.IP, error) {
 	return &ip, nil
 }
 
-// release IP
+// Release IP
 func (i *IPAM) Release(ip *net.IP) error {
 
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if !i.release(ip.String()) {
+		return nil
+	}
+
+	return i.save()
+}
+
+// Reconcile leases with addresses in use:
+// leases without owner reference are released after grace period,
+// addresses in use without lease are reserved in pool
+func (i *IPAM) Reconcile(owners map[string]string) error {
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
 	var (
-		lease = ip.String()
+		changed bool
+		now     = time.Now()
 	)
 
-	// Mark IP as released
-	delete(i.leased, lease)
-	i.reserved--
-	// Add IP as released and increase available count
-	i.released[lease] = true
-	i.available++
+	for ip, owner := range owners {
+
+		if l, ok := i.leases[ip]; ok {
+			if l.Owner != owner {
+				l.Owner = owner
+				changed = true
+			}
+			continue
+		}
+
+		p := i.pool(net.ParseIP(ip))
+		if p == nil {
+			continue
+		}
+
+		log.Warnf("%s reserve address %s used by %s without lease", logIPAMPrefix, ip, owner)
+
+		i.leases[ip] = &types.IPAMLease{IP: ip, Pool: p.name, Owner: owner, Created: now}
+		p.reserved++
+		changed = true
+	}
+
+	for ip, l := range i.leases {
+
+		if _, ok := owners[ip]; ok {
+			continue
+		}
+
+		if now.Sub(l.Created) < ReconcileGrace {
+			continue
+		}
+
+		log.Warnf("%s release leaked address %s of %s", logIPAMPrefix, ip, l.Owner)
+
+		i.release(ip)
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
 
 	return i.save()
 }
 
-// Available ips count
-func (i *IPAM) Available() int {
-	return i.available
+// Pools with addresses usage
+func (i *IPAM) Pools() map[string]*types.IPAMPool {
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	return i.status()
 }
 
-// Reserved ips count
-func (i *IPAM) Reserved() int {
-	return i.reserved
+func (i *IPAM) status() map[string]*types.IPAMPool {
+
+	pools := make(map[string]*types.IPAMPool, len(i.pools))
+	for name, p := range i.pools {
+		pools[name] = &types.IPAMPool{
+			Name:   p.name,
+			CIDR:   p.cidr,
+			Family: p.family,
+			Status: p.status(),
+		}
+	}
+
+	return pools
 }
 
-func (i *IPAM) save() error {
+func (i *IPAM) release(ip string) bool {
 
-	var (
-		ips = make([]string, 0)
-	)
+	l, ok := i.leases[ip]
+	if !ok {
+		return false
+	}
 
-	for ip := range i.leased {
-		ips = append(ips, ip)
+	delete(i.leases, ip)
+
+	if p, ok := i.pools[l.Pool]; ok && p.reserved > 0 {
+		p.reserved--
 	}
 
-	opts := storage.GetOpts()
-	opts.Force = true
-	return i.storage.Set(context.Background(), i.storage.Collection().System(), "ipam", &ips, opts)
+	return true
 }
 
-// New IPAM object initializing and returning
-func New(cidr string) (*IPAM, error) {
+// pool returns pool which contains provided address
+func (i *IPAM) pool(ip net.IP) *pool {
+
+	names := make([]string, 0, len(i.pools))
+	for name := range i.pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if i.pools[name].contains(ip) {
+			return i.pools[name]
+		}
+	}
+
+	return nil
+}
+
+func (i *IPAM) save() error {
+	state := types.NewIPAM()
+	state.Pools = i.status()
+	state.Leases = i.leases
+	return distribution.NewIPAMModel(context.Background(), i.storage).Set(state)
+}
+
+// restore leases from storage, addresses out of configured pools are dropped
+func (i *IPAM) restore() error {
 
 	var (
-		skip = true
-		ipam = new(IPAM)
-		stg  = envs.Get().GetStorage()
+		leases = make(map[string]*types.IPAMLease)
+		im     = distribution.NewIPAMModel(context.Background(), i.storage)
 	)
 
-	ipam.storage = envs.Get().GetStorage()
-	ipam.leased = make(map[string]bool, 0)
-	ipam.released = make(map[string]bool, 0)
-
-	if cidr == "" {
-		cidr = defaultCIDR
+	state, err := im.Get()
+	if err != nil {
+		log.Errorf("%s get context error: %s", logIPAMPrefix, err.Error())
+		return err
 	}
 
-	// Get IP range by network CIDR
-	ip, ipnet, err := net.ParseCIDR(cidr)
-	if err != nil {
-		return nil, err
+	if state != nil {
+		leases = state.Leases
+	} else {
+
+		ips := make([]string, 0)
+
+		// Get IP list stored before pools were introduced
+		err = i.storage.Get(context.Background(), i.storage.Collection().System(), legacyKey, &ips, nil)
+		if err != nil {
+			if !errors.Storage().IsErrEntityNotFound(err) {
+				log.Errorf("%s get context error: %s", logIPAMPrefix, err.Error())
+				return err
+			}
+		}
+
+		for _, ip := range ips {
+			leases[ip] = &types.IPAMLease{IP: ip, Created: time.Now()}
+		}
 	}
 
-	for ip := ip.Mask(ipnet.Mask); ipnet.Contains(ip); inc(ip) {
-		if skip {
-			// remove network address and broadcast address
-			skip = false
+	// Mark IPs as leased
+	for ip, l := range leases {
+
+		p := i.pool(net.ParseIP(ip))
+		if p == nil {
+			log.Warnf("%s drop lease %s: address is out of pools", logIPAMPrefix, ip)
 			continue
 		}
 
-		ipam.released[ip.String()] = true
-		ipam.available++
+		l.Pool = p.name
+		i.leases[ip] = l
+		p.reserved++
 	}
 
-	ips := make([]string, 0)
+	return nil
+}
 
-	// Get IP list from database storage
-	err = stg.Get(context.Background(), stg.Collection().System(), "ipam", &ips, nil)
-	if err != nil {
-		if !errors.Storage().IsErrEntityNotFound(err) {
-			log.Errorf("%s get context error: %s", logIPAMPrefix, err.Error())
-			return nil, err
+// New IPAM object initializing with pools CIDRs mapped by name and returning
+func New(pools map[string]string) (*IPAM, error) {
+
+	var (
+		ipam = new(IPAM)
+	)
+
+	ipam.storage = envs.Get().GetStorage()
+	ipam.pools = make(map[string]*pool, 0)
+	ipam.leases = make(map[string]*types.IPAMLease, 0)
+
+	if _, ok := pools[types.IPAMPoolDefault]; !ok {
+		if pools == nil {
+			pools = make(map[string]string)
 		}
+		pools[types.IPAMPoolDefault] = defaultCIDR
 	}
 
-	// Mark IPs as leased
-	for _, item := range ips {
-		if _, ok := ipam.released[item]; ok {
-			delete(ipam.released, item)
-			ipam.available--
-			ipam.leased[item] = true
-			ipam.reserved++
+	for name, cidr := range pools {
+
+		// Get IP range by network CIDR
+		p, err := newPool(name, cidr)
+		if err != nil {
+			return nil, err
 		}
+
+		ipam.pools[name] = p
+	}
+
+	if err := ipam.restore(); err != nil {
+		return nil, err
 	}
 
 	return ipam, nil
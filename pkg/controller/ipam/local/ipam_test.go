@@ -0,0 +1,230 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package local
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func setup(t *testing.T) storage.Storage {
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+	assert.NoError(t, stg.Del(context.Background(), stg.Collection().System(), types.EmptyString))
+	return stg
+}
+
+func TestIPAMLease(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		pools   map[string]string
+		pool    string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "lease from default pool",
+			pools: nil,
+			pool:  types.EmptyString,
+			want:  []string{"172.17.0.1", "172.17.0.2"},
+		},
+		{
+			name:  "lease from ipv4 pool skips network and broadcast",
+			pools: map[string]string{"small": "10.0.0.0/30"},
+			pool:  "small",
+			want:  []string{"10.0.0.1", "10.0.0.2"},
+		},
+		{
+			name:  "lease from ipv6 pool",
+			pools: map[string]string{"v6": "fd00::/120"},
+			pool:  "v6",
+			want:  []string{"fd00::1", "fd00::2"},
+		},
+		{
+			name:    "lease from exhausted pool",
+			pools:   map[string]string{"small": "10.0.0.0/30"},
+			pool:    "small",
+			want:    []string{"10.0.0.1", "10.0.0.2", ""},
+			wantErr: IPAMLeaseNotAvailable,
+		},
+		{
+			name:    "lease from unknown pool",
+			pool:    "unknown",
+			want:    []string{""},
+			wantErr: IPAMPoolNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			setup(t)
+
+			i, err := New(tc.pools)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, w := range tc.want {
+
+				ip, err := i.Lease(tc.pool, "ns:svc")
+				if w == types.EmptyString {
+					if assert.Error(t, err) {
+						assert.Equal(t, tc.wantErr, err.Error())
+					}
+					continue
+				}
+
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.Equal(t, w, ip.String())
+			}
+		})
+	}
+}
+
+func TestIPAMRelease(t *testing.T) {
+
+	setup(t)
+
+	i, err := New(map[string]string{"small": "10.0.0.0/30"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	a, err := i.Lease("small", "ns:a")
+	assert.NoError(t, err)
+	_, err = i.Lease("small", "ns:b")
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(0), i.Pools()["small"].Status.Available)
+
+	assert.NoError(t, i.Release(a))
+	assert.Equal(t, uint64(1), i.Pools()["small"].Status.Available)
+
+	// releasing unknown address is not an error
+	unknown := net.ParseIP("192.168.0.1")
+	assert.NoError(t, i.Release(&unknown))
+
+	ip, err := i.Lease("small", "ns:c")
+	if assert.NoError(t, err) {
+		assert.Equal(t, a.String(), ip.String())
+	}
+}
+
+func TestIPAMReconcile(t *testing.T) {
+
+	setup(t)
+
+	i, err := New(nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	leaked, _ := i.Lease(types.EmptyString, "ns:leaked")
+	fresh, _ := i.Lease(types.EmptyString, "ns:fresh")
+	used, _ := i.Lease(types.EmptyString, types.EmptyString)
+
+	i.leases[leaked.String()].Created = time.Now().Add(-2 * ReconcileGrace)
+	i.leases[used.String()].Created = time.Now().Add(-2 * ReconcileGrace)
+
+	owners := map[string]string{
+		used.String(): "ns:used",
+		"172.17.1.1":  "ns:manual",
+		"10.0.0.1":    "ns:external",
+	}
+
+	if !assert.NoError(t, i.Reconcile(owners)) {
+		return
+	}
+
+	assert.NotContains(t, i.leases, leaked.String(), "leaked address should be released")
+	assert.Contains(t, i.leases, fresh.String(), "address in grace period should be kept")
+	assert.Contains(t, i.leases, "172.17.1.1", "used address should be reserved")
+	assert.NotContains(t, i.leases, "10.0.0.1", "address out of pools should be skipped")
+	assert.Equal(t, "ns:used", i.leases[used.String()].Owner)
+	assert.Equal(t, uint64(3), i.Pools()[types.IPAMPoolDefault].Status.Reserved)
+}
+
+func TestIPAMRestore(t *testing.T) {
+
+	t.Run("restore state", func(t *testing.T) {
+
+		setup(t)
+
+		i, err := New(nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		ip, err := i.Lease(types.EmptyString, "ns:svc")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		r, err := New(nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		if assert.Contains(t, r.leases, ip.String()) {
+			assert.Equal(t, "ns:svc", r.leases[ip.String()].Owner)
+		}
+		assert.Equal(t, uint64(1), r.Pools()[types.IPAMPoolDefault].Status.Reserved)
+
+		state, err := distribution.NewIPAMModel(context.Background(), envs.Get().GetStorage()).Get()
+		if assert.NoError(t, err) && assert.NotNil(t, state) {
+			assert.Contains(t, state.Pools, types.IPAMPoolDefault)
+		}
+	})
+
+	t.Run("import legacy leases", func(t *testing.T) {
+
+		stg := setup(t)
+
+		ips := []string{"172.17.0.1", "10.0.0.1"}
+		err := stg.Put(context.Background(), stg.Collection().System(), legacyKey, &ips, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		i, err := New(nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Contains(t, i.leases, "172.17.0.1")
+		assert.NotContains(t, i.leases, "10.0.0.1")
+
+		ip, err := i.Lease(types.EmptyString, "ns:svc")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "172.17.0.2", ip.String())
+		}
+	})
+}
@@ -0,0 +1,141 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package local
+
+import (
+	"math"
+	"math/big"
+	"net"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+// pool - named range of usable addresses,
+// addresses are calculated by offset from the first usable address
+type pool struct {
+	name     string
+	cidr     string
+	family   string
+	network  *net.IPNet
+	first    *big.Int
+	size     *big.Int
+	next     *big.Int
+	reserved uint64
+}
+
+// lease returns next address which is not leased yet
+func (p *pool) lease(leased map[string]*types.IPAMLease) (net.IP, error) {
+
+	if new(big.Int).SetUint64(p.reserved).Cmp(p.size) >= 0 {
+		return nil, errors.New(IPAMLeaseNotAvailable)
+	}
+
+	for {
+		ip := p.ip(p.next)
+
+		p.next.Add(p.next, big.NewInt(1))
+		if p.next.Cmp(p.size) >= 0 {
+			p.next.SetInt64(0)
+		}
+
+		if _, ok := leased[ip.String()]; !ok {
+			return ip, nil
+		}
+	}
+}
+
+// contains checks if address is in pool usable range
+func (p *pool) contains(ip net.IP) bool {
+
+	if ip == nil || !p.network.Contains(ip) {
+		return false
+	}
+
+	offset := new(big.Int).Sub(ipToInt(ip), p.first)
+	return offset.Sign() >= 0 && offset.Cmp(p.size) < 0
+}
+
+// ip returns address by offset from the first usable address
+func (p *pool) ip(offset *big.Int) net.IP {
+
+	var (
+		v  = new(big.Int).Add(p.first, offset)
+		b  = v.Bytes()
+		ip = make(net.IP, len(p.network.IP))
+	)
+
+	copy(ip[len(ip)-len(b):], b)
+	return ip
+}
+
+// status returns pool addresses usage
+func (p *pool) status() types.IPAMPoolStatus {
+
+	s := types.IPAMPoolStatus{}
+	s.Total = math.MaxUint64
+	if p.size.IsUint64() {
+		s.Total = p.size.Uint64()
+	}
+
+	s.Reserved = p.reserved
+	if s.Total > s.Reserved {
+		s.Available = s.Total - s.Reserved
+	}
+
+	return s
+}
+
+func newPool(name, cidr string) (*pool, error) {
+
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := new(pool)
+	p.name = name
+	p.cidr = network.String()
+	p.network = network
+	p.family = types.IPAMFamilyIPv6
+	p.next = big.NewInt(0)
+
+	if ip := network.IP.To4(); ip != nil {
+		p.family = types.IPAMFamilyIPv4
+		p.network.IP = ip
+	}
+
+	ones, bits := network.Mask.Size()
+	hosts := uint(bits - ones)
+
+	p.first = ipToInt(network.IP)
+	p.size = new(big.Int).Lsh(big.NewInt(1), hosts)
+
+	// skip network address and IPv4 broadcast address
+	switch {
+	case p.family == types.IPAMFamilyIPv4 && hosts >= 2:
+		p.first.Add(p.first, big.NewInt(1))
+		p.size.Sub(p.size, big.NewInt(2))
+	case p.family == types.IPAMFamilyIPv6 && hosts >= 1:
+		p.first.Add(p.first, big.NewInt(1))
+		p.size.Sub(p.size, big.NewInt(1))
+	}
+
+	return p, nil
+}
@@ -19,14 +19,13 @@
 package local
 
 import (
+	"math/big"
 	"net"
 )
 
-func inc(ip net.IP) {
-	for j := len(ip) - 1; j >= 0; j-- {
-		ip[j]++
-		if ip[j] > 0 {
-			break
-		}
+func ipToInt(ip net.IP) *big.Int {
+	if v := ip.To4(); v != nil {
+		return new(big.Int).SetBytes(v)
 	}
+	return new(big.Int).SetBytes(ip.To16())
 }
@@ -0,0 +1,86 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package lifecycle
+
+import (
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+const (
+	// DefaultGracePeriod - time without node heartbeat before node is marked as not ready
+	DefaultGracePeriod = 40 * time.Second
+	// DefaultEvictionTimeout - time node stays not ready before its pods are evicted
+	DefaultEvictionTimeout = 5 * time.Minute
+	// CheckInterval - nodes heartbeat check interval
+	CheckInterval = 5 * time.Second
+)
+
+// Lifecycle tracks nodes heartbeats:
+// node without heartbeat during grace period is marked as not ready,
+// pods of not ready node are evicted after eviction timeout
+type Lifecycle struct {
+	grace    time.Duration
+	eviction time.Duration
+}
+
+// GracePeriod returns time without heartbeat before node is marked as not ready
+func (l *Lifecycle) GracePeriod() time.Duration {
+	return l.grace
+}
+
+// EvictionTimeout returns time node stays not ready before its pods are evicted
+func (l *Lifecycle) EvictionTimeout() time.Duration {
+	return l.eviction
+}
+
+// Expired checks if node heartbeat is older than grace period
+func (l *Lifecycle) Expired(n *types.Node, now time.Time) bool {
+	if n.Status.Heartbeat.IsZero() {
+		return false
+	}
+	return now.Sub(n.Status.Heartbeat) > l.grace
+}
+
+// Lost checks if node is not ready longer than eviction timeout
+func (l *Lifecycle) Lost(n *types.Node, now time.Time) bool {
+	if n.Status.Online || n.Status.Heartbeat.IsZero() {
+		return false
+	}
+	return now.Sub(n.Status.Heartbeat) > l.grace+l.eviction
+}
+
+// New returns nodes lifecycle instance, defaults are used for zero durations
+func New(grace, eviction time.Duration) *Lifecycle {
+
+	l := new(Lifecycle)
+	l.grace = grace
+	l.eviction = eviction
+
+	if l.grace <= 0 {
+		l.grace = DefaultGracePeriod
+	}
+
+	if l.eviction <= 0 {
+		l.eviction = DefaultEvictionTimeout
+	}
+
+	return l
+}
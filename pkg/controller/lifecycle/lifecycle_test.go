@@ -0,0 +1,98 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package lifecycle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func getNodeAsset(online bool, heartbeat time.Time) *types.Node {
+	n := new(types.Node)
+	n.Meta.Name = "node"
+	n.Status.Online = online
+	n.Status.Heartbeat = heartbeat
+	return n
+}
+
+func TestLifecycle(t *testing.T) {
+
+	var (
+		now = time.Now()
+		lc  = New(time.Minute, 5*time.Minute)
+	)
+
+	var tests = []struct {
+		name    string
+		node    *types.Node
+		expired bool
+		lost    bool
+	}{
+		{
+			name: "node without heartbeat is skipped",
+			node: getNodeAsset(true, time.Time{}),
+		},
+		{
+			name: "node with recent heartbeat is ready",
+			node: getNodeAsset(true, now.Add(-30*time.Second)),
+		},
+		{
+			name:    "node heartbeat expired after grace period",
+			node:    getNodeAsset(true, now.Add(-2*time.Minute)),
+			expired: true,
+		},
+		{
+			name:    "online node is not lost",
+			node:    getNodeAsset(true, now.Add(-10*time.Minute)),
+			expired: true,
+		},
+		{
+			name:    "not ready node is not lost before eviction timeout",
+			node:    getNodeAsset(false, now.Add(-3*time.Minute)),
+			expired: true,
+		},
+		{
+			name:    "not ready node is lost after eviction timeout",
+			node:    getNodeAsset(false, now.Add(-7*time.Minute)),
+			expired: true,
+			lost:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expired, lc.Expired(tc.node, now), "expired not equal")
+			assert.Equal(t, tc.lost, lc.Lost(tc.node, now), "lost not equal")
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+
+	lc := New(0, 0)
+	assert.Equal(t, DefaultGracePeriod, lc.GracePeriod(), "grace period not equal")
+	assert.Equal(t, DefaultEvictionTimeout, lc.EvictionTimeout(), "eviction timeout not equal")
+
+	lc = New(time.Second, time.Minute)
+	assert.Equal(t, time.Second, lc.GracePeriod(), "grace period not equal")
+	assert.Equal(t, time.Minute, lc.EvictionTimeout(), "eviction timeout not equal")
+}
@@ -40,7 +40,8 @@ func (o *Observer) Loop() {
 }
 
 func (o *Observer) Stop() {
-	o.state = nil
+	o.state.Stop()
+	o.state = state.NewState()
 }
 
 func NewObserver(ctx context.Context) *Observer {
@@ -0,0 +1,190 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package scheduler
+
+import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+// Predicate checks if node can be used for request
+type Predicate func(st *State, n *types.Node, req *Request) bool
+
+// PredicateNodeOnline skips nodes which are not online
+func PredicateNodeOnline(st *State, n *types.Node, req *Request) bool {
+	return n.Status.Online
+}
+
+// PredicateNodeSchedulable skips cordoned nodes
+func PredicateNodeSchedulable(st *State, n *types.Node, req *Request) bool {
+	return !n.Spec.Unschedulable
+}
+
+// PredicateNodeTaints requires all node taints to be tolerated by selector
+func PredicateNodeTaints(st *State, n *types.Node, req *Request) bool {
+	for _, t := range n.Spec.Taints {
+		if !req.Selector.Tolerate(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// PredicateNodeSelector matches node by name if it is set in selector
+func PredicateNodeSelector(st *State, n *types.Node, req *Request) bool {
+	if req.Selector.Node == types.EmptyString {
+		return true
+	}
+	return n.SelfLink() == req.Selector.Node
+}
+
+// PredicateNodeLabels requires all selector labels to be present on node with equal values
+func PredicateNodeLabels(st *State, n *types.Node, req *Request) bool {
+	for k, v := range req.Selector.Labels {
+		l, ok := n.Meta.Labels[k]
+		if !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
+
+// PredicateNodeResources checks that node has enough free resources for request.
+// CPU is checked only if node reports its capacity
+func PredicateNodeResources(st *State, n *types.Node, req *Request) bool {
+
+	var (
+		capacity  = n.Status.Capacity
+		allocated = n.Status.Allocated
+		r         = req.Resources
+	)
+
+	if r.Pods > 0 && capacity.Pods > 0 {
+		if capacity.Pods-allocated.Pods < r.Pods {
+			return false
+		}
+	}
+
+	if r.Memory > 0 {
+		if capacity.Memory-allocated.Memory < r.Memory {
+			return false
+		}
+	}
+
+	if r.Cpu > 0 && capacity.Cpu > 0 {
+		if capacity.Cpu-allocated.Cpu < r.Cpu {
+			return false
+		}
+	}
+
+	if r.Storage > 0 {
+		if capacity.Storage-allocated.Storage < r.Storage {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PredicatePodAffinity requires pods of selected services in node topology domain.
+// Affinity to own service is ignored until the first pod of service is placed
+func PredicatePodAffinity(st *State, n *types.Node, req *Request) bool {
+	for _, a := range req.Selector.Affinity {
+
+		svc := serviceLink(req, a.Service)
+		if svc == req.Service && st.Total(svc) == 0 {
+			continue
+		}
+
+		if a.Topology != types.EmptyString {
+			if _, ok := n.Meta.Labels[a.Topology]; !ok {
+				return false
+			}
+		}
+
+		if st.Domain(n, a.Topology, svc) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// PredicatePodAntiAffinity skips nodes with pods of selected services in node topology domain
+func PredicatePodAntiAffinity(st *State, n *types.Node, req *Request) bool {
+	for _, a := range req.Selector.AntiAffinity {
+		if st.Domain(n, a.Topology, serviceLink(req, a.Service)) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// spread keeps nodes where placing a pod doesn't exceed allowed skew between topology domains.
+// Only domains with at least one suitable node are counted
+func spread(st *State, nodes []*types.Node, req *Request, c types.SpecSelectorSpread) []*types.Node {
+
+	var (
+		list    = make([]*types.Node, 0)
+		domains = make(map[string]int)
+		skew    = c.MaxSkew
+		min     = -1
+	)
+
+	if skew < 1 {
+		skew = 1
+	}
+
+	for _, n := range nodes {
+		value, ok := n.Meta.Labels[c.Topology]
+		if !ok {
+			continue
+		}
+
+		if _, ok := domains[value]; !ok {
+			domains[value] = st.Domain(n, c.Topology, req.Service)
+		}
+	}
+
+	for _, count := range domains {
+		if min == -1 || count < min {
+			min = count
+		}
+	}
+
+	for _, n := range nodes {
+		value, ok := n.Meta.Labels[c.Topology]
+		if !ok {
+			continue
+		}
+
+		if domains[value]+1-min <= skew {
+			list = append(list, n)
+		}
+	}
+
+	return list
+}
+
+// serviceLink returns service self link by name in request namespace,
+// service of request is used if name is empty
+func serviceLink(req *Request, name string) string {
+	if name == types.EmptyString {
+		return req.Service
+	}
+	return new(types.Service).CreateSelfLink(req.Namespace, name)
+}
@@ -0,0 +1,146 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package scheduler
+
+import (
+	"sort"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+const (
+	StrategySpread         = "spread"
+	StrategyBinPack        = "binpack"
+	StrategyLeastAllocated = "least-allocated"
+
+	SchedulerStrategyNotSupported = "SchedulerStrategyNotSupported"
+)
+
+// Request describes resources and placement rules needed by the scheduled object
+type Request struct {
+	// Namespace of scheduled object, used to resolve services in selector
+	Namespace string
+	// Service self link of scheduled object
+	Service string
+	// Node placement rules
+	Selector types.SpecSelector
+	// Resources to allocate on node
+	Resources types.NodeResources
+}
+
+// Scheduler selects the best node for the request:
+// nodes are filtered by predicates first and then scored by strategy
+type Scheduler struct {
+	strategy   string
+	predicates []Predicate
+	score      Strategy
+}
+
+// Strategy returns current scheduler scoring strategy name
+func (s *Scheduler) Strategy() string {
+	return s.strategy
+}
+
+// Filter returns nodes passed all predicates and spread constraints, sorted by self link
+func (s *Scheduler) Filter(st *State, req *Request) []*types.Node {
+
+	var list = make([]*types.Node, 0)
+
+	for _, n := range st.Nodes {
+
+		var fit = true
+		for _, p := range s.predicates {
+			if !p(st, n, req) {
+				fit = false
+				break
+			}
+		}
+
+		if fit {
+			list = append(list, n)
+		}
+	}
+
+	for _, c := range req.Selector.Spread {
+		list = spread(st, list, req, c)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].SelfLink() < list[j].SelfLink()
+	})
+
+	return list
+}
+
+// Schedule returns the node with the highest score for the request.
+// Nodes with equal score are resolved by self link order to keep results stable.
+// Returns nil if no node satisfies the request
+func (s *Scheduler) Schedule(st *State, req *Request) *types.Node {
+
+	var (
+		node  *types.Node
+		score float64
+	)
+
+	for _, n := range s.Filter(st, req) {
+		ns := s.score(n, req)
+		if node == nil || ns > score {
+			node = n
+			score = ns
+		}
+	}
+
+	return node
+}
+
+// New scheduler with scoring strategy, spread strategy is used by default
+func New(strategy string) (*Scheduler, error) {
+
+	var s = new(Scheduler)
+
+	if strategy == types.EmptyString {
+		strategy = StrategySpread
+	}
+
+	switch strategy {
+	case StrategySpread:
+		s.score = ScoreSpread
+	case StrategyBinPack:
+		s.score = ScoreBinPack
+	case StrategyLeastAllocated:
+		s.score = ScoreLeastAllocated
+	default:
+		return nil, errors.New(SchedulerStrategyNotSupported)
+	}
+
+	s.strategy = strategy
+	s.predicates = []Predicate{
+		PredicateNodeOnline,
+		PredicateNodeSchedulable,
+		PredicateNodeTaints,
+		PredicateNodeSelector,
+		PredicateNodeLabels,
+		PredicateNodeResources,
+		PredicatePodAffinity,
+		PredicatePodAntiAffinity,
+	}
+
+	return s, nil
+}
@@ -0,0 +1,388 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func getNodeAsset(name string, labels map[string]string, capacity, allocated types.NodeResources) *types.Node {
+
+	n := new(types.Node)
+
+	n.Meta.Name = name
+	n.Meta.Labels = labels
+	n.Status.Online = true
+	n.Status.Capacity = capacity
+	n.Status.Allocated = allocated
+	n.SelfLink()
+
+	return n
+}
+
+func getStateAsset(nodes ...*types.Node) *State {
+	st := NewState()
+	for _, n := range nodes {
+		st.Nodes[n.SelfLink()] = n
+	}
+	return st
+}
+
+func TestScheduler_Filter(t *testing.T) {
+
+	var (
+		capacity = types.NodeResources{Pods: 10, Memory: 1024, Cpu: 2000, Storage: 1024}
+		empty    = types.NodeResources{}
+	)
+
+	offline := getNodeAsset("offline", nil, capacity, empty)
+	offline.Status.Online = false
+
+	cordoned := getNodeAsset("cordoned", nil, capacity, empty)
+	cordoned.Spec.Unschedulable = true
+
+	tainted := getNodeAsset("tainted", nil, capacity, empty)
+	tainted.Spec.Taints = []types.NodeTaint{{Key: "dedicated", Value: "db", Effect: types.NodeTaintEffectNoSchedule}}
+
+	drained := getNodeAsset("drained", nil, capacity, empty)
+	drained.Spec.Taints = []types.NodeTaint{{Key: types.NodeTaintDrain, Effect: types.NodeTaintEffectNoExecute}}
+
+	var tests = []struct {
+		name  string
+		nodes *State
+		req   *Request
+		want  []string
+	}{
+		{
+			name: "skip offline nodes",
+			nodes: getStateAsset(
+				getNodeAsset("node", nil, capacity, empty),
+				offline,
+			),
+			req:  &Request{},
+			want: []string{"node"},
+		},
+		{
+			name: "select node by name",
+			nodes: getStateAsset(
+				getNodeAsset("a", nil, capacity, empty),
+				getNodeAsset("b", nil, capacity, empty),
+			),
+			req:  &Request{Selector: types.SpecSelector{Node: "b"}},
+			want: []string{"b"},
+		},
+		{
+			name: "all selector labels should match",
+			nodes: getStateAsset(
+				getNodeAsset("a", map[string]string{"zone": "a", "type": "ssd"}, capacity, empty),
+				getNodeAsset("b", map[string]string{"zone": "a", "type": "hdd"}, capacity, empty),
+				getNodeAsset("c", map[string]string{"zone": "b"}, capacity, empty),
+				getNodeAsset("d", nil, capacity, empty),
+			),
+			req:  &Request{Selector: types.SpecSelector{Labels: map[string]string{"zone": "a", "type": "ssd"}}},
+			want: []string{"a"},
+		},
+		{
+			name: "skip nodes without free resources",
+			nodes: getStateAsset(
+				getNodeAsset("memory", nil, capacity, types.NodeResources{Memory: 1000}),
+				getNodeAsset("cpu", nil, capacity, types.NodeResources{Cpu: 1900}),
+				getNodeAsset("pods", nil, capacity, types.NodeResources{Pods: 10}),
+				getNodeAsset("free", nil, capacity, empty),
+			),
+			req:  &Request{Resources: types.NodeResources{Pods: 1, Memory: 128, Cpu: 500}},
+			want: []string{"free"},
+		},
+		{
+			name: "skip nodes without free storage",
+			nodes: getStateAsset(
+				getNodeAsset("a", nil, capacity, types.NodeResources{Storage: 1000}),
+				getNodeAsset("b", nil, capacity, empty),
+			),
+			req:  &Request{Resources: types.NodeResources{Storage: 512}},
+			want: []string{"b"},
+		},
+		{
+			name:  "skip cordoned nodes",
+			nodes: getStateAsset(getNodeAsset("node", nil, capacity, empty), cordoned),
+			req:   &Request{},
+			want:  []string{"node"},
+		},
+		{
+			name:  "skip tainted nodes without toleration",
+			nodes: getStateAsset(getNodeAsset("node", nil, capacity, empty), tainted, drained),
+			req:   &Request{},
+			want:  []string{"node"},
+		},
+		{
+			name:  "toleration with equal operator matches key and value",
+			nodes: getStateAsset(tainted, drained),
+			req: &Request{Selector: types.SpecSelector{Tolerations: []types.SpecSelectorToleration{
+				{Key: "dedicated", Value: "db"},
+				{Key: types.NodeTaintDrain, Value: "any"},
+			}}},
+			want: []string{"tainted"},
+		},
+		{
+			name:  "toleration with exists operator matches key and effect",
+			nodes: getStateAsset(tainted, drained),
+			req: &Request{Selector: types.SpecSelector{Tolerations: []types.SpecSelectorToleration{
+				{Key: types.NodeTaintDrain, Operator: types.SpecSelectorTolerationOperatorExists, Effect: types.NodeTaintEffectNoExecute},
+				{Key: "dedicated", Operator: types.SpecSelectorTolerationOperatorExists, Effect: types.NodeTaintEffectNoExecute},
+			}}},
+			want: []string{"drained"},
+		},
+		{
+			name:  "toleration with empty key tolerates all taints",
+			nodes: getStateAsset(tainted, drained, cordoned),
+			req: &Request{Selector: types.SpecSelector{Tolerations: []types.SpecSelectorToleration{
+				{Operator: types.SpecSelectorTolerationOperatorExists},
+			}}},
+			want: []string{"drained", "tainted"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			s, err := New(StrategySpread)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			got := make([]string, 0)
+			for _, n := range s.Filter(tc.nodes, tc.req) {
+				got = append(got, n.SelfLink())
+			}
+
+			assert.Equal(t, tc.want, got, "filtered nodes are different")
+		})
+	}
+}
+
+func TestScheduler_Schedule(t *testing.T) {
+
+	var (
+		capacity = types.NodeResources{Pods: 10, Memory: 1000, Cpu: 1000, Storage: 1000}
+		req      = &Request{Resources: types.NodeResources{Pods: 1, Memory: 100}}
+	)
+
+	nodes := func() *State {
+		return getStateAsset(
+			getNodeAsset("a", nil, capacity, types.NodeResources{Pods: 1, Memory: 600}),
+			getNodeAsset("b", nil, capacity, types.NodeResources{Pods: 3, Memory: 300}),
+			getNodeAsset("c", nil, capacity, types.NodeResources{Pods: 1, Memory: 100}),
+		)
+	}
+
+	var tests = []struct {
+		name     string
+		strategy string
+		nodes    *State
+		want     string
+	}{
+		{
+			name:     "spread selects node with fewer pods",
+			strategy: StrategySpread,
+			nodes:    nodes(),
+			want:     "a",
+		},
+		{
+			name:     "binpack selects the most allocated node",
+			strategy: StrategyBinPack,
+			nodes:    nodes(),
+			want:     "a",
+		},
+		{
+			name:     "least allocated selects node with the most free resources",
+			strategy: StrategyLeastAllocated,
+			nodes:    nodes(),
+			want:     "c",
+		},
+		{
+			name:     "equal scores resolved by node name",
+			strategy: StrategyLeastAllocated,
+			nodes: getStateAsset(
+				getNodeAsset("z", nil, capacity, types.NodeResources{}),
+				getNodeAsset("m", nil, capacity, types.NodeResources{}),
+				getNodeAsset("x", nil, capacity, types.NodeResources{}),
+			),
+			want: "m",
+		},
+		{
+			name:     "no nodes available",
+			strategy: StrategySpread,
+			nodes:    getStateAsset(),
+			want:     types.EmptyString,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			s, err := New(tc.strategy)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			n := s.Schedule(tc.nodes, req)
+			if tc.want == types.EmptyString {
+				assert.Nil(t, n, "node should be nil")
+				return
+			}
+
+			if !assert.NotNil(t, n, "node should not be nil") {
+				return
+			}
+
+			assert.Equal(t, tc.want, n.SelfLink(), "scheduled node is different")
+		})
+	}
+}
+
+func TestScheduler_FilterPlacement(t *testing.T) {
+
+	var (
+		capacity = types.NodeResources{Pods: 10, Memory: 1024, Cpu: 2000, Storage: 1024}
+		empty    = types.NodeResources{}
+		svc      = "test:service"
+		db       = "test:db"
+	)
+
+	nodes := func() *State {
+		return getStateAsset(
+			getNodeAsset("a", map[string]string{"zone": "a"}, capacity, empty),
+			getNodeAsset("b", map[string]string{"zone": "a"}, capacity, empty),
+			getNodeAsset("c", map[string]string{"zone": "b"}, capacity, empty),
+			getNodeAsset("d", nil, capacity, empty),
+		)
+	}
+
+	var tests = []struct {
+		name     string
+		state    *State
+		pods     map[string][]string
+		selector types.SpecSelector
+		want     []string
+	}{
+		{
+			name:  "anti affinity to own service skips nodes with replicas",
+			state: nodes(),
+			pods:  map[string][]string{"a": {svc}, "c": {svc}},
+			selector: types.SpecSelector{
+				AntiAffinity: []types.SpecSelectorAffinity{{}},
+			},
+			want: []string{"b", "d"},
+		},
+		{
+			name:  "anti affinity with topology skips the whole domain",
+			state: nodes(),
+			pods:  map[string][]string{"a": {svc}},
+			selector: types.SpecSelector{
+				AntiAffinity: []types.SpecSelectorAffinity{{Topology: "zone"}},
+			},
+			want: []string{"c", "d"},
+		},
+		{
+			name:  "affinity selects nodes with pods of another service",
+			state: nodes(),
+			pods:  map[string][]string{"b": {db}},
+			selector: types.SpecSelector{
+				Affinity: []types.SpecSelectorAffinity{{Service: "db"}},
+			},
+			want: []string{"b"},
+		},
+		{
+			name:  "affinity with topology selects nodes in the same domain",
+			state: nodes(),
+			pods:  map[string][]string{"b": {db}},
+			selector: types.SpecSelector{
+				Affinity: []types.SpecSelectorAffinity{{Service: "db", Topology: "zone"}},
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name:  "affinity to own service allows the first replica anywhere",
+			state: nodes(),
+			selector: types.SpecSelector{
+				Affinity: []types.SpecSelectorAffinity{{Service: "service"}},
+			},
+			want: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:  "spread selects nodes in the least loaded domain",
+			state: nodes(),
+			pods:  map[string][]string{"a": {svc}},
+			selector: types.SpecSelector{
+				Spread: []types.SpecSelectorSpread{{Topology: "zone", MaxSkew: 1}},
+			},
+			want: []string{"c"},
+		},
+		{
+			name:  "spread allows any labeled node while skew is satisfied",
+			state: nodes(),
+			pods:  map[string][]string{"a": {svc}, "c": {svc}},
+			selector: types.SpecSelector{
+				Spread: []types.SpecSelectorSpread{{Topology: "zone", MaxSkew: 1}},
+			},
+			want: []string{"a", "b", "c"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			s, err := New(StrategySpread)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for node, services := range tc.pods {
+				for _, link := range services {
+					tc.state.Add(node, link)
+				}
+			}
+
+			req := &Request{Namespace: "test", Service: svc, Selector: tc.selector}
+
+			got := make([]string, 0)
+			for _, n := range s.Filter(tc.state, req) {
+				got = append(got, n.SelfLink())
+			}
+
+			assert.Equal(t, tc.want, got, "filtered nodes are different")
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+
+	s, err := New("")
+	if assert.NoError(t, err) {
+		assert.Equal(t, StrategySpread, s.Strategy(), "default strategy is different")
+	}
+
+	_, err = New("unknown")
+	if assert.Error(t, err) {
+		assert.Equal(t, SchedulerStrategyNotSupported, err.Error(), "err message different")
+	}
+}
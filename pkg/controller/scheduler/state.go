@@ -0,0 +1,87 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package scheduler
+
+import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+// State is a snapshot of cluster nodes and pods placed on them
+type State struct {
+	// Nodes available for scheduling
+	Nodes map[string]*types.Node
+	// Pods count by node self link and service self link
+	Pods map[string]map[string]int
+}
+
+// Count returns pods count of service on node
+func (s *State) Count(node, service string) int {
+	if _, ok := s.Pods[node]; !ok {
+		return 0
+	}
+	return s.Pods[node][service]
+}
+
+// Total returns pods count of service in cluster
+func (s *State) Total(service string) int {
+	var count int
+	for node := range s.Pods {
+		count += s.Pods[node][service]
+	}
+	return count
+}
+
+// Domain returns pods count of service on all nodes in node topology domain.
+// Node itself is used as domain if topology is empty
+func (s *State) Domain(n *types.Node, topology, service string) int {
+
+	if topology == types.EmptyString {
+		return s.Count(n.SelfLink(), service)
+	}
+
+	value, ok := n.Meta.Labels[topology]
+	if !ok {
+		return 0
+	}
+
+	var count int
+	for _, d := range s.Nodes {
+		if v, ok := d.Meta.Labels[topology]; ok && v == value {
+			count += s.Count(d.SelfLink(), service)
+		}
+	}
+
+	return count
+}
+
+// Add pod of service on node
+func (s *State) Add(node, service string) {
+	if _, ok := s.Pods[node]; !ok {
+		s.Pods[node] = make(map[string]int)
+	}
+	s.Pods[node][service]++
+}
+
+// NewState returns new empty cluster snapshot
+func NewState() *State {
+	s := new(State)
+	s.Nodes = make(map[string]*types.Node)
+	s.Pods = make(map[string]map[string]int)
+	return s
+}
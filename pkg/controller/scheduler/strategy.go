@@ -0,0 +1,74 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package scheduler
+
+import (
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+// Strategy scores node for request, node with higher score wins
+type Strategy func(n *types.Node, req *Request) float64
+
+// ScoreSpread prefers nodes with fewer pods to distribute load across cluster
+func ScoreSpread(n *types.Node, req *Request) float64 {
+	return -float64(n.Status.Allocated.Pods)
+}
+
+// ScoreBinPack prefers the most allocated nodes to keep other nodes free
+func ScoreBinPack(n *types.Node, req *Request) float64 {
+	return usage(n, req)
+}
+
+// ScoreLeastAllocated prefers nodes with the most free resources
+func ScoreLeastAllocated(n *types.Node, req *Request) float64 {
+	return 1 - usage(n, req)
+}
+
+// usage returns average share of node resources allocated after placing the request
+func usage(n *types.Node, req *Request) float64 {
+
+	var (
+		capacity  = n.Status.Capacity
+		allocated = n.Status.Allocated
+		r         = req.Resources
+		total     float64
+		count     float64
+	)
+
+	if capacity.Memory > 0 {
+		total += float64(allocated.Memory+r.Memory) / float64(capacity.Memory)
+		count++
+	}
+
+	if capacity.Cpu > 0 {
+		total += float64(allocated.Cpu+r.Cpu) / float64(capacity.Cpu)
+		count++
+	}
+
+	if r.Storage > 0 && capacity.Storage > 0 {
+		total += float64(allocated.Storage+r.Storage) / float64(capacity.Storage)
+		count++
+	}
+
+	if count == 0 {
+		return 0
+	}
+
+	return total / count
+}
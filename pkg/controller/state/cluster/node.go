@@ -20,9 +20,15 @@ package cluster
 
 import (
 	"context"
+	"reflect"
+	"sort"
+	"time"
+
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/controller/scheduler"
 	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
 )
 
 type NodeLease struct {
@@ -36,90 +42,273 @@ type NodeLease struct {
 }
 
 type NodeLeaseOptions struct {
+	Pod      *types.Pod
 	Node     *string
 	Memory   *int64
+	CPU      *int64
 	Storage  *int64
 	Selector types.SpecSelector
 }
 
-func (nl *NodeLease) Wait() {
-	<-nl.done
+// NodeMatch describes request for nodes matched by selector
+type NodeMatch struct {
+	done     chan []*types.Node
+	Selector types.SpecSelector
 }
 
-func handleNodeLease(cs *ClusterState, nl *NodeLease) error {
+// nodeObserve updates node in local cache and evicts pods
+// which do not tolerate node NoExecute taints
+func nodeObserve(cs *ClusterState, n *types.Node) {
 
-	defer func() {
-		if !nl.sync {
-			nl.done <- true
+	// node restored without heartbeat gets grace period from now
+	if n.Status.Online && n.Status.Heartbeat.IsZero() {
+		n.Status.Heartbeat = time.Now()
+	}
+
+	prev, ok := cs.node.list[n.SelfLink()]
+	if !ok || nodePlacementChanged(prev, n) {
+		nodeNotify(cs, n)
+	}
+
+	cs.node.list[n.SelfLink()] = n
+
+	pods := make([]*types.Pod, 0)
+	for _, p := range cs.pod.list {
+		if podTainted(n, p) {
+			pods = append(pods, p)
 		}
-	}()
+	}
+
+	podEvict(cs, false, pods...)
+}
+
+// nodeRemove removes node from local cache.
+// Pods placed on removed node can not be stopped gracefully and are evicted as lost
+func nodeRemove(cs *ClusterState, n *types.Node) {
+
+	node, ok := cs.node.list[n.SelfLink()]
+	if !ok {
+		return
+	}
+
+	delete(cs.node.list, n.SelfLink())
+
+	pods := make([]*types.Pod, 0)
+	for _, p := range cs.pod.list {
+		if podEvictable(node, p) {
+			pods = append(pods, p)
+		}
+	}
+
+	podEvict(cs, true, pods...)
+	nodeNotify(cs, node)
+}
+
+// nodeMatch returns copies of nodes matched by selector sorted by self link
+func nodeMatch(cs *ClusterState, selector types.SpecSelector) []*types.Node {
+
+	var (
+		list = make([]*types.Node, 0)
+		req  = &scheduler.Request{Selector: selector}
+	)
 
 	for _, n := range cs.node.list {
 
-		// check selectors first
+		if !scheduler.PredicateNodeSelector(nil, n, req) || !scheduler.PredicateNodeLabels(nil, n, req) {
+			continue
+		}
 
-		if nl.Request.Selector.Node != types.EmptyString {
-			if n.SelfLink() != nl.Request.Selector.Node {
-				continue
+		var tolerate = true
+		for _, t := range n.Spec.Taints {
+			if t.Effect == types.NodeTaintEffectNoExecute && !selector.Tolerate(t) {
+				tolerate = false
+				break
 			}
 		}
 
-		if len(nl.Request.Selector.Labels) > 0 {
-			if len(n.Meta.Labels) == 0 {
-				continue
-			}
+		if !tolerate {
+			continue
+		}
 
-			for k, v := range nl.Request.Selector.Labels {
-				if _, ok := n.Meta.Labels[k]; !ok {
-					continue
-				}
+		node := *n
+		list = append(list, &node)
+	}
 
-				if n.Meta.Labels[k] != v {
-					continue
-				}
-			}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].SelfLink() < list[j].SelfLink()
+	})
+
+	return list
+}
+
+// nodePlacementChanged checks if node attributes used for pods placement are changed
+func nodePlacementChanged(prev, n *types.Node) bool {
+
+	switch true {
+	case prev.Status.Online != n.Status.Online:
+		return true
+	case prev.Spec.Unschedulable != n.Spec.Unschedulable:
+		return true
+	case !reflect.DeepEqual(prev.Spec.Taints, n.Spec.Taints):
+		return true
+	case len(prev.Meta.Labels) != len(n.Meta.Labels):
+		return true
+	}
+
+	for k, v := range n.Meta.Labels {
+		if l, ok := prev.Meta.Labels[k]; !ok || l != v {
+			return true
+		}
+	}
+
+	return false
+}
 
+// nodeNotify passes changed node to service controllers.
+// Node is sent in background not to block cluster observer loop,
+// pending notifications are dropped when state is stopped
+func nodeNotify(cs *ClusterState, n *types.Node) {
+	go func() {
+		select {
+		case cs.node.notify <- n:
+		case <-cs.ctx.Done():
 		}
+	}()
+}
+
+// nodeLifecycle marks nodes without heartbeat during grace period as not ready
+// and evicts pods from nodes which are not ready longer than eviction timeout
+func nodeLifecycle(cs *ClusterState) {
 
-		var (
-			node      *types.Node
-			allocated = new(types.NodeResources)
-		)
+	lc := cs.Lifecycle()
+	if lc == nil {
+		return
+	}
 
-		if nl.Request.Memory != nil {
-			if (n.Status.Capacity.Memory - n.Status.Allocated.Memory) > *nl.Request.Memory {
-				node = n
-				allocated.Pods++
-				allocated.Memory += *nl.Request.Memory
+	var (
+		now = time.Now()
+		nm  = distribution.NewNodeModel(context.Background(), envs.Get().GetStorage())
+	)
+
+	for _, n := range cs.node.list {
+
+		if n.Status.Online && lc.Expired(n, now) {
+			log.Warnf("%s:> node %s heartbeat expired: mark node as not ready", logPrefix, n.SelfLink())
+
+			if err := nodeOffline(nm, n); err != nil {
+				log.Errorf("%s:> node %s set status err: %s", logPrefix, n.SelfLink(), err.Error())
 			}
+			continue
+		}
+
+		if !lc.Lost(n, now) {
+			continue
 		}
 
-		if nl.Request.Storage != nil {
-			if (n.Status.Capacity.Storage - n.Status.Allocated.Storage) > *nl.Request.Storage {
-				if node == nil {
-					node = n
-				}
-				allocated.Storage += *nl.Request.Storage
+		pods := make([]*types.Pod, 0)
+		for _, p := range cs.pod.list {
+			if podEvictable(n, p) {
+				pods = append(pods, p)
 			}
 		}
 
-		if node != nil {
+		if len(pods) > 0 {
+			log.Warnf("%s:> node %s is lost: evict %d pods", logPrefix, n.SelfLink(), len(pods))
+		}
 
-			node.Status.Allocated.Pods += allocated.Pods
-			node.Status.Allocated.Memory += allocated.Memory
-			node.Status.Allocated.Storage += allocated.Storage
+		podEvict(cs, true, pods...)
+	}
+}
 
-			nm := distribution.NewNodeModel(context.Background(), envs.Get().GetStorage())
-			if err := nm.Set(n); err != nil {
-				return err
-			}
+// nodeOffline marks stored node as not ready, only status flags are changed
+// not to overwrite node attributes updated after node was cached.
+// Node is skipped if heartbeat was received after cached one
+func nodeOffline(nm *distribution.Node, n *types.Node) error {
+
+	node, err := nm.Get(n.Meta.Name)
+	if err != nil {
+		return err
+	}
+
+	if node == nil || node.Status.Heartbeat.After(n.Status.Heartbeat) {
+		return nil
+	}
+
+	node.Status.Online = false
+	node.Status.State.Ready = false
+
+	if err := nm.Set(node); err != nil {
+		return err
+	}
+
+	n.Status.Online = false
+	n.Status.State.Ready = false
+
+	return nil
+}
+
+func (nl *NodeLease) Wait() {
+	<-nl.done
+}
+
+// request converts lease options to scheduler request
+func (nl *NodeLease) request() *scheduler.Request {
+
+	req := new(scheduler.Request)
+	req.Selector = nl.Request.Selector
+
+	if nl.Request.Pod != nil {
+		req.Namespace = nl.Request.Pod.Meta.Namespace
+		req.Service = nl.Request.Pod.ServiceLink()
+	}
+
+	if nl.Request.Memory != nil {
+		req.Resources.Pods = 1
+		req.Resources.Memory = *nl.Request.Memory
+	}
+
+	if nl.Request.CPU != nil {
+		req.Resources.Cpu = int(*nl.Request.CPU)
+	}
 
-			nl.Response.Node = n
-			return nil
+	if nl.Request.Storage != nil {
+		req.Resources.Storage = *nl.Request.Storage
+	}
+
+	return req
+}
+
+func handleNodeLease(cs *ClusterState, nl *NodeLease) error {
+
+	defer func() {
+		if !nl.sync {
+			nl.done <- true
 		}
+	}()
+
+	req := nl.request()
 
+	node := cs.Scheduler().Schedule(cs.snapshot(), req)
+	if node == nil {
+		return nil
+	}
+
+	node.Status.Allocated.Pods += req.Resources.Pods
+	node.Status.Allocated.Memory += req.Resources.Memory
+	node.Status.Allocated.Cpu += req.Resources.Cpu
+	node.Status.Allocated.Storage += req.Resources.Storage
+
+	nm := distribution.NewNodeModel(context.Background(), envs.Get().GetStorage())
+	if err := nm.Set(node); err != nil {
+		nl.Response.Err = err
+		return err
 	}
 
+	if nl.Request.Pod != nil {
+		podPlace(cs, nl.Request.Pod, node.SelfLink())
+	}
+
+	nl.Response.Node = node
 	return nil
 }
 
@@ -131,6 +320,11 @@ func handleNodeRelease(cs *ClusterState, nl *NodeLease) error {
 		}
 	}()
 
+	if nl.Request.Pod != nil {
+		delete(cs.pod.list, nl.Request.Pod.SelfLink())
+		delete(cs.pod.evicting, nl.Request.Pod.SelfLink())
+	}
+
 	if _, ok := cs.node.list[*nl.Request.Node]; !ok {
 		return nil
 	}
@@ -142,6 +336,10 @@ func handleNodeRelease(cs *ClusterState, nl *NodeLease) error {
 		n.Status.Allocated.Memory -= *nl.Request.Memory
 	}
 
+	if nl.Request.CPU != nil {
+		n.Status.Allocated.Cpu -= int(*nl.Request.CPU)
+	}
+
 	if nl.Request.Storage != nil {
 		n.Status.Allocated.Storage -= *nl.Request.Storage
 	}
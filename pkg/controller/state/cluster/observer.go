@@ -20,9 +20,12 @@ package cluster
 
 import (
 	"context"
+	"time"
 
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
 	"github.com/lastbackend/lastbackend/pkg/controller/ipam/ipam"
+	"github.com/lastbackend/lastbackend/pkg/controller/lifecycle"
+	"github.com/lastbackend/lastbackend/pkg/controller/scheduler"
 	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
@@ -41,21 +44,47 @@ type ClusterState struct {
 	}
 	volume struct {
 		observer chan *types.Volume
+		remove   chan *types.Volume
 		list     map[string]*types.Volume
 	}
 	node struct {
 		observer chan *types.Node
+		remove   chan *types.Node
 		lease    chan *NodeLease
 		release  chan *NodeLease
+		match    chan *NodeMatch
+		notify   chan *types.Node
 		list     map[string]*types.Node
 	}
+	pod struct {
+		observer chan *types.Pod
+		remove   chan *types.Pod
+		evict    chan *PodEviction
+		list     map[string]*types.Pod
+		// evicting - node of pods with eviction sent to service controllers
+		evicting map[string]string
+	}
+
+	// ctx is canceled when state is stopped, observer and watchers exit and requests are ignored
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 // Runtime cluster describes main cluster state loop
 func (cs *ClusterState) Observe() {
+
+	// Check nodes heartbeats
+	ticker := time.NewTicker(lifecycle.CheckInterval)
+	defer ticker.Stop()
+
 	// Watch node changes
 	for {
 		select {
+		case <-cs.ctx.Done():
+			return
+		case <-ticker.C:
+			nodeLifecycle(cs)
+			break
 		case l := <-cs.node.lease:
 			handleNodeLease(cs, l)
 			break
@@ -64,7 +93,22 @@ func (cs *ClusterState) Observe() {
 			break
 		case n := <-cs.node.observer:
 			log.V(7).Debugf("node: %s", n.Meta.Name)
-			cs.node.list[n.SelfLink()] = n
+			nodeObserve(cs, n)
+			break
+		case n := <-cs.node.remove:
+			log.V(7).Debugf("node remove: %s", n.Meta.Name)
+			nodeRemove(cs, n)
+			break
+		case m := <-cs.node.match:
+			m.done <- nodeMatch(cs, m.Selector)
+			break
+		case p := <-cs.pod.observer:
+			log.V(7).Debugf("pod: %s", p.SelfLink())
+			podObserve(cs, p)
+			break
+		case p := <-cs.pod.remove:
+			log.V(7).Debugf("pod remove: %s", p.SelfLink())
+			podRemove(cs, p)
 			break
 		case v := <-cs.volume.observer:
 			log.V(7).Debugf("volume: %s", v.SelfLink())
@@ -72,6 +116,10 @@ func (cs *ClusterState) Observe() {
 				log.Errorf("%s", err.Error())
 			}
 			break
+		case v := <-cs.volume.remove:
+			log.V(7).Debugf("volume remove: %s", v.SelfLink())
+			delete(cs.volume.list, v.SelfLink())
+			break
 		}
 	}
 }
@@ -102,7 +150,7 @@ func (cs *ClusterState) Loop() error {
 		// Run node observers
 	}
 
-	go cs.subscribe(context.Background(), &nl.System.Revision)
+	go cs.subscribe(cs.ctx, &nl.System.Revision)
 	return nil
 }
 
@@ -145,7 +193,11 @@ func (cs *ClusterState) lease(opts NodeLeaseOptions) (*types.Node, error) {
 	req := new(NodeLease)
 	req.Request = opts
 	req.done = make(chan bool)
-	cs.node.lease <- req
+	select {
+	case cs.node.lease <- req:
+	case <-cs.ctx.Done():
+		return nil, cs.ctx.Err()
+	}
 	req.Wait()
 	return req.Response.Node, req.Response.Err
 }
@@ -156,7 +208,11 @@ func (cs *ClusterState) release(opts NodeLeaseOptions) (*types.Node, error) {
 	req := new(NodeLease)
 	req.Request = opts
 	req.done = make(chan bool)
-	cs.node.release <- req
+	select {
+	case cs.node.release <- req:
+	case <-cs.ctx.Done():
+		return nil, cs.ctx.Err()
+	}
 	req.Wait()
 	return req.Response.Node, req.Response.Err
 }
@@ -195,33 +251,92 @@ func (cs *ClusterState) IPAM() ipam.IPAM {
 	return envs.Get().GetIPAM()
 }
 
+// Lifecycle used for nodes heartbeats tracking
+func (cs *ClusterState) Lifecycle() *lifecycle.Lifecycle {
+	return envs.Get().GetLifecycle()
+}
+
+// Scheduler used for node leases
+func (cs *ClusterState) Scheduler() *scheduler.Scheduler {
+	return envs.Get().GetScheduler()
+}
+
+// PodEvictions returns pods which should be moved from tainted or lost nodes
+func (cs *ClusterState) PodEvictions() <-chan *PodEviction {
+	return cs.pod.evict
+}
+
 func (cs *ClusterState) SetNode(n *types.Node) {
-	cs.node.observer <- n
+	select {
+	case cs.node.observer <- n:
+	case <-cs.ctx.Done():
+	}
 }
 
 func (cs *ClusterState) DelNode(n *types.Node) {
-	delete(cs.node.list, n.Meta.SelfLink)
+	select {
+	case cs.node.remove <- n:
+	case <-cs.ctx.Done():
+	}
+}
+
+// NodeChanges returns nodes joined, removed or changed placement attributes:
+// labels, taints, schedulable and online flags
+func (cs *ClusterState) NodeChanges() <-chan *types.Node {
+	return cs.node.notify
+}
+
+// NodeMatch returns nodes matched by selector labels and node name,
+// nodes with NoExecute taints not tolerated by selector are skipped
+func (cs *ClusterState) NodeMatch(selector types.SpecSelector) []*types.Node {
+	req := new(NodeMatch)
+	req.Selector = selector
+	req.done = make(chan []*types.Node)
+	select {
+	case cs.node.match <- req:
+	case <-cs.ctx.Done():
+		return nil
+	}
+	return <-req.done
+}
+
+func (cs *ClusterState) SetPod(p *types.Pod) {
+	select {
+	case cs.pod.observer <- p:
+	case <-cs.ctx.Done():
+	}
+}
+
+func (cs *ClusterState) DelPod(p *types.Pod) {
+	select {
+	case cs.pod.remove <- p:
+	case <-cs.ctx.Done():
+	}
 }
 
 func (cs *ClusterState) SetVolume(v *types.Volume) {
-	cs.volume.observer <- v
+	select {
+	case cs.volume.observer <- v:
+	case <-cs.ctx.Done():
+	}
 }
 
 func (cs *ClusterState) DelVolume(v *types.Volume) {
-	delete(cs.volume.list, v.Meta.SelfLink)
+	select {
+	case cs.volume.remove <- v:
+	case <-cs.ctx.Done():
+	}
 }
 
 func (cs *ClusterState) PodLease(p *types.Pod) (*types.Node, error) {
 
-	var RAM int64
-
-	for _, s := range p.Spec.Template.Containers {
-		RAM += s.Resources.Request.RAM
-	}
+	r := p.Spec.Template.ResourceRequest()
 
 	opts := NodeLeaseOptions{
+		Pod:      p,
 		Selector: p.Spec.Selector,
-		Memory:   &RAM,
+		Memory:   &r.RAM,
+		CPU:      &r.CPU,
 	}
 
 	node, err := cs.lease(opts)
@@ -234,15 +349,13 @@ func (cs *ClusterState) PodLease(p *types.Pod) (*types.Node, error) {
 }
 
 func (cs *ClusterState) PodRelease(p *types.Pod) (*types.Node, error) {
-	var RAM int64
-
-	for _, s := range p.Spec.Template.Containers {
-		RAM += s.Resources.Request.RAM
-	}
+	r := p.Spec.Template.ResourceRequest()
 
 	opts := NodeLeaseOptions{
+		Pod:    p,
 		Node:   &p.Meta.Node,
-		Memory: &RAM,
+		Memory: &r.RAM,
+		CPU:    &r.CPU,
 	}
 
 	node, err := cs.release(opts)
@@ -287,23 +400,41 @@ func (cs *ClusterState) VolumeRelease(v *types.Volume) (*types.Node, error) {
 	return node, err
 }
 
+// Stop stops cluster state observer and nodes watcher
+func (cs *ClusterState) Stop() {
+	cs.cancel()
+}
+
 // NewClusterState returns new cluster state instance
 func NewClusterState() *ClusterState {
 
 	var cs = new(ClusterState)
 
+	cs.ctx, cs.cancel = context.WithCancel(context.Background())
+
 	cs.ingress.list = make(map[string]*types.Ingress)
 
 	cs.volume.list = make(map[string]*types.Volume)
 	cs.volume.observer = make(chan *types.Volume)
+	cs.volume.remove = make(chan *types.Volume)
 
 	cs.node.observer = make(chan *types.Node)
+	cs.node.remove = make(chan *types.Node)
+	cs.node.match = make(chan *NodeMatch)
+	cs.node.notify = make(chan *types.Node)
 	cs.node.list = make(map[string]*types.Node)
 
 	cs.node.lease = make(chan *NodeLease)
 	cs.node.release = make(chan *NodeLease)
 
 	cs.node.observer = make(chan *types.Node)
+
+	cs.pod.observer = make(chan *types.Pod)
+	cs.pod.remove = make(chan *types.Pod)
+	cs.pod.evict = make(chan *PodEviction)
+	cs.pod.list = make(map[string]*types.Pod)
+	cs.pod.evicting = make(map[string]string)
+
 	go cs.Observe()
 
 	return cs
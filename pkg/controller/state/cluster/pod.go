@@ -0,0 +1,163 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/controller/scheduler"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+// podObserve keeps pods placement used by scheduler for affinity and spread rules
+func podObserve(cs *ClusterState, p *types.Pod) {
+
+	if p.Status.State == types.StateDestroyed {
+		podRemove(cs, p)
+		return
+	}
+
+	// pod moved from node can be evicted again
+	if node, ok := cs.pod.evicting[p.SelfLink()]; ok && node != p.Meta.Node {
+		delete(cs.pod.evicting, p.SelfLink())
+	}
+
+	// pod is not placed yet or lease is in progress
+	if p.Meta.Node == types.EmptyString {
+		return
+	}
+
+	cs.pod.list[p.SelfLink()] = p
+
+	n, ok := cs.node.list[p.Meta.Node]
+	if !ok {
+		return
+	}
+
+	if lc := cs.Lifecycle(); lc != nil && lc.Lost(n, time.Now()) {
+		if podEvictable(n, p) {
+			podEvict(cs, true, p)
+		}
+		return
+	}
+
+	if podTainted(n, p) {
+		podEvict(cs, false, p)
+	}
+}
+
+// podRemove removes pod from local cache
+func podRemove(cs *ClusterState, p *types.Pod) {
+	delete(cs.pod.list, p.SelfLink())
+	delete(cs.pod.evicting, p.SelfLink())
+}
+
+// podEvictable checks if pod is placed on node and is not destroyed yet
+func podEvictable(n *types.Node, p *types.Pod) bool {
+
+	if p.Meta.Node != n.SelfLink() || p.Spec.State.Destroy {
+		return false
+	}
+
+	switch p.Status.State {
+	case types.StateDestroy, types.StateDestroyed:
+		return false
+	}
+
+	return true
+}
+
+// podTainted checks if pod does not tolerate node NoExecute taints
+func podTainted(n *types.Node, p *types.Pod) bool {
+
+	if !podEvictable(n, p) {
+		return false
+	}
+
+	for _, t := range n.Spec.Taints {
+		if t.Effect != types.NodeTaintEffectNoExecute {
+			continue
+		}
+
+		if !p.Spec.Selector.Tolerate(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PodEviction describes pod which should be moved from node
+type PodEviction struct {
+	Pod *types.Pod
+	// Lost is set if node is not ready and can not stop pod gracefully
+	Lost bool
+}
+
+// podEvict passes pods to service controllers to be moved from node.
+// Pods are sent in background not to block cluster observer loop,
+// pod eviction is sent once until pod is moved from node or removed
+func podEvict(cs *ClusterState, lost bool, pods ...*types.Pod) {
+
+	var list = make([]*types.Pod, 0)
+
+	for _, p := range pods {
+		if node, ok := cs.pod.evicting[p.SelfLink()]; ok && node == p.Meta.Node {
+			continue
+		}
+		cs.pod.evicting[p.SelfLink()] = p.Meta.Node
+		list = append(list, p)
+	}
+
+	if len(list) == 0 {
+		return
+	}
+
+	pods = list
+
+	go func() {
+		for _, p := range pods {
+			select {
+			case cs.pod.evict <- &PodEviction{Pod: p, Lost: lost}:
+			case <-cs.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// podPlace marks pod as placed on node right after lease
+func podPlace(cs *ClusterState, p *types.Pod, node string) {
+	pod := *p
+	pod.Meta.Node = node
+	cs.pod.list[p.SelfLink()] = &pod
+}
+
+// snapshot returns current nodes and pods placement for scheduler
+func (cs *ClusterState) snapshot() *scheduler.State {
+
+	st := scheduler.NewState()
+	st.Nodes = cs.node.list
+
+	for _, p := range cs.pod.list {
+		st.Add(p.Meta.Node, p.ServiceLink())
+	}
+
+	return st
+}
@@ -0,0 +1,235 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package cronjob
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/util/cron"
+)
+
+const logCronJobPrefix = "state:observer:cronjob"
+
+// cronJobObserve applies cron job changes and checks schedule
+func cronJobObserve(cs *CronJobState, c *types.CronJob) error {
+
+	log.V(logLevel).Debugf("%s:> observe start: %s > %s", logCronJobPrefix, c.SelfLink(), c.Status.State)
+
+	cs.cronjob = c
+
+	if err := cronJobSync(cs, time.Now()); err != nil {
+		return err
+	}
+
+	log.V(logLevel).Debugf("%s:> observe finish: %s > %s", logCronJobPrefix, c.SelfLink(), c.Status.State)
+
+	return nil
+}
+
+// cronJobSync updates active jobs list, removes finished jobs above history limits
+// and creates job if schedule time is reached according to concurrency policy.
+// Missed schedules are looked up since last schedule time, so schedules missed
+// during controller downtime are started unless starting deadline is exceeded
+func cronJobSync(cs *CronJobState, now time.Time) error {
+
+	c := cs.cronjob
+	if c == nil {
+		return nil
+	}
+
+	if c.Spec.State.Destroy {
+		return cronJobDestroy(cs)
+	}
+
+	var (
+		status    = c.Status
+		active    = make([]*types.Job, 0)
+		succeeded = make([]*types.Job, 0)
+		failed    = make([]*types.Job, 0)
+	)
+
+	for _, j := range cs.job.list {
+
+		if j.Spec.State.Destroy {
+			continue
+		}
+
+		switch j.Status.State {
+		case types.JobStateCompleted:
+			succeeded = append(succeeded, j)
+		case types.JobStateFailed:
+			failed = append(failed, j)
+		default:
+			active = append(active, j)
+		}
+	}
+
+	defer func() {
+		c.Status.Active = make([]string, 0)
+		for _, j := range active {
+			if !j.Spec.State.Destroy {
+				c.Status.Active = append(c.Status.Active, j.Meta.Name)
+			}
+		}
+		sort.Strings(c.Status.Active)
+
+		if !reflect.DeepEqual(status, c.Status) {
+			cm := distribution.NewCronJobModel(context.Background(), envs.Get().GetStorage())
+			if err := cm.Set(c); err != nil {
+				log.Errorf("%s:> set cron job status err: %s", logCronJobPrefix, err.Error())
+			}
+		}
+	}()
+
+	if err := jobsHistoryLimit(succeeded, c.Spec.History.Succeeded); err != nil {
+		return err
+	}
+
+	if err := jobsHistoryLimit(failed, c.Spec.History.Failed); err != nil {
+		return err
+	}
+
+	sched, err := cron.Parse(c.Spec.Schedule)
+	if err != nil {
+		c.Status.State = types.StateError
+		c.Status.Message = fmt.Sprintf("%s: %s", types.CronJobMessageInvalidSchedule, err.Error())
+		return nil
+	}
+
+	loc, err := c.Location()
+	if err != nil {
+		c.Status.State = types.StateError
+		c.Status.Message = fmt.Sprintf("%s: %s", types.CronJobMessageInvalidSchedule, err.Error())
+		return nil
+	}
+
+	if c.Status.State != types.StateReady {
+		c.Status.State = types.StateReady
+		c.Status.Message = types.EmptyString
+	}
+
+	t, missed := cronJobScheduled(c, sched, now.In(loc))
+	if missed > types.CronJobMaxMissed {
+		log.Warnf("%s:> cron job %s missed %d schedules, skip them", logCronJobPrefix, c.SelfLink(), missed)
+		c.Status.Message = types.CronJobMessageTooManyMissed
+		c.Status.LastSchedule = now
+		return nil
+	}
+
+	if t.IsZero() {
+		return nil
+	}
+
+	switch c.Spec.Concurrency {
+	case types.CronJobConcurrencyForbid:
+		// last schedule is not updated, so job is started when running one is finished
+		// if it is still allowed by starting deadline
+		if len(active) > 0 {
+			log.V(logLevel).Debugf("%s:> cron job %s schedule is skipped, job is still running", logCronJobPrefix, c.SelfLink())
+			return nil
+		}
+	case types.CronJobConcurrencyReplace:
+		if err := jobsDestroy(active); err != nil {
+			return err
+		}
+	}
+
+	j, err := jobCreate(cs, t)
+	if err != nil {
+		return err
+	}
+
+	if j != nil && !j.Finished() {
+		active = append(active, j)
+		c.Status.Message = types.EmptyString
+	}
+
+	c.Status.LastSchedule = t
+	return nil
+}
+
+// cronJobScheduled returns the latest schedule time which is not started yet
+// and count of schedule times found since last schedule
+func cronJobScheduled(c *types.CronJob, sched *cron.Schedule, now time.Time) (time.Time, int) {
+
+	var (
+		last    time.Time
+		missed  int
+		earlier = c.Status.LastSchedule
+	)
+
+	if earlier.IsZero() {
+		earlier = c.Meta.Created
+	}
+
+	// schedules missed before starting deadline can not be started anymore
+	if c.Spec.StartingDeadline > 0 {
+		if d := now.Add(-time.Duration(c.Spec.StartingDeadline) * time.Second); d.After(earlier) {
+			earlier = d
+		}
+	}
+
+	for t := sched.Next(earlier.In(now.Location())); !t.IsZero() && !t.After(now); t = sched.Next(t) {
+		last = t
+		missed++
+		if missed > types.CronJobMaxMissed {
+			return time.Time{}, missed
+		}
+	}
+
+	return last, missed
+}
+
+// cronJobDestroy destroys all created jobs and removes cron job when jobs are removed
+func cronJobDestroy(cs *CronJobState) error {
+
+	c := cs.cronjob
+	cm := distribution.NewCronJobModel(context.Background(), envs.Get().GetStorage())
+
+	if c.Status.State != types.StateDestroy {
+		c.Status.State = types.StateDestroy
+		if err := cm.Set(c); err != nil {
+			return err
+		}
+	}
+
+	if len(cs.job.list) > 0 {
+		jobs := make([]*types.Job, 0)
+		for _, j := range cs.job.list {
+			jobs = append(jobs, j)
+		}
+		return jobsDestroy(jobs)
+	}
+
+	if err := cm.Remove(c); err != nil {
+		log.Errorf("%s:> remove cron job err: %s", logCronJobPrefix, err.Error())
+		return err
+	}
+
+	cs.cronjob = nil
+	return nil
+}
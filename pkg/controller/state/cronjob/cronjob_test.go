@@ -0,0 +1,299 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package cronjob
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+}
+
+func TestCronJobSync(t *testing.T) {
+
+	var (
+		now    = time.Date(2018, 5, 1, 10, 30, 20, 0, time.UTC)
+		hourly = time.Date(2018, 5, 1, 10, 0, 0, 0, time.UTC)
+	)
+
+	type want struct {
+		state        string
+		message      string
+		lastSchedule time.Time
+		created      string
+		active       []string
+		destroyed    []string
+	}
+
+	tests := []struct {
+		name    string
+		cronjob func() *types.CronJob
+		jobs    func(c *types.CronJob) []*types.Job
+		quotas  types.NamespaceQuotas
+		want    want
+	}{
+		{
+			name: "create job on schedule",
+			cronjob: func() *types.CronJob {
+				return getCronJobAsset("@hourly", hourly.Add(-time.Minute))
+			},
+			want: want{
+				state:        types.StateReady,
+				lastSchedule: hourly,
+				created:      "demo-" + unix(hourly),
+				active:       []string{"demo-" + unix(hourly)},
+			},
+		},
+		{
+			name: "skip schedule until time is reached",
+			cronjob: func() *types.CronJob {
+				return getCronJobAsset("@hourly", hourly.Add(time.Minute))
+			},
+			want: want{state: types.StateReady},
+		},
+		{
+			name: "start latest missed schedule only",
+			cronjob: func() *types.CronJob {
+				return getCronJobAsset("*/10 * * * *", hourly.Add(-time.Hour))
+			},
+			want: want{
+				state:        types.StateReady,
+				lastSchedule: hourly.Add(30 * time.Minute),
+				created:      "demo-" + unix(hourly.Add(30*time.Minute)),
+				active:       []string{"demo-" + unix(hourly.Add(30*time.Minute))},
+			},
+		},
+		{
+			name: "skip missed schedule after starting deadline",
+			cronjob: func() *types.CronJob {
+				c := getCronJobAsset("@hourly", hourly.Add(-time.Minute))
+				c.Spec.StartingDeadline = 60
+				return c
+			},
+			want: want{state: types.StateReady},
+		},
+		{
+			name: "skip too many missed schedules",
+			cronjob: func() *types.CronJob {
+				return getCronJobAsset("* * * * *", hourly.Add(-24*time.Hour))
+			},
+			want: want{
+				state:        types.StateReady,
+				message:      types.CronJobMessageTooManyMissed,
+				lastSchedule: now,
+			},
+		},
+		{
+			name: "skip schedule while job is running with forbid policy",
+			cronjob: func() *types.CronJob {
+				c := getCronJobAsset("@hourly", hourly.Add(-time.Minute))
+				c.Spec.Concurrency = types.CronJobConcurrencyForbid
+				return c
+			},
+			jobs: func(c *types.CronJob) []*types.Job {
+				return []*types.Job{getJobAsset(c, "demo-1", types.StateProvision, time.Time{})}
+			},
+			want: want{state: types.StateReady, active: []string{"demo-1"}},
+		},
+		{
+			name: "replace running job with replace policy",
+			cronjob: func() *types.CronJob {
+				c := getCronJobAsset("@hourly", hourly.Add(-time.Minute))
+				c.Spec.Concurrency = types.CronJobConcurrencyReplace
+				return c
+			},
+			jobs: func(c *types.CronJob) []*types.Job {
+				return []*types.Job{getJobAsset(c, "demo-1", types.StateProvision, time.Time{})}
+			},
+			want: want{
+				state:        types.StateReady,
+				lastSchedule: hourly,
+				created:      "demo-" + unix(hourly),
+				active:       []string{"demo-" + unix(hourly)},
+				destroyed:    []string{"demo-1"},
+			},
+		},
+		{
+			name: "destroy finished jobs above history limits",
+			cronjob: func() *types.CronJob {
+				c := getCronJobAsset("@hourly", hourly.Add(time.Minute))
+				c.Spec.History.Succeeded = 1
+				c.Spec.History.Failed = 0
+				return c
+			},
+			jobs: func(c *types.CronJob) []*types.Job {
+				return []*types.Job{
+					getJobAsset(c, "demo-1", types.JobStateCompleted, hourly.Add(-3*time.Hour)),
+					getJobAsset(c, "demo-2", types.JobStateCompleted, hourly.Add(-2*time.Hour)),
+					getJobAsset(c, "demo-3", types.JobStateFailed, hourly.Add(-time.Hour)),
+				}
+			},
+			want: want{state: types.StateReady, destroyed: []string{"demo-1", "demo-3"}},
+		},
+		{
+			name: "skip schedule if namespace quotas exceeded",
+			cronjob: func() *types.CronJob {
+				c := getCronJobAsset("@hourly", hourly.Add(-time.Minute))
+				c.Spec.Job.Parallelism = 2
+				return c
+			},
+			quotas: types.NamespaceQuotas{Pods: 1},
+			want: want{
+				state:        types.StateReady,
+				message:      "Pods quota exceeded",
+				lastSchedule: hourly,
+			},
+		},
+		{
+			name: "mark cron job with unknown time zone as failed",
+			cronjob: func() *types.CronJob {
+				c := getCronJobAsset("@hourly", hourly.Add(-time.Minute))
+				c.Spec.TimeZone = "Mars/Olympus"
+				return c
+			},
+			want: want{state: types.StateError},
+		},
+	}
+
+	for _, tc := range tests {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			var (
+				ctx = context.Background()
+				stg = envs.Get().GetStorage()
+			)
+
+			clear := func() {
+				for _, c := range []string{stg.Collection().Namespace(), stg.Collection().CronJob(), stg.Collection().Job()} {
+					err := stg.Del(ctx, c, types.EmptyString)
+					assert.NoError(t, err)
+				}
+			}
+
+			clear()
+			defer clear()
+
+			ns := new(types.Namespace)
+			ns.Meta.SetDefault()
+			ns.Meta.Name = "demo"
+			ns.Spec.Quotas = tc.quotas
+
+			err := stg.Put(ctx, stg.Collection().Namespace(), stg.Key().Namespace(ns.Meta.Name), ns, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			c := tc.cronjob()
+			err = stg.Put(ctx, stg.Collection().CronJob(), stg.Key().CronJob(c.Meta.Namespace, c.Meta.Name), c, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			cs := NewCronJobState(c)
+
+			if tc.jobs != nil {
+				for _, j := range tc.jobs(c) {
+					err := stg.Put(ctx, stg.Collection().Job(), stg.Key().Job(j.Meta.Namespace, j.Meta.Name), j, nil)
+					if !assert.NoError(t, err) {
+						return
+					}
+					cs.job.list[j.SelfLink()] = j
+				}
+			}
+
+			if !assert.NoError(t, cronJobSync(cs, now)) {
+				return
+			}
+
+			assert.Equal(t, tc.want.state, c.Status.State, "state mismatch")
+			if tc.want.state != types.StateError {
+				assert.Equal(t, tc.want.message, c.Status.Message, "message mismatch")
+			}
+			assert.True(t, tc.want.lastSchedule.Equal(c.Status.LastSchedule), "last schedule mismatch: %s", c.Status.LastSchedule)
+
+			active := tc.want.active
+			if active == nil {
+				active = make([]string, 0)
+			}
+			assert.Equal(t, active, c.Status.Active, "active jobs mismatch")
+
+			if tc.want.created != types.EmptyString {
+				j := new(types.Job)
+				err := stg.Get(ctx, stg.Collection().Job(), stg.Key().Job(c.Meta.Namespace, tc.want.created), j, nil)
+				if assert.NoError(t, err, "job should be created") {
+					assert.Equal(t, c.Meta.Name, j.Meta.CronJob, "job owner mismatch")
+					assert.Equal(t, c.Spec.Job.Completions, j.Spec.Completions, "job spec mismatch")
+				}
+			}
+
+			for _, name := range tc.want.destroyed {
+				j := new(types.Job)
+				err := stg.Get(ctx, stg.Collection().Job(), stg.Key().Job(c.Meta.Namespace, name), j, nil)
+				if assert.NoError(t, err) {
+					assert.True(t, j.Spec.State.Destroy, "job %s should be destroyed", name)
+				}
+			}
+		})
+	}
+}
+
+func getCronJobAsset(schedule string, created time.Time) *types.CronJob {
+	var c = types.CronJob{}
+	c.Meta.SetDefault()
+	c.Meta.Namespace = "demo"
+	c.Meta.Name = "demo"
+	c.Meta.Created = created
+	c.Spec.SetDefault()
+	c.Spec.Schedule = schedule
+	c.Spec.Job.Template.Containers = append(c.Spec.Job.Template.Containers, &types.SpecTemplateContainer{
+		Name:  "demo",
+		Image: types.SpecTemplateContainerImage{Name: "redis"},
+	})
+	c.Status.State = types.StateCreated
+	c.SelfLink()
+	return &c
+}
+
+func getJobAsset(c *types.CronJob, name, state string, finished time.Time) *types.Job {
+	var j = types.Job{}
+	j.Meta.SetDefault()
+	j.Meta.Namespace = c.Meta.Namespace
+	j.Meta.Name = name
+	j.Meta.CronJob = c.Meta.Name
+	j.Spec = c.Spec.Job
+	j.Status.State = state
+	j.Status.Finished = finished
+	j.SelfLink()
+	return &j
+}
+
+func unix(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
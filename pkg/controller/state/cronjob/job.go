@@ -0,0 +1,140 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package cronjob
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+)
+
+const logJobPrefix = "state:observer:cronjob:job"
+
+// jobObserve stores job created by cron job and updates cron job status
+func jobObserve(cs *CronJobState, j *types.Job) error {
+
+	log.V(logLevel).Debugf("%s:> observe start: %s > %s", logJobPrefix, j.SelfLink(), j.Status.State)
+
+	cs.job.list[j.SelfLink()] = j
+
+	log.V(logLevel).Debugf("%s:> observe finish: %s > %s", logJobPrefix, j.SelfLink(), j.Status.State)
+
+	return cronJobSync(cs, time.Now())
+}
+
+// jobCreate creates job for provided schedule time,
+// nil job is returned if job can not be created due to namespace quotas
+func jobCreate(cs *CronJobState, t time.Time) (*types.Job, error) {
+
+	var (
+		c  = cs.cronjob
+		nm = distribution.NewNamespaceModel(context.Background(), envs.Get().GetStorage())
+		jm = distribution.NewJobModel(context.Background(), envs.Get().GetStorage())
+	)
+
+	ns, err := nm.Get(c.Meta.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if ns == nil {
+		return nil, nil
+	}
+
+	j, err := jm.Get(c.Meta.Namespace, c.JobName(t))
+	if err != nil {
+		return nil, err
+	}
+
+	// job for schedule time is already created
+	if j != nil {
+		cs.job.list[j.SelfLink()] = j
+		return j, nil
+	}
+
+	j = new(types.Job)
+	j.Meta.Name = c.JobName(t)
+	j.Meta.CronJob = c.Meta.Name
+	j.Meta.Labels = make(map[string]string)
+	for k, v := range c.Meta.Labels {
+		j.Meta.Labels[k] = v
+	}
+	j.Spec = c.Spec.Job
+	j.Spec.State = types.SpecState{}
+
+	used, err := nm.Usage(ns)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ns.Spec.Quotas.Admit(*used, types.NamespaceResources{}, j.QuotaUsage()); err != nil {
+		log.Warnf("%s:> cron job %s schedule is skipped: %s", logJobPrefix, c.SelfLink(), err.Error())
+		c.Status.Message = err.Error()
+		return nil, nil
+	}
+
+	if j, err = jm.Create(ns, j); err != nil {
+		log.Errorf("%s:> create job err: %s", logJobPrefix, err.Error())
+		return nil, err
+	}
+
+	if err := nm.UpdateResources(ns); err != nil {
+		log.Errorf("%s:> update namespace resources err: %s", logJobPrefix, err.Error())
+	}
+
+	cs.job.list[j.SelfLink()] = j
+	return j, nil
+}
+
+// jobsHistoryLimit destroys the oldest finished jobs above history limit
+func jobsHistoryLimit(jobs []*types.Job, limit int) error {
+
+	if len(jobs) <= limit {
+		return nil
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Status.Finished.Before(jobs[j].Status.Finished)
+	})
+
+	return jobsDestroy(jobs[:len(jobs)-limit])
+}
+
+func jobsDestroy(jobs []*types.Job) error {
+
+	jm := distribution.NewJobModel(context.Background(), envs.Get().GetStorage())
+
+	for _, j := range jobs {
+
+		if j.Spec.State.Destroy {
+			continue
+		}
+
+		if _, err := jm.Destroy(j); err != nil {
+			log.Errorf("%s:> destroy job err: %s", logJobPrefix, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
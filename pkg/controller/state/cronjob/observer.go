@@ -0,0 +1,163 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package cronjob
+
+import (
+	"context"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+)
+
+const (
+	logLevel  = 3
+	logPrefix = "state:cronjob"
+)
+
+type CronJobState struct {
+	cronjob *types.CronJob
+
+	job struct {
+		list map[string]*types.Job
+	}
+
+	observers struct {
+		cronjob chan *types.CronJob
+		job     chan *types.Job
+		del     chan *types.Job
+		sync    chan time.Time
+	}
+
+	// ctx is canceled when state is stopped, observer exits and requests are ignored
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (cs *CronJobState) Restore() error {
+
+	log.V(logLevel).Debugf("%s:restore state for cron job: %s", logPrefix, cs.cronjob.SelfLink())
+
+	jm := distribution.NewJobModel(context.Background(), envs.Get().GetStorage())
+	jl, err := jm.ListByCronJob(cs.cronjob.Meta.Namespace, cs.cronjob.Meta.Name)
+	if err != nil {
+		log.Errorf("%s:restore:> get job list error: %v", logPrefix, err)
+		return err
+	}
+
+	for _, j := range jl.Items {
+		log.Infof("%s: restore: restore job: %s", logPrefix, j.SelfLink())
+		cs.job.list[j.SelfLink()] = j
+	}
+
+	cs.SetCronJob(cs.cronjob)
+
+	return nil
+}
+
+func (cs *CronJobState) Observe() {
+	for {
+		select {
+
+		case <-cs.ctx.Done():
+			return
+
+		case j := <-cs.observers.job:
+			log.V(logLevel).Debugf("%s:observe:job:> %s", logPrefix, j.SelfLink())
+			if err := jobObserve(cs, j); err != nil {
+				log.Errorf("%s:observe:job err:> %s", logPrefix, err.Error())
+			}
+			break
+
+		case j := <-cs.observers.del:
+			log.V(logLevel).Debugf("%s:observe:job:remove:> %s", logPrefix, j.SelfLink())
+			delete(cs.job.list, j.SelfLink())
+			break
+
+		case c := <-cs.observers.cronjob:
+			log.V(logLevel).Debugf("%s:observe:cronjob:> %s", logPrefix, c.SelfLink())
+			if err := cronJobObserve(cs, c); err != nil {
+				log.Errorf("%s:observe:cronjob err:> %s", logPrefix, err.Error())
+			}
+			break
+
+		case t := <-cs.observers.sync:
+			if err := cronJobSync(cs, t); err != nil {
+				log.Errorf("%s:observe:sync err:> %s", logPrefix, err.Error())
+			}
+			break
+		}
+	}
+}
+
+func (cs *CronJobState) SetCronJob(c *types.CronJob) {
+	select {
+	case cs.observers.cronjob <- c:
+	case <-cs.ctx.Done():
+	}
+}
+
+func (cs *CronJobState) SetJob(j *types.Job) {
+	select {
+	case cs.observers.job <- j:
+	case <-cs.ctx.Done():
+	}
+}
+
+func (cs *CronJobState) DelJob(j *types.Job) {
+	select {
+	case cs.observers.del <- j:
+	case <-cs.ctx.Done():
+	}
+}
+
+// Sync requests schedule check, jobs are created if schedule time is reached
+func (cs *CronJobState) Sync(t time.Time) {
+	select {
+	case cs.observers.sync <- t:
+	case <-cs.ctx.Done():
+	}
+}
+
+// Stop stops cron job state observer
+func (cs *CronJobState) Stop() {
+	cs.cancel()
+}
+
+func NewCronJobState(c *types.CronJob) *CronJobState {
+
+	var cs = new(CronJobState)
+
+	cs.ctx, cs.cancel = context.WithCancel(context.Background())
+
+	cs.cronjob = c
+
+	cs.observers.cronjob = make(chan *types.CronJob)
+	cs.observers.job = make(chan *types.Job)
+	cs.observers.del = make(chan *types.Job)
+	cs.observers.sync = make(chan time.Time)
+
+	cs.job.list = make(map[string]*types.Job)
+
+	go cs.Observe()
+
+	return cs
+}
@@ -0,0 +1,190 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+)
+
+const logJobPrefix = "state:observer:job"
+
+// jobObserve applies job changes and syncs job pods
+func jobObserve(js *JobState, j *types.Job) error {
+
+	log.V(logLevel).Debugf("%s:> observe start: %s > %s", logJobPrefix, j.SelfLink(), j.Status.State)
+
+	js.job = j
+
+	if err := jobSync(js, time.Now()); err != nil {
+		return err
+	}
+
+	log.V(logLevel).Debugf("%s:> observe finish: %s > %s", logJobPrefix, j.SelfLink(), j.Status.State)
+
+	return nil
+}
+
+// jobSync counts job pods, creates pods up to parallelism until completions are reached
+// and marks job as failed when backoff limit or active deadline is exceeded.
+// Finished pods are kept to inspect their status and logs until job is removed
+func jobSync(js *JobState, now time.Time) error {
+
+	j := js.job
+	if j == nil || j.Finished() {
+		return nil
+	}
+
+	status := j.Status
+
+	if j.Spec.State.Destroy {
+		return jobDestroy(js)
+	}
+
+	var active = make([]*types.Pod, 0)
+
+	j.Status.Active = 0
+	j.Status.Succeeded = 0
+	j.Status.Failed = 0
+
+	for _, p := range js.pod.list {
+
+		if p.Spec.State.Destroy {
+			continue
+		}
+
+		switch true {
+		case p.Succeeded():
+			j.Status.Succeeded++
+		case p.Failed():
+			j.Status.Failed++
+		default:
+			j.Status.Active++
+			active = append(active, p)
+		}
+	}
+
+	defer func() {
+		if status != j.Status {
+			jm := distribution.NewJobModel(context.Background(), envs.Get().GetStorage())
+			if err := jm.Set(j); err != nil {
+				log.Errorf("%s:> set job status err: %s", logJobPrefix, err.Error())
+			}
+		}
+	}()
+
+	if j.Finished() {
+		return nil
+	}
+
+	switch true {
+	case j.Status.Succeeded >= j.Spec.Completions:
+		jobFinish(j, types.JobStateCompleted, types.EmptyString, now)
+		return jobPodsDestroy(js, active)
+	case j.Status.Failed > j.Spec.BackoffLimit:
+		jobFinish(j, types.JobStateFailed, types.JobMessageBackoffLimit, now)
+		return jobPodsDestroy(js, active)
+	case !j.Deadline().IsZero() && !now.Before(j.Deadline()):
+		jobFinish(j, types.JobStateFailed, types.JobMessageDeadline, now)
+		return jobPodsDestroy(js, active)
+	}
+
+	if j.Status.State != types.StateProvision {
+		j.Status.State = types.StateProvision
+		j.Status.Started = now
+	}
+
+	want := j.Spec.Completions - j.Status.Succeeded
+	if want > j.Spec.Parallelism {
+		want = j.Spec.Parallelism
+	}
+	want -= j.Status.Active
+
+	// wait for backoff delay before failed pods are retried
+	if want > 0 && j.Status.Failed > 0 && now.Before(js.pod.failed.Add(j.BackoffDelay(j.Status.Failed))) {
+		log.V(logLevel).Debugf("%s:> job %s retry is delayed by backoff", logJobPrefix, j.SelfLink())
+		return nil
+	}
+
+	for i := 0; i < want; i++ {
+		p, err := podCreate(j)
+		if err != nil {
+			log.Errorf("%s:> create job pod err: %s", logJobPrefix, err.Error())
+			return err
+		}
+		js.pod.list[p.SelfLink()] = p
+		j.Status.Active++
+	}
+
+	return nil
+}
+
+// jobDestroy destroys all job pods and removes job when pods are removed
+func jobDestroy(js *JobState) error {
+
+	j := js.job
+
+	if j.Status.State != types.StateDestroy {
+		j.Status.State = types.StateDestroy
+		jm := distribution.NewJobModel(context.Background(), envs.Get().GetStorage())
+		if err := jm.Set(j); err != nil {
+			return err
+		}
+	}
+
+	if len(js.pod.list) > 0 {
+		for _, p := range js.pod.list {
+			if err := podDestroy(js, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	jm := distribution.NewJobModel(context.Background(), envs.Get().GetStorage())
+	if err := jm.Remove(j); err != nil {
+		log.Errorf("%s:> remove job err: %s", logJobPrefix, err.Error())
+		return err
+	}
+
+	js.job = nil
+	return nil
+}
+
+// jobPodsDestroy stops pods which are still active when job is finished
+func jobPodsDestroy(js *JobState, pods []*types.Pod) error {
+	for _, p := range pods {
+		if err := podDestroy(js, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func jobFinish(j *types.Job, state, message string, now time.Time) {
+	j.Status.State = state
+	j.Status.Message = message
+	j.Status.Active = 0
+	j.Status.Finished = now
+}
@@ -0,0 +1,337 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/controller/ipam"
+	"github.com/lastbackend/lastbackend/pkg/controller/scheduler"
+	"github.com/lastbackend/lastbackend/pkg/controller/state/cluster"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	stg, _ := storage.Get("mock")
+	envs.Get().SetStorage(stg)
+
+	ipm, _ := ipam.New(nil)
+	envs.Get().SetIPAM(ipm)
+
+	sch, _ := scheduler.New("")
+	envs.Get().SetScheduler(sch)
+}
+
+func TestJobSync(t *testing.T) {
+
+	now := time.Now()
+
+	type want struct {
+		state     string
+		message   string
+		active    int
+		succeeded int
+		failed    int
+		pods      int
+		destroyed int
+	}
+
+	tests := []struct {
+		name string
+		job  func() *types.Job
+		pods func(j *types.Job) []*types.Pod
+		// last observed pod failure
+		failed time.Time
+		want   want
+	}{
+		{
+			name: "create pods up to parallelism",
+			job: func() *types.Job {
+				j := getJobAsset("demo", "demo")
+				j.Spec.Completions = 3
+				j.Spec.Parallelism = 2
+				return j
+			},
+			pods: func(j *types.Job) []*types.Pod { return nil },
+			want: want{state: types.StateProvision, active: 2, pods: 2},
+		},
+		{
+			name: "create pods for remaining completions only",
+			job: func() *types.Job {
+				j := getJobAsset("demo", "demo")
+				j.Spec.Completions = 3
+				j.Spec.Parallelism = 3
+				return j
+			},
+			pods: func(j *types.Job) []*types.Pod {
+				return []*types.Pod{
+					getPodAsset(j, "p1", types.StateReady, 0),
+					getPodAsset(j, "p2", types.StateReady, 0),
+				}
+			},
+			want: want{state: types.StateProvision, active: 1, succeeded: 2, pods: 3},
+		},
+		{
+			name: "complete job when completions reached",
+			job: func() *types.Job {
+				j := getJobAsset("demo", "demo")
+				j.Spec.Completions = 1
+				return j
+			},
+			pods: func(j *types.Job) []*types.Pod {
+				return []*types.Pod{getPodAsset(j, "p1", types.StateReady, 0)}
+			},
+			want: want{state: types.JobStateCompleted, succeeded: 1, pods: 1},
+		},
+		{
+			name: "fail job when backoff limit exceeded",
+			job: func() *types.Job {
+				j := getJobAsset("demo", "demo")
+				j.Spec.BackoffLimit = 1
+				return j
+			},
+			pods: func(j *types.Job) []*types.Pod {
+				return []*types.Pod{
+					getPodAsset(j, "p1", types.StateReady, 1),
+					getPodAsset(j, "p2", types.StateError, 0),
+				}
+			},
+			want: want{state: types.JobStateFailed, message: types.JobMessageBackoffLimit, failed: 2, pods: 2},
+		},
+		{
+			name: "fail job and destroy active pods when deadline exceeded",
+			job: func() *types.Job {
+				j := getJobAsset("demo", "demo")
+				j.Spec.ActiveDeadline = 60
+				j.Status.State = types.StateProvision
+				j.Status.Started = now.Add(-2 * time.Minute)
+				return j
+			},
+			pods: func(j *types.Job) []*types.Pod {
+				return []*types.Pod{getPodAsset(j, "p1", types.StateProvision, 0)}
+			},
+			want: want{state: types.JobStateFailed, message: types.JobMessageDeadline, pods: 1, destroyed: 1},
+		},
+		{
+			name: "delay failed pod retry by backoff",
+			job: func() *types.Job {
+				return getJobAsset("demo", "demo")
+			},
+			pods: func(j *types.Job) []*types.Pod {
+				return []*types.Pod{getPodAsset(j, "p1", types.StateReady, 1)}
+			},
+			failed: now,
+			want:   want{state: types.StateProvision, failed: 1, pods: 1},
+		},
+		{
+			name: "retry failed pod after backoff delay",
+			job: func() *types.Job {
+				return getJobAsset("demo", "demo")
+			},
+			pods: func(j *types.Job) []*types.Pod {
+				return []*types.Pod{getPodAsset(j, "p1", types.StateReady, 1)}
+			},
+			failed: now.Add(-time.Minute),
+			want:   want{state: types.StateProvision, active: 1, failed: 1, pods: 2},
+		},
+	}
+
+	for _, tc := range tests {
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			var (
+				ctx = context.Background()
+				stg = envs.Get().GetStorage()
+			)
+
+			clear := func() {
+				err := stg.Del(ctx, stg.Collection().Job(), types.EmptyString)
+				assert.NoError(t, err)
+
+				err = stg.Del(ctx, stg.Collection().Pod(), types.EmptyString)
+				assert.NoError(t, err)
+			}
+
+			clear()
+			defer clear()
+
+			j := tc.job()
+			err := stg.Put(ctx, stg.Collection().Job(), stg.Key().Job(j.Meta.Namespace, j.Meta.Name), j, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			js := getJobStateAsset(j)
+			js.pod.failed = tc.failed
+
+			for _, p := range tc.pods(j) {
+				err := stg.Put(ctx, stg.Collection().Pod(), stg.Key().Pod(p.Meta.Namespace, p.Meta.Service, p.Meta.Deployment, p.Meta.Name), p, nil)
+				if !assert.NoError(t, err) {
+					return
+				}
+				js.pod.list[p.SelfLink()] = p
+			}
+
+			if !assert.NoError(t, jobSync(js, now)) {
+				return
+			}
+
+			assert.Equal(t, tc.want.state, js.job.Status.State, "job state mismatch")
+			assert.Equal(t, tc.want.message, js.job.Status.Message, "job message mismatch")
+			assert.Equal(t, tc.want.active, js.job.Status.Active, "active pods count mismatch")
+			assert.Equal(t, tc.want.succeeded, js.job.Status.Succeeded, "succeeded pods count mismatch")
+			assert.Equal(t, tc.want.failed, js.job.Status.Failed, "failed pods count mismatch")
+			assert.Len(t, js.pod.list, tc.want.pods, "pods count mismatch")
+
+			var destroyed int
+			for _, p := range js.pod.list {
+				if p.Spec.State.Destroy {
+					destroyed++
+				}
+			}
+			assert.Equal(t, tc.want.destroyed, destroyed, "destroyed pods count mismatch")
+
+			if tc.want.state == types.JobStateCompleted || tc.want.state == types.JobStateFailed {
+				assert.False(t, js.job.Status.Finished.IsZero(), "finished time should be set")
+			}
+		})
+	}
+}
+
+func TestJobObservePodRemove(t *testing.T) {
+
+	var (
+		ctx = context.Background()
+		stg = envs.Get().GetStorage()
+	)
+
+	clear := func() {
+		err := stg.Del(ctx, stg.Collection().Pod(), types.EmptyString)
+		assert.NoError(t, err)
+	}
+
+	clear()
+	defer clear()
+
+	j := getJobAsset("demo", "demo")
+	j.Status.State = types.JobStateCompleted
+
+	js := getJobStateAsset(j)
+	defer js.Stop()
+
+	p1 := getPodAsset(j, "p1", types.StateDestroyed, 0)
+	p2 := getPodAsset(j, "p2", types.StateDestroyed, 0)
+
+	for _, p := range []*types.Pod{p1, p2} {
+		err := stg.Put(ctx, stg.Collection().Pod(), stg.Key().Pod(p.Meta.Namespace, p.Meta.Service, p.Meta.Deployment, p.Meta.Name), p, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	done := make(chan bool)
+	go func() {
+		js.SetPod(p1)
+		js.SetPod(p2)
+		js.Sync(time.Now())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job observer is blocked after destroyed pod removal")
+	}
+
+	for _, p := range []*types.Pod{p1, p2} {
+		got := new(types.Pod)
+		err := stg.Get(ctx, stg.Collection().Pod(), stg.Key().Pod(p.Meta.Namespace, p.Meta.Service, p.Meta.Deployment, p.Meta.Name), got, nil)
+		assert.True(t, errors.Storage().IsErrEntityNotFound(err), "pod %s should be removed from storage", p.Meta.Name)
+	}
+}
+
+func getJobAsset(namespace, name string) *types.Job {
+	var j = types.Job{}
+	j.Meta.SetDefault()
+	j.Meta.Namespace = namespace
+	j.Meta.Name = name
+	j.Spec.SetDefault()
+	j.Spec.Template.Containers = append(j.Spec.Template.Containers, &types.SpecTemplateContainer{
+		Name:  "demo",
+		Image: types.SpecTemplateContainerImage{Name: "redis"},
+	})
+	j.Status.State = types.StateCreated
+	j.SelfLink()
+	return &j
+}
+
+// getPodAsset returns job pod, pod in ready state is stopped with provided exit code
+func getPodAsset(j *types.Job, name, state string, code int) *types.Pod {
+
+	var p = types.Pod{}
+	p.Meta.SetDefault()
+	p.Meta.Name = name
+	p.Meta.Namespace = j.Meta.Namespace
+	p.Meta.Service = types.JobPodService
+	p.Meta.Deployment = j.Meta.Name
+	p.Meta.Job = j.Meta.Name
+	p.SelfLink()
+
+	p.Status.State = state
+	p.Status.Containers = make(map[string]*types.PodContainer)
+
+	if state == types.StateReady {
+		c := new(types.PodContainer)
+		c.Name = "demo"
+		c.State.Stopped.Stopped = true
+		c.State.Stopped.Exit.Code = code
+		p.Status.Status = types.StatusStopped
+		p.Status.Containers[c.Name] = c
+	}
+
+	return &p
+}
+
+func getJobStateAsset(j *types.Job) *JobState {
+
+	n := new(types.Node)
+
+	n.Meta.Name = "node"
+	n.Meta.Hostname = "node.local"
+	n.Status.Online = true
+	n.Status.Capacity = types.NodeResources{
+		Containers: 10,
+		Pods:       10,
+		Memory:     1000,
+		Cpu:        1,
+		Storage:    1000,
+	}
+	n.SelfLink()
+
+	cs := cluster.NewClusterState()
+	cs.SetNode(n)
+	return NewJobState(cs, j)
+}
@@ -0,0 +1,189 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/controller/state/cluster"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+)
+
+const (
+	logLevel  = 3
+	logPrefix = "state:job"
+)
+
+type JobState struct {
+	cluster *cluster.ClusterState
+	job     *types.Job
+
+	pod struct {
+		list map[string]*types.Pod
+		// last time job pod failure was observed, used for retries backoff
+		failed time.Time
+	}
+
+	observers struct {
+		job   chan *types.Job
+		pod   chan *types.Pod
+		del   chan *types.Pod
+		evict chan *cluster.PodEviction
+		sync  chan time.Time
+	}
+
+	// ctx is canceled when state is stopped, observer exits and requests are ignored
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (js *JobState) Restore() error {
+
+	log.V(logLevel).Debugf("%s:restore state for job: %s", logPrefix, js.job.SelfLink())
+
+	pm := distribution.NewPodModel(context.Background(), envs.Get().GetStorage())
+	pl, err := pm.ListByJob(js.job.Meta.Namespace, js.job.Meta.Name)
+	if err != nil {
+		log.Errorf("%s:restore:> get pod list error: %v", logPrefix, err)
+		return err
+	}
+
+	for _, p := range pl.Items {
+		log.Infof("%s: restore: restore pod: %s", logPrefix, p.SelfLink())
+		js.pod.list[p.SelfLink()] = p
+	}
+
+	for _, p := range pl.Items {
+		js.SetPod(p)
+	}
+
+	js.SetJob(js.job)
+
+	return nil
+}
+
+func (js *JobState) Observe() {
+	for {
+		select {
+
+		case <-js.ctx.Done():
+			return
+
+		case p := <-js.observers.pod:
+			log.V(logLevel).Debugf("%s:observe:pod:> %s", logPrefix, p.SelfLink())
+			if err := podObserve(js, p); err != nil {
+				log.Errorf("%s:observe:pod err:> %s", logPrefix, err.Error())
+			}
+			break
+
+		case p := <-js.observers.del:
+			log.V(logLevel).Debugf("%s:observe:pod:remove:> %s", logPrefix, p.SelfLink())
+			delete(js.pod.list, p.SelfLink())
+			break
+
+		case e := <-js.observers.evict:
+			log.V(logLevel).Debugf("%s:observe:evict:> %s", logPrefix, e.Pod.SelfLink())
+			if err := podEvictObserve(js, e); err != nil {
+				log.Errorf("%s:observe:evict err:> %s", logPrefix, err.Error())
+			}
+			break
+
+		case j := <-js.observers.job:
+			log.V(logLevel).Debugf("%s:observe:job:> %s", logPrefix, j.SelfLink())
+			if err := jobObserve(js, j); err != nil {
+				log.Errorf("%s:observe:job err:> %s", logPrefix, err.Error())
+			}
+			break
+
+		case t := <-js.observers.sync:
+			if err := jobSync(js, t); err != nil {
+				log.Errorf("%s:observe:sync err:> %s", logPrefix, err.Error())
+			}
+			break
+		}
+	}
+}
+
+func (js *JobState) SetJob(j *types.Job) {
+	select {
+	case js.observers.job <- j:
+	case <-js.ctx.Done():
+	}
+}
+
+func (js *JobState) SetPod(p *types.Pod) {
+	select {
+	case js.observers.pod <- p:
+	case <-js.ctx.Done():
+	}
+}
+
+// EvictPod handles job pod placed on tainted or lost node
+func (js *JobState) EvictPod(e *cluster.PodEviction) {
+	select {
+	case js.observers.evict <- e:
+	case <-js.ctx.Done():
+	}
+}
+
+// Sync requests job deadline and retries check, finished jobs are skipped by observer
+func (js *JobState) Sync(t time.Time) {
+	select {
+	case js.observers.sync <- t:
+	case <-js.ctx.Done():
+	}
+}
+
+func (js *JobState) DelPod(p *types.Pod) {
+	select {
+	case js.observers.del <- p:
+	case <-js.ctx.Done():
+	}
+}
+
+// Stop stops job state observer
+func (js *JobState) Stop() {
+	js.cancel()
+}
+
+func NewJobState(cs *cluster.ClusterState, j *types.Job) *JobState {
+
+	var js = new(JobState)
+
+	js.ctx, js.cancel = context.WithCancel(context.Background())
+
+	js.job = j
+	js.cluster = cs
+
+	js.observers.job = make(chan *types.Job)
+	js.observers.pod = make(chan *types.Pod)
+	js.observers.del = make(chan *types.Pod)
+	js.observers.evict = make(chan *cluster.PodEviction)
+	js.observers.sync = make(chan time.Time)
+
+	js.pod.list = make(map[string]*types.Pod)
+
+	go js.Observe()
+
+	return js
+}
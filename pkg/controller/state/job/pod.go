@@ -0,0 +1,290 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/controller/state/cluster"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+)
+
+const logPodPrefix = "state:observer:job:pod"
+
+// podObserve manages job pod based on pod state
+func podObserve(js *JobState, p *types.Pod) error {
+
+	log.V(logLevel).Debugf("%s:> observe start: %s > state %s", logPodPrefix, p.SelfLink(), p.Status.State)
+
+	switch p.Status.State {
+	case types.StateCreated, types.StateProvision:
+		// pods created for already finished job are not needed anymore
+		if js.job == nil || js.job.Finished() || js.job.Spec.State.Destroy {
+			if err := podDestroy(js, p); err != nil {
+				return err
+			}
+			break
+		}
+		if err := podProvision(js, p); err != nil {
+			return err
+		}
+	case types.StateDestroy:
+		if err := podDestroy(js, p); err != nil {
+			return err
+		}
+	case types.StateDestroyed:
+		if err := podRemove(js, p); err != nil {
+			return err
+		}
+	default:
+		if err := podRelease(js, p); err != nil {
+			return err
+		}
+	}
+
+	if p.Status.State != types.StateDestroyed {
+
+		if prev, ok := js.pod.list[p.SelfLink()]; (!ok || !prev.Failed()) && p.Failed() {
+			js.pod.failed = time.Now()
+		}
+
+		js.pod.list[p.SelfLink()] = p
+	}
+
+	log.V(logLevel).Debugf("%s:> observe finish: %s > %s", logPodPrefix, p.SelfLink(), p.Status.State)
+
+	return jobSync(js, time.Now())
+}
+
+// podEvictObserve removes job pod from lost node, job creates replacement pod.
+// Pods on tainted nodes are left to run to completion
+func podEvictObserve(js *JobState, e *cluster.PodEviction) error {
+
+	if !e.Lost {
+		return nil
+	}
+
+	pod, ok := js.pod.list[e.Pod.SelfLink()]
+	if !ok || pod.Meta.Node != e.Pod.Meta.Node {
+		return nil
+	}
+
+	log.V(logLevel).Debugf("%s:> remove pod %s from lost node %s", logPodPrefix, pod.SelfLink(), pod.Meta.Node)
+
+	pod.Spec.State.Destroy = true
+	pod.Status.State = types.StateDestroyed
+	pod.Status.Running = false
+	pod.Status.Network = types.PodNetwork{}
+
+	if err := podRemove(js, pod); err != nil {
+		return err
+	}
+
+	return jobSync(js, time.Now())
+}
+
+// podCreate creates new pod based on job spec
+func podCreate(j *types.Job) (*types.Pod, error) {
+	pm := distribution.NewPodModel(context.Background(), envs.Get().GetStorage())
+	return pm.CreateForJob(j)
+}
+
+// podProvision leases node for pod and puts pod manifest to node
+func podProvision(js *JobState, p *types.Pod) (err error) {
+
+	t := p.Meta.Updated
+
+	defer func() {
+		if err == nil {
+			err = podUpdate(p, t)
+		}
+	}()
+
+	if p.Meta.Node == types.EmptyString {
+
+		var node *types.Node
+
+		node, err = js.cluster.PodLease(p)
+		if err != nil {
+			log.Errorf("%s:> pod node lease err: %s", logPodPrefix, err.Error())
+			return err
+		}
+
+		if node == nil {
+			p.Status.State = types.StateError
+			p.Status.Message = errors.NodeNotFound
+			p.Meta.Updated = time.Now()
+			return nil
+		}
+
+		p.Meta.Node = node.SelfLink()
+		p.Meta.Updated = time.Now()
+	}
+
+	pm := distribution.NewPodModel(context.Background(), envs.Get().GetStorage())
+	m, err := pm.ManifestGet(p.Meta.Node, p.SelfLink())
+	if err != nil && !errors.Storage().IsErrEntityNotFound(err) {
+		log.Errorf("%s:> pod manifest get err: %s", logPodPrefix, err.Error())
+		return err
+	}
+
+	if m == nil {
+		pmf := types.PodManifest(p.Spec)
+		if err = pm.ManifestAdd(p.Meta.Node, p.SelfLink(), &pmf); err != nil {
+			log.Errorf("%s:> pod manifest create err: %s", logPodPrefix, err.Error())
+			return err
+		}
+	}
+
+	if p.Status.State != types.StateProvision {
+		p.Status.State = types.StateProvision
+		p.Meta.Updated = time.Now()
+	}
+
+	return nil
+}
+
+// podDestroy marks pod spec as destroy, pod is removed after node confirms destroy
+func podDestroy(js *JobState, p *types.Pod) (err error) {
+
+	t := p.Meta.Updated
+	defer func() {
+		if err == nil {
+			err = podUpdate(p, t)
+		}
+	}()
+
+	if p.Spec.State.Destroy {
+
+		if p.Meta.Node == types.EmptyString && p.Status.State != types.StateDestroyed {
+			p.Status.State = types.StateDestroyed
+			p.Meta.Updated = time.Now()
+			return nil
+		}
+
+		if p.Meta.Node != types.EmptyString && p.Status.State != types.StateDestroy && p.Status.State != types.StateDestroyed {
+			p.Status.State = types.StateDestroy
+			p.Meta.Updated = time.Now()
+		}
+
+		return nil
+	}
+
+	p.Spec.State.Destroy = true
+	p.Meta.Updated = time.Now()
+
+	if p.Meta.Node == types.EmptyString {
+		p.Status.State = types.StateDestroyed
+		return nil
+	}
+
+	pm := distribution.NewPodModel(context.Background(), envs.Get().GetStorage())
+	m, err := pm.ManifestGet(p.Meta.Node, p.SelfLink())
+	if err != nil {
+		if !errors.Storage().IsErrEntityNotFound(err) {
+			return err
+		}
+		err = nil
+	}
+
+	if m == nil {
+		p.Status.State = types.StateDestroyed
+		return nil
+	}
+
+	*m = types.PodManifest(p.Spec)
+	if err = pm.ManifestSet(p.Meta.Node, p.SelfLink(), m); err != nil {
+		return err
+	}
+
+	p.Status.State = types.StateDestroy
+	return nil
+}
+
+// podRemove releases node resources, removes pod manifest and pod from storage
+func podRemove(js *JobState, p *types.Pod) (err error) {
+
+	pm := distribution.NewPodModel(context.Background(), envs.Get().GetStorage())
+
+	if p.Meta.Node != types.EmptyString {
+
+		if !p.Status.Released {
+			if _, err = js.cluster.PodRelease(p); err != nil {
+				return err
+			}
+		}
+
+		if err = pm.ManifestDel(p.Meta.Node, p.SelfLink()); err != nil && !errors.Storage().IsErrEntityNotFound(err) {
+			return err
+		}
+	}
+
+	p.Meta.Node = types.EmptyString
+	p.Meta.Updated = time.Now()
+
+	if err = pm.Remove(p); err != nil && !errors.Storage().IsErrEntityNotFound(err) {
+		log.Errorf("%s:> remove pod err: %s", logPodPrefix, err.Error())
+		return err
+	}
+
+	// pod is removed by observer itself, so it is deleted from list directly
+	delete(js.pod.list, p.SelfLink())
+	return nil
+}
+
+// podRelease releases node resources of finished pod,
+// pod and its containers are kept on node to inspect status and logs
+func podRelease(js *JobState, p *types.Pod) error {
+
+	if p.Meta.Node == types.EmptyString || p.Status.Released || p.Spec.State.Destroy {
+		return nil
+	}
+
+	if !p.Succeeded() && !p.Failed() {
+		return nil
+	}
+
+	if _, err := js.cluster.PodRelease(p); err != nil {
+		return err
+	}
+
+	t := p.Meta.Updated
+	p.Status.Released = true
+	p.Meta.Updated = time.Now()
+
+	return podUpdate(p, t)
+}
+
+func podUpdate(p *types.Pod, timestamp time.Time) error {
+
+	if timestamp.Before(p.Meta.Updated) {
+		pm := distribution.NewPodModel(context.Background(), envs.Get().GetStorage())
+		if err := pm.Update(p); err != nil {
+			log.Errorf("%s:> update pod err: %s", logPodPrefix, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
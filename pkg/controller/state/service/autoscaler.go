@@ -0,0 +1,370 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+)
+
+const (
+	logAutoscalerPrefix = "state:observer:autoscaler"
+
+	// autoscalerUsageExpire - pod usage sampled earlier is not used for replicas calculation
+	autoscalerUsageExpire = time.Minute
+)
+
+// autoscalerUsage - average utilisation of ready pods in percent of their resource requests
+type autoscalerUsage struct {
+	pods int
+	cpu  int
+	ram  int
+}
+
+// autoscalerObserve calculates desired service replicas from pods resources usage
+// and scales service when replicas are changed after stabilisation
+func autoscalerObserve(ss *ServiceState, now time.Time) (err error) {
+
+	a := ss.autoscaler.autoscaler
+	// daemon service replicas are set by matched nodes count
+	if a == nil || a.Spec.Disabled || ss.service == nil || ss.service.Spec.Daemon() {
+		return nil
+	}
+
+	log.V(logLevel).Debugf("%s:> observe start: %s", logAutoscalerPrefix, a.SelfLink())
+
+	status := a.Status
+	defer func() {
+		if err != nil || status == a.Status {
+			return
+		}
+
+		am := distribution.NewAutoscalerModel(context.Background(), envs.Get().GetStorage())
+		if err = am.Set(a); err != nil {
+			log.Errorf("%s:> set status err: %s", logAutoscalerPrefix, err.Error())
+		}
+	}()
+
+	a.Status.Replicas = ss.service.Spec.Replicas
+
+	// provision, rollout and destroy of service are not interrupted by autoscaler
+	if ss.service.Status.State != types.StateReady || ss.deployment.active == nil || ss.deployment.provision != nil {
+		a.Status.Message = types.AutoscalerMessageServiceBusy
+		return nil
+	}
+
+	usage, message := autoscalerUsageGet(ss, a.Spec, now)
+	a.Status.Usage.CPU = usage.cpu
+	a.Status.Usage.RAM = usage.ram
+
+	var desired = a.Status.Replicas
+	if message == types.EmptyString {
+		desired = autoscalerReplicas(a.Spec, usage)
+	}
+
+	desired, limit := autoscalerReplicasLimit(a.Spec, desired)
+	if limit != types.EmptyString {
+		message = limit
+	}
+
+	ss.autoscaler.recommendations = append(ss.autoscaler.recommendations, types.AutoscalerRecommendation{Replicas: desired, Time: now})
+
+	stabilized := autoscalerStabilize(ss, a.Spec, a.Status.Replicas, now)
+	if stabilized != desired && message == types.EmptyString {
+		message = types.AutoscalerMessageStabilization
+	}
+
+	a.Status.Desired = stabilized
+	a.Status.Message = message
+
+	if stabilized > a.Status.Replicas {
+		admitted, quota, err := autoscalerQuotaLimit(ss, stabilized)
+		if err != nil {
+			log.Errorf("%s:> check namespace quotas err: %s", logAutoscalerPrefix, err.Error())
+			return err
+		}
+
+		if quota != types.EmptyString {
+			stabilized = admitted
+			a.Status.Message = quota
+		}
+	}
+
+	if stabilized == a.Status.Replicas {
+		return nil
+	}
+
+	log.V(logLevel).Debugf("%s:> scale %s: %d -> %d", logAutoscalerPrefix, ss.service.SelfLink(), a.Status.Replicas, stabilized)
+
+	ss.service.Spec.Replicas = stabilized
+	ss.service.Status.State = types.StateProvision
+	ss.service.Meta.Updated = now
+
+	sm := distribution.NewServiceModel(context.Background(), envs.Get().GetStorage())
+	if _, err := sm.Update(ss.service); err != nil {
+		log.Errorf("%s:> update service err: %s", logAutoscalerPrefix, err.Error())
+		return err
+	}
+
+	a.Status.Replicas = stabilized
+	a.Status.Scaled = now
+
+	return nil
+}
+
+// autoscalerQuotaLimit returns the highest replicas count up to provided one admitted by namespace quotas,
+// quota error message is returned when replicas are limited
+func autoscalerQuotaLimit(ss *ServiceState, replicas int) (int, string, error) {
+
+	nm := distribution.NewNamespaceModel(context.Background(), envs.Get().GetStorage())
+
+	ns, err := nm.Get(ss.service.Meta.Namespace)
+	if err != nil {
+		return 0, types.EmptyString, err
+	}
+
+	if ns == nil {
+		return replicas, types.EmptyString, nil
+	}
+
+	used, err := nm.Usage(ns)
+	if err != nil {
+		return 0, types.EmptyString, err
+	}
+
+	var (
+		next  = *ss.service
+		quota error
+	)
+
+	for ; replicas > ss.service.Spec.Replicas; replicas-- {
+
+		next.Spec.Replicas = replicas
+
+		err := ns.Spec.Quotas.Admit(*used, ss.service.QuotaUsage(), next.QuotaUsage())
+		if err == nil {
+			break
+		}
+
+		if quota == nil {
+			quota = err
+		}
+	}
+
+	if quota != nil {
+		return replicas, quota.Error(), nil
+	}
+
+	return replicas, types.EmptyString, nil
+}
+
+// autoscalerUsageGet returns average utilisation of active deployment ready pods with recent usage sample
+func autoscalerUsageGet(ss *ServiceState, spec types.AutoscalerSpec, now time.Time) (autoscalerUsage, string) {
+
+	var (
+		usage    autoscalerUsage
+		cpu, ram int64
+	)
+
+	d := ss.deployment.active
+
+	rcpu, rram := autoscalerRequests(d.Spec.Template)
+	if (spec.Target.CPU > 0 && rcpu == 0) || (spec.Target.RAM > 0 && rram == 0) {
+		return usage, types.AutoscalerMessageNoRequests
+	}
+
+	for _, p := range ss.pod.list[d.SelfLink()] {
+
+		if p.Status.State != types.StateReady || !p.Status.Running {
+			continue
+		}
+
+		if p.Status.Usage.Updated.IsZero() || now.Sub(p.Status.Usage.Updated) > autoscalerUsageExpire {
+			continue
+		}
+
+		usage.pods++
+		cpu += p.Status.Usage.CPU
+		ram += p.Status.Usage.RAM
+	}
+
+	if usage.pods == 0 {
+		return usage, types.AutoscalerMessageNoMetrics
+	}
+
+	if rcpu > 0 {
+		usage.cpu = int(cpu * 100 / (rcpu * int64(usage.pods)))
+	}
+
+	if rram > 0 {
+		usage.ram = int(ram * 100 / (rram * int64(usage.pods)))
+	}
+
+	return usage, types.EmptyString
+}
+
+// autoscalerRequests returns pod cpu request in millicores and ram request in MB,
+// container limit is used if container request is not set
+func autoscalerRequests(t types.SpecTemplate) (int64, int64) {
+
+	var cpu, ram int64
+
+	for _, c := range t.Containers {
+
+		var (
+			ccpu = c.Resources.Request.CPU
+			cram = c.Resources.Request.RAM
+		)
+
+		if ccpu == 0 {
+			ccpu = c.Resources.Limits.CPU
+		}
+
+		if cram == 0 {
+			cram = c.Resources.Limits.RAM
+		}
+
+		cpu += ccpu * 1000 / types.CPUSharesPerCore
+		ram += cram
+	}
+
+	return cpu, ram
+}
+
+// autoscalerReplicas returns replicas needed to keep target utilisation,
+// the highest value is used when both cpu and ram targets are set
+func autoscalerReplicas(spec types.AutoscalerSpec, usage autoscalerUsage) int {
+
+	var replicas int
+
+	calc := func(current, target int) int {
+
+		ratio := float64(current) / float64(target)
+		if math.Abs(ratio-1) <= float64(types.AutoscalerTolerance)/100 {
+			return usage.pods
+		}
+
+		return int(math.Ceil(ratio * float64(usage.pods)))
+	}
+
+	if spec.Target.CPU > 0 {
+		if r := calc(usage.cpu, spec.Target.CPU); r > replicas {
+			replicas = r
+		}
+	}
+
+	if spec.Target.RAM > 0 {
+		if r := calc(usage.ram, spec.Target.RAM); r > replicas {
+			replicas = r
+		}
+	}
+
+	return replicas
+}
+
+// autoscalerReplicasLimit keeps replicas between min and max replicas
+func autoscalerReplicasLimit(spec types.AutoscalerSpec, replicas int) (int, string) {
+
+	min := spec.MinReplicas
+	if min < 1 {
+		min = 1
+	}
+
+	switch true {
+	case spec.MaxReplicas > 0 && replicas > spec.MaxReplicas:
+		return spec.MaxReplicas, types.AutoscalerMessageLimitedMax
+	case replicas < min:
+		return min, types.AutoscalerMessageLimitedMin
+	}
+
+	return replicas, types.EmptyString
+}
+
+// autoscalerStabilize returns replicas from recommendations in stabilisation windows:
+// service is scaled up to the lowest recommendation of upscale window
+// and scaled down to the highest recommendation of downscale window
+func autoscalerStabilize(ss *ServiceState, spec types.AutoscalerSpec, current int, now time.Time) int {
+
+	var (
+		up              = now.Add(-spec.UpscaleWindow())
+		down            = now.Add(-spec.DownscaleWindow())
+		expire          = up
+		upscale         = math.MaxInt32
+		downscale       = 0
+		recommendations = make([]types.AutoscalerRecommendation, 0)
+	)
+
+	if down.Before(expire) {
+		expire = down
+	}
+
+	for _, r := range ss.autoscaler.recommendations {
+
+		if r.Time.Before(expire) {
+			continue
+		}
+		recommendations = append(recommendations, r)
+
+		if !r.Time.Before(up) && r.Replicas < upscale {
+			upscale = r.Replicas
+		}
+
+		if !r.Time.Before(down) && r.Replicas > downscale {
+			downscale = r.Replicas
+		}
+	}
+
+	ss.autoscaler.recommendations = recommendations
+
+	replicas := current
+	if replicas < upscale && upscale != math.MaxInt32 {
+		replicas = upscale
+	}
+
+	if replicas > downscale {
+		replicas = downscale
+	}
+
+	return replicas
+}
+
+// autoscalerRemove removes autoscaler of destroyed service
+func autoscalerRemove(ss *ServiceState) error {
+
+	a := ss.autoscaler.autoscaler
+	if a == nil {
+		return nil
+	}
+
+	am := distribution.NewAutoscalerModel(context.Background(), envs.Get().GetStorage())
+	if err := am.Remove(a); err != nil {
+		return err
+	}
+
+	ss.autoscaler.autoscaler = nil
+	ss.autoscaler.recommendations = nil
+
+	return nil
+}
@@ -0,0 +1,221 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package service
+
+import (
+	"context"
+	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAutoscalerObserve(t *testing.T) {
+
+	type suit struct {
+		name string
+		args struct {
+			state           string
+			cpu             int64
+			updated         time.Duration
+			max             int
+			quota           int64
+			window          int
+			recommendations []int
+		}
+		want struct {
+			replicas int
+			desired  int
+			message  string
+		}
+	}
+
+	var tests = []suit{
+		func() suit {
+			s := suit{name: "service is scaled up on high cpu usage"}
+			s.args.state = types.StateReady
+			s.args.cpu = 900
+			s.args.max = 10
+			s.want.replicas = 4
+			s.want.desired = 4
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "service is scaled up to max replicas"}
+			s.args.state = types.StateReady
+			s.args.cpu = 1000
+			s.args.max = 3
+			s.want.replicas = 3
+			s.want.desired = 3
+			s.want.message = types.AutoscalerMessageLimitedMax
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "service is scaled up to replicas admitted by namespace quota"}
+			s.args.state = types.StateReady
+			s.args.cpu = 900
+			s.args.max = 10
+			s.args.quota = 3 * types.CPUSharesPerCore
+			s.want.replicas = 3
+			s.want.desired = 4
+			s.want.message = "CPU quota exceeded"
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "service is not scaled up if namespace quota is exceeded"}
+			s.args.state = types.StateReady
+			s.args.cpu = 900
+			s.args.max = 10
+			s.args.quota = 2 * types.CPUSharesPerCore
+			s.want.replicas = 2
+			s.want.desired = 4
+			s.want.message = "CPU quota exceeded"
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "service is not scaled within tolerance"}
+			s.args.state = types.StateReady
+			s.args.cpu = 520
+			s.args.max = 10
+			s.want.replicas = 2
+			s.want.desired = 2
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "service scale down is held by downscale window"}
+			s.args.state = types.StateReady
+			s.args.cpu = 100
+			s.args.max = 10
+			s.args.recommendations = []int{2}
+			s.want.replicas = 2
+			s.want.desired = 2
+			s.want.message = types.AutoscalerMessageStabilization
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "service is scaled down without downscale window"}
+			s.args.state = types.StateReady
+			s.args.cpu = 100
+			s.args.max = 10
+			s.args.window = -1
+			s.args.recommendations = []int{2}
+			s.want.replicas = 1
+			s.want.desired = 1
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "service is not scaled without recent usage"}
+			s.args.state = types.StateReady
+			s.args.cpu = 900
+			s.args.updated = 2 * time.Minute
+			s.args.max = 10
+			s.want.replicas = 2
+			s.want.desired = 2
+			s.want.message = types.AutoscalerMessageNoMetrics
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "service is not scaled in provision"}
+			s.args.state = types.StateProvision
+			s.args.cpu = 900
+			s.args.max = 10
+			s.want.replicas = 2
+			s.want.message = types.AutoscalerMessageServiceBusy
+			return s
+		}(),
+	}
+
+	stg := envs.Get().GetStorage()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			now := time.Now()
+
+			svc := getServiceAsset(tt.args.state, types.EmptyString)
+			svc.Spec.Replicas = 2
+			svc.Spec.Template.Containers = types.SpecTemplateContainers{new(types.SpecTemplateContainer)}
+			svc.Spec.Template.Containers[0].Resources.Request.CPU = types.CPUSharesPerCore
+			svc.Spec.Template.Containers[0].Resources.Request.RAM = 256
+
+			a := new(types.Autoscaler)
+			a.Meta.Namespace = svc.Meta.Namespace
+			a.Meta.Service = svc.Meta.Name
+			a.Meta.Name = svc.Meta.Name
+			a.Spec.MaxReplicas = tt.args.max
+			a.Spec.Target.CPU = 50
+			a.Spec.Window.Downscale = tt.args.window
+
+			ns := new(types.Namespace)
+			ns.Meta.Name = svc.Meta.Namespace
+			ns.Spec.Quotas.CPU = tt.args.quota
+
+			err := stg.Put(context.Background(), stg.Collection().Namespace(), stg.Key().Namespace(ns.Meta.Name), ns, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer stg.Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+
+			err = stg.Put(context.Background(), stg.Collection().Service(), stg.Key().Service(svc.Meta.Namespace, svc.Meta.Name), svc, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			d := getDeploymentAsset(svc, types.StateReady, types.EmptyString)
+
+			ss := getServiceStateAsset(svc)
+			ss.autoscaler.autoscaler = a
+			ss.deployment.active = d
+			ss.deployment.list[d.SelfLink()] = d
+			ss.pod.list[d.SelfLink()] = make(map[string]*types.Pod)
+
+			for i := 0; i < svc.Spec.Replicas; i++ {
+				p := getPodAsset(d, types.StateReady, types.EmptyString)
+				p.Status.Usage.CPU = tt.args.cpu
+				p.Status.Usage.RAM = 128
+				p.Status.Usage.Updated = now.Add(-tt.args.updated)
+				ss.pod.list[d.SelfLink()][p.SelfLink()] = p
+			}
+
+			for _, r := range tt.args.recommendations {
+				ss.autoscaler.recommendations = append(ss.autoscaler.recommendations,
+					types.AutoscalerRecommendation{Replicas: r, Time: now.Add(-time.Minute)})
+			}
+
+			defer func() {
+				stg.Del(context.Background(), stg.Collection().Service(), stg.Key().Service(svc.Meta.Namespace, svc.Meta.Name))
+				stg.Del(context.Background(), stg.Collection().Autoscaler(), stg.Key().Autoscaler(a.Meta.Namespace, a.Meta.Service))
+			}()
+
+			if !assert.NoError(t, autoscalerObserve(ss, now)) {
+				return
+			}
+
+			assert.Equal(t, tt.want.replicas, svc.Spec.Replicas, "service replicas")
+			assert.Equal(t, tt.want.replicas, a.Status.Replicas, "autoscaler replicas")
+			assert.Equal(t, tt.want.desired, a.Status.Desired, "autoscaler desired replicas")
+			assert.Equal(t, tt.want.message, a.Status.Message, "autoscaler message")
+
+			if tt.want.replicas != 2 {
+				assert.Equal(t, types.StateProvision, svc.Status.State, "service state")
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+)
+
+const logDaemonPrefix = "state:observer:daemon"
+
+// serviceDaemon checks that service runs one pod on every node matched by selector
+func serviceDaemon(ss *ServiceState) bool {
+	return ss.service != nil && ss.service.Spec.Daemon()
+}
+
+// daemonNodeObserve places daemon pods on joined nodes and removes them
+// from nodes which left cluster or are not matched by service selector anymore
+func daemonNodeObserve(ss *ServiceState, n *types.Node) error {
+
+	if !serviceDaemon(ss) {
+		return nil
+	}
+
+	log.V(logLevel).Debugf("%s:> node changed: %s", logDaemonPrefix, n.SelfLink())
+
+	return deploymentsPodProvision(ss)
+}
+
+// daemonPodProvision - keeps one deployment pod on every node matched by deployment selector.
+// Pods are destroyed on nodes which are not matched anymore, new pods are pinned to node
+// and created only after previous pods of service are removed from this node.
+// Active deployment does not take released nodes during rolling update
+func daemonPodProvision(ss *ServiceState, d *types.Deployment) (err error) {
+
+	t := d.Meta.Updated
+
+	var (
+		provision bool
+		nodes     = ss.cluster.NodeMatch(d.Spec.Selector)
+		matched   = make(map[string]bool)
+		placed    = make(map[string]bool)
+	)
+
+	defer func() {
+		if err == nil {
+			err = deploymentUpdate(d, t)
+		}
+	}()
+
+	pods, ok := ss.pod.list[d.SelfLink()]
+	if !ok {
+		pods = make(map[string]*types.Pod)
+		ss.pod.list[d.SelfLink()] = pods
+	}
+
+	for _, n := range nodes {
+		matched[n.SelfLink()] = true
+	}
+
+	for _, p := range podsLive(pods) {
+
+		node := podNodeLink(p)
+		if matched[node] && !placed[node] {
+			placed[node] = true
+			continue
+		}
+
+		log.V(logLevel).Debugf("%s:> remove pod %s from node %s", logDaemonPrefix, p.SelfLink(), node)
+		if err = podDestroy(ss, p); err != nil {
+			log.Errorf("%s", err.Error())
+			return err
+		}
+		provision = true
+	}
+
+	if !deploymentRolling(ss) || ss.deployment.provision.SelfLink() == d.SelfLink() {
+
+		joined := make([]*types.Node, 0)
+		for _, n := range nodes {
+
+			if placed[n.SelfLink()] || !daemonSchedulable(d, n) || daemonNodeBusy(ss, n.SelfLink()) {
+				continue
+			}
+
+			joined = append(joined, n)
+		}
+
+		if joined, err = daemonQuotaAdmit(ss, d, joined); err != nil {
+			log.Errorf("%s", err.Error())
+			return err
+		}
+
+		for _, n := range joined {
+
+			log.V(logLevel).Debugf("%s:> create pod on node %s", logDaemonPrefix, n.SelfLink())
+			p, err := daemonPodCreate(d, n.SelfLink())
+			if err != nil {
+				log.Errorf("%s", err.Error())
+				return err
+			}
+
+			pods[p.SelfLink()] = p
+			placed[n.SelfLink()] = true
+			provision = true
+		}
+	}
+
+	if d.Spec.Replicas != len(placed) {
+		d.Spec.Replicas = len(placed)
+		d.Meta.Updated = time.Now()
+	}
+
+	if provision && d.Status.State != types.StateProvision {
+		d.Status.State = types.StateProvision
+		d.Meta.Updated = time.Now()
+	}
+
+	return nil
+}
+
+// daemonRollout - moves daemon service rolling update one node at a time:
+// next active pod is destroyed when all provision pods are available and previous active pod
+// is removed from node, provision pod is created on released node by deployment provision
+func daemonRollout(ss *ServiceState) error {
+
+	var (
+		active    = ss.deployment.active
+		provision = ss.deployment.provision
+	)
+
+	if daemonRolloutDone(ss) {
+		return nil
+	}
+
+	if deploymentRolloutExpired(ss) {
+		return deploymentRolloutFail(ss, types.DeploymentRolloutDeadlineExceeded)
+	}
+
+	for _, p := range ss.pod.list[provision.SelfLink()] {
+		if !podAvailable(p) {
+			return nil
+		}
+	}
+
+	pods := podsLive(ss.pod.list[active.SelfLink()])
+	if len(pods) == 0 || len(pods) < len(ss.pod.list[active.SelfLink()]) {
+		return nil
+	}
+
+	sort.Slice(pods, func(i, j int) bool {
+		return podNodeLink(pods[i]) < podNodeLink(pods[j])
+	})
+
+	log.V(logLevel).Debugf("%s:> rollout: replace pod %s on node %s", logDaemonPrefix, pods[0].SelfLink(), podNodeLink(pods[0]))
+
+	return podDestroy(ss, pods[0])
+}
+
+// daemonRolloutDone checks that active deployment pods are removed from all nodes
+// and provision deployment pods are available
+func daemonRolloutDone(ss *ServiceState) bool {
+
+	if len(ss.pod.list[ss.deployment.active.SelfLink()]) > 0 {
+		return false
+	}
+
+	for _, p := range ss.pod.list[ss.deployment.provision.SelfLink()] {
+		if !podAvailable(p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// daemonQuotaAdmit returns joined nodes which daemon pods are admitted by namespace quotas,
+// pods are not placed on rest of nodes and deployment status message is set to quota error
+func daemonQuotaAdmit(ss *ServiceState, d *types.Deployment, nodes []*types.Node) ([]*types.Node, error) {
+
+	if len(nodes) == 0 {
+		return nodes, nil
+	}
+
+	nm := distribution.NewNamespaceModel(context.Background(), envs.Get().GetStorage())
+
+	ns, err := nm.Get(ss.service.Meta.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if ns == nil {
+		return nodes, nil
+	}
+
+	used, err := nm.Usage(ns)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		count    = daemonNodes(ss)
+		next     = *ss.service
+		admitted = len(nodes)
+		quota    error
+	)
+
+	for ; admitted > 0; admitted-- {
+
+		next.Status.Nodes = count + admitted
+
+		err := ns.Spec.Quotas.Admit(*used, ss.service.QuotaUsage(), next.QuotaUsage())
+		if err == nil {
+			break
+		}
+
+		if quota == nil {
+			quota = err
+		}
+	}
+
+	if quota != nil {
+		log.Warnf("%s:> daemon pods are not created on %d nodes: %s", logDaemonPrefix, len(nodes)-admitted, quota.Error())
+		d.Status.Message = quota.Error()
+		d.Meta.Updated = time.Now()
+	}
+
+	return nodes[:admitted], nil
+}
+
+// daemonNodes returns count of nodes with daemon service pods of any deployment
+func daemonNodes(ss *ServiceState) int {
+	nodes := make(map[string]bool)
+	for _, pl := range ss.pod.list {
+		for _, p := range pl {
+			nodes[podNodeLink(p)] = true
+		}
+	}
+	return len(nodes)
+}
+
+// daemonSchedulable checks that new deployment pod can be placed on node
+func daemonSchedulable(d *types.Deployment, n *types.Node) bool {
+
+	if !n.Status.Online || n.Spec.Unschedulable {
+		return false
+	}
+
+	for _, t := range n.Spec.Taints {
+		if !d.Spec.Selector.Tolerate(t) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// daemonNodeBusy checks that any pod of service is placed on node
+func daemonNodeBusy(ss *ServiceState, node string) bool {
+	for _, pl := range ss.pod.list {
+		for _, p := range pl {
+			if podNodeLink(p) == node {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// daemonPodCreate creates new deployment pod pinned to node by selector
+func daemonPodCreate(d *types.Deployment, node string) (*types.Pod, error) {
+	dp := *d
+	dp.Spec.Selector.Node = node
+	return podCreate(&dp)
+}
+
+// podNodeLink returns node pod is placed on or pinned to
+func podNodeLink(p *types.Pod) string {
+	if p.Meta.Node != types.EmptyString {
+		return p.Meta.Node
+	}
+	return p.Spec.Selector.Node
+}
@@ -0,0 +1,399 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/controller/state/cluster"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDaemonPodProvision(t *testing.T) {
+
+	type suit struct {
+		name string
+		args struct {
+			nodes   []*types.Node
+			pods    []string
+			busy    []string
+			rolling bool
+		}
+		want struct {
+			nodes []string
+		}
+	}
+
+	var tests = []suit{
+		func() suit {
+			s := suit{name: "pods created on matched nodes"}
+			s.args.nodes = []*types.Node{
+				getDaemonNodeAsset("node-1", "logs"),
+				getDaemonNodeAsset("node-2", "logs"),
+				getDaemonNodeAsset("node-3", types.EmptyString),
+			}
+			s.want.nodes = []string{"node-1", "node-2"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "pod removed from node which lost label"}
+			s.args.nodes = []*types.Node{
+				getDaemonNodeAsset("node-1", "logs"),
+				getDaemonNodeAsset("node-2", types.EmptyString),
+			}
+			s.args.pods = []string{"node-1", "node-2"}
+			s.want.nodes = []string{"node-1"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "pod removed from node which left cluster"}
+			s.args.nodes = []*types.Node{
+				getDaemonNodeAsset("node-1", "logs"),
+			}
+			s.args.pods = []string{"node-1", "node-2"}
+			s.want.nodes = []string{"node-1"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "duplicate pod on node removed"}
+			s.args.nodes = []*types.Node{
+				getDaemonNodeAsset("node-1", "logs"),
+			}
+			s.args.pods = []string{"node-1", "node-1"}
+			s.want.nodes = []string{"node-1"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "pod not created on offline and unschedulable nodes"}
+			s.args.nodes = []*types.Node{
+				getDaemonNodeAsset("node-1", "logs"),
+				getDaemonNodeAsset("node-2", "logs"),
+				getDaemonNodeAsset("node-3", "logs"),
+			}
+			s.args.nodes[1].Status.Online = false
+			s.args.nodes[2].Spec.Unschedulable = true
+			s.want.nodes = []string{"node-1"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "pod not created until previous pod is removed from node"}
+			s.args.nodes = []*types.Node{
+				getDaemonNodeAsset("node-1", "logs"),
+				getDaemonNodeAsset("node-2", "logs"),
+			}
+			s.args.busy = []string{"node-2"}
+			s.want.nodes = []string{"node-1"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "active deployment does not take nodes during rolling update"}
+			s.args.nodes = []*types.Node{
+				getDaemonNodeAsset("node-1", "logs"),
+				getDaemonNodeAsset("node-2", "logs"),
+			}
+			s.args.pods = []string{"node-1"}
+			s.args.rolling = true
+			s.want.nodes = []string{"node-1"}
+			return s
+		}(),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			svc := getServiceAsset(types.StateReady, types.EmptyString)
+			svc.Spec.Mode = types.ServiceModeDaemon
+			svc.Spec.Selector.Labels = map[string]string{"type": "logs"}
+
+			d := getDeploymentAsset(svc, types.StateReady, types.EmptyString)
+			d.Spec.Selector = svc.Spec.Selector
+
+			cs := cluster.NewClusterState()
+			for _, n := range tt.args.nodes {
+				cs.SetNode(n)
+			}
+
+			ss := NewServiceState(cs, svc)
+			ss.deployment.active = d
+			ss.deployment.list[d.SelfLink()] = d
+			ss.pod.list[d.SelfLink()] = make(map[string]*types.Pod)
+
+			for _, node := range tt.args.pods {
+				p := getDaemonPodAsset(d, node)
+				ss.pod.list[d.SelfLink()][p.SelfLink()] = p
+			}
+
+			if len(tt.args.busy) > 0 {
+				od := getDeploymentAsset(svc, types.StateDestroy, types.EmptyString)
+				ss.pod.list[od.SelfLink()] = make(map[string]*types.Pod)
+				for _, node := range tt.args.busy {
+					p := getDaemonPodAsset(od, node)
+					p.Spec.State.Destroy = true
+					p.Status.State = types.StateDestroy
+					ss.pod.list[od.SelfLink()][p.SelfLink()] = p
+				}
+			}
+
+			if tt.args.rolling {
+				svc.Spec.Template.Updated = time.Now()
+				provision := getDeploymentAsset(svc, types.StateProvision, types.EmptyString)
+				ss.deployment.provision = provision
+				ss.deployment.list[provision.SelfLink()] = provision
+			}
+
+			if !assert.NoError(t, deploymentPodProvision(ss, d)) {
+				return
+			}
+
+			nodes := make([]string, 0)
+			for _, p := range podsLive(ss.pod.list[d.SelfLink()]) {
+				nodes = append(nodes, podNodeLink(p))
+				assert.Equal(t, podNodeLink(p), p.Spec.Selector.Node, "pod pinned to node")
+			}
+			sort.Strings(nodes)
+
+			assert.Equal(t, tt.want.nodes, nodes, "pods nodes")
+			assert.Equal(t, len(tt.want.nodes), d.Spec.Replicas, "deployment replicas")
+		})
+	}
+}
+
+func TestDaemonRollout(t *testing.T) {
+
+	type suit struct {
+		name string
+		args struct {
+			active    map[string]string
+			provision map[string]string
+		}
+		want struct {
+			active []string
+			done   bool
+		}
+	}
+
+	var tests = []suit{
+		func() suit {
+			s := suit{name: "rollout replaces pod on first node"}
+			s.args.active = map[string]string{"node-1": types.StateReady, "node-2": types.StateReady}
+			s.want.active = []string{"node-2"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "rollout moves to next node after provision pod is available"}
+			s.args.active = map[string]string{"node-2": types.StateReady, "node-3": types.StateReady}
+			s.args.provision = map[string]string{"node-1": types.StateReady}
+			s.want.active = []string{"node-3"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "rollout waits for provision pod"}
+			s.args.active = map[string]string{"node-2": types.StateReady}
+			s.args.provision = map[string]string{"node-1": types.StateProvision}
+			s.want.active = []string{"node-2"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "rollout waits for previous pod removal"}
+			s.args.active = map[string]string{"node-1": types.StateDestroy, "node-2": types.StateReady}
+			s.want.active = []string{"node-2"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "rollout done"}
+			s.args.provision = map[string]string{"node-1": types.StateReady, "node-2": types.StateReady}
+			s.want.active = []string{}
+			s.want.done = true
+			return s
+		}(),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			svc := getServiceAsset(types.StateProvision, types.EmptyString)
+			svc.Spec.Mode = types.ServiceModeDaemon
+
+			active := getDeploymentAsset(svc, types.StateReady, types.EmptyString)
+			active.Spec.Template.Updated = svc.Spec.Template.Updated.Add(-time.Minute)
+
+			provision := getDeploymentAsset(svc, types.StateProvision, types.EmptyString)
+			provision.Meta.Created = time.Now()
+
+			ss := getServiceStateAsset(svc)
+			ss.deployment.active = active
+			ss.deployment.provision = provision
+			ss.deployment.list[active.SelfLink()] = active
+			ss.deployment.list[provision.SelfLink()] = provision
+			ss.pod.list[active.SelfLink()] = make(map[string]*types.Pod)
+			ss.pod.list[provision.SelfLink()] = make(map[string]*types.Pod)
+
+			for node, state := range tt.args.active {
+				p := getDaemonPodAsset(active, node)
+				p.Status.State = state
+				if state == types.StateDestroy {
+					p.Spec.State.Destroy = true
+				}
+				ss.pod.list[active.SelfLink()][p.SelfLink()] = p
+			}
+
+			for node, state := range tt.args.provision {
+				p := getDaemonPodAsset(provision, node)
+				p.Status.State = state
+				p.Status.Ready = state == types.StateReady
+				ss.pod.list[provision.SelfLink()][p.SelfLink()] = p
+			}
+
+			assert.True(t, deploymentRolling(ss), "rolling update")
+
+			if !assert.NoError(t, deploymentRollout(ss)) {
+				return
+			}
+
+			nodes := make([]string, 0)
+			for _, p := range podsLive(ss.pod.list[active.SelfLink()]) {
+				nodes = append(nodes, podNodeLink(p))
+			}
+			sort.Strings(nodes)
+
+			assert.Equal(t, tt.want.active, nodes, "active pods nodes")
+			assert.Equal(t, tt.want.done, deploymentRolloutDone(ss), "rollout done")
+		})
+	}
+}
+
+func TestDaemonQuotaAdmit(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		quota    int
+		nodes    int
+		placed   []string
+		admitted int
+		message  string
+	}{
+		{
+			name:     "all joined nodes admitted without quota",
+			nodes:    3,
+			admitted: 3,
+		},
+		{
+			name:     "joined nodes admitted up to pods quota",
+			quota:    2,
+			nodes:    3,
+			admitted: 2,
+			message:  "Pods quota exceeded",
+		},
+		{
+			name:     "nodes with placed pods are counted by quota",
+			quota:    2,
+			nodes:    2,
+			placed:   []string{"node-0"},
+			admitted: 1,
+			message:  "Pods quota exceeded",
+		},
+	}
+
+	stg := envs.Get().GetStorage()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			svc := getServiceAsset(types.StateReady, types.EmptyString)
+			svc.Spec.Mode = types.ServiceModeDaemon
+			svc.Status.Nodes = len(tt.placed)
+
+			ns := new(types.Namespace)
+			ns.Meta.Name = svc.Meta.Namespace
+			ns.Spec.Quotas.Pods = tt.quota
+
+			err := stg.Put(context.Background(), stg.Collection().Namespace(), stg.Key().Namespace(ns.Meta.Name), ns, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer stg.Del(context.Background(), stg.Collection().Namespace(), types.EmptyString)
+
+			err = stg.Put(context.Background(), stg.Collection().Service(), stg.Key().Service(svc.Meta.Namespace, svc.Meta.Name), svc, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer stg.Del(context.Background(), stg.Collection().Service(), types.EmptyString)
+
+			d := getDeploymentAsset(svc, types.StateReady, types.EmptyString)
+
+			ss := getServiceStateAsset(svc)
+			ss.pod.list[d.SelfLink()] = make(map[string]*types.Pod)
+			for _, node := range tt.placed {
+				p := getDaemonPodAsset(d, node)
+				ss.pod.list[d.SelfLink()][p.SelfLink()] = p
+			}
+
+			nodes := make([]*types.Node, 0)
+			for i := 1; i <= tt.nodes; i++ {
+				nodes = append(nodes, getDaemonNodeAsset(fmt.Sprintf("node-%d", i), "logs"))
+			}
+
+			admitted, err := daemonQuotaAdmit(ss, d, nodes)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, tt.admitted, len(admitted), "admitted nodes")
+			assert.Equal(t, tt.message, d.Status.Message, "deployment message")
+		})
+	}
+}
+
+func getDaemonNodeAsset(name, label string) *types.Node {
+
+	n := new(types.Node)
+
+	n.Meta.Name = name
+	n.Meta.Labels = make(map[string]string)
+	if label != types.EmptyString {
+		n.Meta.Labels["type"] = label
+	}
+
+	n.Status.Online = true
+	n.Status.Capacity = types.NodeResources{
+		Containers: 10,
+		Pods:       10,
+		Memory:     1000,
+		Cpu:        1,
+		Storage:    1000,
+	}
+	n.SelfLink()
+
+	return n
+}
+
+func getDaemonPodAsset(d *types.Deployment, node string) *types.Pod {
+
+	p := getPodAsset(d, types.StateReady, types.EmptyString)
+	p.Meta.Node = node
+	p.Spec.Selector.Node = node
+
+	return p
+}
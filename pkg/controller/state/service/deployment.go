@@ -25,6 +25,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
+	"sort"
 	"time"
 )
 
@@ -34,6 +35,13 @@ func deploymentObserve(ss *ServiceState, d *types.Deployment) error {
 
 	log.V(logLevel).Debugf("%s:> observe start: %s > %s", logDeploymentPrefix, d.SelfLink(), d.Status.State)
 
+	// destroyed deployments kept in history are not observed
+	if _, ok := ss.deployment.list[d.SelfLink()]; !ok && d.Status.State == types.StateDestroyed {
+		return nil
+	}
+
+	deploymentSpecSync(ss, d)
+
 	if _, ok := ss.pod.list[d.SelfLink()]; !ok {
 		ss.pod.list[d.SelfLink()] = make(map[string]*types.Pod)
 	}
@@ -91,6 +99,14 @@ func deploymentObserve(ss *ServiceState, d *types.Deployment) error {
 
 	log.V(logLevel).Debugf("%s:> observe state: %s > %s", logDeploymentPrefix, d.SelfLink(), d.Status.State)
 
+	if err := deploymentRelease(ss); err != nil {
+		return err
+	}
+
+	if err := deploymentRollout(ss); err != nil {
+		return err
+	}
+
 	if err := endpointCheck(ss); err != nil {
 		return err
 	}
@@ -132,6 +148,21 @@ func handleDeploymentStateReady(ss *ServiceState, d *types.Deployment) error {
 
 	log.V(logLevel).Debugf("%s:> handleDeploymentStateReady: %s > %s", logDeploymentPrefix, d.SelfLink(), d.Status.State)
 
+	// rolling update step is ready: active deployment is kept until all replicas are replaced
+	if deploymentRolloutStep(ss, d) && !deploymentRolloutDone(ss) {
+		return nil
+	}
+
+	// canary and blue/green deployment replaces active deployment after promotion with all replicas
+	if deploymentReleaseStep(ss, d) && !deploymentReleaseDone(ss) {
+		return nil
+	}
+
+	// paused provision deployment does not replace active deployment until rollout is resumed
+	if d.Spec.Paused && ss.deployment.provision != nil && ss.deployment.provision.SelfLink() == d.SelfLink() {
+		return nil
+	}
+
 	if ss.deployment.active != nil {
 		if ss.deployment.active.SelfLink() != d.SelfLink() {
 			if err := deploymentDestroy(ss, ss.deployment.active); err != nil {
@@ -160,6 +191,12 @@ func handleDeploymentStateError(ss *ServiceState, d *types.Deployment) error {
 
 	log.V(logLevel).Debugf("%s:> handleDeploymentStateError: %s > %s", logDeploymentPrefix, d.SelfLink(), d.Status.State)
 
+	// rolling update keeps trying until deadline is exceeded,
+	// canary and blue/green release keeps trying until it is aborted
+	if deploymentRolloutStep(ss, d) || deploymentReleaseStep(ss, d) {
+		return nil
+	}
+
 	if ss.deployment.active == nil {
 		ss.deployment.provision = nil
 		ss.deployment.active = d
@@ -187,6 +224,10 @@ func handleDeploymentStateDegradation(ss *ServiceState, d *types.Deployment) err
 		return err
 	}
 
+	if deploymentRolloutStep(ss, d) || deploymentReleaseStep(ss, d) {
+		return nil
+	}
+
 	if ss.deployment.active == nil {
 		ss.deployment.provision = nil
 		ss.deployment.active = d
@@ -252,7 +293,7 @@ func handleDeploymentStateDestroyed(ss *ServiceState, d *types.Deployment) error
 		return dm.Update(d)
 	}
 
-	if err := deploymentRemove(d); err != nil {
+	if err := deploymentHistory(ss, d); err != nil {
 		log.Errorf("%s", err.Error())
 		return err
 	}
@@ -265,10 +306,24 @@ func deploymentSpecValidate(d *types.Deployment, svc *types.Service) bool {
 	return d.Spec.Template.Updated.Equal(svc.Spec.Template.Updated) && d.Spec.Selector.Updated.Equal(svc.Spec.Selector.Updated)
 }
 
+// deploymentReplicasValidate checks that deployment runs service replicas,
+// daemon deployment replicas are set by matched nodes count
+func deploymentReplicasValidate(ss *ServiceState, d *types.Deployment) bool {
+	return ss.service.Spec.Daemon() || d.Spec.Replicas == ss.service.Spec.Replicas
+}
+
 // deploymentPodProvision - handles deployment provision logic
 // based on current deployment state and current pod list of provided deployment
 func deploymentPodProvision(ss *ServiceState, d *types.Deployment) (err error) {
 
+	if serviceDaemon(ss) {
+		return daemonPodProvision(ss, d)
+	}
+
+	if serviceStateful(ss) {
+		return statefulPodProvision(ss, d)
+	}
+
 	t := d.Meta.Updated
 
 	var (
@@ -381,11 +436,124 @@ func deploymentPodProvision(ss *ServiceState, d *types.Deployment) (err error) {
 	return nil
 }
 
-func deploymentCreate(svc *types.Service) (*types.Deployment, error) {
+// deploymentsPodProvision provisions pods of active and provision deployments of daemon and stateful services
+func deploymentsPodProvision(ss *ServiceState) error {
+
+	switch ss.service.Status.State {
+	case types.StateDestroy, types.StateDestroyed:
+		return nil
+	}
+
+	for _, d := range []*types.Deployment{ss.deployment.active, ss.deployment.provision} {
+
+		if d == nil || deploymentRolloutFailed(d) {
+			continue
+		}
+
+		switch d.Status.State {
+		case types.StateDestroy, types.StateDestroyed:
+			continue
+		}
+
+		if err := deploymentPodProvision(ss, d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deploymentPodEvict - moves queued pods from tainted nodes.
+// Unavailable pods are destroyed at once, available pods are destroyed
+// only while deployment unavailable replicas fit service max unavailable limit.
+// Replacements are created by deployment provision and placed on other nodes
+func deploymentPodEvict(ss *ServiceState, d *types.Deployment) error {
+
+	var (
+		evict     = make([]*types.Pod, 0)
+		available int
+	)
+
+	pods := ss.pod.list[d.SelfLink()]
+
+	for link, p := range ss.pod.evict {
+
+		if p.DeploymentLink() != d.SelfLink() {
+			continue
+		}
+
+		pod, ok := pods[link]
+		if !ok || pod.Spec.State.Destroy {
+			delete(ss.pod.evict, link)
+			continue
+		}
+
+		evict = append(evict, pod)
+	}
+
+	if len(evict) == 0 {
+		return nil
+	}
+
+	switch d.Status.State {
+	case types.StateDestroy, types.StateDestroyed:
+		for _, p := range evict {
+			delete(ss.pod.evict, p.SelfLink())
+		}
+		return nil
+	}
+
+	for _, p := range pods {
+		if podAvailable(p) {
+			available++
+		}
+	}
+
+	limit := ss.service.Spec.Strategy.RollingOptions.MaxUnavailable
+	if limit < 1 {
+		limit = 1
+	}
+
+	budget := limit - (d.Spec.Replicas - available)
+
+	var evicted bool
+
+	for _, p := range evict {
+
+		if podAvailable(p) {
+			if budget <= 0 {
+				continue
+			}
+			budget--
+		}
+
+		log.V(logLevel).Debugf("%s:> evict pod: %s from node %s", logDeploymentPrefix, p.SelfLink(), p.Meta.Node)
+
+		if err := podDestroy(ss, p); err != nil {
+			log.Errorf("%s", err.Error())
+			return err
+		}
+
+		delete(ss.pod.evict, p.SelfLink())
+		evicted = true
+	}
+
+	if !evicted {
+		return nil
+	}
+
+	return deploymentPodProvision(ss, d)
+}
+
+func deploymentCreate(svc *types.Service, replicas int) (*types.Deployment, error) {
 
 	dm := distribution.NewDeploymentModel(context.Background(), envs.Get().GetStorage())
 
-	d, err := dm.Create(svc)
+	// deployment is created from service spec copy to start with requested replicas
+	s := *svc
+	s.Spec.Replicas = replicas
+
+	d, err := dm.Create(&s)
 	if err != nil {
 		return nil, err
 	}
@@ -427,17 +595,24 @@ func deploymentDestroy(ss *ServiceState, d *types.Deployment) (err error) {
 		return nil
 	}
 
-	for _, p := range pl {
+	// stateful pods are stopped one at a time from the highest ordinal
+	if serviceStateful(ss) {
+		if err := statefulPodsDestroy(ss, pl); err != nil {
+			return err
+		}
+	} else {
+		for _, p := range pl {
 
-		if p.Status.State != types.StateDestroy {
-			if err := podDestroy(ss, p); err != nil {
-				return err
+			if p.Status.State != types.StateDestroy {
+				if err := podDestroy(ss, p); err != nil {
+					return err
+				}
 			}
-		}
 
-		if p.Status.State == types.StateDestroyed {
-			if err := podRemove(ss, p); err != nil {
-				return err
+			if p.Status.State == types.StateDestroyed {
+				if err := podRemove(ss, p); err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -460,6 +635,70 @@ func deploymentRemove(d *types.Deployment) error {
 	return nil
 }
 
+// deploymentHistory keeps destroyed deployment for rollback and
+// removes deployments above service revision history limit
+func deploymentHistory(ss *ServiceState, d *types.Deployment) error {
+
+	if ss.service == nil || ss.service.Spec.Strategy.History <= 0 {
+		return deploymentRemove(d)
+	}
+
+	switch ss.service.Status.State {
+	case types.StateDestroy, types.StateDestroyed:
+		return deploymentRemove(d)
+	}
+
+	dm := distribution.NewDeploymentModel(context.Background(), envs.Get().GetStorage())
+
+	d.Spec.State.Destroy = true
+	d.Status.State = types.StateDestroyed
+	d.Meta.Updated = time.Now()
+
+	if err := dm.Update(d); err != nil {
+		return err
+	}
+
+	return deploymentHistoryClean(ss, ss.service, ss.service.Spec.Strategy.History)
+}
+
+// deploymentHistoryClean removes destroyed deployments kept in history above limit, latest versions are kept
+func deploymentHistoryClean(ss *ServiceState, svc *types.Service, limit int) error {
+
+	dm := distribution.NewDeploymentModel(context.Background(), envs.Get().GetStorage())
+
+	dl, err := dm.ListByService(svc.Meta.Namespace, svc.Meta.Name)
+	if err != nil {
+		return err
+	}
+
+	history := make([]*types.Deployment, 0)
+	for _, d := range dl.Items {
+		if d.Status.State == types.StateDestroyed && len(ss.pod.list[d.SelfLink()]) == 0 {
+			history = append(history, d)
+		}
+	}
+
+	if len(history) <= limit {
+		return nil
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		if history[i].Meta.Version == history[j].Meta.Version {
+			return history[i].Meta.Created.After(history[j].Meta.Created)
+		}
+		return history[i].Meta.Version > history[j].Meta.Version
+	})
+
+	for _, d := range history[limit:] {
+		log.V(logLevel).Debugf("%s:> history: remove deployment %s", logDeploymentPrefix, d.SelfLink())
+		if err := deploymentRemove(d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func deploymentScale(d *types.Deployment, replicas int) error {
 	d.Status.State = types.StateProvision
 	d.Spec.Replicas = replicas
@@ -478,6 +717,11 @@ func deploymentStatusState(d *types.Deployment, pl map[string]*types.Pod) (err e
 		}
 	}()
 
+	// deployment with failed rollout keeps error state while its pods are removed
+	if deploymentRolloutFailed(d) {
+		return nil
+	}
+
 	var (
 		state   = make(map[string]int)
 		message string
@@ -614,3 +858,340 @@ func deploymentStatusState(d *types.Deployment, pl map[string]*types.Pod) (err e
 
 	return nil
 }
+
+// deploymentRolling checks that rolling update from active to provision deployment is in progress,
+// daemon and stateful services are always updated by rolling update
+func deploymentRolling(ss *ServiceState) bool {
+	return ss.service != nil && (ss.service.Spec.Strategy.Rolling() || ss.service.Spec.Ordered()) && deploymentReplacing(ss)
+}
+
+// deploymentReplacing checks that active deployment is running while current provision deployment is provisioned
+func deploymentReplacing(ss *ServiceState) bool {
+
+	var (
+		active    = ss.deployment.active
+		provision = ss.deployment.provision
+	)
+
+	if ss.service == nil || active == nil || provision == nil {
+		return false
+	}
+
+	if active.SelfLink() == provision.SelfLink() || !deploymentSpecValidate(provision, ss.service) {
+		return false
+	}
+
+	switch active.Status.State {
+	case types.StateDestroy, types.StateDestroyed:
+		return false
+	}
+
+	return true
+}
+
+// deploymentRolloutStep checks that deployment is provision deployment of rolling update in progress
+func deploymentRolloutStep(ss *ServiceState, d *types.Deployment) bool {
+	return deploymentRolling(ss) && ss.deployment.provision.SelfLink() == d.SelfLink()
+}
+
+// deploymentRolloutDone checks that provision deployment has all replicas available
+func deploymentRolloutDone(ss *ServiceState) bool {
+	if serviceDaemon(ss) {
+		return daemonRolloutDone(ss)
+	}
+	if serviceStateful(ss) {
+		return statefulRolloutDone(ss)
+	}
+	d := ss.deployment.provision
+	return d.Spec.Replicas == ss.service.Spec.Replicas && deploymentPodsAvailable(ss, d) >= d.Spec.Replicas
+}
+
+// deploymentRolloutFailed checks that deployment rollout was stopped by deadline or aborted
+func deploymentRolloutFailed(d *types.Deployment) bool {
+	if d.Status.State != types.StateError {
+		return false
+	}
+	return d.Status.Message == types.DeploymentRolloutDeadlineExceeded || d.Status.Message == types.DeploymentRolloutAborted
+}
+
+// deploymentRolloutExpired checks that provision deployment was not rolled out before strategy deadline
+func deploymentRolloutExpired(ss *ServiceState) bool {
+	var (
+		d        = ss.deployment.provision
+		deadline = ss.service.Spec.Strategy.Deadline
+	)
+
+	if deadline <= 0 || d.Spec.Paused {
+		return false
+	}
+
+	// paused rollout deadline is counted from resume
+	started := d.Meta.Created
+	if d.Meta.Resumed.After(started) {
+		started = d.Meta.Resumed
+	}
+
+	return time.Since(started) > time.Duration(deadline)*time.Second
+}
+
+// deploymentSpecSync applies rollout pause, promotion and abort changes to tracked deployments
+// and restarts rollout deadline timer when rollout is resumed
+func deploymentSpecSync(ss *ServiceState, d *types.Deployment) {
+
+	dp, ok := ss.deployment.list[d.SelfLink()]
+	if !ok || dp == d {
+		return
+	}
+
+	resumed := dp.Spec.Paused && !d.Spec.Paused
+
+	for _, i := range []*types.Deployment{dp, ss.deployment.active, ss.deployment.provision} {
+		if i == nil || i.SelfLink() != d.SelfLink() {
+			continue
+		}
+		i.Spec.Paused = d.Spec.Paused
+		i.Spec.Promoted = d.Spec.Promoted
+		i.Spec.Aborted = d.Spec.Aborted
+		i.Meta.Resumed = d.Meta.Resumed
+	}
+
+	if resumed && deploymentRolloutStep(ss, d) {
+		log.V(logLevel).Debugf("%s:> rollout resumed: %s", logDeploymentPrefix, d.SelfLink())
+		deploymentRolloutDeadline(ss, d)
+	}
+}
+
+// deploymentRolloutDeadline requests deployment observe when strategy deadline is exceeded
+func deploymentRolloutDeadline(ss *ServiceState, d *types.Deployment) {
+	deadline := ss.service.Spec.Strategy.Deadline
+	if deadline <= 0 {
+		return
+	}
+	time.AfterFunc(time.Duration(deadline)*time.Second+time.Second, func() {
+		ss.SetDeployment(d)
+	})
+}
+
+// deploymentRollout - moves rolling update one step forward: scales active deployment down
+// while available replicas fit max unavailable and scales provision deployment up
+// while total replicas fit max surge
+func deploymentRollout(ss *ServiceState) error {
+
+	if !deploymentRolling(ss) || ss.deployment.provision.Spec.Paused {
+		return nil
+	}
+
+	if serviceDaemon(ss) {
+		return daemonRollout(ss)
+	}
+
+	if serviceStateful(ss) {
+		return statefulRollout(ss)
+	}
+
+	var (
+		active    = ss.deployment.active
+		provision = ss.deployment.provision
+		desired   = ss.service.Spec.Replicas
+	)
+
+	if deploymentRolloutDone(ss) {
+		return nil
+	}
+
+	if deploymentRolloutExpired(ss) {
+		return deploymentRolloutFail(ss, types.DeploymentRolloutDeadlineExceeded)
+	}
+
+	surge, unavailable := ss.service.Spec.Strategy.RollingBounds(desired)
+
+	var (
+		available = deploymentPodsAvailable(ss, active)
+		excess    = available + deploymentPodsAvailable(ss, provision) - (desired - unavailable)
+	)
+
+	// unavailable active replicas are removed at once
+	replicas := available
+	if excess > 0 {
+		replicas -= excess
+	}
+	if replicas < 0 {
+		replicas = 0
+	}
+
+	if replicas < active.Spec.Replicas {
+		log.V(logLevel).Debugf("%s:> rollout: scale down %s: %d -> %d", logDeploymentPrefix, active.SelfLink(), active.Spec.Replicas, replicas)
+		if err := deploymentScale(active, replicas); err != nil {
+			log.Errorf("%s:> rollout: scale down err: %s", logDeploymentPrefix, err.Error())
+			return err
+		}
+	}
+
+	replicas = provision.Spec.Replicas
+	if up := desired + surge - active.Spec.Replicas; up > replicas {
+		replicas = up
+	}
+	if replicas > desired {
+		replicas = desired
+	}
+
+	if replicas != provision.Spec.Replicas {
+		log.V(logLevel).Debugf("%s:> rollout: scale up %s: %d -> %d", logDeploymentPrefix, provision.SelfLink(), provision.Spec.Replicas, replicas)
+		if err := deploymentScale(provision, replicas); err != nil {
+			log.Errorf("%s:> rollout: scale up err: %s", logDeploymentPrefix, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deploymentRolloutFail - marks provision deployment as failed with message, removes its pods
+// and scales active deployment back to service replicas
+func deploymentRolloutFail(ss *ServiceState, message string) error {
+
+	var (
+		d  = ss.deployment.provision
+		dm = distribution.NewDeploymentModel(context.Background(), envs.Get().GetStorage())
+	)
+
+	log.V(logLevel).Debugf("%s:> rollout: %s: %s", logDeploymentPrefix, message, d.SelfLink())
+
+	d.Spec.Replicas = 0
+	d.Status.State = types.StateError
+	d.Status.Message = message
+	d.Meta.Updated = time.Now()
+
+	if err := dm.Update(d); err != nil {
+		log.Errorf("%s:> rollout: update deployment err: %s", logDeploymentPrefix, err.Error())
+		return err
+	}
+
+	for _, p := range ss.pod.list[d.SelfLink()] {
+		if p.Status.State == types.StateDestroy || p.Status.State == types.StateDestroyed {
+			continue
+		}
+		if err := podDestroy(ss, p); err != nil {
+			log.Errorf("%s:> rollout: pod destroy err: %s", logDeploymentPrefix, err.Error())
+			return err
+		}
+	}
+
+	ss.deployment.provision = nil
+
+	// daemon pods of active deployment are placed on nodes released by removed pods
+	if ss.deployment.active == nil || ss.deployment.active.SelfLink() == d.SelfLink() || serviceDaemon(ss) {
+		return nil
+	}
+
+	if ss.deployment.active.Spec.Replicas != ss.service.Spec.Replicas {
+		return deploymentScale(ss.deployment.active, ss.service.Spec.Replicas)
+	}
+
+	return nil
+}
+
+// deploymentReleasing checks that canary or blue/green release of provision deployment is in progress
+func deploymentReleasing(ss *ServiceState) bool {
+
+	if ss.service == nil {
+		return false
+	}
+
+	if ss.service.Spec.Ordered() || !ss.service.Spec.Strategy.Canary() && !ss.service.Spec.Strategy.BlueGreen() {
+		return false
+	}
+
+	return deploymentReplacing(ss)
+}
+
+// deploymentReleaseStep checks that deployment is provision deployment of canary or blue/green release
+func deploymentReleaseStep(ss *ServiceState, d *types.Deployment) bool {
+	return deploymentReleasing(ss) && ss.deployment.provision.SelfLink() == d.SelfLink()
+}
+
+// deploymentReleaseDone checks that provision deployment is promoted and has all replicas available
+func deploymentReleaseDone(ss *ServiceState) bool {
+	return ss.deployment.provision.Spec.Promoted && deploymentRolloutDone(ss)
+}
+
+// deploymentCanary checks that canary pods serve traffic next to active deployment pods
+func deploymentCanary(ss *ServiceState) bool {
+	return deploymentReleasing(ss) && ss.service.Spec.Strategy.Canary()
+}
+
+// deploymentRelease - aborts provision deployment on request
+// and scales canary deployment to canary replicas or to service replicas after promotion
+func deploymentRelease(ss *ServiceState) error {
+
+	d := ss.deployment.provision
+	if d == nil || ss.service == nil {
+		return nil
+	}
+
+	if d.Spec.Aborted && !deploymentRolloutFailed(d) {
+		return deploymentRolloutFail(ss, types.DeploymentRolloutAborted)
+	}
+
+	if !deploymentReleasing(ss) {
+		return nil
+	}
+
+	replicas := ss.service.Spec.Replicas
+	if ss.service.Spec.Strategy.Canary() && !d.Spec.Promoted {
+		replicas = ss.service.Spec.Strategy.CanaryReplicas(replicas)
+	}
+
+	if d.Spec.Replicas != replicas {
+		log.V(logLevel).Debugf("%s:> release: scale %s: %d -> %d", logDeploymentPrefix, d.SelfLink(), d.Spec.Replicas, replicas)
+		if err := deploymentScale(d, replicas); err != nil {
+			log.Errorf("%s:> release: scale err: %s", logDeploymentPrefix, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deploymentPodsAvailable returns count of deployment pods available for traffic
+func deploymentPodsAvailable(ss *ServiceState, d *types.Deployment) int {
+	var available int
+	for _, p := range ss.pod.list[d.SelfLink()] {
+		if podAvailable(p) {
+			available++
+		}
+	}
+	return available
+}
+
+// deploymentRolloutRestore restores rolling update deployments: provision deployment
+// is the latest one created for service spec, active is the ready or latest previous one
+func deploymentRolloutRestore(ss *ServiceState) {
+
+	for _, d := range ss.deployment.list {
+
+		switch d.Status.State {
+		case types.StateDestroy, types.StateDestroyed:
+			continue
+		}
+
+		if deploymentRolloutFailed(d) {
+			continue
+		}
+
+		if deploymentSpecValidate(d, ss.service) {
+			if ss.deployment.provision == nil || ss.deployment.provision.Meta.Created.Before(d.Meta.Created) {
+				ss.deployment.provision = d
+			}
+			continue
+		}
+
+		switch true {
+		case ss.deployment.active == nil:
+			ss.deployment.active = d
+		case ss.deployment.active.Status.State == types.StateReady:
+		case d.Status.State == types.StateReady || ss.deployment.active.Meta.Created.Before(d.Meta.Created):
+			ss.deployment.active = d
+		}
+	}
+}
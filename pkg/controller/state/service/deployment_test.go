@@ -22,9 +22,11 @@ import (
 	"context"
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
 	"github.com/lastbackend/lastbackend/pkg/controller/ipam"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func testDeploymentObserver(t *testing.T, name, werr string, wst *ServiceState, state *ServiceState, d *types.Deployment) {
@@ -35,7 +37,7 @@ func testDeploymentObserver(t *testing.T, name, werr string, wst *ServiceState,
 
 	stg := envs.Get().GetStorage()
 
-	ipm, _ := ipam.New("")
+	ipm, _ := ipam.New(nil)
 	envs.Get().SetIPAM(ipm)
 
 	err = stg.Del(ctx, stg.Collection().Deployment(), "")
@@ -1040,3 +1042,282 @@ func TestHandleDeploymentStateDestroyed(t *testing.T) {
 		testDeploymentObserver(t, tt.name, tt.want.err, tt.want.state, tt.args.state, tt.args.d)
 	}
 }
+
+func TestDeploymentRollout(t *testing.T) {
+
+	type suit struct {
+		name string
+		args struct {
+			surge       int
+			unavailable int
+			deadline    int
+			created     time.Time
+			ready       int
+			provision   int
+			paused      bool
+		}
+		want struct {
+			active    int
+			provision int
+			failed    bool
+		}
+	}
+
+	var tests = []suit{
+		func() suit {
+			s := suit{name: "rollout start with max surge"}
+			s.args.surge = 1
+			s.args.ready = 3
+			s.want.active = 3
+			s.want.provision = 1
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "rollout start with max unavailable"}
+			s.args.unavailable = 1
+			s.args.ready = 3
+			s.want.active = 2
+			s.want.provision = 1
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "rollout step after provision replica is ready"}
+			s.args.surge = 1
+			s.args.ready = 3
+			s.args.provision = 1
+			s.want.active = 2
+			s.want.provision = 2
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "paused rollout is not moved forward"}
+			s.args.surge = 1
+			s.args.deadline = 10
+			s.args.created = time.Now().Add(-time.Minute)
+			s.args.ready = 3
+			s.args.paused = true
+			s.want.active = 3
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "rollout deadline exceeded"}
+			s.args.surge = 1
+			s.args.deadline = 10
+			s.args.created = time.Now().Add(-time.Minute)
+			s.args.ready = 3
+			s.want.active = 3
+			s.want.failed = true
+			return s
+		}(),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			svc := getServiceAsset(types.StateReady, types.EmptyString)
+			svc.Spec.Replicas = 3
+
+			active := getDeploymentAsset(svc, types.StateReady, types.EmptyString)
+			active.Spec.Template.Updated = svc.Spec.Template.Updated.Add(-time.Minute)
+
+			svc.Spec.Strategy.Type = types.SpecStrategyTypeRolling
+			svc.Spec.Strategy.RollingOptions.MaxSurge = tt.args.surge
+			svc.Spec.Strategy.RollingOptions.MaxUnavailable = tt.args.unavailable
+			svc.Spec.Strategy.Deadline = tt.args.deadline
+
+			provision := getDeploymentAsset(svc, types.StateProvision, types.EmptyString)
+			provision.Spec.Replicas = tt.args.provision
+			provision.Spec.Paused = tt.args.paused
+			provision.Meta.Created = time.Now()
+			if !tt.args.created.IsZero() {
+				provision.Meta.Created = tt.args.created
+			}
+
+			ss := getServiceStateAsset(svc)
+			ss.deployment.active = active
+			ss.deployment.provision = provision
+			ss.deployment.list[active.SelfLink()] = active
+			ss.deployment.list[provision.SelfLink()] = provision
+			ss.pod.list[active.SelfLink()] = make(map[string]*types.Pod)
+			ss.pod.list[provision.SelfLink()] = make(map[string]*types.Pod)
+
+			for i := 0; i < tt.args.ready; i++ {
+				p := getPodAsset(active, types.StateReady, types.EmptyString)
+				ss.pod.list[active.SelfLink()][p.SelfLink()] = p
+			}
+
+			for i := 0; i < tt.args.provision; i++ {
+				p := getPodAsset(provision, types.StateReady, types.EmptyString)
+				ss.pod.list[provision.SelfLink()][p.SelfLink()] = p
+			}
+
+			if !assert.NoError(t, deploymentRollout(ss)) {
+				return
+			}
+
+			assert.Equal(t, tt.want.active, active.Spec.Replicas, "active replicas")
+			assert.Equal(t, tt.want.provision, provision.Spec.Replicas, "provision replicas")
+
+			if tt.want.failed {
+				assert.Nil(t, ss.deployment.provision, "provision deployment")
+				assert.Equal(t, types.StateError, provision.Status.State, "provision state")
+				assert.Equal(t, types.DeploymentRolloutDeadlineExceeded, provision.Status.Message, "provision message")
+				return
+			}
+
+			assert.Equal(t, provision, ss.deployment.provision, "provision deployment")
+		})
+	}
+}
+
+func TestDeploymentHistoryClean(t *testing.T) {
+
+	stg := envs.Get().GetStorage()
+
+	svc := getServiceAsset(types.StateReady, types.EmptyString)
+	svc.Spec.Strategy.History = 2
+
+	ss := getServiceStateAsset(svc)
+
+	active := getDeploymentAsset(svc, types.StateReady, types.EmptyString)
+	active.Meta.Version = 5
+
+	items := []*types.Deployment{active}
+	for i := 1; i < 5; i++ {
+		d := getDeploymentAsset(svc, types.StateDestroyed, types.EmptyString)
+		d.Meta.Version = i
+		items = append(items, d)
+	}
+
+	for _, d := range items {
+		err := stg.Put(context.Background(), stg.Collection().Deployment(), stg.Key().Deployment(d.Meta.Namespace, d.Meta.Service, d.Meta.Name), d, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	defer func() {
+		for _, d := range items {
+			stg.Del(context.Background(), stg.Collection().Deployment(), stg.Key().Deployment(d.Meta.Namespace, d.Meta.Service, d.Meta.Name))
+		}
+	}()
+
+	if !assert.NoError(t, deploymentHistoryClean(ss, svc, svc.Spec.Strategy.History)) {
+		return
+	}
+
+	dm := distribution.NewDeploymentModel(context.Background(), stg)
+	dl, err := dm.ListByService(svc.Meta.Namespace, svc.Meta.Name)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	links := make(map[string]bool)
+	for _, d := range items {
+		links[d.SelfLink()] = true
+	}
+
+	versions := make([]int, 0)
+	for _, d := range dl.Items {
+		if links[d.SelfLink()] {
+			versions = append(versions, d.Meta.Version)
+		}
+	}
+
+	assert.ElementsMatch(t, []int{3, 4, 5}, versions, "kept deployment versions")
+}
+
+func TestDeploymentRelease(t *testing.T) {
+
+	type suit struct {
+		name string
+		args struct {
+			strategy  string
+			canary    int
+			provision int
+			promoted  bool
+			aborted   bool
+		}
+		want struct {
+			provision int
+			aborted   bool
+		}
+	}
+
+	var tests = []suit{
+		func() suit {
+			s := suit{name: "canary is scaled to canary replicas"}
+			s.args.strategy = types.SpecStrategyTypeCanary
+			s.args.canary = 1
+			s.want.provision = 1
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "canary is scaled to service replicas after promotion"}
+			s.args.strategy = types.SpecStrategyTypeCanary
+			s.args.canary = 1
+			s.args.provision = 1
+			s.args.promoted = true
+			s.want.provision = 3
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "blue/green is scaled to service replicas"}
+			s.args.strategy = types.SpecStrategyTypeBlueGreen
+			s.want.provision = 3
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "aborted release is failed"}
+			s.args.strategy = types.SpecStrategyTypeCanary
+			s.args.canary = 1
+			s.args.provision = 1
+			s.args.aborted = true
+			s.want.aborted = true
+			return s
+		}(),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			svc := getServiceAsset(types.StateReady, types.EmptyString)
+			svc.Spec.Replicas = 3
+
+			active := getDeploymentAsset(svc, types.StateReady, types.EmptyString)
+			active.Spec.Template.Updated = svc.Spec.Template.Updated.Add(-time.Minute)
+
+			svc.Spec.Strategy.Type = tt.args.strategy
+			svc.Spec.Strategy.CanaryOptions.Replicas = tt.args.canary
+
+			provision := getDeploymentAsset(svc, types.StateProvision, types.EmptyString)
+			provision.Spec.Replicas = tt.args.provision
+			provision.Spec.Promoted = tt.args.promoted
+			provision.Spec.Aborted = tt.args.aborted
+
+			ss := getServiceStateAsset(svc)
+			ss.deployment.active = active
+			ss.deployment.provision = provision
+			ss.deployment.list[active.SelfLink()] = active
+			ss.deployment.list[provision.SelfLink()] = provision
+			ss.pod.list[active.SelfLink()] = make(map[string]*types.Pod)
+			ss.pod.list[provision.SelfLink()] = make(map[string]*types.Pod)
+
+			if !assert.NoError(t, deploymentRelease(ss)) {
+				return
+			}
+
+			assert.Equal(t, 3, active.Spec.Replicas, "active replicas")
+
+			if tt.want.aborted {
+				assert.Nil(t, ss.deployment.provision, "provision deployment")
+				assert.Equal(t, 0, provision.Spec.Replicas, "provision replicas")
+				assert.Equal(t, types.StateError, provision.Status.State, "provision state")
+				assert.Equal(t, types.DeploymentRolloutAborted, provision.Status.Message, "provision message")
+				return
+			}
+
+			assert.Equal(t, tt.want.provision, provision.Spec.Replicas, "provision replicas")
+		})
+	}
+}
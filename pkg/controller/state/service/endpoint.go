@@ -20,6 +20,7 @@ package service
 
 import (
 	"context"
+	"net"
 
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
 	"github.com/lastbackend/lastbackend/pkg/distribution"
@@ -131,7 +132,7 @@ func endpointAdd(ss *ServiceState, svc *types.Service) error {
 	)
 
 	if svc.Spec.Network.IP == types.EmptyString {
-		ip, err := envs.Get().GetIPAM().Lease()
+		ip, err := envs.Get().GetIPAM().Lease(svc.Spec.Network.Pool, svc.SelfLink())
 		if err != nil {
 			log.Errorf("%s", err.Error())
 			return err
@@ -193,6 +194,12 @@ func endpointDel(ss *ServiceState) error {
 			log.Errorf("%s> del endpoint error: %s", logEndpointPrefix, err.Error())
 			return err
 		}
+
+		if ip := net.ParseIP(ss.endpoint.endpoint.Spec.IP); ip != nil {
+			if err := envs.Get().GetIPAM().Release(&ip); err != nil {
+				log.Errorf("%s> release endpoint ip error: %s", logEndpointPrefix, err.Error())
+			}
+		}
 	}
 
 	ss.endpoint.endpoint = nil
@@ -297,15 +304,11 @@ func endpointManifestProvision(ss *ServiceState) error {
 
 	if ss.endpoint.manifest != nil {
 
-		var pl = make(map[string]*types.Pod)
-
-		if ss.deployment.active != nil {
-			if _, ok := ss.pod.list[ss.deployment.active.SelfLink()]; ok {
-				pl = ss.pod.list[ss.deployment.active.SelfLink()]
-			}
-		}
+		var pl = endpointPods(ss)
 
-		if !endpointManifestSpecEqual(ss.endpoint.endpoint, ss.endpoint.manifest) || !endpointManifestUpstreamsEqual(ss.endpoint.manifest, pl) {
+		if !endpointManifestSpecEqual(ss.endpoint.endpoint, ss.endpoint.manifest) || !endpointManifestUpstreamsEqual(ss.endpoint.manifest, pl) ||
+			!endpointManifestWeightsEqual(ss.endpoint.manifest, endpointManifestGetWeights(ss)) ||
+			!endpointManifestHostsEqual(ss.endpoint.manifest, endpointManifestGetHosts(ss)) {
 			if err := endpointManifestSet(ss); err != nil {
 				return err
 			}
@@ -327,7 +330,7 @@ func endpointManifestAdd(ss *ServiceState) error {
 	var (
 		err error
 		em  = distribution.NewEndpointModel(context.Background(), envs.Get().GetStorage())
-		pl  = make(map[string]*types.Pod)
+		pl  = endpointPods(ss)
 	)
 
 	if ss.endpoint.endpoint == nil {
@@ -335,12 +338,6 @@ func endpointManifestAdd(ss *ServiceState) error {
 		return nil
 	}
 
-	if ss.deployment.active != nil {
-		if _, ok := ss.pod.list[ss.deployment.active.SelfLink()]; ok {
-			pl = ss.pod.list[ss.deployment.active.SelfLink()]
-		}
-	}
-
 	epm, err := em.ManifestGet(ss.endpoint.endpoint.SelfLink())
 	if err != nil {
 		return err
@@ -350,6 +347,8 @@ func endpointManifestAdd(ss *ServiceState) error {
 		ss.endpoint.manifest = &types.EndpointManifest{}
 		ss.endpoint.manifest.EndpointSpec = ss.endpoint.endpoint.Spec
 		ss.endpoint.manifest.Upstreams = endpointManifestGetUpstreams(pl)
+		ss.endpoint.manifest.Weights = endpointManifestGetWeights(ss)
+		ss.endpoint.manifest.Hosts = endpointManifestGetHosts(ss)
 
 		if err = em.ManifestAdd(ss.endpoint.endpoint.SelfLink(), ss.endpoint.manifest); err != nil {
 			log.Errorf("%s> add endpoint manifest error: %s", logPrefix, err.Error())
@@ -365,6 +364,8 @@ func endpointManifestAdd(ss *ServiceState) error {
 
 	epm.EndpointSpec = ss.endpoint.endpoint.Spec
 	epm.Upstreams = endpointManifestGetUpstreams(pl)
+	epm.Weights = endpointManifestGetWeights(ss)
+	epm.Hosts = endpointManifestGetHosts(ss)
 
 	if err = em.ManifestSet(ss.endpoint.endpoint.SelfLink(), epm); err != nil {
 		log.Errorf("%s> update endpoint manifest error: %s", logPrefix, err.Error())
@@ -380,7 +381,7 @@ func endpointManifestSet(ss *ServiceState) error {
 	var (
 		err error
 		em  = distribution.NewEndpointModel(context.Background(), envs.Get().GetStorage())
-		pl  = make(map[string]*types.Pod)
+		pl  = endpointPods(ss)
 	)
 
 	if ss.endpoint.endpoint == nil {
@@ -388,14 +389,10 @@ func endpointManifestSet(ss *ServiceState) error {
 		return nil
 	}
 
-	if ss.deployment.active != nil {
-		if _, ok := ss.pod.list[ss.deployment.active.SelfLink()]; ok {
-			pl = ss.pod.list[ss.deployment.active.SelfLink()]
-		}
-	}
-
 	ss.endpoint.manifest.EndpointSpec = ss.endpoint.endpoint.Spec
 	ss.endpoint.manifest.Upstreams = endpointManifestGetUpstreams(pl)
+	ss.endpoint.manifest.Weights = endpointManifestGetWeights(ss)
+	ss.endpoint.manifest.Hosts = endpointManifestGetHosts(ss)
 
 	if err = em.ManifestSet(ss.endpoint.endpoint.SelfLink(), ss.endpoint.manifest); err != nil {
 		log.Errorf("%s> update endpoint manifest error: %s", logPrefix, err.Error())
@@ -421,15 +418,132 @@ func endpointManifestDel(ss *ServiceState) error {
 	return nil
 }
 
+// endpointPods returns pods serving traffic: active deployment pods
+// and provision deployment pods during rolling update and canary release
+func endpointPods(ss *ServiceState) map[string]*types.Pod {
+
+	var pl = make(map[string]*types.Pod)
+
+	if ss.deployment.active != nil {
+		for link, p := range ss.pod.list[ss.deployment.active.SelfLink()] {
+			pl[link] = p
+		}
+	}
+
+	if deploymentRolling(ss) || deploymentCanary(ss) {
+		for link, p := range ss.pod.list[ss.deployment.provision.SelfLink()] {
+			pl[link] = p
+		}
+	}
+
+	return pl
+}
+
 func endpointManifestGetUpstreams(pl map[string]*types.Pod) []string {
 
 	ips := make([]string, 0)
 
 	for _, p := range pl {
-		if p.Status.State == types.StateReady && p.Status.Network.PodIP != types.EmptyString {
+		if p.Status.State == types.StateReady && p.Status.Ready && p.Status.Network.PodIP != types.EmptyString {
 			ips = append(ips, p.Status.Network.PodIP)
 		}
 	}
 
 	return ips
 }
+
+// endpointManifestGetWeights returns upstreams weights to send canary weight percent
+// of traffic to canary pods, nil is returned if traffic is shared by pods count
+func endpointManifestGetWeights(ss *ServiceState) map[string]int {
+
+	if !deploymentCanary(ss) || ss.deployment.provision.Spec.Promoted {
+		return nil
+	}
+
+	weight := ss.service.Spec.Strategy.CanaryOptions.Weight
+	if weight <= 0 || weight > 100 {
+		return nil
+	}
+
+	var (
+		active = endpointManifestGetUpstreams(ss.pod.list[ss.deployment.active.SelfLink()])
+		canary = endpointManifestGetUpstreams(ss.pod.list[ss.deployment.provision.SelfLink()])
+	)
+
+	if len(active) == 0 || len(canary) == 0 {
+		return nil
+	}
+
+	// canary pods share is weight percent: canary * wc / (canary * wc + active * wa) = weight / 100
+	var (
+		wc = weight * len(active)
+		wa = (100 - weight) * len(canary)
+		d  = gcd(wc, wa)
+	)
+
+	weights := make(map[string]int)
+	for _, ip := range active {
+		weights[ip] = wa / d
+	}
+	for _, ip := range canary {
+		weights[ip] = wc / d
+	}
+
+	return weights
+}
+
+func endpointManifestWeightsEqual(m *types.EndpointManifest, weights map[string]int) bool {
+
+	if len(m.Weights) != len(weights) {
+		return false
+	}
+
+	for ip, w := range weights {
+		if m.Weights[ip] != w {
+			return false
+		}
+	}
+
+	return true
+}
+
+// endpointManifestGetHosts returns upstreams by pod name to resolve stateful service pods
+// by ordinal name, nil is returned for other services
+func endpointManifestGetHosts(ss *ServiceState) map[string]string {
+
+	if !serviceStateful(ss) {
+		return nil
+	}
+
+	hosts := make(map[string]string)
+
+	for _, p := range endpointPods(ss) {
+		if p.Status.State == types.StateReady && p.Status.Ready && p.Status.Network.PodIP != types.EmptyString {
+			hosts[p.Meta.Name] = p.Status.Network.PodIP
+		}
+	}
+
+	return hosts
+}
+
+func endpointManifestHostsEqual(m *types.EndpointManifest, hosts map[string]string) bool {
+
+	if len(m.Hosts) != len(hosts) {
+		return false
+	}
+
+	for name, ip := range hosts {
+		if m.Hosts[name] != ip {
+			return false
+		}
+	}
+
+	return true
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
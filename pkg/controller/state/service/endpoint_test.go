@@ -0,0 +1,189 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointManifestGetUpstreams(t *testing.T) {
+
+	pod := func(state string, ready bool, ip string) *types.Pod {
+		p := types.NewPod()
+		p.Status.State = state
+		p.Status.Ready = ready
+		p.Status.Network.PodIP = ip
+		return p
+	}
+
+	tests := []struct {
+		name string
+		pods map[string]*types.Pod
+		want []string
+	}{
+		{
+			name: "ready pods are upstreams",
+			pods: map[string]*types.Pod{
+				"p1": pod(types.StateReady, true, "10.0.0.1"),
+			},
+			want: []string{"10.0.0.1"},
+		},
+		{
+			name: "running pods without passed readiness probe are skipped",
+			pods: map[string]*types.Pod{
+				"p1": pod(types.StateReady, true, "10.0.0.1"),
+				"p2": pod(types.StateReady, false, "10.0.0.2"),
+			},
+			want: []string{"10.0.0.1"},
+		},
+		{
+			name: "not running pods are skipped",
+			pods: map[string]*types.Pod{
+				"p1": pod(types.StateProvision, true, "10.0.0.1"),
+				"p2": pod(types.StateReady, true, types.EmptyString),
+			},
+			want: []string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, endpointManifestGetUpstreams(tc.pods), "upstreams mismatch")
+		})
+	}
+}
+
+func TestEndpointManifestGetWeights(t *testing.T) {
+
+	type suit struct {
+		name string
+		args struct {
+			strategy string
+			weight   int
+			active   int
+			canary   int
+			promoted bool
+		}
+		want struct {
+			active int
+			canary int
+		}
+	}
+
+	var tests = []suit{
+		func() suit {
+			s := suit{name: "canary pods share traffic by weight"}
+			s.args.strategy = types.SpecStrategyTypeCanary
+			s.args.weight = 20
+			s.args.active = 2
+			s.args.canary = 1
+			s.want.active = 2
+			s.want.canary = 1
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "canary pods receive all traffic"}
+			s.args.strategy = types.SpecStrategyTypeCanary
+			s.args.weight = 100
+			s.args.active = 2
+			s.args.canary = 1
+			s.want.active = 0
+			s.want.canary = 1
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "promoted canary is not weighted"}
+			s.args.strategy = types.SpecStrategyTypeCanary
+			s.args.weight = 20
+			s.args.active = 2
+			s.args.canary = 1
+			s.args.promoted = true
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "blue/green release is not weighted"}
+			s.args.strategy = types.SpecStrategyTypeBlueGreen
+			s.args.weight = 20
+			s.args.active = 2
+			s.args.canary = 1
+			return s
+		}(),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			svc := getServiceAsset(types.StateReady, types.EmptyString)
+			svc.Spec.Replicas = 2
+
+			active := getDeploymentAsset(svc, types.StateReady, types.EmptyString)
+			active.Spec.Template.Updated = svc.Spec.Template.Updated.Add(-time.Minute)
+
+			svc.Spec.Strategy.Type = tt.args.strategy
+			svc.Spec.Strategy.CanaryOptions.Weight = tt.args.weight
+
+			canary := getDeploymentAsset(svc, types.StateReady, types.EmptyString)
+			canary.Spec.Promoted = tt.args.promoted
+
+			ss := getServiceStateAsset(svc)
+			ss.deployment.active = active
+			ss.deployment.provision = canary
+			ss.deployment.list[active.SelfLink()] = active
+			ss.deployment.list[canary.SelfLink()] = canary
+			ss.pod.list[active.SelfLink()] = make(map[string]*types.Pod)
+			ss.pod.list[canary.SelfLink()] = make(map[string]*types.Pod)
+
+			var ip = 0
+			pods := func(d *types.Deployment, count int) []string {
+				ips := make([]string, 0)
+				for i := 0; i < count; i++ {
+					ip++
+					p := getPodAsset(d, types.StateReady, types.EmptyString)
+					p.Status.Network.PodIP = fmt.Sprintf("10.0.0.%d", ip)
+					ss.pod.list[d.SelfLink()][p.SelfLink()] = p
+					ips = append(ips, p.Status.Network.PodIP)
+				}
+				return ips
+			}
+
+			var (
+				aips = pods(active, tt.args.active)
+				cips = pods(canary, tt.args.canary)
+			)
+
+			weights := endpointManifestGetWeights(ss)
+			if tt.want.active == 0 && tt.want.canary == 0 {
+				assert.Nil(t, weights, "weights")
+				return
+			}
+
+			for _, ip := range aips {
+				assert.Equal(t, tt.want.active, weights[ip], "active upstream weight")
+			}
+			for _, ip := range cips {
+				assert.Equal(t, tt.want.canary, weights[ip], "canary upstream weight")
+			}
+		})
+	}
+}
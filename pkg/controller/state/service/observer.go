@@ -21,6 +21,7 @@ package service
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
 	"github.com/lastbackend/lastbackend/pkg/controller/state/cluster"
@@ -50,14 +51,31 @@ type ServiceState struct {
 		list      map[string]*types.Deployment
 	}
 	pod struct {
-		list map[string]map[string]*types.Pod
+		list  map[string]map[string]*types.Pod
+		evict map[string]*types.Pod
+	}
+
+	autoscaler struct {
+		autoscaler      *types.Autoscaler
+		recommendations []types.AutoscalerRecommendation
 	}
 
 	observers struct {
 		service    chan *types.Service
 		deployment chan *types.Deployment
 		pod        chan *types.Pod
+		del        chan *types.Pod
+		evict      chan *cluster.PodEviction
+		node       chan *types.Node
+		volume     chan *types.Volume
+		autoscaler chan *types.Autoscaler
+		unscale    chan *types.Autoscaler
+		autoscale  chan time.Time
 	}
+
+	// ctx is canceled when state is stopped, observer exits and requests are ignored
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 func (ss *ServiceState) Restore() error {
@@ -98,6 +116,10 @@ func (ss *ServiceState) Restore() error {
 	}
 
 	for _, d := range dl.Items {
+		// skip destroyed deployments kept in history
+		if d.Status.State == types.StateDestroyed && len(ss.pod.list[d.SelfLink()]) == 0 {
+			continue
+		}
 		log.Infof("%s: restore deployment: %s", logPrefix, d.SelfLink())
 		ss.deployment.list[d.SelfLink()] = d
 	}
@@ -115,6 +137,11 @@ func (ss *ServiceState) Restore() error {
 	// if service is in provision state - mark deployment in ready state as current
 	case types.StateProvision:
 
+		if ss.service.Spec.Ordered() || ss.service.Spec.Strategy.Rolling() || ss.service.Spec.Strategy.Canary() || ss.service.Spec.Strategy.BlueGreen() {
+			deploymentRolloutRestore(ss)
+			break
+		}
+
 		for _, d := range ss.deployment.list {
 			if d.Status.State == types.StateReady {
 				ss.deployment.active = d
@@ -141,6 +168,14 @@ func (ss *ServiceState) Restore() error {
 		break
 	}
 
+	// Get autoscaler
+	am := distribution.NewAutoscalerModel(context.Background(), stg)
+	ss.autoscaler.autoscaler, err = am.Get(ss.service.Meta.Namespace, ss.service.Meta.Name)
+	if err != nil {
+		log.Errorf("%s:restore:> get autoscaler error: %v", logPrefix, err)
+		return err
+	}
+
 	// Get endpoint
 	if err := endpointRestore(ss); err != nil {
 		log.Errorf("%s: restore endpoint: %s", logPrefix, err.Error())
@@ -159,7 +194,7 @@ func (ss *ServiceState) Restore() error {
 		ss.observers.deployment <- d
 	}
 
-	ss.observers.service <- ss.service
+	ss.SetService(ss.service)
 
 	return nil
 }
@@ -168,6 +203,9 @@ func (ss *ServiceState) Observe() {
 	for {
 		select {
 
+		case <-ss.ctx.Done():
+			return
+
 		case p := <-ss.observers.pod:
 			log.V(logLevel).Debugf("%s:observe:pod:> %s", logPrefix, p.SelfLink())
 			if err := PodObserve(ss, p); err != nil {
@@ -175,6 +213,32 @@ func (ss *ServiceState) Observe() {
 			}
 			break
 
+		case p := <-ss.observers.del:
+			log.V(logLevel).Debugf("%s:observe:pod:remove:> %s", logPrefix, p.SelfLink())
+			podDel(ss, p)
+			break
+
+		case e := <-ss.observers.evict:
+			log.V(logLevel).Debugf("%s:observe:evict:> %s", logPrefix, e.Pod.SelfLink())
+			if err := podEvictObserve(ss, e); err != nil {
+				log.Errorf("%s:observe:evict err:> %s", logPrefix, err.Error())
+			}
+			break
+
+		case n := <-ss.observers.node:
+			log.V(logLevel).Debugf("%s:observe:node:> %s", logPrefix, n.SelfLink())
+			if err := daemonNodeObserve(ss, n); err != nil {
+				log.Errorf("%s:observe:node err:> %s", logPrefix, err.Error())
+			}
+			break
+
+		case v := <-ss.observers.volume:
+			log.V(logLevel).Debugf("%s:observe:volume:> %s", logPrefix, v.SelfLink())
+			if err := statefulVolumeObserve(ss, v); err != nil {
+				log.Errorf("%s:observe:volume err:> %s", logPrefix, err.Error())
+			}
+			break
+
 		case d := <-ss.observers.deployment:
 			log.V(logLevel).Debugf("%s:observe:deployment:> %s", logPrefix, d.SelfLink())
 			if err := deploymentObserve(ss, d); err != nil {
@@ -188,17 +252,46 @@ func (ss *ServiceState) Observe() {
 				log.Errorf("%s:observe:service err:> %s", logPrefix, err.Error())
 			}
 			break
+
+		case a := <-ss.observers.autoscaler:
+			log.V(logLevel).Debugf("%s:observe:autoscaler:> %s", logPrefix, a.SelfLink())
+			if ss.autoscaler.autoscaler == nil || ss.autoscaler.autoscaler.Spec != a.Spec {
+				ss.autoscaler.recommendations = make([]types.AutoscalerRecommendation, 0)
+			}
+			ss.autoscaler.autoscaler = a
+			break
+
+		case a := <-ss.observers.unscale:
+			log.V(logLevel).Debugf("%s:observe:autoscaler:remove:> %s", logPrefix, a.SelfLink())
+			if ss.autoscaler.autoscaler == nil || ss.autoscaler.autoscaler.SelfLink() != a.SelfLink() {
+				break
+			}
+			ss.autoscaler.autoscaler = nil
+			ss.autoscaler.recommendations = nil
+			break
+
+		case t := <-ss.observers.autoscale:
+			if err := autoscalerObserve(ss, t); err != nil {
+				log.Errorf("%s:observe:autoscale err:> %s", logPrefix, err.Error())
+			}
+			break
 		}
 
 	}
 }
 
 func (ss *ServiceState) SetService(s *types.Service) {
-	ss.observers.service <- s
+	select {
+	case ss.observers.service <- s:
+	case <-ss.ctx.Done():
+	}
 }
 
 func (ss *ServiceState) SetDeployment(d *types.Deployment) {
-	ss.observers.deployment <- d
+	select {
+	case ss.observers.deployment <- d:
+	case <-ss.ctx.Done():
+	}
 }
 
 func (ss *ServiceState) DelDeployment(d *types.Deployment) {
@@ -229,30 +322,94 @@ func (ss *ServiceState) DelDeployment(d *types.Deployment) {
 }
 
 func (ss *ServiceState) SetPod(p *types.Pod) {
-	ss.observers.pod <- p
+	select {
+	case ss.observers.pod <- p:
+	case <-ss.ctx.Done():
+	}
+}
+
+// EvictPod requests pod rescheduling to another node
+func (ss *ServiceState) EvictPod(e *cluster.PodEviction) {
+	select {
+	case ss.observers.evict <- e:
+	case <-ss.ctx.Done():
+	}
+}
+
+// SetNode requests daemon pods placement check after node is changed
+func (ss *ServiceState) SetNode(n *types.Node) {
+	select {
+	case ss.observers.node <- n:
+	case <-ss.ctx.Done():
+	}
+}
+
+// SetVolume requests stateful pods provision after namespace volume is changed
+func (ss *ServiceState) SetVolume(v *types.Volume) {
+	select {
+	case ss.observers.volume <- v:
+	case <-ss.ctx.Done():
+	}
+}
+
+func (ss *ServiceState) SetAutoscaler(a *types.Autoscaler) {
+	select {
+	case ss.observers.autoscaler <- a:
+	case <-ss.ctx.Done():
+	}
+}
+
+func (ss *ServiceState) DelAutoscaler(a *types.Autoscaler) {
+	select {
+	case ss.observers.unscale <- a:
+	case <-ss.ctx.Done():
+	}
+}
+
+// Autoscale requests service replicas evaluation by autoscaler,
+// services without autoscaler are skipped by observer
+func (ss *ServiceState) Autoscale(t time.Time) {
+	select {
+	case ss.observers.autoscale <- t:
+	case <-ss.ctx.Done():
+	}
 }
 
 func (ss *ServiceState) DelPod(p *types.Pod) {
-	if _, ok := ss.pod.list[p.DeploymentLink()]; !ok {
-		return
+	select {
+	case ss.observers.del <- p:
+	case <-ss.ctx.Done():
 	}
+}
 
-	delete(ss.pod.list[p.DeploymentLink()], p.SelfLink())
+// Stop stops service state observer
+func (ss *ServiceState) Stop() {
+	ss.cancel()
 }
 
 func NewServiceState(cs *cluster.ClusterState, s *types.Service) *ServiceState {
 
 	var ss = new(ServiceState)
 
+	ss.ctx, ss.cancel = context.WithCancel(context.Background())
+
 	ss.service = s
 	ss.cluster = cs
 
 	ss.observers.service = make(chan *types.Service)
 	ss.observers.deployment = make(chan *types.Deployment)
 	ss.observers.pod = make(chan *types.Pod)
+	ss.observers.del = make(chan *types.Pod)
+	ss.observers.evict = make(chan *cluster.PodEviction)
+	ss.observers.node = make(chan *types.Node)
+	ss.observers.volume = make(chan *types.Volume)
+	ss.observers.autoscaler = make(chan *types.Autoscaler)
+	ss.observers.unscale = make(chan *types.Autoscaler)
+	ss.observers.autoscale = make(chan time.Time)
 
 	ss.deployment.list = make(map[string]*types.Deployment)
 	ss.pod.list = make(map[string]map[string]*types.Pod)
+	ss.pod.evict = make(map[string]*types.Pod)
 
 	go ss.Observe()
 
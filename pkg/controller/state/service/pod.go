@@ -20,8 +20,10 @@ package service
 
 import (
 	"context"
+	"sort"
 
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/controller/state/cluster"
 	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
@@ -86,6 +88,20 @@ func PodObserve(ss *ServiceState, p *types.Pod) error {
 		pl[p.SelfLink()] = p
 	}
 
+	// daemon pods are placed on node released by removed pod
+	if p.Status.State == types.StateDestroyed && serviceDaemon(ss) {
+		if err := deploymentsPodProvision(ss); err != nil {
+			return err
+		}
+	}
+
+	// stateful pods are created and stopped one at a time after previous pod is changed
+	if serviceStateful(ss) {
+		if err := statefulProvision(ss); err != nil {
+			return err
+		}
+	}
+
 	d, ok := ss.deployment.list[p.DeploymentLink()]
 	if !ok {
 		log.V(logLevel).Debugf("%s:> deployment node found: %s", logPodPrefix, p.DeploymentLink())
@@ -94,11 +110,27 @@ func PodObserve(ss *ServiceState, p *types.Pod) error {
 
 	log.V(logLevel).Debugf("%s:> observe finish: %s > %s", logPodPrefix, p.SelfLink(), p.Status.State)
 
-
 	if err := deploymentStatusState(d, pl); err != nil {
 		return err
 	}
 
+	if err := deploymentPodEvict(ss, d); err != nil {
+		return err
+	}
+
+	// both deployments serve traffic during rolling update and canary release
+	if deploymentRolling(ss) || deploymentCanary(ss) {
+		if err := deploymentRollout(ss); err != nil {
+			return err
+		}
+
+		if err := endpointCheck(ss); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
 	if ss.deployment.active != nil {
 		if ss.deployment.active.SelfLink() == d.SelfLink() && d.Status.State == types.StateReady {
 			if err := endpointCheck(ss); err != nil {
@@ -176,6 +208,99 @@ func handlePodStateDestroyed(ss *ServiceState, p *types.Pod) error {
 	return nil
 }
 
+// podEvictObserve queues pod to be moved from tainted node.
+// Pod from lost node is removed at once and recreated by deployment
+func podEvictObserve(ss *ServiceState, e *cluster.PodEviction) error {
+
+	pl, ok := ss.pod.list[e.Pod.DeploymentLink()]
+	if !ok {
+		return nil
+	}
+
+	pod, ok := pl[e.Pod.SelfLink()]
+	if !ok || pod.Meta.Node != e.Pod.Meta.Node {
+		return nil
+	}
+
+	d, ok := ss.deployment.list[pod.DeploymentLink()]
+
+	if e.Lost {
+
+		if err := podLost(ss, pod); err != nil {
+			return err
+		}
+
+		if ok && d.Status.State != types.StateDestroy && d.Status.State != types.StateDestroyed {
+			if err := deploymentPodProvision(ss, d); err != nil {
+				return err
+			}
+		}
+
+		return endpointCheck(ss)
+	}
+
+	if pod.Spec.State.Destroy {
+		return nil
+	}
+
+	ss.pod.evict[pod.SelfLink()] = pod
+
+	if !ok {
+		return nil
+	}
+
+	return deploymentPodEvict(ss, d)
+}
+
+// podLost removes pod placed on lost node.
+// Node can not confirm pod destroy, so pod is removed at once
+// to release node resources and pod network
+func podLost(ss *ServiceState, p *types.Pod) error {
+
+	log.V(logLevel).Debugf("%s:> remove pod %s from lost node %s", logPodPrefix, p.SelfLink(), p.Meta.Node)
+
+	p.Spec.State.Destroy = true
+	p.Status.State = types.StateDestroyed
+	p.Status.Running = false
+	p.Status.Network = types.PodNetwork{}
+
+	return podRemove(ss, p)
+}
+
+// podAvailable checks if pod is running and serves traffic
+func podAvailable(p *types.Pod) bool {
+	return !p.Spec.State.Destroy && p.Status.State == types.StateReady && p.Status.Running && p.Status.Ready
+}
+
+// podsLive returns pods which are not destroyed sorted by creation time
+func podsLive(pods map[string]*types.Pod) []*types.Pod {
+
+	var list = make([]*types.Pod, 0)
+
+	for _, p := range pods {
+
+		if p.Spec.State.Destroy {
+			continue
+		}
+
+		switch p.Status.State {
+		case types.StateDestroy, types.StateDestroyed:
+			continue
+		}
+
+		list = append(list, p)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Meta.Created.Equal(list[j].Meta.Created) {
+			return list[i].SelfLink() < list[j].SelfLink()
+		}
+		return list[i].Meta.Created.Before(list[j].Meta.Created)
+	})
+
+	return list
+}
+
 // podCreate function creates new pod based on deployment spec
 func podCreate(d *types.Deployment) (*types.Pod, error) {
 	dm := distribution.NewPodModel(context.Background(), envs.Get().GetStorage())
@@ -304,10 +429,21 @@ func podRemove(ss *ServiceState, p *types.Pod) (err error) {
 		return err
 	}
 
-	ss.DelPod(p)
+	podDel(ss, p)
 	return nil
 }
 
+// podDel removes pod from service state, called only from service observer
+func podDel(ss *ServiceState, p *types.Pod) {
+	delete(ss.pod.evict, p.SelfLink())
+
+	if _, ok := ss.pod.list[p.DeploymentLink()]; !ok {
+		return
+	}
+
+	delete(ss.pod.list[p.DeploymentLink()], p.SelfLink())
+}
+
 func podUpdate(p *types.Pod, timestamp time.Time) error {
 
 	if timestamp.Before(p.Meta.Updated) {
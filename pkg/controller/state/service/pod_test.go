@@ -36,7 +36,7 @@ func testPodObserver(t *testing.T, name, werr string, wst *ServiceState, state *
 
 	stg :=	envs.Get().GetStorage()
 
-	ipm, _ := ipam.New("")
+	ipm, _ := ipam.New(nil)
 	envs.Get().SetIPAM(ipm)
 
 	err = stg.Del(ctx, stg.Collection().Deployment(), "")
@@ -190,6 +190,17 @@ func handleServiceStateDestroy(ss *ServiceState, svc *types.Service) (err error)
 
 	dm := distribution.NewDeploymentModel(context.Background(), envs.Get().GetStorage())
 	if len(ss.deployment.list) == 0 {
+
+		if err = deploymentHistoryClean(ss, svc, 0); err != nil {
+			log.Errorf("%s:> deployment history remove err: %s", logServicePrefix, err.Error())
+			return err
+		}
+
+		if err = autoscalerRemove(ss); err != nil {
+			log.Errorf("%s:> autoscaler remove err: %s", logServicePrefix, err.Error())
+			return err
+		}
+
 		sm := distribution.NewServiceModel(context.Background(), envs.Get().GetStorage())
 		if err = sm.Remove(svc); err != nil {
 			log.Errorf("%s:> service remove err: %s", logServicePrefix, err.Error())
@@ -258,6 +269,16 @@ func handleServiceStateDestroyed(ss *ServiceState, svc *types.Service) (err erro
 		return nil
 	}
 
+	if err = deploymentHistoryClean(ss, svc, 0); err != nil {
+		log.Errorf("%s:> deployment history remove err: %s", logServicePrefix, err.Error())
+		return err
+	}
+
+	if err = autoscalerRemove(ss); err != nil {
+		log.Errorf("%s:> autoscaler remove err: %s", logServicePrefix, err.Error())
+		return err
+	}
+
 	sm := distribution.NewServiceModel(context.Background(), envs.Get().GetStorage())
 	if err = sm.Remove(svc); err != nil {
 		log.Errorf("%s:> service remove err: %s", logServicePrefix, err.Error())
@@ -297,6 +318,9 @@ func serviceDeploymentProvision(ss *ServiceState, svc *types.Service) error {
 		d *types.Deployment
 	)
 
+	// rolling update state is calculated for current service spec
+	ss.service = svc
+
 	// select deployment for provision
 	switch true {
 
@@ -312,7 +336,26 @@ func serviceDeploymentProvision(ss *ServiceState, svc *types.Service) error {
 	}
 
 	// if deployment found for provision: check and update replicas
-	if d != nil {
+	// rolling update scales provision deployment by steps
+	if d != nil && deploymentRolloutStep(ss, d) {
+		if err := deploymentRollout(ss); err != nil {
+			log.Errorf("%s:> deployment rollout err: %s", logServicePrefix, err.Error())
+			return err
+		}
+		return nil
+	}
+
+	// canary deployment keeps canary replicas until promotion
+	if d != nil && deploymentReleaseStep(ss, d) {
+		if err := deploymentRelease(ss); err != nil {
+			log.Errorf("%s:> deployment release err: %s", logServicePrefix, err.Error())
+			return err
+		}
+		return nil
+	}
+
+	// daemon deployment replicas are set by matched nodes count
+	if d != nil && !svc.Spec.Daemon() {
 		if d.Spec.Replicas != svc.Spec.Replicas {
 			if err := deploymentScale(d, svc.Spec.Replicas); err != nil {
 				log.Errorf("%s:> deployment scale err: %s", logServicePrefix, err.Error())
@@ -324,7 +367,34 @@ func serviceDeploymentProvision(ss *ServiceState, svc *types.Service) error {
 	// create deployment if needed
 	if d == nil {
 
-		d, err := deploymentCreate(svc)
+		// rolling update starts new deployment without replicas and scales it by steps,
+		// daemon service replaces pods by rolling update one node at a time,
+		// stateful service keeps replicas and replaces pods one ordinal at a time,
+		// canary and blue/green release keeps active deployment until new deployment is promoted
+		var replace = ss.deployment.active != nil
+		if replace {
+			switch ss.deployment.active.Status.State {
+			case types.StateDestroy, types.StateDestroyed:
+				replace = false
+			}
+		}
+
+		var (
+			rolling = replace && (svc.Spec.Strategy.Rolling() || svc.Spec.Ordered())
+			release = replace && !svc.Spec.Ordered() && (svc.Spec.Strategy.Canary() || svc.Spec.Strategy.BlueGreen())
+		)
+
+		replicas := svc.Spec.Replicas
+		switch true {
+		case svc.Spec.Stateful():
+			break
+		case rolling, svc.Spec.Daemon():
+			replicas = 0
+		case release && svc.Spec.Strategy.Canary():
+			replicas = svc.Spec.Strategy.CanaryReplicas(replicas)
+		}
+
+		d, err := deploymentCreate(svc, replicas)
 		if err != nil {
 			log.Errorf("%s:> deployment create err: %s", logServicePrefix, err.Error())
 			return err
@@ -333,7 +403,7 @@ func serviceDeploymentProvision(ss *ServiceState, svc *types.Service) error {
 		for _, od := range ss.deployment.list {
 
 			if ss.deployment.active != nil {
-				if ss.deployment.active.SelfLink() == od.SelfLink() && od.Status.State == types.StateReady {
+				if ss.deployment.active.SelfLink() == od.SelfLink() && (od.Status.State == types.StateReady || rolling || release) {
 					continue
 				}
 			}
@@ -348,6 +418,14 @@ func serviceDeploymentProvision(ss *ServiceState, svc *types.Service) error {
 
 		ss.deployment.list[d.SelfLink()] = d
 		ss.deployment.provision = d
+
+		if rolling {
+			deploymentRolloutDeadline(ss, d)
+			if err := deploymentRollout(ss); err != nil {
+				log.Errorf("%s:> deployment rollout err: %s", logServicePrefix, err.Error())
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -358,8 +436,13 @@ func serviceStatusState(ss *ServiceState) (err error) {
 
 	status := ss.service.Status
 
+	// daemon service quota usage is based on nodes running service pods
+	if ss.service.Spec.Daemon() {
+		ss.service.Status.Nodes = daemonNodes(ss)
+	}
+
 	defer func() error {
-		if status.State == ss.service.Status.State && status.Message == ss.service.Status.Message {
+		if status.State == ss.service.Status.State && status.Message == ss.service.Status.Message && status.Nodes == ss.service.Status.Nodes {
 			return nil
 		}
 
@@ -376,8 +459,7 @@ func serviceStatusState(ss *ServiceState) (err error) {
 	if ss.service.Status.State == types.StateProvision || ss.service.Status.State == types.StateCreated {
 
 		if ss.deployment.active != nil {
-			if deploymentSpecValidate(ss.deployment.active, ss.service) &&
-				ss.deployment.active.Spec.Replicas == ss.service.Spec.Replicas {
+			if deploymentSpecValidate(ss.deployment.active, ss.service) && deploymentReplicasValidate(ss, ss.deployment.active) {
 				ss.service.Status.State = ss.deployment.active.Status.State
 				ss.service.Status.Message = ss.deployment.active.Status.Message
 				if ss.deployment.active.Status.State == types.StateCreated {
@@ -388,13 +470,13 @@ func serviceStatusState(ss *ServiceState) (err error) {
 		}
 
 		if ss.deployment.provision != nil && ss.deployment.active == nil {
-			if deploymentSpecValidate(ss.deployment.provision, ss.service) &&
-				ss.deployment.provision.Spec.Replicas == ss.service.Spec.Replicas {
+			if deploymentSpecValidate(ss.deployment.provision, ss.service) && deploymentReplicasValidate(ss, ss.deployment.provision) {
 				ss.service.Status.State = ss.deployment.provision.Status.State
 				ss.service.Status.Message = ss.deployment.provision.Status.Message
 			}
 		}
 
+		serviceRolloutStatus(ss)
 		return nil
 	}
 
@@ -428,5 +510,22 @@ func serviceStatusState(ss *ServiceState) (err error) {
 		}
 	}
 
+	serviceRolloutStatus(ss)
 	return nil
 }
+
+// serviceRolloutStatus marks service with error if rolling update of current spec failed
+func serviceRolloutStatus(ss *ServiceState) {
+
+	if ss.deployment.provision != nil {
+		return
+	}
+
+	for _, d := range ss.deployment.list {
+		if deploymentRolloutFailed(d) && deploymentSpecValidate(d, ss.service) {
+			ss.service.Status.State = types.StateError
+			ss.service.Status.Message = d.Status.Message
+			return
+		}
+	}
+}
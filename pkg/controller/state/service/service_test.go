@@ -24,6 +24,7 @@ import (
 
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
 	"github.com/lastbackend/lastbackend/pkg/controller/ipam"
+	"github.com/lastbackend/lastbackend/pkg/controller/scheduler"
 	"github.com/lastbackend/lastbackend/pkg/controller/state/cluster"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/storage"
@@ -36,8 +37,11 @@ func init() {
 	stg, _ := storage.Get("mock")
 	envs.Get().SetStorage(stg)
 
-	ipm, _ := ipam.New("")
+	ipm, _ := ipam.New(nil)
 	envs.Get().SetIPAM(ipm)
+
+	sch, _ := scheduler.New("")
+	envs.Get().SetScheduler(sch)
 }
 
 func testServiceObserver(t *testing.T, name, werr string, wst *ServiceState, state *ServiceState, svc *types.Service) {
@@ -1253,6 +1257,7 @@ func getPodAsset(d *types.Deployment, state, message string) *types.Pod {
 
 	if state == types.StateReady {
 		p.Status.Running = true
+		p.Status.Ready = true
 	}
 
 	p.Spec.State = d.Spec.State
@@ -1267,6 +1272,7 @@ func getServiceStateAsset(svc *types.Service) *ServiceState {
 
 	n.Meta.Name = "node"
 	n.Meta.Hostname = "node.local"
+	n.Status.Online = true
 	n.Status.Capacity = types.NodeResources{
 		Containers: 10,
 		Pods:       10,
@@ -0,0 +1,445 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/controller/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+)
+
+const logStatefulPrefix = "state:observer:stateful"
+
+// serviceStateful checks that service pods have stable ordinal names and dedicated volumes
+func serviceStateful(ss *ServiceState) bool {
+	return ss.service != nil && ss.service.Spec.Stateful()
+}
+
+// statefulVolumeObserve continues pods provision when volume claimed by service pod is provisioned
+func statefulVolumeObserve(ss *ServiceState, v *types.Volume) error {
+
+	if !serviceStateful(ss) || v.Meta.Namespace != ss.service.Meta.Namespace || !statefulClaimVolume(ss.service, v.Meta.Name) {
+		return nil
+	}
+
+	log.V(logLevel).Debugf("%s:> volume changed: %s > %s", logStatefulPrefix, v.SelfLink(), v.Status.State)
+
+	return statefulProvision(ss)
+}
+
+// statefulProvision moves ordered pods provision and shutdown one step forward
+// for every deployment of stateful service
+func statefulProvision(ss *ServiceState) error {
+
+	for _, d := range ss.deployment.list {
+		if d.Status.State == types.StateDestroy {
+			if err := deploymentDestroy(ss, d); err != nil {
+				return err
+			}
+		}
+	}
+
+	return deploymentsPodProvision(ss)
+}
+
+// statefulPodProvision - keeps deployment pods named by ordinal from 0 to replicas.
+// Pod is created only after pods with lower ordinals are available, pods above replicas
+// are removed from the highest ordinal one at a time. Ordinal pod is pinned to node
+// of its claimed volumes, volumes are created on first pod provision and kept after pod removal.
+// Active deployment does not create pods during rolling update
+func statefulPodProvision(ss *ServiceState, d *types.Deployment) (err error) {
+
+	t := d.Meta.Updated
+
+	var (
+		provision bool
+		svc       = ss.service
+		live      = statefulPods(ss)
+	)
+
+	defer func() {
+		if err == nil {
+			err = deploymentUpdate(d, t)
+		}
+	}()
+
+	pods, ok := ss.pod.list[d.SelfLink()]
+	if !ok {
+		pods = make(map[string]*types.Pod)
+		ss.pod.list[d.SelfLink()] = pods
+	}
+
+	defer func() {
+		if provision && d.Status.State != types.StateProvision {
+			d.Status.State = types.StateProvision
+			d.Meta.Updated = time.Now()
+		}
+	}()
+
+	excess := make([]*types.Pod, 0)
+	for _, p := range podsLive(pods) {
+		if ordinal, ok := svc.PodOrdinal(p.Meta.Name); !ok || ordinal >= d.Spec.Replicas {
+			excess = append(excess, p)
+		}
+	}
+
+	if len(excess) > 0 {
+
+		if statefulTerminating(ss) {
+			return nil
+		}
+
+		sort.Slice(excess, func(i, j int) bool {
+			return statefulOrdinal(svc, excess[i]) > statefulOrdinal(svc, excess[j])
+		})
+
+		log.V(logLevel).Debugf("%s:> remove pod %s", logStatefulPrefix, excess[0].SelfLink())
+		if err = podDestroy(ss, excess[0]); err != nil {
+			log.Errorf("%s", err.Error())
+			return err
+		}
+
+		provision = true
+		return nil
+	}
+
+	if deploymentRolling(ss) && ss.deployment.provision.SelfLink() != d.SelfLink() {
+		return nil
+	}
+
+	for i := 0; i < d.Spec.Replicas; i++ {
+
+		if p, ok := live[i]; ok {
+			// next ordinal is created after previous pods are available
+			if !podAvailable(p) {
+				return nil
+			}
+			continue
+		}
+
+		// pod name and volumes are reused after previous pod is removed
+		if statefulOrdinalTerminating(ss, i) {
+			return nil
+		}
+
+		node, ready, err := statefulVolumesProvision(ss, d, i)
+		if err != nil {
+			log.Errorf("%s:> volumes provision err: %s", logStatefulPrefix, err.Error())
+			return err
+		}
+
+		if !ready {
+			log.V(logLevel).Debugf("%s:> wait volumes of pod %s", logStatefulPrefix, svc.PodName(i))
+			provision = true
+			return nil
+		}
+
+		log.V(logLevel).Debugf("%s:> create pod %s on node %s", logStatefulPrefix, svc.PodName(i), node)
+		p, err := statefulPodCreate(svc, d, i, node)
+		if err != nil {
+			log.Errorf("%s", err.Error())
+			return err
+		}
+
+		pods[p.SelfLink()] = p
+		provision = true
+		return nil
+	}
+
+	return nil
+}
+
+// statefulPodsDestroy removes destroyed pods and destroys pod with the highest ordinal
+// after previous destroyed pods are removed
+func statefulPodsDestroy(ss *ServiceState, pl map[string]*types.Pod) error {
+
+	live := podsLive(pl)
+
+	for _, p := range pl {
+		if p.Status.State == types.StateDestroyed {
+			if err := podRemove(ss, p); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(live) == 0 || len(live) < len(pl) {
+		return nil
+	}
+
+	sort.Slice(live, func(i, j int) bool {
+		return statefulOrdinal(ss.service, live[i]) > statefulOrdinal(ss.service, live[j])
+	})
+
+	log.V(logLevel).Debugf("%s:> stop pod %s", logStatefulPrefix, live[0].SelfLink())
+
+	return podDestroy(ss, live[0])
+}
+
+// statefulVolumesProvision creates ordinal volumes for service volume claims and returns node
+// they are placed on. Claims are created one by one, next claim is pinned to node of previous one,
+// ready is false until all claimed volumes are provisioned
+func statefulVolumesProvision(ss *ServiceState, d *types.Deployment, ordinal int) (string, bool, error) {
+
+	var (
+		node string
+		svc  = ss.service
+		vm   = distribution.NewVolumeModel(context.Background(), envs.Get().GetStorage())
+	)
+
+	// pod without volumes is placed by scheduler
+	if len(svc.Spec.VolumeClaims) == 0 {
+		return d.Spec.Selector.Node, true, nil
+	}
+
+	for _, c := range svc.Spec.VolumeClaims {
+
+		name := svc.ClaimVolumeName(c.Name, ordinal)
+
+		v, err := vm.Get(svc.Meta.Namespace, name)
+		if err != nil {
+			return types.EmptyString, false, err
+		}
+
+		if v == nil {
+			return types.EmptyString, false, statefulVolumeCreate(ss, d, c, name, node)
+		}
+
+		if v.Status.State != types.StateReady || v.Meta.Node == types.EmptyString {
+			return types.EmptyString, false, nil
+		}
+
+		if node != types.EmptyString && node != v.Meta.Node {
+			log.Warnf("%s:> volume %s is placed on node %s, pod %s volumes are placed on node %s",
+				logStatefulPrefix, v.SelfLink(), v.Meta.Node, svc.PodName(ordinal), node)
+			return types.EmptyString, false, nil
+		}
+
+		node = v.Meta.Node
+	}
+
+	return node, true, nil
+}
+
+// statefulVolumeCreate creates claim volume placed by deployment selector or pinned to node
+func statefulVolumeCreate(ss *ServiceState, d *types.Deployment, claim types.ServiceVolumeClaim, name, node string) error {
+
+	var (
+		stg = envs.Get().GetStorage()
+		nm  = distribution.NewNamespaceModel(context.Background(), stg)
+		vm  = distribution.NewVolumeModel(context.Background(), stg)
+	)
+
+	ns, err := nm.Get(ss.service.Meta.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if ns == nil {
+		return nil
+	}
+
+	v := new(types.Volume)
+	v.Meta.Name = name
+	v.Spec.Type = types.KindVolumeHostDir
+	v.Spec.Capacity = claim.Capacity
+	v.Spec.Selector.Labels = d.Spec.Selector.Labels
+	v.Spec.Selector.Node = d.Spec.Selector.Node
+	if node != types.EmptyString {
+		v.Spec.Selector.Node = node
+	}
+	v.Spec.Updated = time.Now()
+
+	used, err := nm.Usage(ns)
+	if err != nil {
+		return err
+	}
+
+	if err := ns.Spec.Quotas.Admit(*used, types.NamespaceResources{}, v.QuotaUsage()); err != nil {
+		log.Warnf("%s:> volume %s is not created: %s", logStatefulPrefix, name, err.Error())
+		d.Status.Message = err.Error()
+		d.Meta.Updated = time.Now()
+		return nil
+	}
+
+	log.V(logLevel).Debugf("%s:> create volume %s:%s", logStatefulPrefix, ns.Meta.Name, name)
+
+	if _, err := vm.Create(ns, v); err != nil {
+		return err
+	}
+
+	if err := nm.UpdateResources(ns); err != nil {
+		log.Errorf("%s:> update namespace resources err: %s", logStatefulPrefix, err.Error())
+	}
+
+	return nil
+}
+
+// statefulPodCreate creates ordinal pod pinned to node, persistent volumes referred
+// by claim name are replaced with ordinal claim volumes
+func statefulPodCreate(svc *types.Service, d *types.Deployment, ordinal int, node string) (*types.Pod, error) {
+
+	dp := *d
+	dp.Spec.Selector.Node = node
+	dp.Spec.Template.Volumes = make(types.SpecTemplateVolumeList, 0)
+
+	for _, v := range d.Spec.Template.Volumes {
+		vc := *v
+		if svc.Spec.VolumeClaim(vc.Volume.Name) {
+			vc.Volume.Name = svc.ClaimVolumeName(vc.Volume.Name, ordinal)
+		}
+		dp.Spec.Template.Volumes = append(dp.Spec.Template.Volumes, &vc)
+	}
+
+	pm := distribution.NewPodModel(context.Background(), envs.Get().GetStorage())
+	return pm.CreateNamed(&dp, svc.PodName(ordinal))
+}
+
+// statefulRollout - moves stateful service rolling update one ordinal at a time from the highest:
+// next active pod is destroyed when provision pods are available and replaced ordinals are
+// taken by provision pods, provision pod is created with released name and volumes by deployment provision
+func statefulRollout(ss *ServiceState) error {
+
+	var (
+		active    = ss.deployment.active
+		provision = ss.deployment.provision
+		desired   = ss.service.Spec.Replicas
+	)
+
+	for _, d := range []*types.Deployment{active, provision} {
+		if d.Spec.Replicas != desired {
+			log.V(logLevel).Debugf("%s:> rollout: scale %s: %d -> %d", logStatefulPrefix, d.SelfLink(), d.Spec.Replicas, desired)
+			if err := deploymentScale(d, desired); err != nil {
+				return err
+			}
+		}
+	}
+
+	if statefulRolloutDone(ss) {
+		return nil
+	}
+
+	if deploymentRolloutExpired(ss) {
+		return deploymentRolloutFail(ss, types.DeploymentRolloutDeadlineExceeded)
+	}
+
+	var (
+		current = podsLive(ss.pod.list[active.SelfLink()])
+		updated = podsLive(ss.pod.list[provision.SelfLink()])
+	)
+
+	for _, p := range updated {
+		if !podAvailable(p) {
+			return nil
+		}
+	}
+
+	if len(current) == 0 || len(current)+len(updated) < desired || statefulTerminating(ss) {
+		return nil
+	}
+
+	sort.Slice(current, func(i, j int) bool {
+		return statefulOrdinal(ss.service, current[i]) > statefulOrdinal(ss.service, current[j])
+	})
+
+	log.V(logLevel).Debugf("%s:> rollout: replace pod %s", logStatefulPrefix, current[0].SelfLink())
+
+	return podDestroy(ss, current[0])
+}
+
+// statefulRolloutDone checks that active deployment pods are removed
+// and provision deployment has all replicas available
+func statefulRolloutDone(ss *ServiceState) bool {
+
+	if len(ss.pod.list[ss.deployment.active.SelfLink()]) > 0 {
+		return false
+	}
+
+	d := ss.deployment.provision
+	return deploymentPodsAvailable(ss, d) >= d.Spec.Replicas
+}
+
+// statefulPods returns live service pods of all deployments by ordinal
+func statefulPods(ss *ServiceState) map[int]*types.Pod {
+
+	var pods = make(map[int]*types.Pod)
+
+	for _, pl := range ss.pod.list {
+		for _, p := range podsLive(pl) {
+			if ordinal, ok := ss.service.PodOrdinal(p.Meta.Name); ok {
+				pods[ordinal] = p
+			}
+		}
+	}
+
+	return pods
+}
+
+// statefulTerminating checks that any service pod is destroyed but not removed yet
+func statefulTerminating(ss *ServiceState) bool {
+	for _, pl := range ss.pod.list {
+		if len(podsLive(pl)) != len(pl) {
+			return true
+		}
+	}
+	return false
+}
+
+// statefulOrdinalTerminating checks that pod with ordinal name is destroyed but not removed yet
+func statefulOrdinalTerminating(ss *ServiceState, ordinal int) bool {
+
+	var name = ss.service.PodName(ordinal)
+
+	for _, pl := range ss.pod.list {
+		for _, p := range pl {
+			if p.Meta.Name == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// statefulOrdinal returns pod ordinal, pods with names out of ordinal format are the last ones
+func statefulOrdinal(svc *types.Service, p *types.Pod) int {
+	if ordinal, ok := svc.PodOrdinal(p.Meta.Name); ok {
+		return ordinal
+	}
+	return int(^uint(0) >> 1)
+}
+
+// statefulClaimVolume checks that volume is created by service volume claim for pod ordinal
+func statefulClaimVolume(svc *types.Service, name string) bool {
+	for _, c := range svc.Spec.VolumeClaims {
+		prefix := c.Name + "-"
+		if strings.HasPrefix(name, prefix) {
+			if _, ok := svc.PodOrdinal(strings.TrimPrefix(name, prefix)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,251 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package service
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatefulPodProvision(t *testing.T) {
+
+	type suit struct {
+		name string
+		args struct {
+			replicas int
+			pods     map[int]string
+			busy     []int
+		}
+		want struct {
+			pods []string
+		}
+	}
+
+	var tests = []suit{
+		func() suit {
+			s := suit{name: "first ordinal pod created"}
+			s.args.replicas = 3
+			s.want.pods = []string{"service-0"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "next ordinal waits for previous pod"}
+			s.args.replicas = 3
+			s.args.pods = map[int]string{0: types.StateProvision}
+			s.want.pods = []string{"service-0"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "next ordinal created after previous pod is available"}
+			s.args.replicas = 3
+			s.args.pods = map[int]string{0: types.StateReady}
+			s.want.pods = []string{"service-0", "service-1"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "missed ordinal created again"}
+			s.args.replicas = 3
+			s.args.pods = map[int]string{0: types.StateReady, 2: types.StateReady}
+			s.want.pods = []string{"service-0", "service-1", "service-2"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "ordinal not created until previous pod is removed"}
+			s.args.replicas = 2
+			s.args.pods = map[int]string{0: types.StateReady}
+			s.args.busy = []int{1}
+			s.want.pods = []string{"service-0"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "scale down removes pod with the highest ordinal"}
+			s.args.replicas = 1
+			s.args.pods = map[int]string{0: types.StateReady, 1: types.StateReady, 2: types.StateReady}
+			s.want.pods = []string{"service-0", "service-1"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "scale down waits for previous pod removal"}
+			s.args.replicas = 1
+			s.args.pods = map[int]string{0: types.StateReady, 1: types.StateReady}
+			s.args.busy = []int{2}
+			s.want.pods = []string{"service-0", "service-1"}
+			return s
+		}(),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			svc := getServiceAsset(types.StateReady, types.EmptyString)
+			svc.Spec.Mode = types.ServiceModeStateful
+			svc.Spec.Replicas = tt.args.replicas
+
+			d := getDeploymentAsset(svc, types.StateReady, types.EmptyString)
+			d.Spec.Replicas = tt.args.replicas
+
+			ss := getServiceStateAsset(svc)
+			ss.deployment.active = d
+			ss.deployment.list[d.SelfLink()] = d
+			ss.pod.list[d.SelfLink()] = make(map[string]*types.Pod)
+
+			for ordinal, state := range tt.args.pods {
+				p := getStatefulPodAsset(svc, d, ordinal, state)
+				ss.pod.list[d.SelfLink()][p.SelfLink()] = p
+			}
+
+			if len(tt.args.busy) > 0 {
+				od := getDeploymentAsset(svc, types.StateDestroy, types.EmptyString)
+				ss.pod.list[od.SelfLink()] = make(map[string]*types.Pod)
+				for _, ordinal := range tt.args.busy {
+					p := getStatefulPodAsset(svc, od, ordinal, types.StateDestroy)
+					p.Spec.State.Destroy = true
+					ss.pod.list[od.SelfLink()][p.SelfLink()] = p
+				}
+			}
+
+			if !assert.NoError(t, deploymentPodProvision(ss, d)) {
+				return
+			}
+
+			assert.Equal(t, tt.want.pods, getStatefulPodsNames(ss.pod.list[d.SelfLink()]), "pods names")
+		})
+	}
+}
+
+func TestStatefulRollout(t *testing.T) {
+
+	type suit struct {
+		name string
+		args struct {
+			active    map[int]string
+			provision map[int]string
+		}
+		want struct {
+			active []string
+			done   bool
+		}
+	}
+
+	var tests = []suit{
+		func() suit {
+			s := suit{name: "rollout replaces pod with the highest ordinal"}
+			s.args.active = map[int]string{0: types.StateReady, 1: types.StateReady}
+			s.want.active = []string{"service-0"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "rollout moves to next ordinal after provision pod is available"}
+			s.args.active = map[int]string{0: types.StateReady}
+			s.args.provision = map[int]string{1: types.StateReady}
+			s.want.active = []string{}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "rollout waits for provision pod"}
+			s.args.active = map[int]string{0: types.StateReady}
+			s.args.provision = map[int]string{1: types.StateProvision}
+			s.want.active = []string{"service-0"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "rollout waits for previous pod removal"}
+			s.args.active = map[int]string{0: types.StateReady, 1: types.StateDestroy}
+			s.want.active = []string{"service-0"}
+			return s
+		}(),
+		func() suit {
+			s := suit{name: "rollout done"}
+			s.args.provision = map[int]string{0: types.StateReady, 1: types.StateReady}
+			s.want.active = []string{}
+			s.want.done = true
+			return s
+		}(),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			svc := getServiceAsset(types.StateProvision, types.EmptyString)
+			svc.Spec.Mode = types.ServiceModeStateful
+			svc.Spec.Replicas = 2
+
+			active := getDeploymentAsset(svc, types.StateReady, types.EmptyString)
+			active.Spec.Replicas = 2
+			active.Spec.Template.Updated = svc.Spec.Template.Updated.Add(-time.Minute)
+
+			provision := getDeploymentAsset(svc, types.StateProvision, types.EmptyString)
+			provision.Spec.Replicas = 2
+			provision.Meta.Created = time.Now()
+
+			ss := getServiceStateAsset(svc)
+			ss.deployment.active = active
+			ss.deployment.provision = provision
+			ss.deployment.list[active.SelfLink()] = active
+			ss.deployment.list[provision.SelfLink()] = provision
+			ss.pod.list[active.SelfLink()] = make(map[string]*types.Pod)
+			ss.pod.list[provision.SelfLink()] = make(map[string]*types.Pod)
+
+			for ordinal, state := range tt.args.active {
+				p := getStatefulPodAsset(svc, active, ordinal, state)
+				if state == types.StateDestroy {
+					p.Spec.State.Destroy = true
+				}
+				ss.pod.list[active.SelfLink()][p.SelfLink()] = p
+			}
+
+			for ordinal, state := range tt.args.provision {
+				p := getStatefulPodAsset(svc, provision, ordinal, state)
+				ss.pod.list[provision.SelfLink()][p.SelfLink()] = p
+			}
+
+			assert.True(t, deploymentRolling(ss), "rolling update")
+
+			if !assert.NoError(t, deploymentRollout(ss)) {
+				return
+			}
+
+			assert.Equal(t, tt.want.active, getStatefulPodsNames(ss.pod.list[active.SelfLink()]), "active pods names")
+			assert.Equal(t, tt.want.done, deploymentRolloutDone(ss), "rollout done")
+		})
+	}
+}
+
+func getStatefulPodAsset(svc *types.Service, d *types.Deployment, ordinal int, state string) *types.Pod {
+
+	p := getPodAsset(d, state, types.EmptyString)
+	p.Meta.Name = svc.PodName(ordinal)
+
+	return p
+}
+
+func getStatefulPodsNames(pods map[string]*types.Pod) []string {
+
+	names := make([]string, 0)
+	for _, p := range podsLive(pods) {
+		names = append(names, p.Meta.Name)
+	}
+	sort.Strings(names)
+
+	return names
+}
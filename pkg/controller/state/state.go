@@ -20,24 +20,55 @@ package state
 
 import (
 	"context"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/lastbackend/lastbackend/pkg/controller/envs"
 	"github.com/lastbackend/lastbackend/pkg/controller/state/cluster"
+	"github.com/lastbackend/lastbackend/pkg/controller/state/cronjob"
+	"github.com/lastbackend/lastbackend/pkg/controller/state/job"
 	"github.com/lastbackend/lastbackend/pkg/controller/state/service"
 	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
 )
 
-const logLevel = 3
+const (
+	logLevel = 3
+
+	// ipamReconcileInterval - interval between IPAM leases and addresses in use reconciliation
+	ipamReconcileInterval = time.Minute
+
+	// autoscaleInterval - interval between services replicas evaluations by autoscalers
+	autoscaleInterval = 15 * time.Second
+
+	// jobSyncInterval - interval between jobs active deadline and retries backoff checks
+	jobSyncInterval = 5 * time.Second
+
+	// cronJobSyncInterval - interval between cron jobs schedules checks
+	cronJobSyncInterval = 10 * time.Second
+)
 
 type State struct {
 	Cluster *cluster.ClusterState
 	Service map[string]*service.ServiceState
+	Job     map[string]*job.JobState
+	CronJob map[string]*cronjob.CronJobState
+
+	// lock guards states maps shared by watchers and periodic checks,
+	// states are called out of lock as their setters wait for own observers
+	lock sync.RWMutex
+
+	// ctx is canceled when controller loses lead, so watchers and schedules are stopped
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 func (s *State) Loop() {
 
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
 	log.Info("start cluster restore")
 	s.Cluster.Loop()
 	log.Info("finish cluster restore\n\n")
@@ -48,6 +79,9 @@ func (s *State) Loop() {
 	vm := distribution.NewVolumeModel(context.Background(), envs.Get().GetStorage())
 	dm := distribution.NewDeploymentModel(context.Background(), envs.Get().GetStorage())
 	pm := distribution.NewPodModel(context.Background(), envs.Get().GetStorage())
+	am := distribution.NewAutoscalerModel(context.Background(), envs.Get().GetStorage())
+	jm := distribution.NewJobModel(context.Background(), envs.Get().GetStorage())
+	cm := distribution.NewCronJobModel(context.Background(), envs.Get().GetStorage())
 
 	dr, err := dm.Runtime()
 	if err != nil {
@@ -73,6 +107,24 @@ func (s *State) Loop() {
 		return
 	}
 
+	ar, err := am.Runtime()
+	if err != nil {
+		log.Errorf("%s", err.Error())
+		return
+	}
+
+	jr, err := jm.Runtime()
+	if err != nil {
+		log.Errorf("%s", err.Error())
+		return
+	}
+
+	cr, err := cm.Runtime()
+	if err != nil {
+		log.Errorf("%s", err.Error())
+		return
+	}
+
 	ns, err := nm.List()
 	if err != nil {
 		log.Errorf("%s", err.Error())
@@ -97,6 +149,48 @@ func (s *State) Loop() {
 			s.Service[svc.SelfLink()].Restore()
 		}
 
+		jl, err := jm.ListByNamespace(n.SelfLink())
+		if err != nil {
+			log.Errorf("%s", err.Error())
+			return
+		}
+
+		for _, j := range jl.Items {
+
+			log.V(logLevel).Debugf("restore job state: %s \n", j.SelfLink())
+			if _, ok := s.Job[j.SelfLink()]; !ok {
+				s.Job[j.SelfLink()] = job.NewJobState(s.Cluster, j)
+			}
+
+			s.Job[j.SelfLink()].Restore()
+		}
+
+		cl, err := cm.ListByNamespace(n.SelfLink())
+		if err != nil {
+			log.Errorf("%s", err.Error())
+			return
+		}
+
+		for _, c := range cl.Items {
+
+			log.V(logLevel).Debugf("restore cron job state: %s \n", c.SelfLink())
+			if _, ok := s.CronJob[c.SelfLink()]; !ok {
+				s.CronJob[c.SelfLink()] = cronjob.NewCronJobState(c)
+			}
+
+			s.CronJob[c.SelfLink()].Restore()
+		}
+
+		pl, err := pm.ListByNamespace(n.SelfLink())
+		if err != nil {
+			log.Errorf("%s", err.Error())
+			return
+		}
+
+		for _, p := range pl.Items {
+			s.Cluster.SetPod(p)
+		}
+
 		vl, err := vm.ListByNamespace(n.SelfLink())
 		if err != nil {
 			log.Errorf("%s", err.Error())
@@ -111,10 +205,19 @@ func (s *State) Loop() {
 
 	}
 
-	go s.watchPods(context.Background(), &pr.System.Revision)
-	go s.watchDeployments(context.Background(), &dr.System.Revision)
-	go s.watchServices(context.Background(), &sr.System.Revision)
-	go s.watchVolumes(context.Background(), &vr.System.Revision)
+	go s.watchPods(s.ctx, &pr.System.Revision)
+	go s.watchDeployments(s.ctx, &dr.System.Revision)
+	go s.watchServices(s.ctx, &sr.System.Revision)
+	go s.watchVolumes(s.ctx, &vr.System.Revision)
+	go s.watchAutoscalers(s.ctx, &ar.System.Revision)
+	go s.autoscale(s.ctx)
+	go s.watchJobs(s.ctx, &jr.System.Revision)
+	go s.syncJobs(s.ctx)
+	go s.watchCronJobs(s.ctx, &cr.System.Revision)
+	go s.syncCronJobs(s.ctx)
+	go s.watchEvictions(s.ctx)
+	go s.watchNodes(s.ctx)
+	go s.reconcileIPAM(s.ctx)
 
 	log.Info("finish services restore\n\n")
 }
@@ -139,19 +242,24 @@ func (s *State) watchServices(ctx context.Context, rev *int64) {
 				}
 
 				if w.IsActionRemove() {
-					_, ok := s.Service[w.Data.SelfLink()]
-					if ok {
+					s.lock.Lock()
+					if ss, ok := s.Service[w.Data.SelfLink()]; ok {
+						ss.Stop()
 						delete(s.Service, w.Data.SelfLink())
 					}
+					s.lock.Unlock()
 					continue
 				}
 
-				_, ok := s.Service[w.Data.SelfLink()]
+				s.lock.Lock()
+				ss, ok := s.Service[w.Data.SelfLink()]
 				if !ok {
-					s.Service[w.Data.SelfLink()] = service.NewServiceState(s.Cluster, w.Data)
+					ss = service.NewServiceState(s.Cluster, w.Data)
+					s.Service[w.Data.SelfLink()] = ss
 				}
+				s.lock.Unlock()
 
-				s.Service[w.Data.SelfLink()].SetService(w.Data)
+				ss.SetService(w.Data)
 			}
 		}
 	}()
@@ -159,6 +267,38 @@ func (s *State) watchServices(ctx context.Context, rev *int64) {
 	sm.Watch(svc, rev)
 }
 
+// serviceState returns service state by service self link
+func (s *State) serviceState(link string) (*service.ServiceState, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	ss, ok := s.Service[link]
+	return ss, ok
+}
+
+// serviceStates returns services states snapshot to be iterated out of lock
+func (s *State) serviceStates() []*service.ServiceState {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	items := make([]*service.ServiceState, 0, len(s.Service))
+	for _, ss := range s.Service {
+		items = append(items, ss)
+	}
+	return items
+}
+
+// namespaceServiceStates returns snapshot of services states in namespace to be iterated out of lock
+func (s *State) namespaceServiceStates(namespace string) []*service.ServiceState {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	items := make([]*service.ServiceState, 0)
+	for link, ss := range s.Service {
+		if strings.HasPrefix(link, namespace+":") {
+			items = append(items, ss)
+		}
+	}
+	return items
+}
+
 func (s *State) watchDeployments(ctx context.Context, rev *int64) {
 
 	// Watch pods change
@@ -179,20 +319,20 @@ func (s *State) watchDeployments(ctx context.Context, rev *int64) {
 					continue
 				}
 
+				ss, ok := s.serviceState(w.Data.ServiceLink())
+
 				if w.IsActionRemove() {
-					_, ok := s.Service[w.Data.ServiceLink()]
 					if ok {
-						s.Service[w.Data.ServiceLink()].DelDeployment(w.Data)
+						ss.DelDeployment(w.Data)
 					}
 					continue
 				}
 
-				_, ok := s.Service[w.Data.ServiceLink()]
 				if !ok {
 					break
 				}
 
-				s.Service[w.Data.ServiceLink()].SetDeployment(w.Data)
+				ss.SetDeployment(w.Data)
 			}
 		}
 	}()
@@ -220,20 +360,28 @@ func (s *State) watchPods(ctx context.Context, rev *int64) {
 					continue
 				}
 
+				if w.Data.Meta.Job != types.EmptyString {
+					s.observeJobPod(w)
+					continue
+				}
+
+				ss, ok := s.serviceState(w.Data.ServiceLink())
+
 				if w.IsActionRemove() {
-					_, ok := s.Service[w.Data.ServiceLink()]
+					s.Cluster.DelPod(w.Data)
 					if ok {
-						s.Service[w.Data.ServiceLink()].DelPod(w.Data)
+						ss.DelPod(w.Data)
 					}
 					continue
 				}
 
-				_, ok := s.Service[w.Data.ServiceLink()]
+				s.Cluster.SetPod(w.Data)
+
 				if !ok {
 					break
 				}
 
-				s.Service[w.Data.ServiceLink()].SetPod(w.Data)
+				ss.SetPod(w.Data)
 			}
 		}
 	}()
@@ -265,6 +413,11 @@ func (s *State) watchVolumes(ctx context.Context, rev *int64) {
 				}
 
 				s.Cluster.SetVolume(w.Data)
+
+				// stateful service pods wait for claimed volumes provision
+				for _, ss := range s.namespaceServiceStates(w.Data.Meta.Namespace) {
+					ss.SetVolume(w.Data)
+				}
 			}
 		}
 	}()
@@ -272,9 +425,413 @@ func (s *State) watchVolumes(ctx context.Context, rev *int64) {
 	sm.Watch(vl, rev)
 }
 
+func (s *State) watchAutoscalers(ctx context.Context, rev *int64) {
+
+	var (
+		a = make(chan types.AutoscalerEvent)
+	)
+
+	am := distribution.NewAutoscalerModel(ctx, envs.Get().GetStorage())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case w := <-a:
+
+				if w.Data == nil {
+					continue
+				}
+
+				ss, ok := s.serviceState(w.Data.ServiceLink())
+				if !ok {
+					continue
+				}
+
+				if w.IsActionRemove() {
+					ss.DelAutoscaler(w.Data)
+					continue
+				}
+
+				ss.SetAutoscaler(w.Data)
+			}
+		}
+	}()
+
+	am.Watch(a, rev)
+}
+
+func (s *State) autoscale(ctx context.Context) {
+
+	// Evaluate services replicas by autoscalers with recent pods usage
+	ticker := time.NewTicker(autoscaleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-ticker.C:
+			for _, ss := range s.serviceStates() {
+				ss.Autoscale(t)
+			}
+		}
+	}
+}
+
+func (s *State) watchJobs(ctx context.Context, rev *int64) {
+
+	var (
+		j = make(chan types.JobEvent)
+	)
+
+	jm := distribution.NewJobModel(ctx, envs.Get().GetStorage())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case w := <-j:
+
+				if w.Data == nil {
+					continue
+				}
+
+				if w.Data.Meta.CronJob != types.EmptyString {
+					s.observeCronJobJob(w)
+				}
+
+				if w.IsActionRemove() {
+					s.lock.Lock()
+					if js, ok := s.Job[w.Data.SelfLink()]; ok {
+						js.Stop()
+						delete(s.Job, w.Data.SelfLink())
+					}
+					s.lock.Unlock()
+					continue
+				}
+
+				s.lock.Lock()
+				js, ok := s.Job[w.Data.SelfLink()]
+				if !ok {
+					js = job.NewJobState(s.Cluster, w.Data)
+					s.Job[w.Data.SelfLink()] = js
+				}
+				s.lock.Unlock()
+
+				js.SetJob(w.Data)
+			}
+		}
+	}()
+
+	jm.Watch(j, rev)
+}
+
+// jobState returns job state by job self link
+func (s *State) jobState(link string) (*job.JobState, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	js, ok := s.Job[link]
+	return js, ok
+}
+
+// jobStates returns jobs states snapshot to be iterated out of lock
+func (s *State) jobStates() []*job.JobState {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	items := make([]*job.JobState, 0, len(s.Job))
+	for _, js := range s.Job {
+		items = append(items, js)
+	}
+	return items
+}
+
+// observeJobPod passes job pod changes to job state
+func (s *State) observeJobPod(w types.PodEvent) {
+
+	js, ok := s.jobState(w.Data.JobLink())
+
+	if w.IsActionRemove() {
+		s.Cluster.DelPod(w.Data)
+		if ok {
+			js.DelPod(w.Data)
+		}
+		return
+	}
+
+	s.Cluster.SetPod(w.Data)
+
+	if ok {
+		js.SetPod(w.Data)
+	}
+}
+
+func (s *State) syncJobs(ctx context.Context) {
+
+	// Check jobs active deadlines and retry failed pods after backoff delay
+	ticker := time.NewTicker(jobSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-ticker.C:
+			for _, js := range s.jobStates() {
+				js.Sync(t)
+			}
+		}
+	}
+}
+
+func (s *State) watchCronJobs(ctx context.Context, rev *int64) {
+
+	var (
+		c = make(chan types.CronJobEvent)
+	)
+
+	cm := distribution.NewCronJobModel(ctx, envs.Get().GetStorage())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case w := <-c:
+
+				if w.Data == nil {
+					continue
+				}
+
+				if w.IsActionRemove() {
+					s.lock.Lock()
+					if cs, ok := s.CronJob[w.Data.SelfLink()]; ok {
+						cs.Stop()
+						delete(s.CronJob, w.Data.SelfLink())
+					}
+					s.lock.Unlock()
+					continue
+				}
+
+				s.lock.Lock()
+				cs, ok := s.CronJob[w.Data.SelfLink()]
+				if !ok {
+					cs = cronjob.NewCronJobState(w.Data)
+					s.CronJob[w.Data.SelfLink()] = cs
+				}
+				s.lock.Unlock()
+
+				cs.SetCronJob(w.Data)
+			}
+		}
+	}()
+
+	cm.Watch(c, rev)
+}
+
+// cronJobState returns cron job state by cron job self link
+func (s *State) cronJobState(link string) (*cronjob.CronJobState, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	cs, ok := s.CronJob[link]
+	return cs, ok
+}
+
+// cronJobStates returns cron jobs states snapshot to be iterated out of lock
+func (s *State) cronJobStates() []*cronjob.CronJobState {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	items := make([]*cronjob.CronJobState, 0, len(s.CronJob))
+	for _, cs := range s.CronJob {
+		items = append(items, cs)
+	}
+	return items
+}
+
+// observeCronJobJob passes changes of jobs created by schedule to cron job state
+func (s *State) observeCronJobJob(w types.JobEvent) {
+
+	cs, ok := s.cronJobState(w.Data.CronJobLink())
+	if !ok {
+		return
+	}
+
+	if w.IsActionRemove() {
+		cs.DelJob(w.Data)
+		return
+	}
+
+	cs.SetJob(w.Data)
+}
+
+func (s *State) syncCronJobs(ctx context.Context) {
+
+	// Check cron jobs schedules, controller state is running on lead controller only,
+	// so each schedule is started once
+	ticker := time.NewTicker(cronJobSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-ticker.C:
+			for _, cs := range s.cronJobStates() {
+				cs.Sync(t)
+			}
+		}
+	}
+}
+
+func (s *State) watchEvictions(ctx context.Context) {
+
+	// Watch pods evicted from tainted or lost nodes
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-s.Cluster.PodEvictions():
+
+			if e == nil || e.Pod == nil {
+				continue
+			}
+
+			if e.Pod.Meta.Job != types.EmptyString {
+				if js, ok := s.jobState(e.Pod.JobLink()); ok {
+					js.EvictPod(e)
+				}
+				continue
+			}
+
+			ss, ok := s.serviceState(e.Pod.ServiceLink())
+			if !ok {
+				continue
+			}
+
+			ss.EvictPod(e)
+		}
+	}
+}
+
+func (s *State) watchNodes(ctx context.Context) {
+
+	// Watch nodes joined, removed or changed to place pods of daemon services
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-s.Cluster.NodeChanges():
+
+			if n == nil {
+				continue
+			}
+
+			for _, ss := range s.serviceStates() {
+				ss.SetNode(n)
+			}
+		}
+	}
+}
+
+func (s *State) reconcileIPAM(ctx context.Context) {
+
+	// Release leaked addresses and reserve addresses used without lease
+	ticker := time.NewTicker(ipamReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+
+			owners, err := ipamOwners(ctx)
+			if err != nil {
+				log.Errorf("ipam reconcile: get addresses in use error: %s", err.Error())
+				continue
+			}
+
+			if err := envs.Get().GetIPAM().Reconcile(owners); err != nil {
+				log.Errorf("ipam reconcile error: %s", err.Error())
+			}
+		}
+	}
+}
+
+// ipamOwners returns addresses in use mapped to owner self link
+func ipamOwners(ctx context.Context) (map[string]string, error) {
+
+	var (
+		owners = make(map[string]string)
+		nm     = distribution.NewNamespaceModel(ctx, envs.Get().GetStorage())
+		sm     = distribution.NewServiceModel(ctx, envs.Get().GetStorage())
+		em     = distribution.NewEndpointModel(ctx, envs.Get().GetStorage())
+	)
+
+	ns, err := nm.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range ns.Items {
+
+		ss, err := sm.List(n.SelfLink())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, svc := range ss.Items {
+			if svc.Spec.Network.IP != types.EmptyString {
+				owners[svc.Spec.Network.IP] = svc.SelfLink()
+			}
+		}
+
+		el, err := em.ListByNamespace(n.SelfLink())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range el.Items {
+			if e.Spec.IP != types.EmptyString {
+				owners[e.Spec.IP] = e.SelfLink()
+			}
+		}
+	}
+
+	return owners, nil
+}
+
+// Stop stops state watchers, periodic checks and observers of cluster, services, jobs and cron jobs states
+func (s *State) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, ss := range s.Service {
+		ss.Stop()
+	}
+
+	for _, js := range s.Job {
+		js.Stop()
+	}
+
+	for _, cs := range s.CronJob {
+		cs.Stop()
+	}
+
+	s.Cluster.Stop()
+}
+
 func NewState() *State {
 	var state = new(State)
 	state.Cluster = cluster.NewClusterState()
 	state.Service = make(map[string]*service.ServiceState)
+	state.Job = make(map[string]*job.JobState)
+	state.CronJob = make(map[string]*cronjob.CronJobState)
 	return state
 }
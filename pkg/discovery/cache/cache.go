@@ -20,13 +20,10 @@ package cache
 
 import (
 	"github.com/lastbackend/lastbackend/pkg/log"
-	"github.com/spf13/viper"
-	"time"
 )
 
 const (
-	logLevel          = 7
-	defaultExpireTime = 24 // 24 hours
+	logLevel = 7
 )
 
 type Cache struct {
@@ -36,13 +33,8 @@ type Cache struct {
 func New() *Cache {
 	log.V(logLevel).Debug("Cache: initialization cache storage")
 
-	var duration = viper.GetDuration("discovery.cache.duration")
-	if duration == 0 {
-		duration = defaultExpireTime
-	}
-
 	return &Cache{
-		endpoints: NewEndpointCache(duration * time.Minute),
+		endpoints: NewEndpointCache(),
 	}
 }
 
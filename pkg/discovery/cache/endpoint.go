@@ -20,89 +20,149 @@ package cache
 
 import (
 	"sync"
-	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 )
 
+// Endpoint - endpoint data used to answer dns queries
+type Endpoint struct {
+	Namespace string
+	Service   string
+	Domain    string
+	IP        string
+	PortMap   map[uint16]string
+	Upstreams []string
+	// Pods addresses by pod name, set for stateful services
+	Hosts map[string]string
+}
+
+// EndpointCache holds in-memory view of cluster endpoints,
+// it is filled on restore and kept consistent by storage watch events
 type EndpointCache struct {
-	mutex sync.RWMutex
-	ttl   time.Duration
-	items map[string]*Item
+	mutex     sync.RWMutex
+	synced    bool
+	endpoints map[string]*types.Endpoint
+	upstreams map[string][]string
+	hosts     map[string]map[string]string
 }
 
-func (ec *EndpointCache) Set(key string, data []string) {
+// Restore replaces cache content with endpoints and endpoint manifests state
+func (ec *EndpointCache) Restore(endpoints []*types.Endpoint, manifests map[string]*types.EndpointManifest) {
 	ec.mutex.Lock()
 	defer ec.mutex.Unlock()
 
-	item := &Item{data: data}
-	item.setExpireTime(ec.ttl)
-	ec.items[key] = item
+	ec.endpoints = make(map[string]*types.Endpoint, len(endpoints))
+	ec.upstreams = make(map[string][]string, len(manifests))
+	ec.hosts = make(map[string]map[string]string, len(manifests))
+
+	for _, e := range endpoints {
+		ec.endpoints[e.SelfLink()] = e
+	}
+
+	for name, m := range manifests {
+		ec.upstreams[name] = m.Upstreams
+		ec.hosts[name] = m.Hosts
+	}
+
+	ec.synced = true
 }
 
-func (ec *EndpointCache) Get(key string) (data []string) {
+// Synced checks if cache was restored from storage
+func (ec *EndpointCache) Synced() bool {
+	ec.mutex.RLock()
+	defer ec.mutex.RUnlock()
+	return ec.synced
+}
+
+func (ec *EndpointCache) Set(e *types.Endpoint) {
 	ec.mutex.Lock()
 	defer ec.mutex.Unlock()
+	ec.endpoints[e.SelfLink()] = e
+}
 
-	item, ok := ec.items[key]
-	if !ok || item.expired() {
-		data = nil
-		return
-	}
+func (ec *EndpointCache) Del(selflink string) {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+	delete(ec.endpoints, selflink)
+}
 
-	item.setExpireTime(ec.ttl)
-	data = item.data
+// SetUpstreams stores endpoint upstreams from endpoint manifest
+func (ec *EndpointCache) SetUpstreams(selflink string, upstreams []string) {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+	ec.upstreams[selflink] = upstreams
+}
 
-	return
+func (ec *EndpointCache) DelUpstreams(selflink string) {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+	delete(ec.upstreams, selflink)
 }
 
-func (ec *EndpointCache) Del(key string) {
+// SetHosts stores endpoint pods addresses by pod name from endpoint manifest
+func (ec *EndpointCache) SetHosts(selflink string, hosts map[string]string) {
 	ec.mutex.Lock()
 	defer ec.mutex.Unlock()
-	delete(ec.items, key)
-	return
+	ec.hosts[selflink] = hosts
 }
 
-func (ec *EndpointCache) Clear() {
-	ec.items = make(map[string]*Item, 0)
+func (ec *EndpointCache) DelHosts(selflink string) {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
+	delete(ec.hosts, selflink)
 }
 
-func (ec *EndpointCache) Count() int {
+// Get returns endpoint by namespace and service name or nil if endpoint not exists
+func (ec *EndpointCache) Get(namespace, service string) *Endpoint {
 	ec.mutex.RLock()
 	defer ec.mutex.RUnlock()
-	return len(ec.items)
+
+	e, ok := ec.endpoints[new(types.Endpoint).CreateSelfLink(namespace, service)]
+	if !ok {
+		return nil
+	}
+
+	return ec.endpoint(e)
 }
 
-func (ec *EndpointCache) cleanup() {
-	ec.mutex.Lock()
-	defer ec.mutex.Unlock()
+// List returns all endpoints
+func (ec *EndpointCache) List() []*Endpoint {
+	ec.mutex.RLock()
+	defer ec.mutex.RUnlock()
 
-	for key, item := range ec.items {
-		if item.expired() {
-			delete(ec.items, key)
-		}
+	list := make([]*Endpoint, 0, len(ec.endpoints))
+	for _, e := range ec.endpoints {
+		list = append(list, ec.endpoint(e))
 	}
+
+	return list
 }
 
-func (ec *EndpointCache) cleanerTimer() {
-	duration := ec.ttl
-	if duration < time.Second {
-		duration = time.Second
-	}
-	ticker := time.Tick(duration)
-	go (func() {
-		for {
-			select {
-			case <-ticker:
-				ec.cleanup()
-			}
-		}
-	})()
+func (ec *EndpointCache) Count() int {
+	ec.mutex.RLock()
+	defer ec.mutex.RUnlock()
+	return len(ec.endpoints)
+}
+
+func (ec *EndpointCache) endpoint(e *types.Endpoint) *Endpoint {
+
+	item := new(Endpoint)
+	item.Namespace = e.Meta.Namespace
+	item.Service = e.Meta.Name
+	item.Domain = e.Spec.Domain
+	item.IP = e.Spec.IP
+	item.PortMap = e.Spec.PortMap
+	item.Upstreams = ec.upstreams[e.SelfLink()]
+	item.Hosts = ec.hosts[e.SelfLink()]
+
+	return item
 }
 
-func NewEndpointCache(duration time.Duration) *EndpointCache {
+func NewEndpointCache() *EndpointCache {
 	cache := &EndpointCache{
-		ttl:   duration,
-		items: make(map[string]*Item, 0),
+		endpoints: make(map[string]*types.Endpoint, 0),
+		upstreams: make(map[string][]string, 0),
+		hosts:     make(map[string]map[string]string, 0),
 	}
-	cache.cleanerTimer()
 	return cache
 }
@@ -0,0 +1,82 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointCache(t *testing.T) {
+
+	ec := NewEndpointCache()
+
+	assert.False(t, ec.Synced(), "cache synced before restore")
+
+	endpoint := func(name, ip string) *types.Endpoint {
+		e := new(types.Endpoint)
+		e.Meta.Name = name
+		e.Meta.Namespace = "demo"
+		e.Spec.IP = ip
+		return e
+	}
+
+	ec.Restore([]*types.Endpoint{endpoint("web", "10.0.0.1")}, map[string]*types.EndpointManifest{
+		"demo:web": {Upstreams: []string{"172.17.0.2"}},
+	})
+
+	assert.True(t, ec.Synced(), "cache not synced after restore")
+
+	e := ec.Get("demo", "web")
+	if assert.NotNil(t, e, "endpoint not found") {
+		assert.Equal(t, "10.0.0.1", e.IP, "ip not equal")
+		assert.Equal(t, []string{"172.17.0.2"}, e.Upstreams, "upstreams not equal")
+	}
+
+	// upstreams can be received before endpoint
+	ec.SetUpstreams("demo:api", []string{"172.17.0.3"})
+	assert.Nil(t, ec.Get("demo", "api"), "endpoint without spec found")
+
+	ec.Set(endpoint("api", "10.0.0.2"))
+	e = ec.Get("demo", "api")
+	if assert.NotNil(t, e, "endpoint not found") {
+		assert.Equal(t, []string{"172.17.0.3"}, e.Upstreams, "upstreams not equal")
+	}
+
+	ec.SetHosts("demo:api", map[string]string{"api-0": "172.17.0.3"})
+	assert.Equal(t, map[string]string{"api-0": "172.17.0.3"}, ec.Get("demo", "api").Hosts, "hosts not equal")
+
+	ec.DelHosts("demo:api")
+	assert.Empty(t, ec.Get("demo", "api").Hosts, "removed hosts found")
+
+	ec.Set(endpoint("api", "10.0.0.3"))
+	assert.Equal(t, "10.0.0.3", ec.Get("demo", "api").IP, "ip not updated")
+	assert.Len(t, ec.List(), 2, "endpoints count not equal")
+
+	ec.Del("demo:api")
+	ec.DelUpstreams("demo:web")
+	assert.Nil(t, ec.Get("demo", "api"), "removed endpoint found")
+	assert.Empty(t, ec.Get("demo", "web").Upstreams, "removed upstreams found")
+
+	// restore replaces cache content
+	ec.Restore(nil, nil)
+	assert.Equal(t, 0, ec.Count(), "cache not cleared on restore")
+}
@@ -26,13 +26,16 @@ import (
 	"syscall"
 
 	"github.com/lastbackend/lastbackend/pkg/api/client"
+	"github.com/lastbackend/lastbackend/pkg/api/client/identity"
 	"github.com/lastbackend/lastbackend/pkg/discovery/cache"
 	"github.com/lastbackend/lastbackend/pkg/discovery/controller"
 	"github.com/lastbackend/lastbackend/pkg/discovery/envs"
 	"github.com/lastbackend/lastbackend/pkg/discovery/runtime"
 	"github.com/lastbackend/lastbackend/pkg/discovery/state"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/storage"
+	"github.com/lastbackend/lastbackend/pkg/util/metrics"
 	"github.com/spf13/viper"
 )
 
@@ -83,6 +86,26 @@ func Daemon() bool {
 			endpoint = viper.GetString("api_uri")
 		}
 
+		// Agent client certificate is issued by cluster with one-time join token
+		if viper.IsSet("api.tls.dir") {
+			opts := identity.Options{
+				Endpoint: endpoint,
+				Kind:     types.AccountKindDiscovery,
+				Name:     st.Discovery().Info.Hostname,
+				Token:    viper.GetString("api.tls.join_token"),
+				Dir:      viper.GetString("api.tls.dir"),
+				CAFile:   viper.GetString("api.tls.ca"),
+			}
+
+			tls, err := identity.Bootstrap(context.Background(), opts)
+			if err != nil {
+				log.Errorf("Issue client certificate err: %s", err)
+			} else {
+				cfg.TLS = tls
+				go identity.Rotate(context.Background(), opts)
+			}
+		}
+
 		rest, err := client.New(client.ClientHTTP, endpoint, cfg)
 		if err != nil {
 			log.Errorf("Init client err: %s", err)
@@ -109,6 +132,14 @@ func Daemon() bool {
 
 	st.Discovery().Status.Ready = true
 
+	if viper.IsSet("metrics.port") {
+		go func() {
+			if err := metrics.Listen(viper.GetString("metrics.host"), viper.GetInt("metrics.port")); err != nil {
+				log.Errorf("discovery:metrics: server err: %s", err.Error())
+			}
+		}()
+	}
+
 	// Handle SIGINT and SIGTERM.
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 
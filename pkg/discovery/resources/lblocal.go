@@ -19,135 +19,79 @@
 package resources
 
 import (
-	"context"
+	"fmt"
 	"net"
-	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/lastbackend/lastbackend/pkg/discovery/cache"
 	"github.com/lastbackend/lastbackend/pkg/discovery/envs"
-	"github.com/lastbackend/lastbackend/pkg/distribution"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/util"
 	"github.com/miekg/dns"
-	"github.com/spf13/viper"
 )
 
+const lbLocalZone = "lb.local."
+
+// lbLocalName describes domain name in lb.local zone:
+// [_<port>._<proto>.][<pod>.]<service>.<namespace>.lb.local,
+// pod label is pod ip or stateful service pod name
+type lbLocalName struct {
+	name      string
+	host      string
+	namespace string
+	service   string
+	pod       net.IP
+	hostname  string
+	port      uint16
+	proto     string
+}
+
 func lbLocal(w dns.ResponseWriter, r *dns.Msg) {
 
 	log.V(logLevel).Debugf("%s:lb.local:> dns request `lb.local.`", logPrefix)
 
-	var (
-		err error
-		v4  bool
-		rr  dns.RR
-	)
-
 	m := new(dns.Msg)
 	m.SetReply(r)
 	m.Compress = false
 
-	em := distribution.NewEndpointModel(context.Background(), envs.Get().GetStorage())
-
 	switch r.Opcode {
 	case dns.OpcodeQuery:
 		log.V(logLevel).Debugf("%s:lb.local:> dns.OpcodeQuery", logPrefix)
 
+		m.Authoritative = true
+
 		for _, q := range m.Question {
 
-			switch r.Question[0].Qtype {
-			case dns.TypeTXT:
-				log.V(logLevel).Debugf("%s:lb.local:> get txt type query", logPrefix)
-				t := new(dns.TXT)
-				t.Hdr = dns.RR_Header{Name: q.Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 0}
-				m.Authoritative = true
-				m.Answer = append(m.Answer, t)
-				m.Extra = append(m.Extra, rr)
-			default:
-				log.V(logLevel).Debugf("%s:lb.local:> get unknown query type", logPrefix)
-				fallthrough
-			case dns.TypeAAAA, dns.TypeA:
-				log.V(logLevel).Debugf("%s:lb.local:> get A or AAAA type query", logPrefix)
-
-				if q.Name[len(q.Name)-1:] != "." {
-					q.Name += "."
-				}
-
-				log.V(logLevel).Debugf("%s:lb.local:> find ip addresses for domain: %s", logPrefix, q.Name)
-
-				// GenerateConfig A and AAAA records
-				ips := make([]net.IP, 0)
-
-				endpoint := util.Trim(q.Name, `.`)
-				item := envs.Get().GetCache().Endpoint().Get(endpoint)
-
-				if item != nil {
-					data := util.RemoveDuplicates(item)
-					ips, err = util.ConvertStringIPToNetIP(data)
-					if err != nil {
-						log.Error(err)
-						return
-					}
-				} else {
-
-					rg, _ := regexp.Compile("^(.+)\\.(.+)\\.lb\\.local$")
-					match := rg.FindStringSubmatch(endpoint)
-
-					if len(match) != 0 {
-
-						log.V(logLevel).Debugf("%s:lb.local:> find endpoint %s:%s", logPrefix, match[2], match[1])
-
-						e, err := em.Get(match[2], match[1])
-						if err != nil {
-							log.V(logLevel).Errorf("%s:lb.local:> get endpoint `%s` err: %v", endpoint, logPrefix, err)
-						}
-
-						if e != nil {
-							envs.Get().GetCache().Endpoint().Set(endpoint, []string{e.Spec.IP})
-
-							ips, err = util.ConvertStringIPToNetIP([]string{e.Spec.IP})
-							if err != nil {
-								log.Error(err)
-								return
-							}
-						}
-
-						if e == nil {
-							break
-						}
-					}
-
-				}
-
-				if len(ips) == 0 {
-					defaultIPs := viper.GetStringSlice("discovery.default_ips")
-					ips, err = util.ConvertStringIPToNetIP(defaultIPs)
-					if err != nil {
-						log.Error(err)
-						return
-					}
-				}
-
-				log.V(logLevel).Debugf("%s:lb.local:> ips list: %s for %s", logPrefix, ips, q.Name)
-
-				for _, ip := range ips {
-					v4 = ip.To4() != nil
-
-					if v4 {
-						rr = new(dns.A)
-						rr.(*dns.A).Hdr = dns.RR_Header{Name: q.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 0}
-						rr.(*dns.A).A = ip.To4()
-					} else {
-						rr = new(dns.AAAA)
-						rr.(*dns.AAAA).Hdr = dns.RR_Header{Name: q.Name, Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: 0}
-						rr.(*dns.AAAA).AAAA = ip
-					}
-
-					m.Authoritative = true
-					m.RecursionAvailable = true
-					m.RecursionDesired = true
-					m.Answer = append(m.Answer, rr)
-				}
+			name := strings.ToLower(dns.Fqdn(q.Name))
+
+			log.V(logLevel).Debugf("%s:lb.local:> find records %s for domain: %s", logPrefix, dns.TypeToString[q.Qtype], name)
+
+			if !envs.Get().GetCache().Endpoint().Synced() {
+				log.V(logLevel).Warnf("%s:lb.local:> endpoints cache is not synced", logPrefix)
+				m.Rcode = dns.RcodeServerFailure
+				break
 			}
+
+			answer, extra, found := lbLocalRecords(name, q.Qtype)
+
+			if !found {
+				log.V(logLevel).Debugf("%s:lb.local:> domain %s not found", logPrefix, name)
+				m.Rcode = dns.RcodeNameError
+				m.Ns = append(m.Ns, soa(lbLocalZone))
+				break
+			}
+
+			if len(answer) == 0 {
+				m.Ns = append(m.Ns, soa(lbLocalZone))
+				continue
+			}
+
+			m.Answer = append(m.Answer, answer...)
+			m.Extra = append(m.Extra, extra...)
 		}
 	case dns.OpcodeUpdate:
 		log.V(logLevel).Debugf("%s:lb.local:> dns.OpcodeUpdate", logPrefix)
@@ -165,3 +109,224 @@ func lbLocal(w dns.ResponseWriter, r *dns.Msg) {
 
 	w.WriteMsg(m)
 }
+
+// lbLocalRecords returns answer and additional records for domain name,
+// found is false if domain does not exist in zone
+func lbLocalRecords(name string, qtype uint16) ([]dns.RR, []dns.RR, bool) {
+
+	n, ok := parseLbLocalName(name)
+	if !ok {
+		return nil, nil, false
+	}
+
+	e := envs.Get().GetCache().Endpoint().Get(n.namespace, n.service)
+	if e == nil || !lbLocalHas(e, n) {
+		return nil, nil, false
+	}
+
+	switch qtype {
+	case dns.TypeA, dns.TypeAAAA:
+		if n.port != 0 {
+			return nil, nil, true
+		}
+		return addressRecords(n.host, qtype, lbLocalAddresses(e, n)), nil, true
+	case dns.TypeSRV:
+		answer := lbLocalServices(e, n)
+		extra := make([]dns.RR, 0)
+		if len(answer) != 0 {
+			extra = append(extra, addressRecords(n.host, dns.TypeA, lbLocalAddresses(e, n))...)
+			extra = append(extra, addressRecords(n.host, dns.TypeAAAA, lbLocalAddresses(e, n))...)
+		}
+		return answer, extra, true
+	}
+
+	return nil, nil, true
+}
+
+// lbLocalHas checks that pod and port from domain name belong to endpoint,
+// pod name is resolved to pod ip by endpoint hosts
+func lbLocalHas(e *cache.Endpoint, n *lbLocalName) bool {
+
+	if n.hostname != types.EmptyString {
+		n.pod = net.ParseIP(e.Hosts[n.hostname])
+		if n.pod == nil {
+			return false
+		}
+	}
+
+	if n.pod != nil {
+		var found bool
+		for _, u := range e.Upstreams {
+			if n.pod.Equal(net.ParseIP(u)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if n.port != 0 {
+		spec, ok := e.PortMap[n.port]
+		if !ok {
+			return false
+		}
+		if _, protocols := parsePortSpec(spec); !hasProtocol(protocols, n.proto) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// lbLocalAddresses returns endpoint ip or pods ips for endpoint without ip,
+// per-pod domain always points to pod ip
+func lbLocalAddresses(e *cache.Endpoint, n *lbLocalName) []net.IP {
+
+	if n.pod != nil {
+		return []net.IP{n.pod}
+	}
+
+	var data []string
+	if e.IP != types.EmptyString {
+		data = []string{e.IP}
+	} else {
+		data = util.RemoveDuplicates(e.Upstreams)
+	}
+
+	ips := make([]net.IP, 0)
+	for _, d := range data {
+		if ip := net.ParseIP(d); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+
+	return ips
+}
+
+// lbLocalServices returns SRV records for endpoint ports, service domain exposes endpoint ports,
+// per-pod domain exposes container ports
+func lbLocalServices(e *cache.Endpoint, n *lbLocalName) []dns.RR {
+
+	ports := make([]int, 0)
+	for p := range e.PortMap {
+		if n.port == 0 || n.port == p {
+			ports = append(ports, int(p))
+		}
+	}
+	sort.Ints(ports)
+
+	rrs := make([]dns.RR, 0)
+	for _, p := range ports {
+
+		target, protocols := parsePortSpec(e.PortMap[uint16(p)])
+		if n.pod == nil || target == 0 {
+			target = uint16(p)
+		}
+
+		for _, proto := range protocols {
+
+			if n.proto != types.EmptyString && n.proto != proto {
+				continue
+			}
+
+			name := n.name
+			if n.port == 0 {
+				name = fmt.Sprintf("_%d._%s.%s", p, proto, n.host)
+			}
+
+			rr := new(dns.SRV)
+			rr.Hdr = dns.RR_Header{Name: name, Rrtype: dns.TypeSRV, Class: dns.ClassINET, Ttl: ttl()}
+			rr.Priority = 0
+			rr.Weight = 10
+			rr.Port = target
+			rr.Target = n.host
+			rrs = append(rrs, rr)
+		}
+	}
+
+	return rrs
+}
+
+func parseLbLocalName(name string) (*lbLocalName, bool) {
+
+	if !dns.IsSubDomain(lbLocalZone, name) {
+		return nil, false
+	}
+
+	labels := dns.SplitDomainName(strings.TrimSuffix(name, lbLocalZone))
+
+	n := new(lbLocalName)
+	n.name = name
+
+	if len(labels) >= 4 && strings.HasPrefix(labels[0], "_") && strings.HasPrefix(labels[1], "_") {
+		port, err := strconv.ParseUint(strings.TrimPrefix(labels[0], "_"), 10, 16)
+		if err != nil || port == 0 {
+			return nil, false
+		}
+		n.port = uint16(port)
+		n.proto = strings.TrimPrefix(labels[1], "_")
+		labels = labels[2:]
+	}
+
+	switch len(labels) {
+	case 2:
+	case 3:
+		n.pod = parsePodLabel(labels[0])
+		if n.pod == nil {
+			n.hostname = labels[0]
+		}
+		labels = labels[1:]
+	default:
+		return nil, false
+	}
+
+	n.service = labels[0]
+	n.namespace = labels[1]
+	n.host = strings.TrimPrefix(name, fmt.Sprintf("_%d._%s.", n.port, n.proto))
+
+	return n, true
+}
+
+// podLabel returns domain label for pod ip: 10-0-0-1 or fd00--1
+func podLabel(ip net.IP) string {
+	if ip.To4() != nil {
+		return strings.Replace(ip.String(), ".", "-", -1)
+	}
+	return strings.Replace(ip.String(), ":", "-", -1)
+}
+
+func parsePodLabel(label string) net.IP {
+	if ip := net.ParseIP(strings.Replace(label, "-", ".", -1)); ip != nil && ip.To4() != nil {
+		return ip
+	}
+	return net.ParseIP(strings.Replace(label, "-", ":", -1))
+}
+
+// parsePortSpec parses endpoint port map value `<port>/<proto>`, `*` means both tcp and udp
+func parsePortSpec(spec string) (uint16, []string) {
+
+	parts := strings.SplitN(spec, "/", 2)
+
+	port, _ := strconv.ParseUint(parts[0], 10, 16)
+
+	if len(parts) == 1 || parts[1] == types.EmptyString {
+		return uint16(port), []string{"tcp"}
+	}
+
+	if parts[1] == "*" {
+		return uint16(port), []string{"tcp", "udp"}
+	}
+
+	return uint16(port), []string{strings.ToLower(parts[1])}
+}
+
+func hasProtocol(protocols []string, proto string) bool {
+	for _, p := range protocols {
+		if p == proto {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,252 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package resources
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/lastbackend/lastbackend/pkg/discovery/cache"
+	"github.com/lastbackend/lastbackend/pkg/discovery/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+type testWriter struct {
+	msg *dns.Msg
+}
+
+func (w *testWriter) LocalAddr() net.Addr       { return nil }
+func (w *testWriter) RemoteAddr() net.Addr      { return nil }
+func (w *testWriter) WriteMsg(m *dns.Msg) error { w.msg = m; return nil }
+func (w *testWriter) Write([]byte) (int, error) { return 0, nil }
+func (w *testWriter) Close() error              { return nil }
+func (w *testWriter) TsigStatus() error         { return nil }
+func (w *testWriter) TsigTimersOnly(bool)       {}
+func (w *testWriter) Hijack()                   {}
+
+func TestLbLocal(t *testing.T) {
+
+	envs.Get().SetCache(cache.New())
+
+	endpoint := new(types.Endpoint)
+	endpoint.Meta.Name = "web"
+	endpoint.Meta.Namespace = "demo"
+	endpoint.Spec.IP = "10.0.0.10"
+	endpoint.Spec.Domain = "web.demo.lb.local"
+	endpoint.Spec.PortMap = map[uint16]string{80: "8080/tcp", 53: "5353/*"}
+
+	manifest := new(types.EndpointManifest)
+	manifest.Upstreams = []string{"172.17.0.2", "172.17.0.3"}
+
+	stateful := new(types.Endpoint)
+	stateful.Meta.Name = "db"
+	stateful.Meta.Namespace = "demo"
+	stateful.Spec.IP = "10.0.0.11"
+	stateful.Spec.Domain = "db.demo.lb.local"
+	stateful.Spec.PortMap = map[uint16]string{5432: "5432/tcp"}
+
+	hosts := new(types.EndpointManifest)
+	hosts.Upstreams = []string{"172.17.0.5", "172.17.0.6"}
+	hosts.Hosts = map[string]string{"db-0": "172.17.0.5", "db-1": "172.17.0.6"}
+
+	envs.Get().GetCache().Endpoint().Restore([]*types.Endpoint{endpoint, stateful},
+		map[string]*types.EndpointManifest{endpoint.SelfLink(): manifest, stateful.SelfLink(): hosts})
+
+	tests := []struct {
+		name    string
+		qname   string
+		qtype   uint16
+		rcode   int
+		answers []string
+	}{
+		{
+			name:    "service address",
+			qname:   "web.demo.lb.local.",
+			qtype:   dns.TypeA,
+			rcode:   dns.RcodeSuccess,
+			answers: []string{"10.0.0.10"},
+		},
+		{
+			name:  "service without ipv6 address",
+			qname: "web.demo.lb.local.",
+			qtype: dns.TypeAAAA,
+			rcode: dns.RcodeSuccess,
+		},
+		{
+			name:    "pod address",
+			qname:   "172-17-0-3.web.demo.lb.local.",
+			qtype:   dns.TypeA,
+			rcode:   dns.RcodeSuccess,
+			answers: []string{"172.17.0.3"},
+		},
+		{
+			name:  "unknown pod address",
+			qname: "172-17-0-9.web.demo.lb.local.",
+			qtype: dns.TypeA,
+			rcode: dns.RcodeNameError,
+		},
+		{
+			name:    "stateful pod address",
+			qname:   "db-1.db.demo.lb.local.",
+			qtype:   dns.TypeA,
+			rcode:   dns.RcodeSuccess,
+			answers: []string{"172.17.0.6"},
+		},
+		{
+			name:  "unknown stateful pod address",
+			qname: "db-2.db.demo.lb.local.",
+			qtype: dns.TypeA,
+			rcode: dns.RcodeNameError,
+		},
+		{
+			name:  "pod name of stateless service",
+			qname: "web-0.web.demo.lb.local.",
+			qtype: dns.TypeA,
+			rcode: dns.RcodeNameError,
+		},
+		{
+			name:    "stateful pod port by name",
+			qname:   "_5432._tcp.db-0.db.demo.lb.local.",
+			qtype:   dns.TypeSRV,
+			rcode:   dns.RcodeSuccess,
+			answers: []string{"_5432._tcp.db-0.db.demo.lb.local.:5432"},
+		},
+		{
+			name:  "unknown service",
+			qname: "api.demo.lb.local.",
+			qtype: dns.TypeA,
+			rcode: dns.RcodeNameError,
+		},
+		{
+			name:    "service ports",
+			qname:   "web.demo.lb.local.",
+			qtype:   dns.TypeSRV,
+			rcode:   dns.RcodeSuccess,
+			answers: []string{"_53._tcp.web.demo.lb.local.:53", "_53._udp.web.demo.lb.local.:53", "_80._tcp.web.demo.lb.local.:80"},
+		},
+		{
+			name:    "service port by name",
+			qname:   "_80._tcp.web.demo.lb.local.",
+			qtype:   dns.TypeSRV,
+			rcode:   dns.RcodeSuccess,
+			answers: []string{"_80._tcp.web.demo.lb.local.:80"},
+		},
+		{
+			name:    "pod port by name",
+			qname:   "_80._tcp.172-17-0-2.web.demo.lb.local.",
+			qtype:   dns.TypeSRV,
+			rcode:   dns.RcodeSuccess,
+			answers: []string{"_80._tcp.172-17-0-2.web.demo.lb.local.:8080"},
+		},
+		{
+			name:  "unknown port",
+			qname: "_80._udp.web.demo.lb.local.",
+			qtype: dns.TypeSRV,
+			rcode: dns.RcodeNameError,
+		},
+		{
+			name:    "endpoint reverse lookup",
+			qname:   "10.0.0.10.in-addr.arpa.",
+			qtype:   dns.TypePTR,
+			rcode:   dns.RcodeSuccess,
+			answers: []string{"web.demo.lb.local."},
+		},
+		{
+			name:    "pod reverse lookup",
+			qname:   "2.0.17.172.in-addr.arpa.",
+			qtype:   dns.TypePTR,
+			rcode:   dns.RcodeSuccess,
+			answers: []string{"172-17-0-2.web.demo.lb.local."},
+		},
+		{
+			name:    "stateful pod reverse lookup",
+			qname:   "5.0.17.172.in-addr.arpa.",
+			qtype:   dns.TypePTR,
+			rcode:   dns.RcodeSuccess,
+			answers: []string{"db-0.db.demo.lb.local."},
+		},
+		{
+			name:  "unknown reverse lookup",
+			qname: "1.1.1.1.in-addr.arpa.",
+			qtype: dns.TypePTR,
+			rcode: dns.RcodeNameError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			r := new(dns.Msg)
+			r.SetQuestion(tc.qname, tc.qtype)
+
+			w := new(testWriter)
+			if dns.IsSubDomain(lbLocalZone, tc.qname) {
+				lbLocal(w, r)
+			} else {
+				reverse(w, r)
+			}
+
+			if !assert.NotNil(t, w.msg, "message not sent") {
+				return
+			}
+
+			assert.Equal(t, tc.rcode, w.msg.Rcode, "rcode not equal")
+
+			answers := make([]string, 0)
+			for _, rr := range w.msg.Answer {
+				assert.NotZero(t, rr.Header().Ttl, "ttl is zero")
+				switch v := rr.(type) {
+				case *dns.A:
+					answers = append(answers, v.A.String())
+				case *dns.SRV:
+					answers = append(answers, fmt.Sprintf("%s:%d", v.Hdr.Name, v.Port))
+				case *dns.PTR:
+					answers = append(answers, v.Ptr)
+				}
+			}
+
+			if len(tc.answers) == 0 {
+				assert.Empty(t, answers, "answers not empty")
+				return
+			}
+
+			assert.ElementsMatch(t, tc.answers, answers, "answers not equal")
+		})
+	}
+}
+
+func TestLbLocalNotSynced(t *testing.T) {
+
+	envs.Get().SetCache(cache.New())
+
+	r := new(dns.Msg)
+	r.SetQuestion("web.demo.lb.local.", dns.TypeA)
+
+	w := new(testWriter)
+	lbLocal(w, r)
+
+	if !assert.NotNil(t, w.msg, "message not sent") {
+		return
+	}
+
+	assert.Equal(t, dns.RcodeServerFailure, w.msg.Rcode, "rcode not equal")
+}
@@ -19,14 +19,69 @@
 package resources
 
 import (
+	"net"
+	"time"
+
 	"github.com/miekg/dns"
+	"github.com/spf13/viper"
 )
 
 const (
 	logLevel  = 3
 	logPrefix = "dns:resources"
+
+	// defaultTTL - records time to live in seconds if not provided
+	defaultTTL = 10
 )
 
 var Map = map[string]dns.HandlerFunc{
-	"lb.local.": lbLocal,
+	"lb.local.":     lbLocal,
+	"in-addr.arpa.": reverse,
+	"ip6.arpa.":     reverse,
+}
+
+// ttl returns records time to live in seconds
+func ttl() uint32 {
+	if t := viper.GetInt("dns.ttl"); t > 0 {
+		return uint32(t)
+	}
+	return defaultTTL
+}
+
+// soa returns zone authority record used for negative answers
+func soa(zone string) dns.RR {
+	rr := new(dns.SOA)
+	rr.Hdr = dns.RR_Header{Name: zone, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: ttl()}
+	rr.Ns = "ns.dns." + zone
+	rr.Mbox = "hostmaster." + zone
+	rr.Serial = uint32(time.Now().Unix())
+	rr.Refresh = 7200
+	rr.Retry = 1800
+	rr.Expire = 86400
+	rr.Minttl = ttl()
+	return rr
+}
+
+// addressRecords returns A or AAAA records for ips matching query type
+func addressRecords(name string, qtype uint16, ips []net.IP) []dns.RR {
+
+	rrs := make([]dns.RR, 0)
+
+	for _, ip := range ips {
+
+		switch {
+		case ip.To4() != nil && qtype == dns.TypeA:
+			rr := new(dns.A)
+			rr.Hdr = dns.RR_Header{Name: name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: ttl()}
+			rr.A = ip.To4()
+			rrs = append(rrs, rr)
+		case ip.To4() == nil && qtype == dns.TypeAAAA:
+			rr := new(dns.AAAA)
+			rr.Hdr = dns.RR_Header{Name: name, Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: ttl()}
+			rr.AAAA = ip
+			rrs = append(rrs, rr)
+		}
+	}
+
+	return rrs
 }
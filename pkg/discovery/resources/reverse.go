@@ -0,0 +1,181 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package resources
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/discovery/envs"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/miekg/dns"
+)
+
+const (
+	reverseZoneV4 = "in-addr.arpa."
+	reverseZoneV6 = "ip6.arpa."
+)
+
+func reverse(w dns.ResponseWriter, r *dns.Msg) {
+
+	log.V(logLevel).Debugf("%s:reverse:> dns request `arpa.`", logPrefix)
+
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Compress = false
+
+	switch r.Opcode {
+	case dns.OpcodeQuery:
+		log.V(logLevel).Debugf("%s:reverse:> dns.OpcodeQuery", logPrefix)
+
+		for _, q := range m.Question {
+
+			name := strings.ToLower(dns.Fqdn(q.Name))
+
+			ip := parseReverseName(name)
+			if ip == nil {
+				m.Rcode = dns.RcodeNameError
+				break
+			}
+
+			log.V(logLevel).Debugf("%s:reverse:> find domains for ip: %s", logPrefix, ip)
+
+			if !envs.Get().GetCache().Endpoint().Synced() {
+				log.V(logLevel).Warnf("%s:reverse:> endpoints cache is not synced", logPrefix)
+				m.Rcode = dns.RcodeServerFailure
+				break
+			}
+
+			domains := reverseDomains(ip)
+
+			if len(domains) == 0 {
+				m.Rcode = dns.RcodeNameError
+				break
+			}
+
+			m.Authoritative = true
+
+			if q.Qtype != dns.TypePTR {
+				continue
+			}
+
+			for _, d := range domains {
+				rr := new(dns.PTR)
+				rr.Hdr = dns.RR_Header{Name: name, Rrtype: dns.TypePTR, Class: dns.ClassINET, Ttl: ttl()}
+				rr.Ptr = d
+				m.Answer = append(m.Answer, rr)
+			}
+		}
+	case dns.OpcodeUpdate:
+		log.V(logLevel).Debugf("%s:reverse:> dns.OpcodeUpdate", logPrefix)
+	}
+
+	if r.IsTsig() != nil {
+		if w.TsigStatus() == nil {
+			m.SetTsig(r.Extra[len(r.Extra)-1].(*dns.TSIG).Hdr.Name, dns.HmacMD5, 300, time.Now().Unix())
+		} else {
+			log.V(logLevel).Errorf("%s:reverse:> tsig status err: %s", logPrefix, w.TsigStatus())
+		}
+	}
+
+	log.V(logLevel).Debugf("%s:reverse:> send message info  %#v", logPrefix, m)
+
+	w.WriteMsg(m)
+}
+
+// reverseDomains returns endpoint domains pointing to ip:
+// endpoint domain for endpoint ip and per-pod domain for upstream ip,
+// stateful service pod domain is named by pod name
+func reverseDomains(ip net.IP) []string {
+
+	domains := make([]string, 0)
+
+	for _, e := range envs.Get().GetCache().Endpoint().List() {
+
+		domain := e.Domain
+		if domain == types.EmptyString {
+			domain = fmt.Sprintf("%s.%s.%s", e.Service, e.Namespace, lbLocalZone)
+		}
+		domain = strings.ToLower(dns.Fqdn(domain))
+
+		if ip.Equal(net.ParseIP(e.IP)) {
+			domains = append(domains, domain)
+		}
+
+		for _, u := range e.Upstreams {
+			if ip.Equal(net.ParseIP(u)) {
+				domains = append(domains, fmt.Sprintf("%s.%s", reversePodLabel(e.Hosts, ip), domain))
+				break
+			}
+		}
+	}
+
+	sort.Strings(domains)
+
+	return domains
+}
+
+// reversePodLabel returns pod name for ip from endpoint hosts or pod ip label
+func reversePodLabel(hosts map[string]string, ip net.IP) string {
+	for name, h := range hosts {
+		if ip.Equal(net.ParseIP(h)) {
+			return name
+		}
+	}
+	return podLabel(ip)
+}
+
+// parseReverseName converts reverse lookup domain name to ip
+func parseReverseName(name string) net.IP {
+
+	switch {
+	case strings.HasSuffix(name, "."+reverseZoneV4):
+		labels := dns.SplitDomainName(strings.TrimSuffix(name, "."+reverseZoneV4))
+		if len(labels) != net.IPv4len {
+			return nil
+		}
+		for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+			labels[i], labels[j] = labels[j], labels[i]
+		}
+		return net.ParseIP(strings.Join(labels, ".")).To4()
+
+	case strings.HasSuffix(name, "."+reverseZoneV6):
+		labels := dns.SplitDomainName(strings.TrimSuffix(name, "."+reverseZoneV6))
+		if len(labels) != net.IPv6len*2 {
+			return nil
+		}
+		var b strings.Builder
+		for i := len(labels) - 1; i >= 0; i-- {
+			if len(labels[i]) != 1 {
+				return nil
+			}
+			b.WriteString(labels[i])
+			if i%4 == 0 && i != 0 {
+				b.WriteString(":")
+			}
+		}
+		return net.ParseIP(b.String())
+	}
+
+	return nil
+}
@@ -20,6 +20,8 @@ package endpoint
 
 import (
 	"context"
+	"time"
+
 	"github.com/lastbackend/lastbackend/pkg/discovery/envs"
 	"github.com/lastbackend/lastbackend/pkg/distribution"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
@@ -29,46 +31,102 @@ import (
 const (
 	logLevel  = 3
 	logPrefix = "runtime:endpoint"
+
+	// resyncInterval - delay before cache restore after watch failure
+	resyncInterval = time.Second
 )
 
+// Watch keeps endpoints cache in sync with storage:
+// cache is restored from storage and then updated by endpoints and endpoint manifests events
 func Watch(ctx context.Context) {
 
-	log.V(logLevel).Debugf("%s:restore:> watch change endpoint start", logPrefix)
+	log.V(logLevel).Debugf("%s:watch:> watch change endpoint start", logPrefix)
+
+	for {
+
+		if err := sync(ctx); err != nil {
+			log.Errorf("%s:watch:> sync endpoints err: %v", logPrefix, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resyncInterval):
+			log.V(logLevel).Debugf("%s:watch:> resync endpoints", logPrefix)
+		}
+	}
+}
+
+func sync(ctx context.Context) error {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
 	var (
-		em    = distribution.NewEndpointModel(ctx, envs.Get().GetStorage())
-		cache = envs.Get().GetCache().Endpoint()
-		event = make(chan types.EndpointEvent)
+		em        = distribution.NewEndpointModel(ctx, envs.Get().GetStorage())
+		cache     = envs.Get().GetCache().Endpoint()
+		endpoints = make(chan types.EndpointEvent)
+		manifests = make(chan types.EndpointManifestEvent)
+		errs      = make(chan error, 2)
 	)
 
+	el, err := em.List()
+	if err != nil {
+		return err
+	}
+
+	mf, err := em.ManifestMap()
+	if err != nil {
+		return err
+	}
+
+	cache.Restore(el.Items, mf.Items)
+
+	log.V(logLevel).Debugf("%s:sync:> restored %d endpoints", logPrefix, cache.Count())
+
 	go func() {
-		for {
-			select {
-			case e := <-event:
-				{
-
-					if e.Data == nil {
-						continue
-					}
-
-					endpoint := e.Data
-
-					switch e.Action {
-					case types.EventActionCreate:
-						fallthrough
-					case types.EventActionUpdate:
-						cache.Del(endpoint.Spec.Domain)
-						envs.Get().GetCache().Endpoint().Set(endpoint.Spec.Domain, []string{endpoint.Spec.IP})
-						continue
-					case types.EventActionDelete:
-						cache.Del(endpoint.Spec.Domain)
-						continue
-					}
-
-				}
-			}
-		}
+		errs <- em.Watch(endpoints, &el.System.Revision)
 	}()
 
-	go em.Watch(event, nil)
+	go func() {
+		errs <- em.ManifestWatch(manifests, &mf.System.Revision)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case err := <-errs:
+			return err
+
+		case e := <-endpoints:
+
+			if e.Data == nil {
+				continue
+			}
+
+			switch e.Action {
+			case types.EventActionCreate, types.EventActionUpdate:
+				cache.Set(e.Data)
+			case types.EventActionDelete:
+				cache.Del(e.Data.SelfLink())
+			}
+
+		case e := <-manifests:
+
+			if e.Data == nil {
+				continue
+			}
+
+			switch e.Action {
+			case types.EventActionCreate, types.EventActionUpdate:
+				cache.SetUpstreams(e.SelfLink, e.Data.Upstreams)
+				cache.SetHosts(e.SelfLink, e.Data.Hosts)
+			case types.EventActionDelete:
+				cache.DelUpstreams(e.SelfLink)
+				cache.DelHosts(e.SelfLink)
+			}
+		}
+	}
 }
@@ -0,0 +1,151 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package distribution
+
+import (
+	"context"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+)
+
+const (
+	logAccountPrefix = "distribution:account"
+)
+
+type Account struct {
+	context context.Context
+	storage storage.Storage
+}
+
+func (a *Account) Get(name string) (*types.Account, error) {
+
+	log.V(logLevel).Debugf("%s:get:> get account %s", logAccountPrefix, name)
+
+	item := new(types.Account)
+
+	err := a.storage.Get(a.context, a.storage.Collection().Account(), a.storage.Key().Account(name), &item, nil)
+	if err != nil {
+
+		if errors.Storage().IsErrEntityNotFound(err) {
+			log.V(logLevel).Warnf("%s:get:> account %s not found", logAccountPrefix, name)
+			return nil, nil
+		}
+
+		log.V(logLevel).Errorf("%s:get:> get account %s error: %v", logAccountPrefix, name, err)
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (a *Account) List() (*types.AccountList, error) {
+
+	log.V(logLevel).Debugf("%s:list:> get accounts list", logAccountPrefix)
+
+	list := types.NewAccountList()
+
+	err := a.storage.List(a.context, a.storage.Collection().Account(), "", list, nil)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> get accounts list err: %v", logAccountPrefix, err)
+		return list, err
+	}
+
+	log.V(logLevel).Debugf("%s:list:> get accounts list result: %d", logAccountPrefix, len(list.Items))
+
+	return list, nil
+}
+
+func (a *Account) Create(account *types.Account) (*types.Account, error) {
+
+	log.V(logLevel).Debugf("%s:create:> create account %s", logAccountPrefix, account.Meta.Name)
+
+	account.Meta.SetDefault()
+	account.SelfLink()
+
+	if account.Spec.Roles == nil {
+		account.Spec.Roles = make(map[string]string)
+	}
+
+	if account.Spec.Tokens == nil {
+		account.Spec.Tokens = make(map[string]*types.AccountToken)
+	}
+
+	if err := a.storage.Put(a.context, a.storage.Collection().Account(),
+		a.storage.Key().Account(account.Meta.Name), account, nil); err != nil {
+		log.V(logLevel).Errorf("%s:create:> insert account err: %v", logAccountPrefix, err)
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (a *Account) Update(account *types.Account) (*types.Account, error) {
+
+	log.V(logLevel).Debugf("%s:update:> update account %s", logAccountPrefix, account.Meta.Name)
+
+	if err := a.storage.Set(a.context, a.storage.Collection().Account(),
+		a.storage.Key().Account(account.Meta.Name), account, nil); err != nil {
+		log.V(logLevel).Errorf("%s:update:> update account err: %v", logAccountPrefix, err)
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (a *Account) Remove(account *types.Account) error {
+
+	log.V(logLevel).Debugf("%s:remove:> remove account %s", logAccountPrefix, account.Meta.Name)
+
+	if err := a.storage.Del(a.context, a.storage.Collection().Account(),
+		a.storage.Key().Account(account.Meta.Name)); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> remove account err: %v", logAccountPrefix, err)
+		return err
+	}
+
+	return nil
+}
+
+// Authenticate returns account which issued provided token,
+// nil account is returned if token is not valid
+func (a *Account) Authenticate(token string) (*types.Account, error) {
+
+	name, id, secret, err := types.ParseAccountToken(token)
+	if err != nil {
+		return nil, nil
+	}
+
+	account, err := a.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if account == nil || !account.VerifyToken(id, secret) {
+		log.V(logLevel).Warnf("%s:authenticate:> invalid token for account %s", logAccountPrefix, name)
+		return nil, nil
+	}
+
+	return account, nil
+}
+
+func NewAccountModel(ctx context.Context, stg storage.Storage) *Account {
+	return &Account{ctx, stg}
+}
@@ -0,0 +1,213 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package distribution
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+)
+
+const (
+	logAutoscalerPrefix = "distribution:autoscaler"
+)
+
+type Autoscaler struct {
+	context context.Context
+	storage storage.Storage
+}
+
+func (a *Autoscaler) Runtime() (*types.Runtime, error) {
+
+	log.V(logLevel).Debugf("%s:get:> get autoscalers runtime info", logAutoscalerPrefix)
+	runtime, err := a.storage.Info(a.context, a.storage.Collection().Autoscaler(), "")
+	if err != nil {
+		log.V(logLevel).Errorf("%s:get:> get runtime info error: %s", logAutoscalerPrefix, err)
+		return &runtime.Runtime, err
+	}
+	return &runtime.Runtime, nil
+}
+
+// Get autoscaler of service
+func (a *Autoscaler) Get(namespace, service string) (*types.Autoscaler, error) {
+
+	log.V(logLevel).Debugf("%s:get:> get autoscaler %s:%s", logAutoscalerPrefix, namespace, service)
+
+	item := new(types.Autoscaler)
+
+	err := a.storage.Get(a.context, a.storage.Collection().Autoscaler(), a.storage.Key().Autoscaler(namespace, service), &item, nil)
+	if err != nil {
+
+		if errors.Storage().IsErrEntityNotFound(err) {
+			log.V(logLevel).Warnf("%s:get:> autoscaler %s:%s not found", logAutoscalerPrefix, namespace, service)
+			return nil, nil
+		}
+
+		log.V(logLevel).Errorf("%s:get:> get autoscaler %s:%s err: %s", logAutoscalerPrefix, namespace, service, err)
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// List autoscalers in namespace
+func (a *Autoscaler) List(namespace string) (*types.AutoscalerList, error) {
+
+	log.V(logLevel).Debugf("%s:list:> get autoscalers list in namespace %s", logAutoscalerPrefix, namespace)
+
+	list := types.NewAutoscalerList()
+	filter := a.storage.Filter().Autoscaler().ByNamespace(namespace)
+
+	err := a.storage.List(a.context, a.storage.Collection().Autoscaler(), filter, list, nil)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> get autoscalers list err: %s", logAutoscalerPrefix, err)
+		return list, err
+	}
+
+	log.V(logLevel).Debugf("%s:list:> get autoscalers list result: %d", logAutoscalerPrefix, len(list.Items))
+
+	return list, nil
+}
+
+// Create autoscaler for service
+func (a *Autoscaler) Create(service *types.Service, autoscaler *types.Autoscaler) (*types.Autoscaler, error) {
+
+	log.V(logLevel).Debugf("%s:create:> create autoscaler for service %s", logAutoscalerPrefix, service.SelfLink())
+
+	autoscaler.Meta.SetDefault()
+	autoscaler.Meta.Name = service.Meta.Name
+	autoscaler.Meta.Namespace = service.Meta.Namespace
+	autoscaler.Meta.Service = service.Meta.Name
+	autoscaler.SelfLink()
+
+	autoscaler.Status.Replicas = service.Spec.Replicas
+	autoscaler.Status.Desired = service.Spec.Replicas
+
+	if err := a.storage.Put(a.context, a.storage.Collection().Autoscaler(),
+		a.storage.Key().Autoscaler(autoscaler.Meta.Namespace, autoscaler.Meta.Service), autoscaler, nil); err != nil {
+		log.V(logLevel).Errorf("%s:create:> insert autoscaler err: %v", logAutoscalerPrefix, err)
+		return nil, err
+	}
+
+	return autoscaler, nil
+}
+
+// Update autoscaler spec
+func (a *Autoscaler) Update(autoscaler *types.Autoscaler) (*types.Autoscaler, error) {
+
+	log.V(logLevel).Debugf("%s:update:> update autoscaler %s", logAutoscalerPrefix, autoscaler.SelfLink())
+
+	autoscaler.Meta.Updated = time.Now()
+
+	if err := a.storage.Set(a.context, a.storage.Collection().Autoscaler(),
+		a.storage.Key().Autoscaler(autoscaler.Meta.Namespace, autoscaler.Meta.Service), autoscaler, nil); err != nil {
+		log.V(logLevel).Errorf("%s:update:> update autoscaler err: %v", logAutoscalerPrefix, err)
+		return nil, err
+	}
+
+	return autoscaler, nil
+}
+
+// Set autoscaler status
+func (a *Autoscaler) Set(autoscaler *types.Autoscaler) error {
+
+	if autoscaler == nil {
+		return errors.New(errors.ErrStructArgIsNil)
+	}
+
+	log.V(logLevel).Debugf("%s:setstatus:> set status for autoscaler %s", logAutoscalerPrefix, autoscaler.SelfLink())
+
+	if err := a.storage.Set(a.context, a.storage.Collection().Autoscaler(),
+		a.storage.Key().Autoscaler(autoscaler.Meta.Namespace, autoscaler.Meta.Service), autoscaler, nil); err != nil {
+		log.Errorf("%s:setstatus:> set status for autoscaler %s err: %v", logAutoscalerPrefix, autoscaler.SelfLink(), err)
+		return err
+	}
+
+	return nil
+}
+
+// Remove autoscaler from storage
+func (a *Autoscaler) Remove(autoscaler *types.Autoscaler) error {
+
+	log.V(logLevel).Debugf("%s:remove:> remove autoscaler %s", logAutoscalerPrefix, autoscaler.SelfLink())
+
+	if err := a.storage.Del(a.context, a.storage.Collection().Autoscaler(),
+		a.storage.Key().Autoscaler(autoscaler.Meta.Namespace, autoscaler.Meta.Service)); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> remove autoscaler err: %v", logAutoscalerPrefix, err)
+		return err
+	}
+
+	return nil
+}
+
+// Watch autoscalers changes
+func (a *Autoscaler) Watch(ch chan types.AutoscalerEvent, rev *int64) error {
+
+	log.V(logLevel).Debugf("%s:watch:> watch autoscalers", logAutoscalerPrefix)
+
+	done := make(chan bool)
+	watcher := storage.NewWatcher()
+
+	go func() {
+		for {
+			select {
+			case <-a.context.Done():
+				done <- true
+				return
+			case e := <-watcher:
+				if e.Data == nil {
+					continue
+				}
+
+				res := types.AutoscalerEvent{}
+				res.Action = e.Action
+				res.Name = e.Name
+
+				autoscaler := new(types.Autoscaler)
+
+				if err := json.Unmarshal(e.Data.([]byte), autoscaler); err != nil {
+					log.Errorf("%s:> parse data err: %v", logAutoscalerPrefix, err)
+					continue
+				}
+
+				res.Data = autoscaler
+
+				ch <- res
+			}
+		}
+	}()
+
+	opts := storage.GetOpts()
+	opts.Rev = rev
+	if err := a.storage.Watch(a.context, a.storage.Collection().Autoscaler(), watcher, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewAutoscalerModel returns new autoscaler management model
+func NewAutoscalerModel(ctx context.Context, stg storage.Storage) *Autoscaler {
+	return &Autoscaler{ctx, stg}
+}
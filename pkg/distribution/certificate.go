@@ -0,0 +1,128 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package distribution
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+)
+
+const (
+	logCertificatePrefix = "distribution:certificate"
+
+	certificateAuthorityKey = "ca"
+	certificateJoinKey      = "join"
+)
+
+type Certificate struct {
+	context context.Context
+	storage storage.Storage
+}
+
+// GetAuthority returns stored cluster certificate authority
+func (c *Certificate) GetAuthority() (*types.CertificateAuthority, error) {
+
+	log.V(logLevel).Debugf("%s:authority:get:> get certificate authority", logCertificatePrefix)
+
+	ca := new(types.CertificateAuthority)
+
+	err := c.storage.Get(c.context, c.storage.Collection().System(), certificateAuthorityKey, ca, nil)
+	if err != nil {
+		if errors.Storage().IsErrEntityNotFound(err) {
+			return nil, nil
+		}
+
+		log.V(logLevel).Errorf("%s:authority:get:> get certificate authority err: %v", logCertificatePrefix, err)
+		return nil, err
+	}
+
+	return ca, nil
+}
+
+// CreateAuthority stores cluster certificate authority, existing authority is not replaced
+func (c *Certificate) CreateAuthority(ca *types.CertificateAuthority) error {
+
+	log.V(logLevel).Debugf("%s:authority:create:> create certificate authority", logCertificatePrefix)
+
+	if err := c.storage.Put(c.context, c.storage.Collection().System(), certificateAuthorityKey, ca, nil); err != nil {
+		log.V(logLevel).Errorf("%s:authority:create:> create certificate authority err: %v", logCertificatePrefix, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetJoinToken returns join token by id
+func (c *Certificate) GetJoinToken(id string) (*types.JoinToken, error) {
+
+	log.V(logLevel).Debugf("%s:join:get:> get join token %s", logCertificatePrefix, id)
+
+	token := new(types.JoinToken)
+
+	err := c.storage.Get(c.context, c.storage.Collection().System(), c.joinKey(id), token, nil)
+	if err != nil {
+		if errors.Storage().IsErrEntityNotFound(err) {
+			return nil, nil
+		}
+
+		log.V(logLevel).Errorf("%s:join:get:> get join token err: %v", logCertificatePrefix, err)
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// CreateJoinToken stores join token
+func (c *Certificate) CreateJoinToken(token *types.JoinToken) error {
+
+	log.V(logLevel).Debugf("%s:join:create:> create join token %s", logCertificatePrefix, token.ID)
+
+	if err := c.storage.Put(c.context, c.storage.Collection().System(), c.joinKey(token.ID), token, nil); err != nil {
+		log.V(logLevel).Errorf("%s:join:create:> create join token err: %v", logCertificatePrefix, err)
+		return err
+	}
+
+	return nil
+}
+
+// RemoveJoinToken removes join token, token can be used only once
+func (c *Certificate) RemoveJoinToken(token *types.JoinToken) error {
+
+	log.V(logLevel).Debugf("%s:join:remove:> remove join token %s", logCertificatePrefix, token.ID)
+
+	if err := c.storage.Del(c.context, c.storage.Collection().System(), c.joinKey(token.ID)); err != nil {
+		log.V(logLevel).Errorf("%s:join:remove:> remove join token err: %v", logCertificatePrefix, err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *Certificate) joinKey(id string) string {
+	return fmt.Sprintf("%s:%s", certificateJoinKey, id)
+}
+
+func NewCertificateModel(ctx context.Context, stg storage.Storage) *Certificate {
+	return &Certificate{ctx, stg}
+}
@@ -0,0 +1,231 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package distribution
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+)
+
+const (
+	logCronJobPrefix = "distribution:cronjob"
+)
+
+type CronJob struct {
+	context context.Context
+	storage storage.Storage
+}
+
+func (c *CronJob) Runtime() (*types.Runtime, error) {
+
+	log.V(logLevel).Debugf("%s:get:> get cron jobs runtime info", logCronJobPrefix)
+	runtime, err := c.storage.Info(c.context, c.storage.Collection().CronJob(), "")
+	if err != nil {
+		log.V(logLevel).Errorf("%s:get:> get runtime info error: %s", logCronJobPrefix, err)
+		return &runtime.Runtime, err
+	}
+	return &runtime.Runtime, nil
+}
+
+// Get cron job by namespace and name
+func (c *CronJob) Get(namespace, name string) (*types.CronJob, error) {
+
+	log.V(logLevel).Debugf("%s:get:> get cronjob %s:%s", logCronJobPrefix, namespace, name)
+
+	item := new(types.CronJob)
+
+	err := c.storage.Get(c.context, c.storage.Collection().CronJob(), c.storage.Key().CronJob(namespace, name), &item, nil)
+	if err != nil {
+
+		if errors.Storage().IsErrEntityNotFound(err) {
+			log.V(logLevel).Warnf("%s:get:> cronjob %s:%s not found", logCronJobPrefix, namespace, name)
+			return nil, nil
+		}
+
+		log.V(logLevel).Errorf("%s:get:> get cronjob %s:%s err: %s", logCronJobPrefix, namespace, name, err)
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// ListByNamespace returns cron jobs in namespace
+func (c *CronJob) ListByNamespace(namespace string) (*types.CronJobList, error) {
+
+	log.V(logLevel).Debugf("%s:list:> get cron jobs list in namespace %s", logCronJobPrefix, namespace)
+
+	list := types.NewCronJobList()
+	filter := c.storage.Filter().CronJob().ByNamespace(namespace)
+
+	err := c.storage.List(c.context, c.storage.Collection().CronJob(), filter, list, nil)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> get cron jobs list err: %s", logCronJobPrefix, err)
+		return list, err
+	}
+
+	log.V(logLevel).Debugf("%s:list:> get cron jobs list result: %d", logCronJobPrefix, len(list.Items))
+
+	return list, nil
+}
+
+// Create cron job in namespace
+func (c *CronJob) Create(namespace *types.Namespace, cronjob *types.CronJob) (*types.CronJob, error) {
+
+	log.V(logLevel).Debugf("%s:create:> create cronjob %s in namespace %s", logCronJobPrefix, cronjob.Meta.Name, namespace.Meta.Name)
+
+	cronjob.Meta.Namespace = namespace.Meta.Name
+	cronjob.Meta.Created = time.Now()
+	cronjob.Meta.Updated = time.Now()
+	cronjob.Meta.SelfLink = ""
+	cronjob.SelfLink()
+
+	cronjob.Status = types.CronJobStatus{}
+	cronjob.Status.State = types.StateCreated
+
+	if err := c.storage.Put(c.context, c.storage.Collection().CronJob(),
+		c.storage.Key().CronJob(cronjob.Meta.Namespace, cronjob.Meta.Name), cronjob, nil); err != nil {
+		log.V(logLevel).Errorf("%s:create:> insert cronjob err: %v", logCronJobPrefix, err)
+		return nil, err
+	}
+
+	return cronjob, nil
+}
+
+// Update cron job spec
+func (c *CronJob) Update(cronjob *types.CronJob) (*types.CronJob, error) {
+
+	log.V(logLevel).Debugf("%s:update:> update cronjob %s", logCronJobPrefix, cronjob.SelfLink())
+
+	cronjob.Meta.Updated = time.Now()
+
+	if err := c.storage.Set(c.context, c.storage.Collection().CronJob(),
+		c.storage.Key().CronJob(cronjob.Meta.Namespace, cronjob.Meta.Name), cronjob, nil); err != nil {
+		log.V(logLevel).Errorf("%s:update:> update cronjob err: %v", logCronJobPrefix, err)
+		return nil, err
+	}
+
+	return cronjob, nil
+}
+
+// Set cron job status
+func (c *CronJob) Set(cronjob *types.CronJob) error {
+
+	if cronjob == nil {
+		return errors.New(errors.ErrStructArgIsNil)
+	}
+
+	log.V(logLevel).Debugf("%s:setstatus:> set status for cron job %s", logCronJobPrefix, cronjob.SelfLink())
+
+	if err := c.storage.Set(c.context, c.storage.Collection().CronJob(),
+		c.storage.Key().CronJob(cronjob.Meta.Namespace, cronjob.Meta.Name), cronjob, nil); err != nil {
+		log.Errorf("%s:setstatus:> set status for cron job %s err: %v", logCronJobPrefix, cronjob.SelfLink(), err)
+		return err
+	}
+
+	return nil
+}
+
+// Destroy cron job, created jobs are destroyed by controller before cron job removal
+func (c *CronJob) Destroy(cronjob *types.CronJob) (*types.CronJob, error) {
+
+	log.V(logLevel).Debugf("%s:destroy:> destroy cronjob %s", logCronJobPrefix, cronjob.SelfLink())
+
+	cronjob.Spec.State.Destroy = true
+	cronjob.Status.State = types.StateDestroy
+	cronjob.Meta.Updated = time.Now()
+
+	if err := c.storage.Set(c.context, c.storage.Collection().CronJob(),
+		c.storage.Key().CronJob(cronjob.Meta.Namespace, cronjob.Meta.Name), cronjob, nil); err != nil {
+		log.V(logLevel).Errorf("%s:destroy:> destroy cronjob err: %v", logCronJobPrefix, err)
+		return nil, err
+	}
+
+	return cronjob, nil
+}
+
+// Remove cron job from storage
+func (c *CronJob) Remove(cronjob *types.CronJob) error {
+
+	log.V(logLevel).Debugf("%s:remove:> remove cronjob %s", logCronJobPrefix, cronjob.SelfLink())
+
+	if err := c.storage.Del(c.context, c.storage.Collection().CronJob(),
+		c.storage.Key().CronJob(cronjob.Meta.Namespace, cronjob.Meta.Name)); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> remove cronjob err: %v", logCronJobPrefix, err)
+		return err
+	}
+
+	return nil
+}
+
+// Watch cron jobs changes
+func (c *CronJob) Watch(ch chan types.CronJobEvent, rev *int64) error {
+
+	log.V(logLevel).Debugf("%s:watch:> watch cron jobs", logCronJobPrefix)
+
+	done := make(chan bool)
+	watcher := storage.NewWatcher()
+
+	go func() {
+		for {
+			select {
+			case <-c.context.Done():
+				done <- true
+				return
+			case e := <-watcher:
+				if e.Data == nil {
+					continue
+				}
+
+				res := types.CronJobEvent{}
+				res.Action = e.Action
+				res.Name = e.Name
+
+				cronjob := new(types.CronJob)
+
+				if err := json.Unmarshal(e.Data.([]byte), cronjob); err != nil {
+					log.Errorf("%s:> parse data err: %v", logCronJobPrefix, err)
+					continue
+				}
+
+				res.Data = cronjob
+
+				ch <- res
+			}
+		}
+	}()
+
+	opts := storage.GetOpts()
+	opts.Rev = rev
+	if err := c.storage.Watch(c.context, c.storage.Collection().CronJob(), watcher, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewCronJobModel returns new cron job management model
+func NewCronJobModel(ctx context.Context, stg storage.Storage) *CronJob {
+	return &CronJob{ctx, stg}
+}
@@ -91,6 +91,20 @@ func (d *Deployment) Create(service *types.Service) (*types.Deployment, error) {
 
 	deployment.SelfLink()
 
+	dl, err := d.ListByService(service.Meta.Namespace, service.Meta.Name)
+	if err != nil {
+		log.Errorf("%s:create:> distribution create in service: %s err: %v", logDeploymentPrefix, service.Meta.Name, err)
+		return nil, err
+	}
+
+	// deployment version is next to the latest service deployment version
+	for _, i := range dl.Items {
+		if i.Meta.Version > deployment.Meta.Version {
+			deployment.Meta.Version = i.Meta.Version
+		}
+	}
+	deployment.Meta.Version++
+
 	deployment.Spec = types.DeploymentSpec{
 		Replicas: service.Spec.Replicas,
 		Template: service.Spec.Template,
@@ -194,6 +208,75 @@ func (d *Deployment) Destroy(dt *types.Deployment) error {
 	return nil
 }
 
+// Pause deployment rollout
+func (d *Deployment) Pause(dt *types.Deployment) error {
+
+	log.V(logLevel).Debugf("%s:pause:> pause deployment %s", logDeploymentPrefix, dt.Meta.Name)
+
+	dt.Spec.Paused = true
+	dt.Meta.Updated = time.Now()
+
+	if err := d.storage.Set(d.context, d.storage.Collection().Deployment(),
+		d.storage.Key().Deployment(dt.Meta.Namespace, dt.Meta.Service, dt.Meta.Name), dt, nil); err != nil {
+		log.V(logLevel).Debugf("%s:pause:> pause deployment %s err: %v", logDeploymentPrefix, dt.Meta.Name, err)
+		return err
+	}
+
+	return nil
+}
+
+// Resume deployment rollout
+func (d *Deployment) Resume(dt *types.Deployment) error {
+
+	log.V(logLevel).Debugf("%s:resume:> resume deployment %s", logDeploymentPrefix, dt.Meta.Name)
+
+	dt.Spec.Paused = false
+	dt.Meta.Resumed = time.Now()
+	dt.Meta.Updated = time.Now()
+
+	if err := d.storage.Set(d.context, d.storage.Collection().Deployment(),
+		d.storage.Key().Deployment(dt.Meta.Namespace, dt.Meta.Service, dt.Meta.Name), dt, nil); err != nil {
+		log.V(logLevel).Debugf("%s:resume:> resume deployment %s err: %v", logDeploymentPrefix, dt.Meta.Name, err)
+		return err
+	}
+
+	return nil
+}
+
+// Promote canary or blue/green deployment to replace active deployment
+func (d *Deployment) Promote(dt *types.Deployment) error {
+
+	log.V(logLevel).Debugf("%s:promote:> promote deployment %s", logDeploymentPrefix, dt.Meta.Name)
+
+	dt.Spec.Promoted = true
+	dt.Meta.Updated = time.Now()
+
+	if err := d.storage.Set(d.context, d.storage.Collection().Deployment(),
+		d.storage.Key().Deployment(dt.Meta.Namespace, dt.Meta.Service, dt.Meta.Name), dt, nil); err != nil {
+		log.V(logLevel).Debugf("%s:promote:> promote deployment %s err: %v", logDeploymentPrefix, dt.Meta.Name, err)
+		return err
+	}
+
+	return nil
+}
+
+// Abort deployment release
+func (d *Deployment) Abort(dt *types.Deployment) error {
+
+	log.V(logLevel).Debugf("%s:abort:> abort deployment %s", logDeploymentPrefix, dt.Meta.Name)
+
+	dt.Spec.Aborted = true
+	dt.Meta.Updated = time.Now()
+
+	if err := d.storage.Set(d.context, d.storage.Collection().Deployment(),
+		d.storage.Key().Deployment(dt.Meta.Namespace, dt.Meta.Service, dt.Meta.Name), dt, nil); err != nil {
+		log.V(logLevel).Debugf("%s:abort:> abort deployment %s err: %v", logDeploymentPrefix, dt.Meta.Name, err)
+		return err
+	}
+
+	return nil
+}
+
 // Destroy deployment
 func (d *Deployment) Remove(dt *types.Deployment) error {
 
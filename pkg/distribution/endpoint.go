@@ -59,6 +59,20 @@ func (e *Endpoint) Get(namespace, service string) (*types.Endpoint, error) {
 	return item, nil
 }
 
+func (e *Endpoint) List() (*types.EndpointList, error) {
+	log.V(logLevel).Debugf("%s:list:> list endpoints", logEndpointPrefix)
+
+	list := types.NewEndpointList()
+
+	err := e.storage.List(e.context, e.storage.Collection().Endpoint(), types.EmptyString, list, nil)
+	if err != nil {
+		log.Errorf("%s:list:> list endpoints err: %v", logEndpointPrefix, err)
+		return nil, err
+	}
+
+	return list, nil
+}
+
 func (e *Endpoint) ListByNamespace(namespace string) (*types.EndpointList, error) {
 	log.V(logLevel).Debugf("%s:listbynamespace:> in namespace: %s", namespace)
 
@@ -190,6 +204,7 @@ func (e *Endpoint) Watch(ch chan types.EndpointEvent, rev *int64) error {
 	}()
 
 	opts := storage.GetOpts()
+	opts.Rev = rev
 	if err := e.storage.Watch(e.context, e.storage.Collection().Endpoint(), watcher, opts); err != nil {
 		return err
 	}
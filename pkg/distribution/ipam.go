@@ -0,0 +1,80 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package distribution
+
+import (
+	"context"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+)
+
+const (
+	logIPAMPrefix = "distribution:ipam"
+	ipamStateKey  = "ipam:state"
+)
+
+// IPAM - distribution model
+type IPAM struct {
+	context context.Context
+	storage storage.Storage
+}
+
+// Get - get IP address management state
+func (i *IPAM) Get() (*types.IPAM, error) {
+
+	log.V(logLevel).Debugf("%s:get:> get ipam state", logIPAMPrefix)
+
+	state := types.NewIPAM()
+
+	err := i.storage.Get(i.context, i.storage.Collection().System(), ipamStateKey, state, nil)
+	if err != nil {
+		if errors.Storage().IsErrEntityNotFound(err) {
+			log.V(logLevel).Warnf("%s:get:> ipam state not found", logIPAMPrefix)
+			return nil, nil
+		}
+
+		log.V(logLevel).Errorf("%s:get:> get ipam state err: %v", logIPAMPrefix, err)
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// Set - save IP address management state
+func (i *IPAM) Set(state *types.IPAM) error {
+
+	log.V(logLevel).Debugf("%s:set:> set ipam state", logIPAMPrefix)
+
+	opts := storage.GetOpts()
+	opts.Force = true
+
+	if err := i.storage.Set(i.context, i.storage.Collection().System(), ipamStateKey, state, opts); err != nil {
+		log.V(logLevel).Errorf("%s:set:> set ipam state err: %v", logIPAMPrefix, err)
+		return err
+	}
+
+	return nil
+}
+
+func NewIPAMModel(ctx context.Context, stg storage.Storage) *IPAM {
+	return &IPAM{ctx, stg}
+}
@@ -0,0 +1,251 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package distribution
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/storage"
+)
+
+const (
+	logJobPrefix = "distribution:job"
+)
+
+type Job struct {
+	context context.Context
+	storage storage.Storage
+}
+
+func (j *Job) Runtime() (*types.Runtime, error) {
+
+	log.V(logLevel).Debugf("%s:get:> get jobs runtime info", logJobPrefix)
+	runtime, err := j.storage.Info(j.context, j.storage.Collection().Job(), "")
+	if err != nil {
+		log.V(logLevel).Errorf("%s:get:> get runtime info error: %s", logJobPrefix, err)
+		return &runtime.Runtime, err
+	}
+	return &runtime.Runtime, nil
+}
+
+// Get job by namespace and name
+func (j *Job) Get(namespace, name string) (*types.Job, error) {
+
+	log.V(logLevel).Debugf("%s:get:> get job %s:%s", logJobPrefix, namespace, name)
+
+	item := new(types.Job)
+
+	err := j.storage.Get(j.context, j.storage.Collection().Job(), j.storage.Key().Job(namespace, name), &item, nil)
+	if err != nil {
+
+		if errors.Storage().IsErrEntityNotFound(err) {
+			log.V(logLevel).Warnf("%s:get:> job %s:%s not found", logJobPrefix, namespace, name)
+			return nil, nil
+		}
+
+		log.V(logLevel).Errorf("%s:get:> get job %s:%s err: %s", logJobPrefix, namespace, name, err)
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// ListByNamespace returns jobs in namespace
+func (j *Job) ListByNamespace(namespace string) (*types.JobList, error) {
+
+	log.V(logLevel).Debugf("%s:list:> get jobs list in namespace %s", logJobPrefix, namespace)
+
+	list := types.NewJobList()
+	filter := j.storage.Filter().Job().ByNamespace(namespace)
+
+	err := j.storage.List(j.context, j.storage.Collection().Job(), filter, list, nil)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:list:> get jobs list err: %s", logJobPrefix, err)
+		return list, err
+	}
+
+	log.V(logLevel).Debugf("%s:list:> get jobs list result: %d", logJobPrefix, len(list.Items))
+
+	return list, nil
+}
+
+// ListByCronJob returns jobs created by cron job schedule
+func (j *Job) ListByCronJob(namespace, cronjob string) (*types.JobList, error) {
+
+	log.V(logLevel).Debugf("%s:list:> get jobs list of cron job %s:%s", logJobPrefix, namespace, cronjob)
+
+	jl, err := j.ListByNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	list := types.NewJobList()
+	for _, item := range jl.Items {
+		if item.Meta.CronJob == cronjob {
+			list.Items = append(list.Items, item)
+		}
+	}
+
+	return list, nil
+}
+
+// Create job in namespace
+func (j *Job) Create(namespace *types.Namespace, job *types.Job) (*types.Job, error) {
+
+	log.V(logLevel).Debugf("%s:create:> create job %s in namespace %s", logJobPrefix, job.Meta.Name, namespace.Meta.Name)
+
+	job.Meta.Namespace = namespace.Meta.Name
+	job.Meta.Created = time.Now()
+	job.Meta.Updated = time.Now()
+	job.Meta.SelfLink = ""
+	job.SelfLink()
+
+	job.Status = types.JobStatus{}
+	job.Status.State = types.StateCreated
+
+	if err := j.storage.Put(j.context, j.storage.Collection().Job(),
+		j.storage.Key().Job(job.Meta.Namespace, job.Meta.Name), job, nil); err != nil {
+		log.V(logLevel).Errorf("%s:create:> insert job err: %v", logJobPrefix, err)
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Update job spec
+func (j *Job) Update(job *types.Job) (*types.Job, error) {
+
+	log.V(logLevel).Debugf("%s:update:> update job %s", logJobPrefix, job.SelfLink())
+
+	job.Meta.Updated = time.Now()
+
+	if err := j.storage.Set(j.context, j.storage.Collection().Job(),
+		j.storage.Key().Job(job.Meta.Namespace, job.Meta.Name), job, nil); err != nil {
+		log.V(logLevel).Errorf("%s:update:> update job err: %v", logJobPrefix, err)
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Set job status
+func (j *Job) Set(job *types.Job) error {
+
+	if job == nil {
+		return errors.New(errors.ErrStructArgIsNil)
+	}
+
+	log.V(logLevel).Debugf("%s:setstatus:> set status for job %s", logJobPrefix, job.SelfLink())
+
+	if err := j.storage.Set(j.context, j.storage.Collection().Job(),
+		j.storage.Key().Job(job.Meta.Namespace, job.Meta.Name), job, nil); err != nil {
+		log.Errorf("%s:setstatus:> set status for job %s err: %v", logJobPrefix, job.SelfLink(), err)
+		return err
+	}
+
+	return nil
+}
+
+// Destroy job, job pods are removed by controller before job removal
+func (j *Job) Destroy(job *types.Job) (*types.Job, error) {
+
+	log.V(logLevel).Debugf("%s:destroy:> destroy job %s", logJobPrefix, job.SelfLink())
+
+	job.Spec.State.Destroy = true
+	job.Status.State = types.StateDestroy
+	job.Meta.Updated = time.Now()
+
+	if err := j.storage.Set(j.context, j.storage.Collection().Job(),
+		j.storage.Key().Job(job.Meta.Namespace, job.Meta.Name), job, nil); err != nil {
+		log.V(logLevel).Errorf("%s:destroy:> destroy job err: %v", logJobPrefix, err)
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Remove job from storage
+func (j *Job) Remove(job *types.Job) error {
+
+	log.V(logLevel).Debugf("%s:remove:> remove job %s", logJobPrefix, job.SelfLink())
+
+	if err := j.storage.Del(j.context, j.storage.Collection().Job(),
+		j.storage.Key().Job(job.Meta.Namespace, job.Meta.Name)); err != nil {
+		log.V(logLevel).Errorf("%s:remove:> remove job err: %v", logJobPrefix, err)
+		return err
+	}
+
+	return nil
+}
+
+// Watch jobs changes
+func (j *Job) Watch(ch chan types.JobEvent, rev *int64) error {
+
+	log.V(logLevel).Debugf("%s:watch:> watch jobs", logJobPrefix)
+
+	done := make(chan bool)
+	watcher := storage.NewWatcher()
+
+	go func() {
+		for {
+			select {
+			case <-j.context.Done():
+				done <- true
+				return
+			case e := <-watcher:
+				if e.Data == nil {
+					continue
+				}
+
+				res := types.JobEvent{}
+				res.Action = e.Action
+				res.Name = e.Name
+
+				job := new(types.Job)
+
+				if err := json.Unmarshal(e.Data.([]byte), job); err != nil {
+					log.Errorf("%s:> parse data err: %v", logJobPrefix, err)
+					continue
+				}
+
+				res.Data = job
+
+				ch <- res
+			}
+		}
+	}()
+
+	opts := storage.GetOpts()
+	opts.Rev = rev
+	if err := j.storage.Watch(j.context, j.storage.Collection().Job(), watcher, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewJobModel returns new job management model
+func NewJobModel(ctx context.Context, stg storage.Storage) *Job {
+	return &Job{ctx, stg}
+}
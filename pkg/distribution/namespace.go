@@ -32,9 +32,7 @@ import (
 )
 
 const (
-	logNamespacePrefix     = "distribution:namespace"
-	defaultNamespaceRam    = 4096
-	defaultNamespaceRoutes = 1
+	logNamespacePrefix = "distribution:namespace"
 )
 
 type Namespace struct {
@@ -106,13 +104,7 @@ func (n *Namespace) Create(opts *types.NamespaceCreateOptions) (*types.Namespace
 	ns.SelfLink()
 
 	if opts.Quotas != nil {
-		ns.Spec.Quotas.RAM = opts.Quotas.RAM
-		ns.Spec.Quotas.Routes = opts.Quotas.Routes
-		ns.Spec.Quotas.Disabled = opts.Quotas.Disabled
-	} else {
-		ns.Spec.Quotas.Disabled = true
-		ns.Spec.Quotas.RAM = defaultNamespaceRam
-		ns.Spec.Quotas.Routes = defaultNamespaceRoutes
+		ns.Spec.Quotas = opts.Quotas.Quotas()
 	}
 
 	ns.Spec.Domain.Internal = viper.GetString("domain.internal")
@@ -139,9 +131,7 @@ func (n *Namespace) Update(namespace *types.Namespace, opts *types.NamespaceUpda
 	}
 
 	if opts.Quotas != nil {
-		namespace.Spec.Quotas.RAM = opts.Quotas.RAM
-		namespace.Spec.Quotas.Routes = opts.Quotas.Routes
-		namespace.Spec.Quotas.Disabled = opts.Quotas.Disabled
+		namespace.Spec.Quotas = opts.Quotas.Quotas()
 	}
 
 	if opts.Domain != nil {
@@ -161,6 +151,87 @@ func (n *Namespace) Update(namespace *types.Namespace, opts *types.NamespaceUpda
 	return nil
 }
 
+// Usage calculates namespace resources used by services, volumes and routes
+func (n *Namespace) Usage(namespace *types.Namespace) (*types.NamespaceResources, error) {
+
+	log.V(logLevel).Debugf("%s:usage:> calculate namespace %s resources usage", logNamespacePrefix, namespace.Meta.Name)
+
+	var (
+		usage = new(types.NamespaceResources)
+		sm    = NewServiceModel(n.context, n.storage)
+		vm    = NewVolumeModel(n.context, n.storage)
+		rm    = NewRouteModel(n.context, n.storage)
+		jm    = NewJobModel(n.context, n.storage)
+	)
+
+	sl, err := sm.List(namespace.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:usage:> get services err: %v", logNamespacePrefix, err)
+		return nil, err
+	}
+
+	for _, s := range sl.Items {
+		if s.Status.State == types.StateDestroy || s.Status.State == types.StateDestroyed {
+			continue
+		}
+		usage.Sum(s.QuotaUsage())
+	}
+
+	vl, err := vm.ListByNamespace(namespace.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:usage:> get volumes err: %v", logNamespacePrefix, err)
+		return nil, err
+	}
+
+	for _, v := range vl.Items {
+		if v.Status.State == types.StateDestroy || v.Status.State == types.StateDestroyed {
+			continue
+		}
+		usage.Sum(v.QuotaUsage())
+	}
+
+	rl, err := rm.ListByNamespace(namespace.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:usage:> get routes err: %v", logNamespacePrefix, err)
+		return nil, err
+	}
+
+	for _, r := range rl.Items {
+		usage.Sum(r.QuotaUsage())
+	}
+
+	jl, err := jm.ListByNamespace(namespace.Meta.Name)
+	if err != nil {
+		log.V(logLevel).Errorf("%s:usage:> get jobs err: %v", logNamespacePrefix, err)
+		return nil, err
+	}
+
+	for _, j := range jl.Items {
+		usage.Sum(j.QuotaUsage())
+	}
+
+	return usage, nil
+}
+
+// UpdateResources recalculates and stores namespace resources usage
+func (n *Namespace) UpdateResources(namespace *types.Namespace) error {
+
+	usage, err := n.Usage(namespace)
+	if err != nil {
+		return err
+	}
+
+	namespace.Spec.Resources = *usage
+
+	if err := n.storage.Set(n.context, n.storage.Collection().Namespace(),
+		n.storage.Key().Namespace(namespace.Meta.Name), namespace, nil); err != nil {
+		log.V(logLevel).Errorf("%s:update:> namespace resources update err: %v", logNamespacePrefix, err)
+		return err
+	}
+
+	return nil
+}
+
 func (n *Namespace) Remove(namespace *types.Namespace) error {
 
 	log.V(logLevel).Debugf("%s:remove:> remove namespace %s", logNamespacePrefix, namespace.Meta.Name)
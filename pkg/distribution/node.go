@@ -63,6 +63,7 @@ func (n *Node) Put(opts *types.NodeCreateOptions) (*types.Node, error) {
 	ni.Status = opts.Status
 	ni.Status.Online = true
 
+	ni.Spec.Port = opts.Port
 	ni.Spec.Security.TLS = opts.Security.TLS
 
 	if opts.Security.SSL != nil {
@@ -78,10 +78,15 @@ func (p *Pod) Get(namespace, service, deployment, name string) (*types.Pod, erro
 
 // Create new pod
 func (p *Pod) Create(deployment *types.Deployment) (*types.Pod, error) {
+	return p.CreateNamed(deployment, strings.Split(generator.GetUUIDV4(), "-")[4][5:])
+}
+
+// CreateNamed creates deployment pod with provided name, stateful service pods are named by ordinal
+func (p *Pod) CreateNamed(deployment *types.Deployment, name string) (*types.Pod, error) {
 
 	pod := types.NewPod()
 	pod.Meta.SetDefault()
-	pod.Meta.Name = strings.Split(generator.GetUUIDV4(), "-")[4][5:]
+	pod.Meta.Name = name
 	pod.Meta.Deployment = deployment.Meta.Name
 	pod.Meta.Service = deployment.Meta.Service
 	pod.Meta.Namespace = deployment.Meta.Namespace
@@ -114,6 +119,64 @@ func (p *Pod) Create(deployment *types.Deployment) (*types.Pod, error) {
 	return pod, nil
 }
 
+// CreateForJob creates new job pod based on job spec,
+// job pods are linked to reserved service and deployment named as job
+func (p *Pod) CreateForJob(job *types.Job) (*types.Pod, error) {
+
+	pod := types.NewPod()
+	pod.Meta.SetDefault()
+	pod.Meta.Name = strings.Split(generator.GetUUIDV4(), "-")[4][5:]
+	pod.Meta.Deployment = job.Meta.Name
+	pod.Meta.Service = types.JobPodService
+	pod.Meta.Job = job.Meta.Name
+	pod.Meta.Namespace = job.Meta.Namespace
+
+	pod.Status.SetCreated()
+	pod.Status.Steps = make(map[string]types.PodStep)
+	pod.Status.Steps[types.StepInitialized] = types.PodStep{
+		Ready:     true,
+		Timestamp: time.Now().UTC(),
+	}
+
+	for _, c := range job.Spec.Template.Containers {
+		s := *c
+		s.Labels = make(map[string]string)
+		s.Labels[types.ContainerTypeLBC] = pod.SelfLink()
+		s.DNS = types.SpecTemplateContainerDNS{}
+		// job containers should not be restarted by runtime after exit
+		s.RestartPolicy = types.SpecTemplateRestartPolicy{Policy: "no"}
+		pod.Spec.Template.Containers = append(pod.Spec.Template.Containers, &s)
+	}
+
+	for _, s := range job.Spec.Template.Volumes {
+		pod.Spec.Template.Volumes = append(pod.Spec.Template.Volumes, s)
+	}
+
+	pod.Spec.Selector = job.Spec.Selector
+	if err := p.storage.Put(p.context, p.storage.Collection().Pod(),
+		p.storage.Key().Pod(pod.Meta.Namespace, pod.Meta.Service, pod.Meta.Deployment, pod.Meta.Name), pod, nil); err != nil {
+		log.Errorf("%s:create:> insert job pod err %v", logPodPrefix, err)
+		return nil, err
+	}
+
+	return pod, nil
+}
+
+// List returns all pods in cluster
+func (p *Pod) List() (*types.PodList, error) {
+	log.V(logLevel).Debugf("%s:list:> get pod list", logPodPrefix)
+
+	list := types.NewPodList()
+
+	err := p.storage.List(p.context, p.storage.Collection().Pod(), types.EmptyString, list, nil)
+	if err != nil {
+		log.V(logLevel).Debugf("%s:list:> get pod list err: %v", logPodPrefix, err)
+		return nil, err
+	}
+
+	return list, nil
+}
+
 // ListByNamespace returns pod list in selected namespace
 func (p *Pod) ListByNamespace(namespace string) (*types.PodList, error) {
 	log.V(logLevel).Debugf("%s:listbynamespace:> get pod list by namespace %s", logPodPrefix, namespace)
@@ -163,6 +226,12 @@ func (p *Pod) ListByDeployment(namespace, service, deployment string) (*types.Po
 	return list, nil
 }
 
+// ListByJob returns pod list of selected job
+func (p *Pod) ListByJob(namespace, job string) (*types.PodList, error) {
+	log.V(logLevel).Debugf("%s:listbyjob:> get pod list by job %s/%s", logPodPrefix, namespace, job)
+	return p.ListByDeployment(namespace, types.JobPodService, job)
+}
+
 // SetNode - set node info to pod
 func (p *Pod) SetNode(pod *types.Pod, node *types.Node) error {
 	log.V(logLevel).Debugf("%s:setnode:> set node for pod: %s", logPodPrefix, pod.Meta.Name)
@@ -0,0 +1,226 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package types
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// AccountKindUser - account used by people
+	AccountKindUser = "user"
+	// AccountKindService - account used by automation
+	AccountKindService = "service"
+	// AccountKindNode - node agent identity
+	AccountKindNode = "node"
+	// AccountKindIngress - ingress agent identity
+	AccountKindIngress = "ingress"
+	// AccountKindDiscovery - discovery agent identity
+	AccountKindDiscovery = "discovery"
+
+	RoleViewer    = "viewer"
+	RoleDeveloper = "developer"
+	RoleAdmin     = "admin"
+
+	// RoleBindingCluster - role binding applied to all namespaces and cluster resources
+	RoleBindingCluster = "*"
+
+	AccessRead  = "read"
+	AccessWrite = "write"
+	AccessAdmin = "admin"
+
+	// AccountRoot - identity of requests authenticated by cluster token
+	AccountRoot = "root"
+
+	accountTokenSeparator = ":"
+)
+
+// swagger:ignore
+type Account struct {
+	Runtime
+	Meta AccountMeta `json:"meta"`
+	Spec AccountSpec `json:"spec"`
+}
+
+// swagger:ignore
+type AccountList struct {
+	Runtime
+	Items []*Account
+}
+
+// swagger:ignore
+type AccountMeta struct {
+	Meta
+}
+
+// swagger:ignore
+type AccountSpec struct {
+	// Account kind: user, service or agent kind
+	Kind string `json:"kind"`
+	// Roles mapped by namespace name, RoleBindingCluster binds role to all namespaces
+	Roles map[string]string `json:"roles"`
+	// Issued tokens mapped by token id
+	Tokens map[string]*AccountToken `json:"tokens"`
+}
+
+// swagger:ignore
+type AccountToken struct {
+	ID string `json:"id"`
+	// Hash of token secret, secret itself is never stored
+	Hash    string    `json:"hash"`
+	Created time.Time `json:"created"`
+}
+
+func (a *Account) SelfLink() string {
+	if a.Meta.SelfLink == "" {
+		a.Meta.SelfLink = a.Meta.Name
+	}
+	return a.Meta.SelfLink
+}
+
+// Agent checks if account is cluster agent identity
+func (a *Account) Agent() bool {
+	switch a.Spec.Kind {
+	case AccountKindNode, AccountKindIngress, AccountKindDiscovery:
+		return true
+	}
+	return false
+}
+
+// Allowed checks if account role in namespace grants requested access,
+// empty namespace means cluster resources and requires cluster role binding
+func (a *Account) Allowed(namespace, access string) bool {
+
+	if a.Agent() {
+		return false
+	}
+
+	role, ok := a.Spec.Roles[RoleBindingCluster]
+	if namespace != EmptyString {
+		if r, ok := a.Spec.Roles[namespace]; ok && RoleAllowed(r, access) {
+			return true
+		}
+	}
+
+	return ok && RoleAllowed(role, access)
+}
+
+// IssueToken creates new account token and returns token value,
+// token value can not be restored after issue
+func (a *Account) IssueToken() (*AccountToken, string, error) {
+
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, EmptyString, err
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, EmptyString, err
+	}
+
+	if a.Spec.Tokens == nil {
+		a.Spec.Tokens = make(map[string]*AccountToken)
+	}
+
+	t := &AccountToken{
+		ID:      id,
+		Hash:    tokenHash(secret),
+		Created: time.Now().UTC(),
+	}
+	a.Spec.Tokens[id] = t
+
+	return t, strings.Join([]string{a.Meta.Name, id, secret}, accountTokenSeparator), nil
+}
+
+// RevokeToken removes token by id
+func (a *Account) RevokeToken(id string) bool {
+	if _, ok := a.Spec.Tokens[id]; !ok {
+		return false
+	}
+	delete(a.Spec.Tokens, id)
+	return true
+}
+
+// VerifyToken checks token issued for account
+func (a *Account) VerifyToken(id, secret string) bool {
+
+	t, ok := a.Spec.Tokens[id]
+	if !ok {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(t.Hash), []byte(tokenHash(secret))) == 1
+}
+
+// ParseAccountToken splits token value to account name, token id and secret
+func ParseAccountToken(token string) (string, string, string, error) {
+	parts := strings.SplitN(token, accountTokenSeparator, 3)
+	if len(parts) != 3 || parts[0] == EmptyString || parts[1] == EmptyString || parts[2] == EmptyString {
+		return EmptyString, EmptyString, EmptyString, fmt.Errorf("invalid token format")
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// RoleAllowed checks if role grants requested access
+func RoleAllowed(role, access string) bool {
+	switch access {
+	case AccessRead:
+		return role == RoleViewer || role == RoleDeveloper || role == RoleAdmin
+	case AccessWrite:
+		return role == RoleDeveloper || role == RoleAdmin
+	case AccessAdmin:
+		return role == RoleAdmin
+	}
+	return false
+}
+
+// NewRootAccount returns identity with cluster admin role
+func NewRootAccount() *Account {
+	a := new(Account)
+	a.Meta.Name = AccountRoot
+	a.Spec.Kind = AccountKindUser
+	a.Spec.Roles = map[string]string{RoleBindingCluster: RoleAdmin}
+	return a
+}
+
+func NewAccountList() *AccountList {
+	dm := new(AccountList)
+	dm.Items = make([]*Account, 0)
+	return dm
+}
+
+func tokenHash(secret string) string {
+	h := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(h[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return EmptyString, err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,161 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// AutoscalerDefaultUpscaleWindow - period in seconds during which the lowest upscale recommendation is used
+	AutoscalerDefaultUpscaleWindow = 60
+	// AutoscalerDefaultDownscaleWindow - period in seconds during which the highest downscale recommendation is used
+	AutoscalerDefaultDownscaleWindow = 300
+	// AutoscalerTolerance - utilisation deviation from target in percent ignored by autoscaler
+	AutoscalerTolerance = 10
+
+	AutoscalerMessageNoMetrics     = "usage metrics are not available"
+	AutoscalerMessageNoRequests    = "resource requests are not set"
+	AutoscalerMessageServiceBusy   = "service is not ready"
+	AutoscalerMessageLimitedMax    = "desired replicas limited by max replicas"
+	AutoscalerMessageLimitedMin    = "desired replicas limited by min replicas"
+	AutoscalerMessageStabilization = "recommendation held by stabilisation window"
+)
+
+// swagger:ignore
+// swagger:model types_autoscaler
+type Autoscaler struct {
+	Runtime
+	Meta   AutoscalerMeta   `json:"meta" yaml:"meta"`
+	Spec   AutoscalerSpec   `json:"spec" yaml:"spec"`
+	Status AutoscalerStatus `json:"status" yaml:"status"`
+}
+
+// swagger:ignore
+type AutoscalerList struct {
+	Runtime
+	Items []*Autoscaler
+}
+
+// swagger:ignore
+// swagger:model types_autoscaler_meta
+type AutoscalerMeta struct {
+	Meta      `yaml:",inline"`
+	Namespace string `json:"namespace" yaml:"namespace"`
+	Service   string `json:"service" yaml:"service"`
+}
+
+// AutoscalerSpec - replicas range and target utilisation of service pods resource requests
+// swagger:model types_autoscaler_spec
+type AutoscalerSpec struct {
+	// Autoscaling is suspended
+	Disabled bool `json:"disabled" yaml:"disabled"`
+	// Minimal service replicas
+	MinReplicas int `json:"min_replicas" yaml:"min_replicas"`
+	// Maximal service replicas
+	MaxReplicas int `json:"max_replicas" yaml:"max_replicas"`
+	// Target average utilisation in percent of requested resources
+	Target AutoscalerTarget `json:"target" yaml:"target"`
+	// Stabilisation windows
+	Window AutoscalerWindow `json:"window" yaml:"window"`
+}
+
+// AutoscalerTarget - target utilisation percent, zero value disables resource check
+// swagger:model types_autoscaler_target
+type AutoscalerTarget struct {
+	CPU int `json:"cpu" yaml:"cpu"`
+	RAM int `json:"ram" yaml:"ram"`
+}
+
+// AutoscalerWindow - stabilisation windows in seconds
+// swagger:model types_autoscaler_window
+type AutoscalerWindow struct {
+	Upscale   int `json:"upscale" yaml:"upscale"`
+	Downscale int `json:"downscale" yaml:"downscale"`
+}
+
+// swagger:model types_autoscaler_status
+type AutoscalerStatus struct {
+	// Current service replicas
+	Replicas int `json:"replicas" yaml:"replicas"`
+	// Replicas desired by autoscaler
+	Desired int `json:"desired" yaml:"desired"`
+	// Current average utilisation in percent of requested resources
+	Usage AutoscalerUsage `json:"usage" yaml:"usage"`
+	// Message describes why desired replicas are not applied
+	Message string `json:"message" yaml:"message"`
+	// Last time service was scaled by autoscaler
+	Scaled time.Time `json:"scaled" yaml:"scaled"`
+}
+
+// swagger:model types_autoscaler_usage
+type AutoscalerUsage struct {
+	CPU int `json:"cpu" yaml:"cpu"`
+	RAM int `json:"ram" yaml:"ram"`
+}
+
+// AutoscalerRecommendation - replicas calculated by autoscaler at time
+type AutoscalerRecommendation struct {
+	Replicas int
+	Time     time.Time
+}
+
+func (a *Autoscaler) SelfLink() string {
+	if a.Meta.SelfLink == "" {
+		a.Meta.SelfLink = a.CreateSelfLink(a.Meta.Namespace, a.Meta.Service)
+	}
+	return a.Meta.SelfLink
+}
+
+func (a *Autoscaler) CreateSelfLink(namespace, service string) string {
+	return fmt.Sprintf("%s:%s", namespace, service)
+}
+
+// ServiceLink returns self link of autoscaled service
+func (a *Autoscaler) ServiceLink() string {
+	return fmt.Sprintf("%s:%s", a.Meta.Namespace, a.Meta.Service)
+}
+
+// UpscaleWindow returns upscale stabilisation window, negative value disables it
+func (s AutoscalerSpec) UpscaleWindow() time.Duration {
+	return autoscalerWindow(s.Window.Upscale, AutoscalerDefaultUpscaleWindow)
+}
+
+// DownscaleWindow returns downscale stabilisation window, negative value disables it
+func (s AutoscalerSpec) DownscaleWindow() time.Duration {
+	return autoscalerWindow(s.Window.Downscale, AutoscalerDefaultDownscaleWindow)
+}
+
+func autoscalerWindow(window, def int) time.Duration {
+	switch true {
+	case window < 0:
+		return 0
+	case window == 0:
+		return time.Duration(def) * time.Second
+	default:
+		return time.Duration(window) * time.Second
+	}
+}
+
+func NewAutoscalerList() *AutoscalerList {
+	dm := new(AutoscalerList)
+	dm.Items = make([]*Autoscaler, 0)
+	return dm
+}
@@ -0,0 +1,107 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package types
+
+import (
+	"crypto/subtle"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultJoinTokenTTL - lifetime of join token if not provided
+	DefaultJoinTokenTTL = time.Hour
+)
+
+// CertificateAuthority - cluster authority used to sign agents certificates
+// swagger:ignore
+type CertificateAuthority struct {
+	// PEM-encoded authority certificate
+	Certificate []byte `json:"certificate"`
+	// PEM-encoded authority private key
+	Key []byte `json:"key"`
+}
+
+// JoinToken - one-time token used by agent to receive client certificate
+// swagger:ignore
+type JoinToken struct {
+	ID string `json:"id"`
+	// Hash of token secret, secret itself is never stored
+	Hash string `json:"hash"`
+	// Agent account kind allowed to join with token
+	Kind string `json:"kind"`
+	// Agent account name allowed to join with token
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires"`
+}
+
+// Expired checks if token can not be used anymore
+func (j *JoinToken) Expired() bool {
+	return time.Now().After(j.Expires)
+}
+
+// Verify checks token secret
+func (j *JoinToken) Verify(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(j.Hash), []byte(tokenHash(secret))) == 1
+}
+
+// Allowed checks that token is issued for agent
+func (j *JoinToken) Allowed(kind, name string) bool {
+	return j.Kind == kind && j.Name == name
+}
+
+// NewJoinToken creates join token for agent kind and name and returns token value,
+// token value can not be restored after creation
+func NewJoinToken(kind, name string, ttl time.Duration) (*JoinToken, string, error) {
+
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, EmptyString, err
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, EmptyString, err
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultJoinTokenTTL
+	}
+
+	j := &JoinToken{
+		ID:      id,
+		Hash:    tokenHash(secret),
+		Kind:    kind,
+		Name:    name,
+		Created: time.Now().UTC(),
+	}
+	j.Expires = j.Created.Add(ttl)
+
+	return j, strings.Join([]string{id, secret}, accountTokenSeparator), nil
+}
+
+// ParseJoinToken splits token value to token id and secret
+func ParseJoinToken(token string) (string, string, bool) {
+	parts := strings.SplitN(token, accountTokenSeparator, 2)
+	if len(parts) != 2 || parts[0] == EmptyString || parts[1] == EmptyString {
+		return EmptyString, EmptyString, false
+	}
+	return parts[0], parts[1], true
+}
@@ -21,6 +21,7 @@ package types
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 
 	"time"
 
@@ -30,6 +31,8 @@ import (
 const (
 	ContainerTypeLBC = "LBC"
 	ContainerTypeLBR = "LBR"
+	// ContainerRoleLBC - label with pod container role
+	ContainerRoleLBC = "LBC_ROLE"
 )
 
 type Container struct {
@@ -228,6 +231,48 @@ type ContainerLinkSpec struct {
 	Alias string `json:"alias"`
 }
 
+// ContainerStats - container resources usage sample
+type ContainerStats struct {
+	// CPU usage in millicores
+	CPU int64 `json:"cpu"`
+	// Memory usage in bytes without page cache
+	Memory int64 `json:"memory"`
+	// Network received bytes total
+	NetworkRX int64 `json:"network_rx"`
+	// Network transmitted bytes total
+	NetworkTX int64 `json:"network_tx"`
+	// Disk read bytes total
+	DiskRead int64 `json:"disk_read"`
+	// Disk written bytes total
+	DiskWrite int64 `json:"disk_write"`
+	// Sample time
+	Read time.Time `json:"read"`
+}
+
+// ContainerExecOptions - options of command executed in container
+type ContainerExecOptions struct {
+	// Command with arguments
+	Command []string `json:"command"`
+	// Allocate terminal for command
+	TTY bool `json:"tty"`
+}
+
+// ContainerStreams - streams attached to container process,
+// nil streams are not attached
+type ContainerStreams struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// Terminal size changes
+	Resize <-chan ContainerTerminalSize
+}
+
+// ContainerTerminalSize - container process terminal size
+type ContainerTerminalSize struct {
+	Width  uint `json:"width"`
+	Height uint `json:"height"`
+}
+
 type ContainerStatusInfo struct {
 	// Container ID on host
 	ID string `json:"cid"`
@@ -0,0 +1,141 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// CronJobConcurrencyAllow - jobs are created by schedule even if previous jobs are still running
+	CronJobConcurrencyAllow = "allow"
+	// CronJobConcurrencyForbid - schedule is skipped while previous job is running
+	CronJobConcurrencyForbid = "forbid"
+	// CronJobConcurrencyReplace - running jobs are destroyed and replaced by new one
+	CronJobConcurrencyReplace = "replace"
+
+	// CronJobDefaultSucceededHistory - successfully completed jobs count kept for inspection
+	CronJobDefaultSucceededHistory = 3
+	// CronJobDefaultFailedHistory - failed jobs count kept for inspection
+	CronJobDefaultFailedHistory = 1
+
+	// CronJobMaxMissed - max missed schedules count looked up after controller downtime
+	CronJobMaxMissed = 100
+	// CronJobNameMaxLength - cron job name length, jobs names are suffixed with schedule unix time
+	CronJobNameMaxLength = 52
+
+	CronJobMessageMissed          = "missed schedule started later than starting deadline"
+	CronJobMessageTooManyMissed   = "too many missed schedules, set starting deadline to start missed schedules"
+	CronJobMessageInvalidSchedule = "invalid schedule"
+)
+
+// swagger:ignore
+// swagger:model types_cronjob
+type CronJob struct {
+	Runtime
+	Meta   CronJobMeta   `json:"meta" yaml:"meta"`
+	Spec   CronJobSpec   `json:"spec" yaml:"spec"`
+	Status CronJobStatus `json:"status" yaml:"status"`
+}
+
+// swagger:ignore
+type CronJobList struct {
+	Runtime
+	Items []*CronJob
+}
+
+// swagger:ignore
+// swagger:model types_cronjob_meta
+type CronJobMeta struct {
+	Meta      `yaml:",inline"`
+	Namespace string `json:"namespace" yaml:"namespace"`
+	SelfLink  string `json:"self_link" yaml:"self_link"`
+}
+
+// CronJobSpec - jobs schedule with concurrency policy and finished jobs history limits
+// swagger:model types_cronjob_spec
+type CronJobSpec struct {
+	State SpecState `json:"state" yaml:"state"`
+	// Cron expression, standard 5 fields format or @hourly, @daily, @weekly, @monthly, @yearly descriptors
+	Schedule string `json:"schedule" yaml:"schedule"`
+	// Time zone name schedule is evaluated in, UTC is used by default
+	TimeZone string `json:"timezone" yaml:"timezone"`
+	// Concurrency policy: allow, forbid or replace
+	Concurrency string `json:"concurrency" yaml:"concurrency"`
+	// Period in seconds missed schedule may be started after, zero value allows to start missed schedule at any time
+	StartingDeadline int `json:"starting_deadline" yaml:"starting_deadline"`
+	// Finished jobs count kept for inspection
+	History CronJobHistory `json:"history" yaml:"history"`
+	// Job created by schedule
+	Job JobSpec `json:"job" yaml:"job"`
+}
+
+// swagger:model types_cronjob_history
+type CronJobHistory struct {
+	Succeeded int `json:"succeeded" yaml:"succeeded"`
+	Failed    int `json:"failed" yaml:"failed"`
+}
+
+// swagger:model types_cronjob_status
+type CronJobStatus struct {
+	State   string `json:"state" yaml:"state"`
+	Message string `json:"message" yaml:"message"`
+	// Names of running jobs
+	Active []string `json:"active" yaml:"active"`
+	// Last time job was scheduled
+	LastSchedule time.Time `json:"last_schedule" yaml:"last_schedule"`
+}
+
+func (c *CronJob) SelfLink() string {
+	if c.Meta.SelfLink == "" {
+		c.Meta.SelfLink = c.CreateSelfLink(c.Meta.Namespace, c.Meta.Name)
+	}
+	return c.Meta.SelfLink
+}
+
+func (c *CronJob) CreateSelfLink(namespace, name string) string {
+	return fmt.Sprintf("%s:%s", namespace, name)
+}
+
+// Location returns time zone location schedule is evaluated in
+func (c *CronJob) Location() (*time.Location, error) {
+	if c.Spec.TimeZone == EmptyString {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(c.Spec.TimeZone)
+}
+
+// JobName returns name of job created by provided schedule time
+func (c *CronJob) JobName(t time.Time) string {
+	return fmt.Sprintf("%s-%d", c.Meta.Name, t.Unix())
+}
+
+func (s *CronJobSpec) SetDefault() {
+	s.Concurrency = CronJobConcurrencyAllow
+	s.History.Succeeded = CronJobDefaultSucceededHistory
+	s.History.Failed = CronJobDefaultFailedHistory
+	s.Job.SetDefault()
+}
+
+func NewCronJobList() *CronJobList {
+	dm := new(CronJobList)
+	dm.Items = make([]*CronJob, 0)
+	return dm
+}
@@ -18,7 +18,16 @@
 
 package types
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
+
+// DeploymentRolloutDeadlineExceeded is a status message of deployment which rolling update failed by deadline
+const DeploymentRolloutDeadlineExceeded = "rollout deadline exceeded"
+
+// DeploymentRolloutAborted is a status message of deployment which release was aborted
+const DeploymentRolloutAborted = "rollout aborted"
 
 type DeploymentMap struct {
 	Runtime
@@ -52,6 +61,8 @@ type DeploymentMeta struct {
 	Endpoint string `json:"endpoint"`
 	// Self Link
 	Status string `json:"status"`
+	// Last rollout resume time
+	Resumed time.Time `json:"resumed"`
 }
 
 type DeploymentSpec struct {
@@ -59,6 +70,12 @@ type DeploymentSpec struct {
 	State    SpecState    `json:"state"`
 	Selector SpecSelector `json:"selector"`
 	Template SpecTemplate `json:"template"`
+	// Rollout is paused
+	Paused bool `json:"paused"`
+	// Canary or blue/green deployment is promoted to replace active deployment
+	Promoted bool `json:"promoted"`
+	// Deployment release is aborted
+	Aborted bool `json:"aborted"`
 }
 
 type DeploymentStatus struct {
@@ -73,12 +73,24 @@ type EndpointSpec struct {
 	Strategy  EndpointSpecStrategy `json:"strategy"`
 	Policy    string               `json:"policy"`
 	Upstreams []string             `json:"upstreams"`
+	// Upstreams balancing weights, upstream weight is 1 if not set
+	Weights map[string]int `json:"weights,omitempty"`
+	// Upstreams addresses by pod name, published for stateful service pods
+	Hosts map[string]string `json:"hosts,omitempty"`
 }
 
 type EndpointState struct {
 	EndpointSpec
 }
 
+// Weight returns balancing weight of upstream
+func (e EndpointSpec) Weight(upstream string) int {
+	if w, ok := e.Weights[upstream]; ok && w >= 0 {
+		return w
+	}
+	return 1
+}
+
 // EndpointSpecStrategy describes route and bind
 // swagger:model types_endpoint_spec_strategy
 type EndpointSpecStrategy struct {
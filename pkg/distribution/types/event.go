@@ -76,6 +76,21 @@ type ConfigEvent struct {
 	Data *Config
 }
 
+type AutoscalerEvent struct {
+	event
+	Data *Autoscaler
+}
+
+type JobEvent struct {
+	event
+	Data *Job
+}
+
+type CronJobEvent struct {
+	event
+	Data *CronJob
+}
+
 
 type RouteEvent struct {
 	event
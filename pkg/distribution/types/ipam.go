@@ -0,0 +1,75 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package types
+
+import "time"
+
+const (
+	// IPAMPoolDefault - pool used when no pool is requested
+	IPAMPoolDefault = "default"
+
+	IPAMFamilyIPv4 = "ipv4"
+	IPAMFamilyIPv6 = "ipv6"
+)
+
+// IPAM - IP address management state
+// swagger:ignore
+type IPAM struct {
+	// Pools by name
+	Pools map[string]*IPAMPool `json:"pools"`
+	// Leases by IP address
+	Leases map[string]*IPAMLease `json:"leases"`
+}
+
+// IPAMPool - named range of IP addresses
+// swagger:ignore
+type IPAMPool struct {
+	Name   string         `json:"name"`
+	CIDR   string         `json:"cidr"`
+	Family string         `json:"family"`
+	Status IPAMPoolStatus `json:"status"`
+}
+
+// IPAMPoolStatus - pool addresses usage
+// swagger:ignore
+type IPAMPoolStatus struct {
+	// Total usable addresses, limited by max uint64 for large IPv6 pools
+	Total uint64 `json:"total"`
+	// Available addresses
+	Available uint64 `json:"available"`
+	// Reserved addresses
+	Reserved uint64 `json:"reserved"`
+}
+
+// IPAMLease - IP address leased from pool
+// swagger:ignore
+type IPAMLease struct {
+	IP   string `json:"ip"`
+	Pool string `json:"pool"`
+	// Owner is a self link of object used the address
+	Owner   string    `json:"owner"`
+	Created time.Time `json:"created"`
+}
+
+func NewIPAM() *IPAM {
+	i := new(IPAM)
+	i.Pools = make(map[string]*IPAMPool)
+	i.Leases = make(map[string]*IPAMLease)
+	return i
+}
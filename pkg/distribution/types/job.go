@@ -0,0 +1,174 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// JobPodService - reserved service name in job pods links, service names are at least 4 symbols long
+	JobPodService = "job"
+
+	// JobDefaultBackoffLimit - failed pods count allowed before job is marked as failed
+	JobDefaultBackoffLimit = 6
+	// JobBackoffDelay - delay before job pod is recreated after first failure, doubled on each next failure
+	JobBackoffDelay = 10 * time.Second
+	// JobBackoffMaxDelay - max delay before job pod is recreated after failure
+	JobBackoffMaxDelay = 6 * time.Minute
+
+	JobStateCompleted = "completed"
+	JobStateFailed    = "failed"
+
+	JobMessageBackoffLimit = "job has reached the specified backoff limit"
+	JobMessageDeadline     = "job was active longer than specified deadline"
+)
+
+// swagger:ignore
+// swagger:model types_job
+type Job struct {
+	Runtime
+	Meta   JobMeta   `json:"meta" yaml:"meta"`
+	Spec   JobSpec   `json:"spec" yaml:"spec"`
+	Status JobStatus `json:"status" yaml:"status"`
+}
+
+// swagger:ignore
+type JobList struct {
+	Runtime
+	Items []*Job
+}
+
+// swagger:ignore
+// swagger:model types_job_meta
+type JobMeta struct {
+	Meta      `yaml:",inline"`
+	Namespace string `json:"namespace" yaml:"namespace"`
+	SelfLink  string `json:"self_link" yaml:"self_link"`
+	// Cron job name if job was created by schedule
+	CronJob string `json:"cronjob,omitempty" yaml:"cronjob,omitempty"`
+}
+
+// JobSpec - run to completion pods template with completions, retries and deadline options
+// swagger:model types_job_spec
+type JobSpec struct {
+	State SpecState `json:"state" yaml:"state"`
+	// Pods count which should complete successfully
+	Completions int `json:"completions" yaml:"completions"`
+	// Max pods count running at the same time
+	Parallelism int `json:"parallelism" yaml:"parallelism"`
+	// Failed pods count allowed before job is marked as failed
+	BackoffLimit int `json:"backoff_limit" yaml:"backoff_limit"`
+	// Period in seconds job may be active before it is marked as failed, zero value disables deadline
+	ActiveDeadline int `json:"active_deadline" yaml:"active_deadline"`
+
+	Selector SpecSelector `json:"selector" yaml:"selector"`
+	Template SpecTemplate `json:"template" yaml:"template"`
+}
+
+// swagger:model types_job_status
+type JobStatus struct {
+	State   string `json:"state" yaml:"state"`
+	Message string `json:"message" yaml:"message"`
+	// Running pods count
+	Active int `json:"active" yaml:"active"`
+	// Successfully completed pods count
+	Succeeded int `json:"succeeded" yaml:"succeeded"`
+	// Failed pods count
+	Failed int `json:"failed" yaml:"failed"`
+	// Time when first job pod was created
+	Started time.Time `json:"started" yaml:"started"`
+	// Time when job was completed or failed
+	Finished time.Time `json:"finished" yaml:"finished"`
+}
+
+func (j *Job) SelfLink() string {
+	if j.Meta.SelfLink == "" {
+		j.Meta.SelfLink = j.CreateSelfLink(j.Meta.Namespace, j.Meta.Name)
+	}
+	return j.Meta.SelfLink
+}
+
+func (j *Job) CreateSelfLink(namespace, name string) string {
+	return fmt.Sprintf("%s:%s", namespace, name)
+}
+
+// CronJobLink returns self link of cron job which created the job
+func (j *Job) CronJobLink() string {
+	return fmt.Sprintf("%s:%s", j.Meta.Namespace, j.Meta.CronJob)
+}
+
+// Finished returns true if job is completed or failed
+func (j *Job) Finished() bool {
+	return j.Status.State == JobStateCompleted || j.Status.State == JobStateFailed
+}
+
+// QuotaUsage returns namespace resources used by running job pods
+func (j *Job) QuotaUsage() NamespaceResources {
+
+	if j.Finished() || j.Spec.State.Destroy {
+		return NamespaceResources{}
+	}
+
+	r := j.Spec.Template.ResourceRequest()
+	return NamespaceResources{
+		RAM:  r.RAM * int64(j.Spec.Parallelism),
+		CPU:  r.CPU * int64(j.Spec.Parallelism),
+		Pods: j.Spec.Parallelism,
+	}
+}
+
+// Deadline returns time when active job should be failed, zero time is returned if deadline is disabled
+func (j *Job) Deadline() time.Time {
+	if j.Spec.ActiveDeadline <= 0 || j.Status.Started.IsZero() {
+		return time.Time{}
+	}
+	return j.Status.Started.Add(time.Duration(j.Spec.ActiveDeadline) * time.Second)
+}
+
+// BackoffDelay returns delay before next pod creation after provided failures count
+func (j *Job) BackoffDelay(failed int) time.Duration {
+
+	if failed <= 0 {
+		return 0
+	}
+
+	delay := JobBackoffDelay
+	for i := 1; i < failed; i++ {
+		delay *= 2
+		if delay >= JobBackoffMaxDelay {
+			return JobBackoffMaxDelay
+		}
+	}
+
+	return delay
+}
+
+func (s *JobSpec) SetDefault() {
+	s.Completions = 1
+	s.Parallelism = 1
+	s.BackoffLimit = JobDefaultBackoffLimit
+}
+
+func NewJobList() *JobList {
+	dm := new(JobList)
+	dm.Items = make([]*Job, 0)
+	return dm
+}
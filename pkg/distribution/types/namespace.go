@@ -76,16 +76,27 @@ type NamespaceDomain struct {
 }
 
 // swagger:ignore
+// NamespaceQuotas - namespace resources limits, zero value means unlimited
+// disabled namespace does not accept new workloads
 type NamespaceQuotas struct {
 	RAM      int64 `json:"ram"`
+	CPU      int64 `json:"cpu"`
+	Pods     int   `json:"pods"`
+	Volumes  int   `json:"volumes"`
+	Storage  int64 `json:"storage"`
 	Routes   int   `json:"routes"`
 	Disabled bool  `json:"disabled"`
 }
 
 // swagger:ignore
+// NamespaceResources - namespace resources used by services, volumes and routes
 type NamespaceResources struct {
-	RAM    int64 `json:"ram"`
-	Routes int   `json:"routes"`
+	RAM     int64 `json:"ram"`
+	CPU     int64 `json:"cpu"`
+	Pods    int   `json:"pods"`
+	Volumes int   `json:"volumes"`
+	Storage int64 `json:"storage"`
+	Routes  int   `json:"routes"`
 }
 
 func (n *Namespace) SelfLink() string {
@@ -138,9 +149,75 @@ type NamespaceRemoveOptions struct {
 type NamespaceQuotasOptions struct {
 	Disabled bool  `json:"disabled"`
 	RAM      int64 `json:"ram"`
+	CPU      int64 `json:"cpu"`
+	Pods     int   `json:"pods"`
+	Volumes  int   `json:"volumes"`
+	Storage  int64 `json:"storage"`
 	Routes   int   `json:"routes"`
 }
 
+// Quotas converts quotas options to namespace quotas
+func (o *NamespaceQuotasOptions) Quotas() NamespaceQuotas {
+	return NamespaceQuotas{
+		RAM:      o.RAM,
+		CPU:      o.CPU,
+		Pods:     o.Pods,
+		Volumes:  o.Volumes,
+		Storage:  o.Storage,
+		Routes:   o.Routes,
+		Disabled: o.Disabled,
+	}
+}
+
+// Sum adds provided resources usage
+func (r *NamespaceResources) Sum(o NamespaceResources) {
+	r.RAM += o.RAM
+	r.CPU += o.CPU
+	r.Pods += o.Pods
+	r.Volumes += o.Volumes
+	r.Storage += o.Storage
+	r.Routes += o.Routes
+}
+
+// Sub subtracts provided resources usage
+func (r *NamespaceResources) Sub(o NamespaceResources) {
+	r.RAM -= o.RAM
+	r.CPU -= o.CPU
+	r.Pods -= o.Pods
+	r.Volumes -= o.Volumes
+	r.Storage -= o.Storage
+	r.Routes -= o.Routes
+}
+
+// Admit checks that namespace resources usage can be changed from prev to next.
+// Only growing resources are checked, so workloads can always be scaled down.
+func (q NamespaceQuotas) Admit(used, prev, next NamespaceResources) error {
+
+	if q.Disabled && (next.Pods > prev.Pods || next.Volumes > prev.Volumes || next.Routes > prev.Routes) {
+		return fmt.Errorf("Namespace is disabled")
+	}
+
+	used.Sub(prev)
+	used.Sum(next)
+
+	switch {
+	case q.RAM > 0 && next.RAM > prev.RAM && used.RAM > q.RAM:
+		return fmt.Errorf("RAM quota exceeded")
+	case q.CPU > 0 && next.CPU > prev.CPU && used.CPU > q.CPU:
+		return fmt.Errorf("CPU quota exceeded")
+	case q.Pods > 0 && next.Pods > prev.Pods && used.Pods > q.Pods:
+		return fmt.Errorf("Pods quota exceeded")
+	case q.Volumes > 0 && next.Volumes > prev.Volumes && used.Volumes > q.Volumes:
+		return fmt.Errorf("Volumes quota exceeded")
+	case q.Storage > 0 && next.Storage > prev.Storage && used.Storage > q.Storage:
+		return fmt.Errorf("Storage quota exceeded")
+	case q.Routes > 0 && next.Routes > prev.Routes && used.Routes > q.Routes:
+		return fmt.Errorf("Routes quota exceeded")
+	}
+
+	return nil
+}
+
 func NewNamespaceList() *NamespaceList {
 	dm := new(NamespaceList)
 	dm.Items = make([]*Namespace, 0)
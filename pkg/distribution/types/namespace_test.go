@@ -0,0 +1,112 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceQuotas_Admit(t *testing.T) {
+
+	var tests = []struct {
+		name   string
+		quotas NamespaceQuotas
+		used   NamespaceResources
+		prev   NamespaceResources
+		next   NamespaceResources
+		err    string
+	}{
+		{
+			name:   "unlimited quotas",
+			quotas: NamespaceQuotas{},
+			used:   NamespaceResources{RAM: 4096, CPU: 4096, Pods: 10},
+			next:   NamespaceResources{RAM: 512, CPU: 512, Pods: 2},
+		},
+		{
+			name:   "fits quotas",
+			quotas: NamespaceQuotas{RAM: 1024, CPU: 1024, Pods: 4},
+			used:   NamespaceResources{RAM: 512, CPU: 512, Pods: 2},
+			next:   NamespaceResources{RAM: 512, CPU: 512, Pods: 2},
+		},
+		{
+			name:   "ram quota exceeded",
+			quotas: NamespaceQuotas{RAM: 1024},
+			used:   NamespaceResources{RAM: 768},
+			next:   NamespaceResources{RAM: 512},
+			err:    "RAM quota exceeded",
+		},
+		{
+			name:   "pods quota exceeded on scale up",
+			quotas: NamespaceQuotas{Pods: 4},
+			used:   NamespaceResources{Pods: 3},
+			prev:   NamespaceResources{Pods: 2},
+			next:   NamespaceResources{Pods: 4},
+			err:    "Pods quota exceeded",
+		},
+		{
+			name:   "scale down over quota",
+			quotas: NamespaceQuotas{Pods: 2},
+			used:   NamespaceResources{Pods: 5},
+			prev:   NamespaceResources{Pods: 4},
+			next:   NamespaceResources{Pods: 3},
+		},
+		{
+			name:   "storage quota exceeded",
+			quotas: NamespaceQuotas{Volumes: 2, Storage: 1024},
+			used:   NamespaceResources{Volumes: 1, Storage: 512},
+			next:   NamespaceResources{Volumes: 1, Storage: 1024},
+			err:    "Storage quota exceeded",
+		},
+		{
+			name:   "routes quota exceeded",
+			quotas: NamespaceQuotas{Routes: 1},
+			used:   NamespaceResources{Routes: 1},
+			next:   NamespaceResources{Routes: 1},
+			err:    "Routes quota exceeded",
+		},
+		{
+			name:   "disabled namespace blocks new workloads",
+			quotas: NamespaceQuotas{Disabled: true},
+			next:   NamespaceResources{Pods: 1},
+			err:    "Namespace is disabled",
+		},
+		{
+			name:   "disabled namespace allows update without new workloads",
+			quotas: NamespaceQuotas{Disabled: true},
+			used:   NamespaceResources{RAM: 256, Pods: 2},
+			prev:   NamespaceResources{RAM: 256, Pods: 2},
+			next:   NamespaceResources{RAM: 512, Pods: 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.quotas.Admit(tc.used, tc.prev, tc.next)
+			if tc.err == EmptyString {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				assert.Equal(t, tc.err, err.Error())
+			}
+		})
+	}
+}
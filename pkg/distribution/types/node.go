@@ -20,9 +20,18 @@ package types
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"net"
+	"strconv"
+	"time"
 )
 
+// DefaultNodePort - node agent API port used if node did not report own port
+const DefaultNodePort = 2969
+
 // swagger:ignore
 // swagger:model types_node_map
 type NodeMap struct {
@@ -109,13 +118,16 @@ type NodeStatus struct {
 	Capacity NodeResources `json:"capacity"`
 	// Node Allocated
 	Allocated NodeResources `json:"allocated"`
+	// Node last heartbeat time
+	Heartbeat time.Time `json:"heartbeat"`
 }
 
 type NodeStatusState struct {
-	CRI NodeStatusInterfaceState `json:"cri"`
-	CNI NodeStatusInterfaceState `json:"cni"`
-	CPI NodeStatusInterfaceState `json:"cpi"`
-	CSI NodeStatusInterfaceState `json:"csi"`
+	Ready bool                     `json:"ready"`
+	CRI   NodeStatusInterfaceState `json:"cri"`
+	CNI   NodeStatusInterfaceState `json:"cni"`
+	CPI   NodeStatusInterfaceState `json:"cpi"`
+	CSI   NodeStatusInterfaceState `json:"csi"`
 }
 
 type NodeStatusInterfaceState struct {
@@ -125,11 +137,64 @@ type NodeStatusInterfaceState struct {
 	Message string `json:"message"`
 }
 
-
 // swagger:ignore
 // swagger:model types_node_spec
 type NodeSpec struct {
-	Security  NodeSecurity            `json:"security"`
+	Security NodeSecurity `json:"security"`
+	// Port - node agent API port
+	Port uint16 `json:"port"`
+	// Unschedulable - node is cordoned and new pods are not placed on it
+	Unschedulable bool `json:"unschedulable"`
+	// Taints - node taints, pods should tolerate them to be placed on node
+	Taints []NodeTaint `json:"taints"`
+}
+
+const (
+	// NodeTaintEffectNoSchedule - new pods without toleration are not placed on node
+	NodeTaintEffectNoSchedule = "NoSchedule"
+	// NodeTaintEffectNoExecute - new pods are not placed and running pods without toleration are evicted
+	NodeTaintEffectNoExecute = "NoExecute"
+	// NodeTaintDrain - taint key used to evict pods from draining node
+	NodeTaintDrain = "node.lastbackend.com/drain"
+)
+
+// swagger:model types_node_taint
+type NodeTaint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Effect string `json:"effect"`
+}
+
+// Draining returns true if node is tainted for drain
+func (s *NodeSpec) Draining() bool {
+	for _, t := range s.Taints {
+		if t.Key == NodeTaintDrain {
+			return true
+		}
+	}
+	return false
+}
+
+// Drain cordons node and taints it to evict running pods
+func (s *NodeSpec) Drain() {
+	s.Unschedulable = true
+	if s.Draining() {
+		return
+	}
+	s.Taints = append(s.Taints, NodeTaint{Key: NodeTaintDrain, Effect: NodeTaintEffectNoExecute})
+}
+
+// Uncordon marks node as schedulable and removes drain taint
+func (s *NodeSpec) Uncordon() {
+	s.Unschedulable = false
+	taints := make([]NodeTaint, 0)
+	for _, t := range s.Taints {
+		if t.Key == NodeTaintDrain {
+			continue
+		}
+		taints = append(taints, t)
+	}
+	s.Taints = taints
 }
 
 type NodeSecurity struct {
@@ -218,6 +283,7 @@ type NodeCreateOptions struct {
 	Info     NodeInfo              `json:"info",yaml:"info"`
 	Status   NodeStatus            `json:"status",yaml:"status"`
 	Security NodeSecurity          `json:"security",yaml:"security"`
+	Port     uint16                `json:"port" yaml:"port"`
 }
 
 func (n *Node) SelfLink() string {
@@ -227,6 +293,45 @@ func (n *Node) SelfLink() string {
 	return n.Meta.SelfLink
 }
 
+// Endpoint returns node agent API address
+func (n *Node) Endpoint() string {
+	port := n.Spec.Port
+	if port == 0 {
+		port = DefaultNodePort
+	}
+	return net.JoinHostPort(n.Meta.InternalIP, strconv.Itoa(int(port)))
+}
+
+// TLSConfig returns client TLS config to connect node agent API:
+// node server certificate is verified by node authority and client certificate provided by node is presented,
+// nil config is returned if node agent API is served without TLS
+func (s NodeSecurity) TLSConfig() (*tls.Config, error) {
+
+	if !s.TLS {
+		return nil, nil
+	}
+
+	if s.SSL == nil {
+		return nil, errors.New("node tls credentials not provided")
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(s.SSL.CA) {
+		return nil, errors.New("invalid node certificate authority")
+	}
+
+	cert, err := tls.X509KeyPair(s.SSL.Cert, s.SSL.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		RootCAs:      pool,
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
 func NewNodeList() *NodeList {
 	dm := new(NodeList)
 	dm.Items = make([]*Node, 0)
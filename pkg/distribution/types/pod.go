@@ -56,6 +56,8 @@ type PodMeta struct {
 	Deployment string `json:"deployment" yaml:"deployment"`
 	// Pod service
 	Service string `json:"service" yaml:"service"`
+	// Pod job, job pods are linked to reserved JobPodService service
+	Job string `json:"job,omitempty" yaml:"job,omitempty"`
 	// Pod service id
 	Namespace string `json:"namespace" yaml:"namespace"`
 	// Pod node hostname
@@ -89,6 +91,8 @@ type PodStatus struct {
 	Running bool `json:"running" yaml:"state"`
 	// Pod state message
 	Message string `json:"message" yaml:"message"`
+	// Pod is ready to serve traffic: running and all containers passed readiness probes
+	Ready bool `json:"ready" yaml:"ready"`
 	// Pod steps
 	Steps PodSteps `json:"steps" yaml:"steps"`
 	// Pod network
@@ -97,6 +101,57 @@ type PodStatus struct {
 	Containers map[string]*PodContainer `json:"containers" yaml:"containers"`
 	// Pod volumes
 	Volumes map[string]*VolumeClaim `json:"volumes" yaml:"volumes"`
+	// Pod resources usage sampled by node
+	Usage PodUsage `json:"usage" yaml:"usage"`
+	// Pod node resources are released while pod is kept on node, set for finished job pods
+	Released bool `json:"released,omitempty" yaml:"released,omitempty"`
+}
+
+// PodUsage - pod containers resources usage
+// swagger:model types_pod_usage
+type PodUsage struct {
+	// CPU usage in millicores
+	CPU int64 `json:"cpu" yaml:"cpu"`
+	// RAM usage in MB
+	RAM int64 `json:"ram" yaml:"ram"`
+	// Network usage
+	Network PodUsageNetwork `json:"network" yaml:"network"`
+	// Disk usage
+	Disk PodUsageDisk `json:"disk" yaml:"disk"`
+	// Usage sample time
+	Updated time.Time `json:"updated" yaml:"updated"`
+}
+
+// Sum adds provided resources usage, latest sample time is kept
+func (u *PodUsage) Sum(usage PodUsage) {
+	u.CPU += usage.CPU
+	u.RAM += usage.RAM
+	u.Network.RX += usage.Network.RX
+	u.Network.TX += usage.Network.TX
+	u.Disk.Read += usage.Disk.Read
+	u.Disk.Write += usage.Disk.Write
+
+	if usage.Updated.After(u.Updated) {
+		u.Updated = usage.Updated
+	}
+}
+
+// PodUsageNetwork - pod containers network usage
+// swagger:model types_pod_usage_network
+type PodUsageNetwork struct {
+	// Received bytes total
+	RX int64 `json:"rx" yaml:"rx"`
+	// Transmitted bytes total
+	TX int64 `json:"tx" yaml:"tx"`
+}
+
+// PodUsageDisk - pod containers disk usage
+// swagger:model types_pod_usage_disk
+type PodUsageDisk struct {
+	// Read bytes total
+	Read int64 `json:"read" yaml:"read"`
+	// Written bytes total
+	Write int64 `json:"write" yaml:"write"`
 }
 
 // PodSteps is a map of pod steps
@@ -128,6 +183,8 @@ type PodContainer struct {
 	Pod string `json:"pod" yaml:"pod"`
 	// Pod container name
 	Name string `json:"name" yaml:"name"`
+	// Pod container role
+	Role string `json:"role" yaml:"role"`
 	// Pod container exec
 	Exec SpecTemplateContainerExec `json:"exec" yaml:"exec"`
 	// Pod container state
@@ -323,6 +380,47 @@ func (p *Pod) DeploymentLink() string {
 	return new(Deployment).CreateSelfLink(p.Meta.Namespace, p.Meta.Service, p.Meta.Deployment)
 }
 
+// JobLink returns self link of job which owns pod
+func (p *Pod) JobLink() string {
+	return new(Job).CreateSelfLink(p.Meta.Namespace, p.Meta.Job)
+}
+
+// Succeeded returns true if all pod containers exited with zero code
+func (p *Pod) Succeeded() bool {
+
+	if p.Status.State != StateReady || p.Status.Status != StatusStopped || len(p.Status.Containers) == 0 {
+		return false
+	}
+
+	for _, c := range p.Status.Containers {
+		if c.State.Stopped.Exit.Code != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Failed returns true if pod is in error state or any pod container exited with non zero code
+func (p *Pod) Failed() bool {
+
+	if p.Status.State == StateError {
+		return true
+	}
+
+	if p.Status.State != StateReady || p.Status.Status != StatusStopped {
+		return false
+	}
+
+	for _, c := range p.Status.Containers {
+		if c.State.Stopped.Exit.Code != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (p *Pod) CreateSelfLink(namespace, service, deployment, name string) string {
 	return fmt.Sprintf("%s:%s:%s:%s", namespace, service, deployment, name)
 }
@@ -23,6 +23,16 @@ import (
 	"time"
 )
 
+const (
+	RouteTLSStateValid    = "valid"
+	RouteTLSStateExpiring = "expiring"
+	RouteTLSStateExpired  = "expired"
+	RouteTLSStateInvalid  = "invalid"
+
+	// RouteTLSExpiringPeriod - certificate is marked as expiring within this period before expiration
+	RouteTLSExpiringPeriod = 30 * 24 * time.Hour
+)
+
 // Route
 // swagger:ignore
 // swagger:model types_route
@@ -56,18 +66,37 @@ type RouteMeta struct {
 // swagger:model types_route_spec
 type RouteSpec struct {
 	Security bool        `json:"security" yaml:"security"`
+	TLS      RouteTLS    `json:"tls" yaml:"tls"`
 	Domain   string      `json:"domain" yaml:"domain"`
 	Port     uint16      `json:"port" yaml:"port"`
 	Rules    []RouteRule `json:"rules" yaml:"rules"`
 	Updated  time.Time   `json:"updated"`
 }
 
+// swagger:model types_route_tls
+// RouteTLS - tls termination options
+type RouteTLS struct {
+	// Name of secret with certificate and key, secret should be KindSecretTLS
+	Secret string `json:"secret" yaml:"secret"`
+	// Redirect http requests to https
+	Redirect bool `json:"redirect" yaml:"redirect"`
+}
+
 // swagger:ignore
 // swagger:model types_route_status
 // RouteStatus - status of current route state
 type RouteStatus struct {
-	State   string `json:"state" yaml:"state"`
-	Message string `json:"message" yaml:"message"`
+	State   string         `json:"state" yaml:"state"`
+	Message string         `json:"message" yaml:"message"`
+	TLS     RouteTLSStatus `json:"tls" yaml:"tls"`
+}
+
+// swagger:ignore
+// RouteTLSStatus - status of route certificate
+type RouteTLSStatus struct {
+	State   string    `json:"state" yaml:"state"`
+	Message string    `json:"message" yaml:"message"`
+	Expires time.Time `json:"expires" yaml:"expires"`
 }
 
 // swagger:model types_route_rule
@@ -78,6 +107,40 @@ type RouteRule struct {
 	Port     int    `json:"port" yaml:"port"`
 }
 
+// Set updates certificate status by certificate expiration time
+func (s *RouteTLSStatus) Set(expires time.Time) {
+	s.Expires = expires
+	s.Message = EmptyString
+	s.State = s.Current()
+}
+
+// SetError marks certificate as invalid
+func (s *RouteTLSStatus) SetError(err error) {
+	s.Expires = time.Time{}
+	s.State = RouteTLSStateInvalid
+	s.Message = err.Error()
+}
+
+// Current returns certificate state at current time
+func (s *RouteTLSStatus) Current() string {
+
+	switch {
+	case s.State == RouteTLSStateInvalid || s.Expires.IsZero():
+		return s.State
+	case time.Now().After(s.Expires):
+		return RouteTLSStateExpired
+	case time.Now().Add(RouteTLSExpiringPeriod).After(s.Expires):
+		return RouteTLSStateExpiring
+	}
+
+	return RouteTLSStateValid
+}
+
+// QuotaUsage returns namespace resources used by route
+func (r *Route) QuotaUsage() NamespaceResources {
+	return NamespaceResources{Routes: 1}
+}
+
 func (r *Route) SelfLink() string {
 	if r.Meta.SelfLink == "" {
 		r.Meta.SelfLink = r.CreateSelfLink(r.Meta.Namespace, r.Meta.Name)
@@ -90,11 +153,19 @@ func (r *Route) CreateSelfLink(namespace, name string) string {
 }
 
 type RouteManifest struct {
-	State    string      `json:"state"`
-	Domain   string      `json:"domain"`
-	Port     uint16      `json:"port"`
-	Endpoint string      `json:"endpoint"`
-	Rules    []RouteRule `json:"rules"`
+	State    string            `json:"state"`
+	Domain   string            `json:"domain"`
+	Port     uint16            `json:"port"`
+	Endpoint string            `json:"endpoint"`
+	Rules    []RouteRule       `json:"rules"`
+	TLS      *RouteManifestTLS `json:"tls,omitempty"`
+}
+
+// RouteManifestTLS - certificate used by ingress to terminate tls
+type RouteManifestTLS struct {
+	Certificate string `json:"certificate"`
+	Key         string `json:"key"`
+	Redirect    bool   `json:"redirect"`
 }
 
 type RouteManifestList struct {
@@ -113,6 +184,26 @@ func (r *RouteManifest) Set(route *Route) {
 	r.Port = route.Spec.Port
 }
 
+// SetTLS sets certificate from route tls secret
+func (r *RouteManifest) SetTLS(route *Route, secret *Secret) error {
+
+	data, err := secret.DecodeSecretTLSData()
+	if err != nil {
+		return err
+	}
+
+	if _, err := data.Parse(); err != nil {
+		return err
+	}
+
+	r.TLS = new(RouteManifestTLS)
+	r.TLS.Certificate = data.Certificate
+	r.TLS.Key = data.Key
+	r.TLS.Redirect = route.Spec.TLS.Redirect
+
+	return nil
+}
+
 func NewRouteList() *RouteList {
 	dm := new(RouteList)
 	dm.Items = make([]*Route, 0)
@@ -20,6 +20,8 @@ package types
 
 import (
 	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -29,9 +31,13 @@ import (
 const (
 	KindSecretOpaque = "opaque"
 	KindSecretAuth   = "auth"
+	KindSecretTLS    = "tls"
 
 	SecretUsernameKey = "username"
 	SecretPasswordKey = "password"
+
+	SecretTLSCertificateKey = "tls.crt"
+	SecretTLSPrivateKey     = "tls.key"
 )
 
 // swagger:ignore
@@ -144,11 +150,57 @@ func (s *Secret) DecodeSecretTextData(key string) (string, error) {
 
 }
 
+func (s *Secret) EncodeSecretTLSData(d SecretTLSData) {
+	s.Spec.Data = make(map[string][]byte)
+	s.Spec.Data[SecretTLSCertificateKey] = []byte(base64.StdEncoding.EncodeToString([]byte(d.Certificate)))
+	s.Spec.Data[SecretTLSPrivateKey] = []byte(base64.StdEncoding.EncodeToString([]byte(d.Key)))
+}
+
+func (s *Secret) DecodeSecretTLSData() (*SecretTLSData, error) {
+
+	if s.Spec.Type != KindSecretTLS {
+		return nil, errors.New("invalid secret type")
+	}
+
+	data := new(SecretTLSData)
+
+	c, err := base64.StdEncoding.DecodeString(string(s.Spec.Data[SecretTLSCertificateKey]))
+	if err != nil {
+		return nil, err
+	}
+	data.Certificate = string(c)
+
+	k, err := base64.StdEncoding.DecodeString(string(s.Spec.Data[SecretTLSPrivateKey]))
+	if err != nil {
+		return nil, err
+	}
+	data.Key = string(k)
+
+	return data, nil
+}
+
 type SecretAuthData struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 }
 
+// SecretTLSData - PEM-encoded certificate chain and private key
+type SecretTLSData struct {
+	Certificate string `json:"certificate"`
+	Key         string `json:"key"`
+}
+
+// Parse checks certificate and key pair and returns leaf certificate
+func (d *SecretTLSData) Parse() (*x509.Certificate, error) {
+
+	pair, err := tls.X509KeyPair([]byte(d.Certificate), []byte(d.Key))
+	if err != nil {
+		return nil, err
+	}
+
+	return x509.ParseCertificate(pair.Certificate[0])
+}
+
 type SecretText struct {
 	Text string `json:"text"`
 }
@@ -20,6 +20,8 @@ package types
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 const (
@@ -27,6 +29,15 @@ const (
 	DEFAULT_SERVICE_REPLICAS int   = 1
 )
 
+const (
+	// ServiceModeReplicated runs service replicas placed by scheduler, it is used by default
+	ServiceModeReplicated = "replicated"
+	// ServiceModeDaemon runs one service pod on every node matched by service selector
+	ServiceModeDaemon = "daemon"
+	// ServiceModeStateful runs service replicas with stable ordinal names and dedicated volumes
+	ServiceModeStateful = "stateful"
+)
+
 type Service struct {
 	Runtime
 	Meta   ServiceMeta   `json:"meta"`
@@ -61,15 +72,28 @@ type ServiceStatus struct {
 	State   string               `json:"state"`
 	Message string               `json:"message"`
 	Network ServiceStatusNetwork `json:"network"`
+	// Nodes - count of nodes running daemon service pods
+	Nodes int `json:"nodes"`
 }
 
 type ServiceSpec struct {
+	// Service mode: replicated, daemon or stateful
+	Mode     string       `json:"mode" yaml:"mode"`
 	Replicas int          `json:"replicas" yaml:"replicas"`
 	State    SpecState    `json:"state" yaml:"state"`
 	Network  SpecNetwork  `json:"network" yaml:"network" `
 	Strategy SpecStrategy `json:"strategy" yaml:"strategy"`
 	Selector SpecSelector `json:"selector" yaml:"selector"`
 	Template SpecTemplate `json:"template" yaml:"template"`
+	// Stateful service volume claims, volume is created for every claim and pod ordinal
+	VolumeClaims []ServiceVolumeClaim `json:"volume_claims" yaml:"volume_claims"`
+}
+
+// ServiceVolumeClaim describes volume template of stateful service pods,
+// pod template volumes refer claim by name
+type ServiceVolumeClaim struct {
+	Name     string             `json:"name" yaml:"name"`
+	Capacity SpecVolumeCapacity `json:"capacity" yaml:"capacity"`
 }
 
 type ServiceStatusNetwork struct {
@@ -108,6 +132,49 @@ func (s *ServiceSpec) SetDefault() {
 	s.Template.Containers = make(SpecTemplateContainers, 0)
 }
 
+// Daemon returns true if service runs one pod on every node matched by selector
+func (s *ServiceSpec) Daemon() bool {
+	return s.Mode == ServiceModeDaemon
+}
+
+// Ordered returns true if service pods are replaced by rolling update one pod at a time:
+// daemon pods are replaced node by node and stateful pods ordinal by ordinal
+func (s *ServiceSpec) Ordered() bool {
+	return s.Daemon() || s.Stateful()
+}
+
+// Stateful returns true if service pods have stable ordinal names and dedicated volumes
+func (s *ServiceSpec) Stateful() bool {
+	return s.Mode == ServiceModeStateful
+}
+
+// VolumeClaim checks that persistent volume name refers stateful service volume claim
+func (s *ServiceSpec) VolumeClaim(name string) bool {
+	for _, c := range s.VolumeClaims {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// QuotaUsage returns namespace resources requested by service pods,
+// daemon service runs one pod on every matched node, so its usage is based on nodes count
+func (s *Service) QuotaUsage() NamespaceResources {
+
+	replicas := s.Spec.Replicas
+	if s.Spec.Daemon() {
+		replicas = s.Status.Nodes
+	}
+
+	r := s.Spec.Template.ResourceRequest()
+	return NamespaceResources{
+		RAM:  r.RAM * int64(replicas),
+		CPU:  r.CPU * int64(replicas),
+		Pods: replicas,
+	}
+}
+
 func (s *Service) SelfLink() string {
 	if s.Meta.SelfLink == "" {
 		s.Meta.SelfLink = s.CreateSelfLink(s.Meta.Namespace, s.Meta.Name)
@@ -119,6 +186,32 @@ func (s *Service) CreateSelfLink(namespace, name string) string {
 	return fmt.Sprintf("%s:%s", namespace, name)
 }
 
+// PodName returns stateful service pod name for ordinal: <service>-<ordinal>
+func (s *Service) PodName(ordinal int) string {
+	return fmt.Sprintf("%s-%d", s.Meta.Name, ordinal)
+}
+
+// PodOrdinal returns ordinal of stateful service pod name
+func (s *Service) PodOrdinal(name string) (int, bool) {
+
+	prefix := s.Meta.Name + "-"
+	if !strings.HasPrefix(name, prefix) {
+		return 0, false
+	}
+
+	ordinal, err := strconv.Atoi(strings.TrimPrefix(name, prefix))
+	if err != nil || ordinal < 0 || s.PodName(ordinal) != name {
+		return 0, false
+	}
+
+	return ordinal, true
+}
+
+// ClaimVolumeName returns name of volume created by claim for pod ordinal: <claim>-<service>-<ordinal>
+func (s *Service) ClaimVolumeName(claim string, ordinal int) string {
+	return fmt.Sprintf("%s-%s", claim, s.PodName(ordinal))
+}
+
 type ServiceManifest struct {
 	Meta ServiceMeta `json:"meta"`
 }
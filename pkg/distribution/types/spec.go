@@ -23,12 +23,20 @@ import (
 	"io"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
 const ContainerRolePrimary = "primary"
 const ContainerRoleSlave = "slave"
 
+// ContainerRoleInit - container runs to completion before pod app containers are started,
+// init containers are run one by one in template order
+const ContainerRoleInit = "init"
+
+// ContainerRoleSidecar - container is started before and stopped after pod app containers
+const ContainerRoleSidecar = "sidecar"
+
 // SpecState is a state of the spec
 // swagger:model types_spec_state
 type SpecState struct {
@@ -53,6 +61,7 @@ type SpecTemplate struct {
 // swagger:model types_spec_template_network
 type SpecNetwork struct {
 	IP       string               `json:"ip"`
+	Pool     string               `json:"pool"`
 	Ports    map[uint16]string    `json:"ports"`
 	Strategy EndpointSpecStrategy `json:"strategy"`
 	Policy   string               `json:"policy"`
@@ -174,6 +183,16 @@ type SpecTemplateContainer struct {
 	RestartPolicy SpecTemplateRestartPolicy `json:"restart" yaml:"restart"`
 }
 
+// Init checks that container runs to completion before app containers
+func (s *SpecTemplateContainer) Init() bool {
+	return s.Role == ContainerRoleInit
+}
+
+// Sidecar checks that container is started before and stopped after app containers
+func (s *SpecTemplateContainer) Sidecar() bool {
+	return s.Role == ContainerRoleSidecar
+}
+
 // swagger:model types_spec_template_container_image
 type SpecTemplateContainerImage struct {
 	Name   string `json:"name" yaml:"name"`
@@ -287,7 +306,7 @@ type SpecTemplateContainerExec struct {
 
 // swagger:model types_spec_template_container_resource
 type SpecTemplateContainerResource struct {
-	// CPU resource option
+	// CPU resource option in CPU shares, 1024 shares is one core
 	CPU int64 `json:"cpu"`
 	// RAM resource option
 	RAM int64 `json:"ram"`
@@ -325,6 +344,13 @@ type SpecTemplateContainerProbe struct {
 		Port     int    `json:"port"`
 	} `json:"socket"`
 
+	// HTTP GET request to check container liveness, status 2xx-3xx is success
+	HTTP struct {
+		Path    string            `json:"path"`
+		Port    int               `json:"port"`
+		Headers map[string]string `json:"headers"`
+	} `json:"http"`
+
 	InitialDelaySeconds int `json:"initial_delay"`
 	TimeoutSeconds      int `json:"timeout_seconds"`
 	PeriodSeconds       int `json:"period_seconds"`
@@ -332,6 +358,34 @@ type SpecTemplateContainerProbe struct {
 	ThresholdFailure    int `json:"threshold_failure"`
 }
 
+// Defined returns true if probe has any check configured
+func (p SpecTemplateContainerProbe) Defined() bool {
+	return len(p.Exec.Command) > 0 || p.Socket.Port > 0 || p.HTTP.Port > 0
+}
+
+// Equal compares probes checks and timings
+func (p SpecTemplateContainerProbe) Equal(o SpecTemplateContainerProbe) bool {
+
+	if strings.Join(p.Exec.Command, " ") != strings.Join(o.Exec.Command, " ") ||
+		p.Socket != o.Socket ||
+		p.HTTP.Path != o.HTTP.Path || p.HTTP.Port != o.HTTP.Port ||
+		len(p.HTTP.Headers) != len(o.HTTP.Headers) {
+		return false
+	}
+
+	for name, value := range p.HTTP.Headers {
+		if v, ok := o.HTTP.Headers[name]; !ok || v != value {
+			return false
+		}
+	}
+
+	return p.InitialDelaySeconds == o.InitialDelaySeconds &&
+		p.TimeoutSeconds == o.TimeoutSeconds &&
+		p.PeriodSeconds == o.PeriodSeconds &&
+		p.ThresholdSuccess == o.ThresholdSuccess &&
+		p.ThresholdFailure == o.ThresholdFailure
+}
+
 // swagger:model types_spec_template_container_security
 type SpecTemplateContainerSecurity struct {
 	// Start container in priveleged mode
@@ -385,16 +439,98 @@ type SpecTemplateRestartPolicy struct {
 	Attempt int `json:"attempt" yaml:"attempt"`
 }
 
+const (
+	// SpecStrategyTypeRolling replaces pods step by step bounded by max surge and max unavailable
+	SpecStrategyTypeRolling = "rolling"
+	// SpecStrategyTypeRecreate starts all new pods and switches traffic when all of them are ready,
+	// it is used by default
+	SpecStrategyTypeRecreate = "recreate"
+	// SpecStrategyTypeCanary runs canary pods next to active deployment pods with weighted share of traffic
+	// until new deployment is promoted
+	SpecStrategyTypeCanary = "canary"
+	// SpecStrategyTypeBlueGreen starts all new pods and switches traffic when new deployment is promoted
+	SpecStrategyTypeBlueGreen = "bluegreen"
+	// SpecStrategyHistoryDefault is a default count of previous deployments kept for rollback
+	SpecStrategyHistoryDefault = 10
+)
+
 // swagger:model types_spec_strategy
 type SpecStrategy struct {
 	Type           string                     `json:"type"` // Rolling
 	RollingOptions SpecStrategyRollingOptions `json:"rollingOptions"`
 	Resources      SpecStrategyResources      `json:"resources"`
 	Deadline       int                        `json:"deadline"`
+	// Canary release options
+	CanaryOptions SpecStrategyCanaryOptions `json:"canary"`
+	// Count of previous deployments kept for rollback
+	History int `json:"history"`
 	// Spec updated time
 	Updated time.Time `json:"updated"`
 }
 
+// Rolling returns true if pods are replaced by rolling update:
+// strategy type is rolling or type is not set and rolling options are provided
+func (s SpecStrategy) Rolling() bool {
+	switch s.Type {
+	case SpecStrategyTypeRolling:
+		return true
+	case EmptyString:
+		return s.RollingOptions.MaxSurge > 0 || s.RollingOptions.MaxUnavailable > 0
+	}
+	return false
+}
+
+// RollingBounds returns max surge and max unavailable replicas for replicas count,
+// at least one of them is positive to let rolling update progress
+func (s SpecStrategy) RollingBounds(replicas int) (int, int) {
+
+	surge, unavailable := s.RollingOptions.MaxSurge, s.RollingOptions.MaxUnavailable
+
+	if unavailable > replicas {
+		unavailable = replicas
+	}
+
+	if surge <= 0 && unavailable <= 0 {
+		surge = 1
+	}
+
+	return surge, unavailable
+}
+
+// Canary returns true if new deployment is released as canary
+func (s SpecStrategy) Canary() bool {
+	return s.Type == SpecStrategyTypeCanary
+}
+
+// BlueGreen returns true if new deployment is released as blue/green
+func (s SpecStrategy) BlueGreen() bool {
+	return s.Type == SpecStrategyTypeBlueGreen
+}
+
+// CanaryReplicas returns canary pods count for replicas count, one pod is used by default
+func (s SpecStrategy) CanaryReplicas(replicas int) int {
+
+	canary := s.CanaryOptions.Replicas
+	if canary <= 0 {
+		canary = 1
+	}
+
+	if canary > replicas {
+		canary = replicas
+	}
+
+	return canary
+}
+
+// swagger:model types_spec_strategy_canary_options
+type SpecStrategyCanaryOptions struct {
+	// Canary pods count
+	Replicas int `json:"replicas"`
+	// Percent of traffic sent to canary pods,
+	// traffic is shared by pods count if not set
+	Weight int `json:"weight"`
+}
+
 // swagger:model types_spec_strategy_resources
 type SpecStrategyResources struct {
 }
@@ -421,10 +557,78 @@ type SpecSelector struct {
 	Labels map[string]string `json:"labels"`
 
 	Node string `json:"node"`
+	// Place pods next to pods of selected services
+	Affinity []SpecSelectorAffinity `json:"affinity,omitempty"`
+	// Do not place pods next to pods of selected services
+	AntiAffinity []SpecSelectorAffinity `json:"anti_affinity,omitempty"`
+	// Spread pods evenly across nodes topology domains
+	Spread []SpecSelectorSpread `json:"spread,omitempty"`
+	// Allow pods placement on tainted nodes
+	Tolerations []SpecSelectorToleration `json:"tolerations,omitempty"`
 	// Spec updated time
 	Updated time.Time `json:"updated"`
 }
 
+// SpecSelectorAffinity describes pods placement rule relative to pods of another service
+// swagger:model types_spec_selector_affinity
+type SpecSelectorAffinity struct {
+	// Service name in the same namespace, own service is used if empty
+	Service string `json:"service,omitempty"`
+	// Node label used as topology domain, node itself is used if empty
+	Topology string `json:"topology,omitempty"`
+}
+
+// SpecSelectorSpread describes pods distribution across topology domains
+// swagger:model types_spec_selector_spread
+type SpecSelectorSpread struct {
+	// Node label used as topology domain
+	Topology string `json:"topology"`
+	// Max allowed difference of pods count between domains
+	MaxSkew int `json:"max_skew"`
+}
+
+const (
+	SpecSelectorTolerationOperatorEqual  = "Equal"
+	SpecSelectorTolerationOperatorExists = "Exists"
+)
+
+// SpecSelectorToleration allows pods placement on nodes with matched taint
+// swagger:model types_spec_selector_toleration
+type SpecSelectorToleration struct {
+	// Taint key, empty key with Exists operator tolerates all taints
+	Key string `json:"key,omitempty"`
+	// Operator is Equal or Exists, Equal is used if empty
+	Operator string `json:"operator,omitempty"`
+	// Taint value for Equal operator
+	Value string `json:"value,omitempty"`
+	// Taint effect, all effects are tolerated if empty
+	Effect string `json:"effect,omitempty"`
+}
+
+// Tolerate checks if toleration matches provided node taint
+func (t SpecSelectorToleration) Tolerate(taint NodeTaint) bool {
+
+	if t.Effect != EmptyString && t.Effect != taint.Effect {
+		return false
+	}
+
+	if t.Operator == SpecSelectorTolerationOperatorExists {
+		return t.Key == EmptyString || t.Key == taint.Key
+	}
+
+	return t.Key == taint.Key && t.Value == taint.Value
+}
+
+// Tolerate checks if any selector toleration matches provided node taint
+func (s SpecSelector) Tolerate(taint NodeTaint) bool {
+	for _, t := range s.Tolerations {
+		if t.Tolerate(taint) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *SpecTemplateContainerEnvs) ToLinuxFormat() []string {
 	env := make([]string, 0)
 
@@ -442,6 +646,19 @@ func (s *SpecTemplate) SetDefault() {
 	s.Volumes = make(SpecTemplateVolumeList, 0)
 }
 
+// CPUSharesPerCore - container CPU resources are counted in docker CPU shares
+const CPUSharesPerCore = 1024
+
+// ResourceRequest returns resources requested by all template containers
+func (s SpecTemplate) ResourceRequest() SpecTemplateContainerResource {
+	var r SpecTemplateContainerResource
+	for _, c := range s.Containers {
+		r.RAM += c.Resources.Request.RAM
+		r.CPU += c.Resources.Request.CPU
+	}
+	return r
+}
+
 func (s *SpecTemplateContainer) SetDefault() {
 	s.Labels = make(map[string]string, 0)
 	s.Resources.Limits.RAM = int64(128)
@@ -43,4 +43,5 @@ const StateError = "error"
 
 const StepInitialized = "initialized"
 const StepPull = "pull"
+const StepInit = "init"
 const StepReady = "ready"
@@ -126,6 +126,14 @@ func (vs *VolumeStatus) SetError (err error) {
 	vs.Message = err.Error()
 }
 
+// QuotaUsage returns namespace resources claimed by volume
+func (v *Volume) QuotaUsage() NamespaceResources {
+	return NamespaceResources{
+		Volumes: 1,
+		Storage: v.Spec.Capacity.Storage,
+	}
+}
+
 func (v *Volume) SelfLink() string {
 	if v.Meta.SelfLink == "" {
 		v.Meta.SelfLink = v.CreateSelfLink(v.Meta.Namespace, v.Meta.Name)
@@ -21,6 +21,7 @@ package ingress
 import (
 	"context"
 	"github.com/lastbackend/lastbackend/pkg/api/client"
+	"github.com/lastbackend/lastbackend/pkg/api/client/identity"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/ingress/controller"
 	"github.com/lastbackend/lastbackend/pkg/ingress/envs"
@@ -28,6 +29,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/ingress/state"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/network"
+	"github.com/lastbackend/lastbackend/pkg/util/metrics"
 	"github.com/spf13/viper"
 	"os"
 	"os/signal"
@@ -89,6 +91,26 @@ func Daemon() bool {
 			endpoint = viper.GetString("api_uri")
 		}
 
+		// Agent client certificate is issued by cluster with one-time join token
+		if viper.IsSet("api.tls.dir") {
+			opts := identity.Options{
+				Endpoint: endpoint,
+				Kind:     types.AccountKindIngress,
+				Name:     st.Ingress().Info.Hostname,
+				Token:    viper.GetString("api.tls.join_token"),
+				Dir:      viper.GetString("api.tls.dir"),
+				CAFile:   viper.GetString("api.tls.ca"),
+			}
+
+			tls, err := identity.Bootstrap(context.Background(), opts)
+			if err != nil {
+				log.Errorf("Issue client certificate err: %s", err)
+			} else {
+				cfg.TLS = tls
+				go identity.Rotate(context.Background(), opts)
+			}
+		}
+
 		rest, err := client.New(client.ClientHTTP, endpoint, cfg)
 		if err != nil {
 			log.Errorf("Init client err: %s", err)
@@ -105,6 +127,14 @@ func Daemon() bool {
 		go ctl.Sync(context.Background())
 	}
 
+	if viper.IsSet("metrics.port") {
+		go func() {
+			if err := metrics.Listen(viper.GetString("metrics.host"), viper.GetInt("metrics.port")); err != nil {
+				log.Errorf("ingress:metrics: server err: %s", err.Error())
+			}
+		}()
+	}
+
 	// Handle SIGINT and SIGTERM.
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 
@@ -24,6 +24,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"syscall"
 
@@ -38,6 +39,7 @@ import (
 
 const (
 	ConfigName      = "haproxy.cfg"
+	CertsDir        = "certs"
 	logConfigPrefix = "runtime:config"
 )
 
@@ -45,6 +47,17 @@ type conf struct {
 	Resolvers map[string]uint16
 	Frontend  map[uint16]*confFrontend
 	Backend   map[string]*confBackend
+	TLS       *confTLS
+}
+
+// confTLS describes frontend which terminates tls for routes with certificates
+type confTLS struct {
+	// Directory with domains certificates
+	Path string
+	// Domains rules: domain -> path -> backend
+	Rules map[string]map[string]string
+	// Domains redirected from http to https
+	Redirect []string
 }
 
 type confFrontend struct {
@@ -86,11 +99,88 @@ func configSync() error {
 
 	log.Debugf("Update routes: %d", len(routes))
 
-	var cfg = conf{}
-	cfg.Resolvers = envs.Get().GetResolvers()
+	if name == types.EmptyString {
+		name = ConfigName
+	}
+
+	cfg, certs := configBuild(routes, envs.Get().GetResolvers(), filepath.Join(path, CertsDir))
+
+	if err := configCertificates(filepath.Join(path, CertsDir), certs); err != nil {
+		log.Errorf("can not write certificates: %s", err.Error())
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	tpl.Execute(buf, cfg)
+	log.Debugf("config path: %s", path)
+
+	var (
+		f   *os.File
+		err error
+	)
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		log.Debugf("config direcotry not exists: %s", path)
+		if err := os.MkdirAll(path, 0644); err != nil {
+			log.Errorf("can not create config dir: %s", err.Error())
+			return err
+		}
+	}
+
+	cfgPath := filepath.Join(path, name)
+	testPath := fmt.Sprintf("%s.test", cfgPath)
+
+	f, err = os.Open(testPath)
+	if os.IsNotExist(err) {
+		log.Debugf("config file not exists: %s", testPath)
+		f, err = os.Create(testPath)
+		if err != nil {
+			log.Errorf("can not create config file: %s", err.Error())
+		}
+	}
+	f.Close()
+
+	if err := ioutil.WriteFile(testPath, buf.Bytes(), 0644); err != nil {
+		log.Errorf("can no write test config: %s", err.Error())
+		return err
+	}
+
+	if err := configValidate(testPath); err != nil {
+		log.Errorf("config is not working (%s)", err.Error())
+		return err
+	}
+
+	f, err = os.Open(cfgPath)
+	if os.IsNotExist(err) {
+		log.Debugf("config file not exists: %s", cfgPath)
+		f, err = os.Create(cfgPath)
+		if err != nil {
+			log.Errorf("can not create config file: %s", err.Error())
+		}
+	}
+	f.Close()
+
+	return ioutil.WriteFile(cfgPath, buf.Bytes(), 0644)
+}
+
+// configBuild creates haproxy config from routes and returns certificates
+// for routes with tls termination: domain -> certificate with key
+func configBuild(routes map[string]*types.RouteManifest, resolvers map[string]uint16, certs string) (*conf, map[string][]byte) {
+
+	var cfg = new(conf)
+	cfg.Resolvers = resolvers
 	cfg.Frontend = make(map[uint16]*confFrontend, 0)
 	cfg.Backend = make(map[string]*confBackend, 0)
 
+	var pems = make(map[string][]byte, 0)
+
+	frontend := func(port uint16, tp string) *confFrontend {
+		if _, ok := cfg.Frontend[port]; !ok {
+			cfg.Frontend[port] = &confFrontend{Type: tp, Rules: make(map[string]map[string]string, 0)}
+		}
+		return cfg.Frontend[port]
+	}
+
 	for n, r := range routes {
 
 		log.Debugf("route configure: %s", n)
@@ -111,19 +201,37 @@ func configSync() error {
 			continue
 		}
 
-		var frontend *confFrontend
+		rules := make(map[string]string, 0)
 
-		if _, ok := cfg.Frontend[r.Port]; ok {
-			frontend = cfg.Frontend[r.Port]
-		} else {
-			frontend = new(confFrontend)
-			frontend.Type = tp
-			frontend.Rules = make(map[string]map[string]string, 0)
-			cfg.Frontend[r.Port] = frontend
-		}
+		// Route with certificate is terminated on https frontend and proxied as http
+		if r.TLS != nil {
+			tp = "http"
+
+			if cfg.TLS == nil {
+				cfg.TLS = new(confTLS)
+				cfg.TLS.Path = certs
+				cfg.TLS.Rules = make(map[string]map[string]string, 0)
+				cfg.TLS.Redirect = make([]string, 0)
+			}
+
+			if _, ok := cfg.TLS.Rules[r.Domain]; !ok {
+				cfg.TLS.Rules[r.Domain] = make(map[string]string, 0)
+			}
+			rules = cfg.TLS.Rules[r.Domain]
+
+			frontend(443, "https")
+			if r.TLS.Redirect {
+				frontend(80, "http")
+				cfg.TLS.Redirect = append(cfg.TLS.Redirect, r.Domain)
+			}
 
-		if _, ok := frontend.Rules[r.Domain]; !ok {
-			frontend.Rules[r.Domain] = make(map[string]string, 0)
+			pems[r.Domain] = []byte(fmt.Sprintf("%s\n%s\n", strings.TrimSpace(r.TLS.Certificate), strings.TrimSpace(r.TLS.Key)))
+		} else {
+			f := frontend(r.Port, tp)
+			if _, ok := f.Rules[r.Domain]; !ok {
+				f.Rules[r.Domain] = make(map[string]string, 0)
+			}
+			rules = f.Rules[r.Domain]
 		}
 
 		for _, b := range r.Rules {
@@ -138,66 +246,45 @@ func configSync() error {
 			backend.Domain = r.Domain
 
 			cfg.Backend[name] = backend
-			frontend.Rules[r.Domain][b.Path] = name
+			rules[b.Path] = name
 		}
-
 	}
 
-	buf := &bytes.Buffer{}
-	tpl.Execute(buf, cfg)
-	log.Debugf("config path: %s", path)
-
-	var (
-		f   *os.File
-		err error
-	)
-
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		log.Debugf("config direcotry not exists: %s", path)
-		if err := os.MkdirAll(path, 0644); err != nil {
-			log.Errorf("can not create config dir: %s", err.Error())
-			return err
-		}
+	if cfg.TLS != nil {
+		sort.Strings(cfg.TLS.Redirect)
 	}
 
-	if name == types.EmptyString {
-		name = ConfigName
-	}
+	return cfg, pems
+}
 
-	cfgPath := filepath.Join(path, name)
-	testPath := fmt.Sprintf("%s.test", cfgPath)
+// configCertificates writes domains certificates to directory and removes unused ones
+func configCertificates(dir string, certs map[string][]byte) error {
 
-	f, err = os.Open(testPath)
-	if os.IsNotExist(err) {
-		log.Debugf("config file not exists: %s", testPath)
-		f, err = os.Create(testPath)
-		if err != nil {
-			log.Errorf("can not create config file: %s", err.Error())
-		}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
 	}
-	f.Close()
 
-	if err := ioutil.WriteFile(testPath, buf.Bytes(), 0644); err != nil {
-		log.Errorf("can no write test config: %s", err.Error())
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
 		return err
 	}
 
-	if err := configValidate(testPath); err != nil {
-		log.Errorf("config is not working (%s)", err.Error())
-		return err
+	for _, f := range files {
+		domain := strings.TrimSuffix(f.Name(), ".pem")
+		if _, ok := certs[domain]; !ok && !f.IsDir() {
+			if err := os.Remove(filepath.Join(dir, f.Name())); err != nil {
+				return err
+			}
+		}
 	}
 
-	f, err = os.Open(cfgPath)
-	if os.IsNotExist(err) {
-		log.Debugf("config file not exists: %s", cfgPath)
-		f, err = os.Create(cfgPath)
-		if err != nil {
-			log.Errorf("can not create config file: %s", err.Error())
+	for domain, data := range certs {
+		if err := ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("%s.pem", domain)), data, 0600); err != nil {
+			return err
 		}
 	}
-	f.Close()
 
-	return ioutil.WriteFile(cfgPath, buf.Bytes(), 0644)
+	return nil
 }
 
 func configValidate(path string) error {
@@ -0,0 +1,103 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package runtime
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigBuild(t *testing.T) {
+
+	routes := map[string]*types.RouteManifest{
+		"demo:plain": {
+			Domain: "plain.lb.local",
+			Port:   80,
+			Rules:  []types.RouteRule{{Endpoint: "plain.demo", Path: "/", Port: 8080}},
+		},
+		"demo:secure": {
+			Domain: "secure.lb.local",
+			Port:   443,
+			Rules:  []types.RouteRule{{Endpoint: "secure.demo", Path: "/", Port: 8080}},
+			TLS:    &types.RouteManifestTLS{Certificate: "cert", Key: "key", Redirect: true},
+		},
+	}
+
+	cfg, certs := configBuild(routes, map[string]uint16{"127.0.0.1": 53}, "/tmp/certs")
+
+	if !assert.NotNil(t, cfg.TLS, "tls config should be created") {
+		return
+	}
+
+	assert.Equal(t, "/tmp/certs", cfg.TLS.Path, "certificates path mismatch")
+	assert.Equal(t, "demo_secure_8080", cfg.TLS.Rules["secure.lb.local"]["/"], "tls rule mismatch")
+	assert.Equal(t, []string{"secure.lb.local"}, cfg.TLS.Redirect, "redirect domains mismatch")
+	assert.Equal(t, "http", cfg.Backend["demo_secure_8080"].Type, "terminated backend should be http")
+	assert.Equal(t, "https", cfg.Frontend[443].Type, "https frontend should be created")
+	assert.Empty(t, cfg.Frontend[443].Rules, "terminated route should not be passed through")
+	assert.Equal(t, "demo_plain_8080", cfg.Frontend[80].Rules["plain.lb.local"]["/"], "http rule mismatch")
+	assert.Equal(t, "cert\nkey\n", string(certs["secure.lb.local"]), "certificate mismatch")
+
+	buf := &bytes.Buffer{}
+	tpl := template.Must(template.New("haproxy").Parse(HaproxyTemplate))
+	if !assert.NoError(t, tpl.Execute(buf, cfg)) {
+		return
+	}
+
+	out := buf.String()
+	assert.Contains(t, out, "use_backend tls_terminate if { req_ssl_sni -i secure.lb.local }")
+	assert.Contains(t, out, "bind abns@tls_terminate accept-proxy ssl crt /tmp/certs")
+	assert.Contains(t, out, "http-request redirect scheme https code 301 if { hdr_dom(host) -i secure.lb.local }")
+	assert.Contains(t, out, "use_backend demo_secure_8080 if r_demo_secure_8080")
+}
+
+func TestConfigCertificates(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "lb-ingress-certs")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	stale := filepath.Join(dir, "stale.lb.local.pem")
+	assert.NoError(t, ioutil.WriteFile(stale, []byte("stale"), 0600))
+
+	err = configCertificates(dir, map[string][]byte{"secure.lb.local": []byte("cert\nkey\n")})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = os.Stat(stale)
+	assert.True(t, os.IsNotExist(err), "stale certificate should be removed")
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "secure.lb.local.pem"))
+	assert.NoError(t, err)
+	assert.Equal(t, "cert\nkey\n", string(data), "certificate content mismatch")
+
+	info, err := os.Stat(filepath.Join(dir, "secure.lb.local.pem"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "certificate should be private")
+}
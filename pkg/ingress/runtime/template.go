@@ -81,7 +81,8 @@ frontend http
   http-send-name-header Host
   http-request set-header Host %[req.hdr(Host)]
   http-request set-header X-Forwarded-Host %[req.hdr(Host)]
-
+  {{if $.TLS}}{{range $domain := $.TLS.Redirect}}http-request redirect scheme https code 301 if { hdr_dom(host) -i {{$domain}} }
+  {{end}}{{end}}
   {{range $domain, $acl := .Rules}}{{range $path, $backend := $acl}}acl r_{{$backend}}  hdr_dom(host) -i {{$domain}}  path_beg {{$path}}
   {{end}}{{end}}
   {{range $domain, $acl := .Rules}}{{range $path, $backend := $acl}}acl r_{{$backend}}_down  nbsrv({{$backend}}) lt 1
@@ -97,15 +98,12 @@ frontend https
   option socket-stats
   tcp-request inspect-delay 5s
   tcp-request content accept if { req_ssl_hello_type 1 }
-  {{range $domain, $acl := .Rules}}{{range $path, $backend := $acl}}acl r_{{$backend}}  hdr_dom(host) -i {{$domain}}  path_beg {{$path}}
+  {{if $.TLS}}{{range $domain, $acl := $.TLS.Rules}}use_backend tls_terminate if { req_ssl_sni -i {{$domain}} }
   {{end}}{{end}}
-	{{range $domain, $acl := .Rules}}{{range $path, $backend := $acl}}acl r_{{$backend}}_down  nbsrv({{$backend}}) lt 1
-  {{end}}{{end}}
-  {{range $domain, $acl := .Rules}}{{range $path, $backend := $acl}}use_backend local_http if r_{{$backend}}_down r_{{$backend}}
+  {{range $domain, $acl := .Rules}}{{range $path, $backend := $acl}}acl r_{{$backend}}  req_ssl_sni -i {{$domain}}
   {{end}}{{end}}
   {{range $domain, $acl := .Rules}}{{range $path, $backend := $acl}}use_backend {{$backend}} if r_{{$backend}}
   {{end}}{{end}}
-  default_backend local_http
 {{else if eq $f.Type "tcp" }}
 frontend {{$port}}_tcp
   bind 0.0.0.0:{{$port}}
@@ -114,6 +112,31 @@ frontend {{$port}}_tcp
   default_backend local_http
 {{end}}{{end}}
 
+{{if .TLS}}
+#---------------------------------------------------------------------
+# frontend which terminates ssl for routes with certificates
+#---------------------------------------------------------------------
+backend tls_terminate
+  server tls_terminate abns@tls_terminate send-proxy-v2
+
+frontend tls_terminate
+  mode http
+  bind abns@tls_terminate accept-proxy ssl crt {{.TLS.Path}}
+  http-send-name-header Host
+  http-request set-header Host %[req.hdr(Host)]
+  http-request set-header X-Forwarded-Host %[req.hdr(Host)]
+  http-request set-header X-Forwarded-Proto https
+
+  {{range $domain, $acl := .TLS.Rules}}{{range $path, $backend := $acl}}acl r_{{$backend}}  hdr_dom(host) -i {{$domain}}  path_beg {{$path}}
+  {{end}}{{end}}
+  {{range $domain, $acl := .TLS.Rules}}{{range $path, $backend := $acl}}acl r_{{$backend}}_down  nbsrv({{$backend}}) lt 1
+  {{end}}{{end}}
+  {{range $domain, $acl := .TLS.Rules}}{{range $path, $backend := $acl}}use_backend local_http if r_{{$backend}}_down r_{{$backend}}
+  {{end}}{{end}}
+  {{range $domain, $acl := .TLS.Rules}}{{range $path, $backend := $acl}}use_backend {{$backend}} if r_{{$backend}}
+  {{end}}{{end}}
+  default_backend local_http
+{{end}}
 #---------------------------------------------------------------------
 # local proxy configuration
 #---------------------------------------------------------------------
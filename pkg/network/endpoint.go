@@ -173,5 +173,12 @@ func endpointEqual(manifest *types.EndpointManifest, state *types.EndpointState)
 		}
 	}
 
+	for _, up := range manifest.Upstreams {
+		if manifest.Weight(up) != state.Weight(up) {
+			log.V(logLevel).Debugf("%s upstream weight changed: %s %d != %d", logEndpointPrefix, up, manifest.Weight(up), state.Weight(up))
+			return false
+		}
+	}
+
 	return true
 }
@@ -68,6 +68,7 @@ func (c *Controller) Connect(ctx context.Context) error {
 	opts.Info = envs.Get().GetState().Node().Info
 	opts.Status = envs.Get().GetState().Node().Status
 	opts.Network = *envs.Get().GetNet().Info(ctx)
+	opts.Port = uint16(viper.GetInt("node.port"))
 
 	if viper.IsSet("node.tls") {
 		opts.TLS = !viper.GetBool("node.tls.insecure")
@@ -218,9 +219,11 @@ func getPodOptions(p *types.PodStatus) *request.NodePodStatusOptions {
 	opts.Status = p.Status
 	opts.Running = p.Running
 	opts.Message = p.Message
+	opts.Ready = p.Ready
 	opts.Containers = p.Containers
 	opts.Network = p.Network
 	opts.Steps = p.Steps
+	opts.Usage = p.Usage
 	return opts
 }
 
@@ -21,6 +21,7 @@ package http
 import (
 	"github.com/gorilla/mux"
 	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/node/http/metrics"
 	"github.com/lastbackend/lastbackend/pkg/node/http/node"
 	"github.com/lastbackend/lastbackend/pkg/node/http/pod"
 	"github.com/lastbackend/lastbackend/pkg/util/http"
@@ -52,6 +53,7 @@ func AddRoutes(r ...[]http.Route) {
 func init() {
 	AddRoutes(node.Routes)
 	AddRoutes(pod.Routes)
+	AddRoutes(metrics.Routes)
 }
 
 func Listen(host string, port int, opts *HttpOpts) error {
@@ -0,0 +1,75 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package metrics
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lastbackend/lastbackend/pkg/node/envs"
+	"github.com/lastbackend/lastbackend/pkg/util/metrics"
+)
+
+func init() {
+	metrics.Register("node:pod", PodCollector)
+}
+
+// PodCollector returns resources usage of running pods on node
+func PodCollector(ctx context.Context) ([]*metrics.Metric, error) {
+
+	var (
+		state = envs.Get().GetState().Pods()
+		usage = metrics.NewUsageMetrics("lb_pod", "pod containers")
+	)
+
+	for key, status := range state.GetPods() {
+
+		if status == nil || !status.Running {
+			continue
+		}
+
+		usage.Add(status.Usage, podLabels(key))
+	}
+
+	pods := metrics.NewGauge("lb_node_pods", "Number of pods on node.").
+		Add(float64(state.GetPodsCount()), nil)
+
+	containers := metrics.NewGauge("lb_node_containers", "Number of containers on node.").
+		Add(float64(state.GetContainersCount()), nil)
+
+	return append(usage.Metrics(), pods, containers), nil
+}
+
+// podLabels returns labels parsed from pod self link: namespace:service:deployment:name
+func podLabels(key string) metrics.Labels {
+
+	labels := metrics.Labels{"pod": key}
+
+	parts := strings.Split(key, ":")
+	if len(parts) != 4 {
+		return labels
+	}
+
+	labels["namespace"] = parts[0]
+	labels["service"] = parts[1]
+	labels["deployment"] = parts[2]
+	labels["pod"] = parts[3]
+
+	return labels
+}
@@ -16,29 +16,14 @@
 // from Last.Backend LLC.
 //
 
-package cache
+package metrics
 
 import (
-	"sync"
-	"time"
+	"github.com/lastbackend/lastbackend/pkg/util/http"
+	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
+	"github.com/lastbackend/lastbackend/pkg/util/metrics"
 )
 
-type Item struct {
-	sync.RWMutex
-	data    []string
-	expires *time.Time
-}
-
-func (item *Item) setExpireTime(duration time.Duration) {
-	item.Lock()
-	defer item.Unlock()
-
-	expires := time.Now().Add(duration)
-	item.expires = &expires
-}
-
-func (item *Item) expired() bool {
-	item.RLock()
-	defer item.RUnlock()
-	return item.expires == nil || item.expires.Before(time.Now())
+var Routes = []http.Route{
+	{Path: "/metrics", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: metrics.Handler},
 }
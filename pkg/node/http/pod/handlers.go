@@ -19,13 +19,19 @@
 package pod
 
 import (
+	"context"
 	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/node/envs"
 	"github.com/lastbackend/lastbackend/pkg/node/runtime"
+	"github.com/lastbackend/lastbackend/pkg/util/console"
+	"github.com/lastbackend/lastbackend/pkg/util/portforward"
+	"net"
 	"net/http"
+	"strconv"
 )
 
 const logLevel = 2
@@ -71,3 +77,122 @@ fmt.Println(">>>>>>", mux.Vars(r)["pod"])
 
 	return
 }
+
+// PodExecH handler runs command in pod container with streams attached to websocket connection
+func PodExecH(w http.ResponseWriter, r *http.Request) {
+
+	log.V(logLevel).Debug("node:http:pod:exec:> exec in pod container")
+
+	var (
+		query    = r.URL.Query()
+		command  = query["command"]
+		tty, _   = strconv.ParseBool(query.Get("tty"))
+		stdin, _ = strconv.ParseBool(query.Get("stdin"))
+	)
+
+	if len(command) == 0 {
+		log.Errorf("node:http:pod:exec:> command not provided")
+		errors.New("command").BadParameter("command").Http(w)
+		return
+	}
+
+	c := runtime.PodContainerGet(mux.Vars(r)["pod"], mux.Vars(r)["container"])
+	if c == nil {
+		log.Errorf("node:http:pod:exec:> container not found")
+		errors.New("container").NotFound().Http(w)
+		return
+	}
+
+	conn, err := console.Upgrade(w, r)
+	if err != nil {
+		log.Errorf("node:http:pod:exec:> websocket upgrade err: %s", err.Error())
+		return
+	}
+	defer conn.Close()
+
+	opts := &types.ContainerExecOptions{Command: command, TTY: tty}
+
+	err = console.Serve(r.Context(), conn, stdin, func(ctx context.Context, streams *types.ContainerStreams) (int, error) {
+		return runtime.PodExec(ctx, c.ID, opts, streams)
+	})
+	if err != nil {
+		log.Errorf("node:http:pod:exec:> console err: %s", err.Error())
+	}
+}
+
+// PodAttachH handler attaches pod container main process streams to websocket connection
+func PodAttachH(w http.ResponseWriter, r *http.Request) {
+
+	log.V(logLevel).Debug("node:http:pod:attach:> attach to pod container")
+
+	stdin, _ := strconv.ParseBool(r.URL.Query().Get("stdin"))
+
+	c := runtime.PodContainerGet(mux.Vars(r)["pod"], mux.Vars(r)["container"])
+	if c == nil {
+		log.Errorf("node:http:pod:attach:> container not found")
+		errors.New("container").NotFound().Http(w)
+		return
+	}
+
+	conn, err := console.Upgrade(w, r)
+	if err != nil {
+		log.Errorf("node:http:pod:attach:> websocket upgrade err: %s", err.Error())
+		return
+	}
+	defer conn.Close()
+
+	err = console.Serve(r.Context(), conn, stdin, func(ctx context.Context, streams *types.ContainerStreams) (int, error) {
+		return 0, runtime.PodAttach(ctx, c.ID, streams)
+	})
+	if err != nil {
+		log.Errorf("node:http:pod:attach:> console err: %s", err.Error())
+	}
+}
+
+// PodPortForwardH handler forwards pod ports over websocket connection
+func PodPortForwardH(w http.ResponseWriter, r *http.Request) {
+
+	log.V(logLevel).Debug("node:http:pod:portforward:> forward pod ports")
+
+	var ports = make([]uint16, 0)
+
+	for _, p := range r.URL.Query()["port"] {
+		port, err := strconv.ParseUint(p, 10, 16)
+		if err != nil || port == 0 {
+			log.Errorf("node:http:pod:portforward:> invalid port %s", p)
+			errors.New("port").BadParameter("port").Http(w)
+			return
+		}
+		ports = append(ports, uint16(port))
+	}
+
+	if len(ports) == 0 {
+		log.Errorf("node:http:pod:portforward:> ports not provided")
+		errors.New("port").BadParameter("port").Http(w)
+		return
+	}
+
+	p := envs.Get().GetState().Pods().GetPod(mux.Vars(r)["pod"])
+	if p == nil || p.Network.PodIP == types.EmptyString {
+		log.Errorf("node:http:pod:portforward:> pod not found")
+		errors.New("pod").NotFound().Http(w)
+		return
+	}
+
+	conn, err := console.Upgrade(w, r)
+	if err != nil {
+		log.Errorf("node:http:pod:portforward:> websocket upgrade err: %s", err.Error())
+		return
+	}
+	defer conn.Close()
+
+	ip := p.Network.PodIP
+
+	err = portforward.Serve(r.Context(), conn, ports, func(ctx context.Context, port uint16) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", net.JoinHostPort(ip, strconv.Itoa(int(port))))
+	})
+	if err != nil {
+		log.Errorf("node:http:pod:portforward:> forward err: %s", err.Error())
+	}
+}
@@ -26,4 +26,7 @@ import (
 var Routes = []http.Route{
 	{Path: "/pod/{pod}", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: PodGetH},
 	{Path: "/pod/{pod}/{container}/logs", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Authenticate}, Handler: PodLogsH},
+	{Path: "/pod/{pod}/{container}/exec", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Cluster}, Handler: PodExecH},
+	{Path: "/pod/{pod}/{container}/attach", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Cluster}, Handler: PodAttachH},
+	{Path: "/pod/{pod}/portforward", Method: http.MethodGet, Middleware: []http.Middleware{middleware.Cluster}, Handler: PodPortForwardH},
 }
@@ -34,6 +34,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/node/state"
 
 	"github.com/lastbackend/lastbackend/pkg/api/client"
+	"github.com/lastbackend/lastbackend/pkg/api/client/identity"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"github.com/lastbackend/lastbackend/pkg/node/envs"
 	"github.com/lastbackend/lastbackend/pkg/node/http"
@@ -105,6 +106,7 @@ func Daemon() {
 	r.Restore(ctx)
 	r.Subscribe(ctx)
 	r.Loop(ctx)
+	go r.Usage(ctx)
 
 	if viper.IsSet("node.manifest.dir") ||  viper.IsSet("dir") {
 
@@ -135,6 +137,26 @@ func Daemon() {
 			endpoint = viper.GetString("api_uri")
 		}
 
+		// Agent client certificate is issued by cluster with one-time join token
+		if viper.IsSet("api.tls.dir") {
+			opts := identity.Options{
+				Endpoint: endpoint,
+				Kind:     types.AccountKindNode,
+				Name:     st.Node().Info.Hostname,
+				Token:    viper.GetString("api.tls.join_token"),
+				Dir:      viper.GetString("api.tls.dir"),
+				CAFile:   viper.GetString("api.tls.ca"),
+			}
+
+			tls, err := identity.Bootstrap(context.Background(), opts)
+			if err != nil {
+				log.Errorf("Issue client certificate err: %s", err)
+			} else {
+				cfg.TLS = tls
+				go identity.Rotate(context.Background(), opts)
+			}
+		}
+
 		rest, err := client.New(client.ClientHTTP, endpoint, cfg)
 		if err != nil {
 			log.Errorf("Init client err: %s", err)
@@ -151,6 +151,14 @@ func containerManifestCreate(ctx context.Context, pod string, spec *types.SpecTe
 	}
 
 	mf.Labels[types.ContainerTypeLBC] = pod
+	if spec.Role != types.EmptyString {
+		mf.Labels[types.ContainerRoleLBC] = spec.Role
+	}
+
+	// init container is not restarted: pod fails if it exits with error
+	if spec.Init() {
+		mf.RestartPolicy = types.SpecTemplateRestartPolicy{}
+	}
 
 	for _, s := range spec.EnvVars {
 
@@ -24,6 +24,7 @@ import (
 	"github.com/lastbackend/lastbackend/pkg/util/system"
 	"github.com/shirou/gopsutil/mem"
 	"github.com/spf13/viper"
+	"runtime"
 	"syscall"
 
 	"fmt"
@@ -90,6 +91,7 @@ func NodeCapacity() types.NodeResources {
 	return types.NodeResources{
 		Storage: 		int64(storage/1024/1024),
 		Memory:     int64(m),
+		Cpu:        runtime.NumCPU() * types.CPUSharesPerCore,
 		Pods:       int(m / MinContainerMemory),
 		Containers: int(m / MinContainerMemory),
 	}
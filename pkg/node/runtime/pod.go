@@ -144,6 +144,7 @@ func PodManage(ctx context.Context, key string, manifest *types.PodManifest) err
 			}
 			return PodRestart(ctx, key)
 		default:
+			podProbesRestore(key, manifest)
 			return nil
 		}
 	}
@@ -172,9 +173,28 @@ func PodRestart(ctx context.Context, key string) error {
 
 	cri := envs.Get().GetCRI()
 
+	// app containers are stopped before sidecars restart and started after
 	for _, c := range pod.Containers {
-		if err := cri.Restart(ctx, c.ID, nil); err != nil {
-			return err
+		if c.Role != types.ContainerRoleSidecar {
+			if err := cri.Stop(ctx, c.ID, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, c := range pod.Containers {
+		if c.Role == types.ContainerRoleSidecar {
+			if err := cri.Restart(ctx, c.ID, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, c := range pod.Containers {
+		if c.Role != types.ContainerRoleSidecar {
+			if err := cri.Start(ctx, c.ID); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -290,111 +310,250 @@ func PodCreate(ctx context.Context, key string, manifest *types.PodManifest) (*t
 		}
 	}
 
+	//==========================================================================
+	// Run init containers =====================================================
+	//==========================================================================
+
+	var initialized bool
 	for _, s := range manifest.Template.Containers {
 
-		//==========================================================================
-		// Create container ========================================================
-		//==========================================================================
+		if !s.Init() {
+			continue
+		}
 
-		var (
-			c = new(types.PodContainer)
-		)
+		log.V(logLevel).Debugf("%s run init container: %s", logPodPrefix, s.Name)
 
-		m, err := containerManifestCreate(ctx, key, s)
-		if err != nil {
-			log.Errorf("%s can not create container manifest from spec: %s", logPodPrefix, err.Error())
-			return setError(err)
-		}
+		if err := podInitContainerRun(ctx, key, status, s); err != nil {
 
-		c.ID, err = envs.Get().GetCRI().Create(ctx, m)
-		if err != nil {
-			switch err {
-			case context.Canceled:
-				log.Errorf("%s stop creating container: %s", logPodPrefix, err.Error())
+			if err == context.Canceled {
+				log.Errorf("%s stop running init container: %s", logPodPrefix, s.Name)
 				PodClean(context.Background(), status)
 				return status, nil
 			}
 
-			log.Errorf("%s can-not create container: %s", logPodPrefix, err)
-			c.State.Error = types.PodContainerStateError{
-				Error:   true,
-				Message: err.Error(),
-				Exit: types.PodContainerStateExit{
-					Timestamp: time.Now().UTC(),
-				},
-			}
-			return status, err
-		}
-
-		if err := containerInspect(context.Background(), status, c); err != nil {
-			log.Errorf("%s inspect container after create: err %s", logPodPrefix, err.Error())
+			log.Errorf("%s init container %s failed: %s", logPodPrefix, s.Name, err.Error())
+			status.SetError(err)
 			PodClean(context.Background(), status)
 			return status, err
 		}
 
-		//==========================================================================
-		// Start container =========================================================
-		//==========================================================================
+		initialized = true
+	}
 
-		c.State.Created = types.PodContainerStateCreated{
-			Created: time.Now().UTC(),
+	if initialized {
+		status.Steps[types.StepInit] = types.PodStep{
+			Ready:     true,
+			Timestamp: time.Now().UTC(),
 		}
-		status.Containers[c.ID] = c
 		envs.Get().GetState().Pods().SetPod(key, status)
-		log.V(logLevel).Debugf("%s container created: %s", logPodPrefix, c.ID)
+	}
 
-		if err := envs.Get().GetCRI().Start(ctx, c.ID); err != nil {
+	//==========================================================================
+	// Run sidecar and app containers ==========================================
+	//==========================================================================
 
-			log.Errorf("%s can-not start container: %s", logPodPrefix, err)
+	for _, s := range podContainersStartOrder(manifest.Template.Containers) {
 
-			switch err {
-			case context.Canceled:
-				log.Errorf("%s stop starting container err: %s", logPodPrefix, err.Error())
-				PodClean(context.Background(), status)
+		c, err := podContainerStart(ctx, key, status, s)
+		if err != nil {
+			if err == context.Canceled {
 				return status, nil
 			}
+			return status, err
+		}
 
-			c.State.Error = types.PodContainerStateError{
-				Error:   true,
-				Message: err.Error(),
-				Exit: types.PodContainerStateExit{
-					Timestamp: time.Now().UTC(),
-				},
-			}
+		ContainerProbesStart(key, c, s)
+	}
 
-			status.Containers[c.ID] = c
-			return status, err
+	status.SetRunning()
+	status.Steps[types.StepReady] = types.PodStep{
+		Ready:     true,
+		Timestamp: time.Now().UTC(),
+	}
+
+	envs.Get().GetState().Pods().SetPod(key, status)
+	return status, nil
+}
+
+// podContainerStart creates and starts pod container, container is added to pod status
+func podContainerStart(ctx context.Context, key string, status *types.PodStatus, s *types.SpecTemplateContainer) (*types.PodContainer, error) {
+
+	//==========================================================================
+	// Create container ========================================================
+	//==========================================================================
+
+	var (
+		c = new(types.PodContainer)
+	)
+
+	c.Pod = key
+	c.Role = s.Role
+
+	m, err := containerManifestCreate(ctx, key, s)
+	if err != nil {
+		log.Errorf("%s can not create container manifest from spec: %s", logPodPrefix, err.Error())
+		status.SetError(err)
+		PodClean(ctx, status)
+		return nil, err
+	}
+
+	c.ID, err = envs.Get().GetCRI().Create(ctx, m)
+	if err != nil {
+		switch err {
+		case context.Canceled:
+			log.Errorf("%s stop creating container: %s", logPodPrefix, err.Error())
+			PodClean(context.Background(), status)
+			return nil, err
+		}
+
+		log.Errorf("%s can-not create container: %s", logPodPrefix, err)
+		c.State.Error = types.PodContainerStateError{
+			Error:   true,
+			Message: err.Error(),
+			Exit: types.PodContainerStateExit{
+				Timestamp: time.Now().UTC(),
+			},
 		}
+		return nil, err
+	}
 
-		log.V(logLevel).Debugf("%s container started: %s", logPodPrefix, c.ID)
+	if err := containerInspect(context.Background(), status, c); err != nil {
+		log.Errorf("%s inspect container after create: err %s", logPodPrefix, err.Error())
+		PodClean(context.Background(), status)
+		return nil, err
+	}
 
-		if err := containerInspect(context.Background(), status, c); err != nil {
-			log.Errorf("%s inspect container after create: err %s", logPodPrefix, err.Error())
-			return status, err
+	//==========================================================================
+	// Start container =========================================================
+	//==========================================================================
+
+	c.State.Created = types.PodContainerStateCreated{
+		Created: time.Now().UTC(),
+	}
+	status.Containers[c.ID] = c
+	envs.Get().GetState().Pods().SetPod(key, status)
+	log.V(logLevel).Debugf("%s container created: %s", logPodPrefix, c.ID)
+
+	if err := envs.Get().GetCRI().Start(ctx, c.ID); err != nil {
+
+		log.Errorf("%s can-not start container: %s", logPodPrefix, err)
+
+		switch err {
+		case context.Canceled:
+			log.Errorf("%s stop starting container err: %s", logPodPrefix, err.Error())
+			PodClean(context.Background(), status)
+			return nil, err
 		}
 
-		c.Ready = true
-		c.State.Started = types.PodContainerStateStarted{
-			Started:   true,
-			Timestamp: time.Now().UTC(),
+		c.State.Error = types.PodContainerStateError{
+			Error:   true,
+			Message: err.Error(),
+			Exit: types.PodContainerStateExit{
+				Timestamp: time.Now().UTC(),
+			},
 		}
+
 		status.Containers[c.ID] = c
-		envs.Get().GetState().Pods().SetPod(key, status)
+		return nil, err
 	}
 
-	status.SetRunning()
-	status.Steps[types.StepReady] = types.PodStep{
-		Ready:     true,
+	log.V(logLevel).Debugf("%s container started: %s", logPodPrefix, c.ID)
+
+	if err := containerInspect(context.Background(), status, c); err != nil {
+		log.Errorf("%s inspect container after create: err %s", logPodPrefix, err.Error())
+		return nil, err
+	}
+
+	// container without readiness probe is ready right after start
+	c.Ready = !s.Probes.ReadProbe.Defined()
+	c.State.Started = types.PodContainerStateStarted{
+		Started:   true,
 		Timestamp: time.Now().UTC(),
 	}
+	status.Containers[c.ID] = c
+	envs.Get().GetState().Pods().SetPod(key, status)
+
+	return c, nil
+}
+
+// podInitContainerRun starts init container and waits for its completion,
+// completed container is removed from pod, non zero exit code fails the pod
+func podInitContainerRun(ctx context.Context, key string, status *types.PodStatus, s *types.SpecTemplateContainer) error {
+
+	c, err := podContainerStart(ctx, key, status, s)
+	if err != nil {
+		return err
+	}
 
+	code, err := envs.Get().GetCRI().Wait(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("init container %s exited with code %d", s.Name, code)
+	}
+
+	log.V(logLevel).Debugf("%s init container completed: %s", logPodPrefix, c.ID)
+
+	if err := envs.Get().GetCRI().Remove(ctx, c.ID, true, true); err != nil {
+		log.Warnf("%s can-not remove init container %s: %s", logPodPrefix, c.ID, err)
+	}
+
+	envs.Get().GetState().Pods().DelContainer(c)
+	delete(status.Containers, c.ID)
 	envs.Get().GetState().Pods().SetPod(key, status)
-	return status, nil
+
+	return nil
+}
+
+// podContainersStartOrder returns pod containers except init ones in start order:
+// sidecars are started before app containers
+func podContainersStartOrder(containers types.SpecTemplateContainers) types.SpecTemplateContainers {
+
+	var (
+		sidecars = make(types.SpecTemplateContainers, 0)
+		apps     = make(types.SpecTemplateContainers, 0)
+	)
+
+	for _, s := range containers {
+		switch true {
+		case s.Init():
+			continue
+		case s.Sidecar():
+			sidecars = append(sidecars, s)
+		default:
+			apps = append(apps, s)
+		}
+	}
+
+	return append(sidecars, apps...)
+}
+
+// podContainersStopOrder returns pod containers in stop order:
+// sidecars are stopped after app containers
+func podContainersStopOrder(containers map[string]*types.PodContainer) []*types.PodContainer {
+
+	var (
+		sidecars = make([]*types.PodContainer, 0)
+		apps     = make([]*types.PodContainer, 0)
+	)
+
+	for _, c := range containers {
+		if c.Role == types.ContainerRoleSidecar {
+			sidecars = append(sidecars, c)
+			continue
+		}
+		apps = append(apps, c)
+	}
+
+	return append(apps, sidecars...)
 }
 
 func PodClean(ctx context.Context, status *types.PodStatus) {
 
-	for _, c := range status.Containers {
+	for _, c := range podContainersStopOrder(status.Containers) {
+		ContainerProbesStop(c.ID)
 		log.V(logLevel).Debugf("%s remove unnecessary container: %s", logPodPrefix, c.ID)
 		if err := envs.Get().GetCRI().Remove(ctx, c.ID, true, true); err != nil {
 			log.Warnf("%s can-not remove unnecessary container %s: %s", logPodPrefix, c.ID, err)
@@ -449,6 +608,7 @@ func PodRestore(ctx context.Context) error {
 		cs := &types.PodContainer{
 			ID:   c.ID,
 			Name: c.Name,
+			Role: c.Labels[types.ContainerRoleLBC],
 			Image: types.PodContainerImage{
 				Name: c.Image,
 			},
@@ -596,6 +756,54 @@ func PodLogs(ctx context.Context, id string, follow bool, s io.Writer, doneChan
 	return nil
 }
 
+// PodContainerGet returns pod container found by id or name
+func PodContainerGet(key, container string) *types.PodContainer {
+
+	p := envs.Get().GetState().Pods().GetPod(key)
+	if p == nil {
+		return nil
+	}
+
+	if c, ok := p.Containers[container]; ok {
+		return c
+	}
+
+	for _, c := range p.Containers {
+		if c.Name == container {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// PodExec runs command in pod container with attached streams and returns command exit code
+func PodExec(ctx context.Context, id string, opts *types.ContainerExecOptions, streams *types.ContainerStreams) (int, error) {
+
+	log.V(logLevel).Debugf("%s exec in container [%s]: %v", logPodPrefix, id, opts.Command)
+
+	code, err := envs.Get().GetCRI().Exec(ctx, id, opts, streams)
+	if err != nil {
+		log.Errorf("%s exec in container [%s] err: %s", logPodPrefix, id, err)
+		return code, err
+	}
+
+	return code, nil
+}
+
+// PodAttach attaches streams to pod container main process
+func PodAttach(ctx context.Context, id string, streams *types.ContainerStreams) error {
+
+	log.V(logLevel).Debugf("%s attach to container [%s]", logPodPrefix, id)
+
+	if err := envs.Get().GetCRI().Attach(ctx, id, streams); err != nil {
+		log.Errorf("%s attach to container [%s] err: %s", logPodPrefix, id, err)
+		return err
+	}
+
+	return nil
+}
+
 func PodSpecCheck(ctx context.Context, key string, manifest *types.PodManifest) bool {
 
 	log.V(logLevel).Infof("%s pod check spec pod: %s", logPodPrefix, key)
@@ -606,6 +814,10 @@ func PodSpecCheck(ctx context.Context, key string, manifest *types.PodManifest)
 	var specc = make(map[string]*types.ContainerManifest, 0)
 
 	for _, c := range manifest.Template.Containers {
+		// init containers are removed from pod after completion
+		if c.Init() {
+			continue
+		}
 		mf, err := containerManifestCreate(ctx, key, c)
 		if err != nil {
 			return false
@@ -1008,6 +1220,39 @@ func podVolumeClaimRestore(key string, manifest *types.PodManifest) {
 	}
 }
 
+// podProbesRestore starts probes for pod containers restored from runtime,
+// restored container is not ready until its readiness probe passes
+func podProbesRestore(key string, manifest *types.PodManifest) {
+
+	pod := envs.Get().GetState().Pods().GetPod(key)
+	if pod == nil {
+		return
+	}
+
+	name := strings.Split(key, ":")
+
+	for _, s := range manifest.Template.Containers {
+		for _, c := range pod.Containers {
+			if c.Name != fmt.Sprintf("%s-%s", name[len(name)-1], s.Name) {
+				continue
+			}
+
+			if envs.Get().GetState().Tasks().GetTask(probeTaskKey(c.ID)) == nil {
+				ready := !s.Probes.ReadProbe.Defined()
+				envs.Get().GetState().Pods().UpdateContainer(key, c.ID, func(container *types.PodContainer) bool {
+					if container.Ready == ready {
+						return false
+					}
+					container.Ready = ready
+					return true
+				})
+			}
+
+			ContainerProbesStart(key, c, s)
+		}
+	}
+}
+
 func podVolumeKeyCreate(pod, volume string) string {
 	return fmt.Sprintf("%s-%s", strings.Replace(pod, ":", "-", -1), volume)
 }
@@ -0,0 +1,90 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPodContainersStartOrder(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		roles map[string]string
+		order []string
+		want  []string
+	}{
+		{
+			name:  "containers without roles keep template order",
+			order: []string{"web", "worker"},
+			want:  []string{"web", "worker"},
+		},
+		{
+			name:  "init containers are skipped",
+			roles: map[string]string{"migrate": types.ContainerRoleInit, "fetch": types.ContainerRoleInit},
+			order: []string{"migrate", "web", "fetch"},
+			want:  []string{"web"},
+		},
+		{
+			name:  "sidecars are started before app containers",
+			roles: map[string]string{"web": types.ContainerRolePrimary, "proxy": types.ContainerRoleSidecar, "logs": types.ContainerRoleSidecar},
+			order: []string{"web", "proxy", "logs"},
+			want:  []string{"proxy", "logs", "web"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			containers := make(types.SpecTemplateContainers, 0)
+			for _, name := range tc.order {
+				containers = append(containers, &types.SpecTemplateContainer{Name: name, Role: tc.roles[name]})
+			}
+
+			names := make([]string, 0)
+			for _, c := range podContainersStartOrder(containers) {
+				names = append(names, c.Name)
+			}
+
+			assert.Equal(t, tc.want, names, "start order mismatch")
+		})
+	}
+}
+
+func TestPodContainersStopOrder(t *testing.T) {
+
+	containers := map[string]*types.PodContainer{
+		"1": {ID: "1", Name: "proxy", Role: types.ContainerRoleSidecar},
+		"2": {ID: "2", Name: "web", Role: types.ContainerRolePrimary},
+		"3": {ID: "3", Name: "worker"},
+		"4": {ID: "4", Name: "logs", Role: types.ContainerRoleSidecar},
+	}
+
+	list := podContainersStopOrder(containers)
+	if !assert.Len(t, list, len(containers), "containers count mismatch") {
+		return
+	}
+
+	for i, c := range list {
+		assert.Equal(t, i >= 2, c.Role == types.ContainerRoleSidecar, "container %s stopped out of order", c.Name)
+	}
+}
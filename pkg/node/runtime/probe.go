@@ -0,0 +1,370 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/node/envs"
+)
+
+const (
+	logProbePrefix = "node:runtime:probe:>"
+
+	probeDefaultTimeout          = 1
+	probeDefaultPeriod           = 10
+	probeDefaultThresholdSuccess = 1
+	probeDefaultThresholdFailure = 3
+)
+
+// probeCounter counts consecutive probe results and switches state on thresholds
+type probeCounter struct {
+	lock      sync.Mutex
+	success   int
+	failure   int
+	threshold struct {
+		success int
+		failure int
+	}
+	// current probe state: nil until first threshold is reached
+	state *bool
+}
+
+func newProbeCounter(probe types.SpecTemplateContainerProbe) *probeCounter {
+	c := new(probeCounter)
+
+	c.threshold.success = probe.ThresholdSuccess
+	if c.threshold.success <= 0 {
+		c.threshold.success = probeDefaultThresholdSuccess
+	}
+
+	c.threshold.failure = probe.ThresholdFailure
+	if c.threshold.failure <= 0 {
+		c.threshold.failure = probeDefaultThresholdFailure
+	}
+
+	return c
+}
+
+// observe records probe result and returns true with new state when state is switched
+func (c *probeCounter) observe(ok bool) (bool, bool) {
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if ok {
+		c.success++
+		c.failure = 0
+	} else {
+		c.failure++
+		c.success = 0
+	}
+
+	switch true {
+	case ok && c.success >= c.threshold.success && (c.state == nil || !*c.state):
+		c.state = &ok
+		return true, ok
+	case !ok && c.failure >= c.threshold.failure && (c.state == nil || *c.state):
+		c.state = &ok
+		return true, ok
+	}
+
+	return false, false
+}
+
+// reset drops counters and state, used after container restart
+func (c *probeCounter) reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.success = 0
+	c.failure = 0
+	c.state = nil
+}
+
+// ContainerProbesStart starts liveness and readiness probes for pod container
+func ContainerProbesStart(key string, c *types.PodContainer, spec *types.SpecTemplateContainer) {
+
+	if !spec.Probes.LiveProbe.Defined() && !spec.Probes.ReadProbe.Defined() {
+		return
+	}
+
+	if task := envs.Get().GetState().Tasks().GetTask(probeTaskKey(c.ID)); task != nil {
+		return
+	}
+
+	log.V(logLevel).Debugf("%s start container probes: %s: %s", logProbePrefix, key, c.ID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	envs.Get().GetState().Tasks().AddTask(probeTaskKey(c.ID), &types.NodeTask{Cancel: cancel})
+
+	// readiness counter is shared with liveness handler to drop readiness state after container restart
+	readiness := newProbeCounter(spec.Probes.ReadProbe)
+
+	if spec.Probes.ReadProbe.Defined() {
+		go probeWatch(ctx, key, c.ID, spec.Probes.ReadProbe, readiness, func(ok bool) bool {
+			containerReadySet(key, c.ID, ok)
+			return false
+		})
+	}
+
+	if spec.Probes.LiveProbe.Defined() {
+		go probeWatch(ctx, key, c.ID, spec.Probes.LiveProbe, newProbeCounter(spec.Probes.LiveProbe), func(ok bool) bool {
+			if ok {
+				return false
+			}
+			if !containerLivenessFail(ctx, key, c.ID, spec) {
+				return false
+			}
+			readiness.reset()
+			return true
+		})
+	}
+}
+
+// ContainerProbesStop stops container probes
+func ContainerProbesStop(id string) {
+	envs.Get().GetState().Tasks().CancelTask(probeTaskKey(id))
+}
+
+// probeWatch runs probe periodically and calls handler on probe state change,
+// probe counters are reset and initial delay is respected again if handler returns true
+func probeWatch(ctx context.Context, key, id string, probe types.SpecTemplateContainerProbe, counter *probeCounter, handler func(ok bool) bool) {
+
+	var (
+		period = time.Duration(probe.PeriodSeconds) * time.Second
+		delay  = time.Duration(probe.InitialDelaySeconds) * time.Second
+	)
+
+	if probe.PeriodSeconds <= 0 {
+		period = probeDefaultPeriod * time.Second
+	}
+
+	for {
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		err := probeRun(ctx, key, id, probe)
+		if err != nil {
+			log.V(logLevel).Debugf("%s container %s probe failed: %s", logProbePrefix, id, err.Error())
+		}
+
+		delay = period
+
+		changed, ok := counter.observe(err == nil)
+		if !changed {
+			continue
+		}
+
+		if handler(ok) {
+			counter.reset()
+			delay = time.Duration(probe.InitialDelaySeconds) * time.Second
+		}
+	}
+}
+
+// probeRun executes single probe check
+func probeRun(ctx context.Context, key, id string, probe types.SpecTemplateContainerProbe) error {
+
+	timeout := time.Duration(probe.TimeoutSeconds) * time.Second
+	if probe.TimeoutSeconds <= 0 {
+		timeout = probeDefaultTimeout * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	switch true {
+	case len(probe.Exec.Command) > 0:
+		code, err := envs.Get().GetCRI().Exec(ctx, id, &types.ContainerExecOptions{Command: probe.Exec.Command}, nil)
+		if err != nil {
+			return err
+		}
+		if code != 0 {
+			return fmt.Errorf("exit code %d", code)
+		}
+		return nil
+	}
+
+	pod := envs.Get().GetState().Pods().GetPod(key)
+	if pod == nil || pod.Network.PodIP == types.EmptyString {
+		return errors.New("pod ip not found")
+	}
+
+	switch true {
+	case probe.Socket.Port > 0:
+		return probeSocket(ctx, pod.Network.PodIP, probe)
+	case probe.HTTP.Port > 0:
+		return probeHTTP(ctx, pod.Network.PodIP, probe)
+	}
+
+	return nil
+}
+
+// probeSocket checks that container port accepts connections
+func probeSocket(ctx context.Context, ip string, probe types.SpecTemplateContainerProbe) error {
+
+	proto := strings.ToLower(probe.Socket.Protocol)
+	if proto == types.EmptyString {
+		proto = "tcp"
+	}
+
+	conn, err := new(net.Dialer).DialContext(ctx, proto, net.JoinHostPort(ip, fmt.Sprintf("%d", probe.Socket.Port)))
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
+// probeHTTP checks that container responds with 2xx-3xx status on GET request
+func probeHTTP(ctx context.Context, ip string, probe types.SpecTemplateContainerProbe) error {
+
+	path := probe.HTTP.Path
+	if !strings.HasPrefix(path, "/") {
+		path = fmt.Sprintf("/%s", path)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s%s", net.JoinHostPort(ip, fmt.Sprintf("%d", probe.HTTP.Port)), path), nil)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range probe.HTTP.Headers {
+		if strings.ToLower(name) == "host" {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	res, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("http status %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// containerReadySet updates container readiness in pod state
+func containerReadySet(key, id string, ready bool) {
+	envs.Get().GetState().Pods().UpdateContainer(key, id, func(c *types.PodContainer) bool {
+		if c.Ready == ready {
+			return false
+		}
+
+		log.V(logLevel).Debugf("%s container %s ready: %t", logProbePrefix, id, ready)
+
+		c.Ready = ready
+		return true
+	})
+}
+
+// containerLivenessFail restarts or stops container according to restart policy,
+// returns true if container was restarted
+func containerLivenessFail(ctx context.Context, key, id string, spec *types.SpecTemplateContainer) bool {
+
+	var (
+		found   bool
+		restart bool
+	)
+
+	envs.Get().GetState().Pods().UpdateContainer(key, id, func(c *types.PodContainer) bool {
+		found = true
+
+		switch spec.RestartPolicy.Policy {
+		case "always", "unless-stopped":
+			restart = true
+		case "on-failure":
+			restart = spec.RestartPolicy.Attempt == 0 || c.State.Restarted.Count < spec.RestartPolicy.Attempt
+		}
+
+		return false
+	})
+
+	if !found {
+		return false
+	}
+
+	if restart {
+		log.V(logLevel).Debugf("%s liveness probe failed: restart container: %s", logProbePrefix, id)
+
+		if err := envs.Get().GetCRI().Restart(ctx, id, nil); err != nil {
+			log.Errorf("%s can not restart container %s: %s", logProbePrefix, id, err.Error())
+			return false
+		}
+
+		envs.Get().GetState().Pods().UpdateContainer(key, id, func(c *types.PodContainer) bool {
+			c.Ready = !spec.Probes.ReadProbe.Defined()
+			c.State.Restarted.Count++
+			c.State.Restarted.Restarted = time.Now().UTC()
+			return true
+		})
+		return true
+	}
+
+	log.V(logLevel).Debugf("%s liveness probe failed: stop container: %s", logProbePrefix, id)
+
+	if err := envs.Get().GetCRI().Stop(ctx, id, nil); err != nil {
+		log.Errorf("%s can not stop container %s: %s", logProbePrefix, id, err.Error())
+		return false
+	}
+
+	envs.Get().GetState().Pods().UpdateContainer(key, id, func(c *types.PodContainer) bool {
+		c.Ready = false
+		c.State.Started.Started = false
+		c.State.Stopped = types.PodContainerStateStopped{
+			Stopped: true,
+			Exit: types.PodContainerStateExit{
+				Timestamp: time.Now().UTC(),
+			},
+		}
+		return true
+	})
+
+	ContainerProbesStop(id)
+	return false
+}
+
+func probeTaskKey(id string) string {
+	return fmt.Sprintf("probe:%s", id)
+}
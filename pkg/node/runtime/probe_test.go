@@ -0,0 +1,158 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package runtime
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbeCounter(t *testing.T) {
+
+	type result struct {
+		changed bool
+		state   bool
+	}
+
+	tests := []struct {
+		name    string
+		success int
+		failure int
+		results []bool
+		want    []result
+	}{
+		{
+			name:    "default thresholds",
+			results: []bool{true, true, false, false, false, false},
+			want:    []result{{true, true}, {}, {}, {}, {true, false}, {}},
+		},
+		{
+			name:    "success threshold",
+			success: 2,
+			failure: 1,
+			results: []bool{false, true, true, false, true},
+			want:    []result{{true, false}, {}, {true, true}, {true, false}, {}},
+		},
+		{
+			name:    "failure counter reset by success",
+			failure: 2,
+			results: []bool{true, false, true, false},
+			want:    []result{{true, true}, {}, {}, {}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			probe := types.SpecTemplateContainerProbe{ThresholdSuccess: tc.success, ThresholdFailure: tc.failure}
+			c := newProbeCounter(probe)
+			for i, r := range tc.results {
+				changed, state := c.observe(r)
+				assert.Equal(t, tc.want[i], result{changed, state}, "result %d mismatch", i)
+			}
+		})
+	}
+}
+
+func TestProbeCounterReset(t *testing.T) {
+
+	c := newProbeCounter(types.SpecTemplateContainerProbe{})
+
+	changed, state := c.observe(true)
+	assert.True(t, changed && state, "first success should switch state")
+
+	changed, _ = c.observe(true)
+	assert.False(t, changed, "same state should not be switched twice")
+
+	c.reset()
+
+	changed, state = c.observe(true)
+	assert.True(t, changed && state, "success after reset should switch state again")
+}
+
+func TestProbeHTTP(t *testing.T) {
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			if r.Header.Get("X-Probe") != "lb" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/redirect":
+			http.Redirect(w, r, "/unknown", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+
+	tests := []struct {
+		name    string
+		path    string
+		headers map[string]string
+		err     bool
+	}{
+		{name: "success status", path: "healthz", headers: map[string]string{"X-Probe": "lb"}},
+		{name: "missing header", path: "/healthz", err: true},
+		{name: "redirect is success", path: "/redirect"},
+		{name: "failure status", path: "/", err: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			probe := types.SpecTemplateContainerProbe{}
+			probe.HTTP.Path = tc.path
+			probe.HTTP.Port = p
+			probe.HTTP.Headers = tc.headers
+
+			err := probeHTTP(context.Background(), host, probe)
+			assert.Equal(t, tc.err, err != nil, "probe result mismatch: %v", err)
+		})
+	}
+}
+
+func TestProbeSocket(t *testing.T) {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+
+	probe := types.SpecTemplateContainerProbe{}
+	probe.Socket.Port = p
+
+	assert.NoError(t, probeSocket(context.Background(), "127.0.0.1", probe), "open port should pass")
+
+	ln.Close()
+	assert.Error(t, probeSocket(context.Background(), "127.0.0.1", probe), "closed port should fail")
+}
@@ -0,0 +1,96 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package runtime
+
+import (
+	"context"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/lastbackend/lastbackend/pkg/log"
+	"github.com/lastbackend/lastbackend/pkg/node/envs"
+)
+
+const (
+	logUsagePrefix = "node:runtime:usage:>"
+
+	// usageInterval - pods resources usage sampling interval
+	usageInterval = 15 * time.Second
+)
+
+// Usage periodically samples resources usage of running pods containers
+// through container runtime and sets it to pods status
+func (r *Runtime) Usage(ctx context.Context) {
+
+	log.V(logLevel).Debugf("%s start usage sampling", logUsagePrefix)
+
+	ticker := time.NewTicker(usageInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+
+			for key, status := range envs.Get().GetState().Pods().GetRunningPods() {
+				usage, err := PodUsage(ctx, status)
+				if err != nil {
+					log.Errorf("%s pod %s usage err: %s", logUsagePrefix, key, err.Error())
+					continue
+				}
+
+				envs.Get().GetState().Pods().SetUsage(key, *usage)
+			}
+		}
+	}
+}
+
+// PodUsage returns sum of pod containers resources usage
+func PodUsage(ctx context.Context, status *types.PodStatus) (*types.PodUsage, error) {
+
+	var (
+		usage  = new(types.PodUsage)
+		memory int64
+	)
+
+	for _, c := range status.Containers {
+
+		if c.ID == types.EmptyString || !c.State.Started.Started {
+			continue
+		}
+
+		stats, err := envs.Get().GetCRI().Stats(ctx, c.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		usage.CPU += stats.CPU
+		usage.Network.RX += stats.NetworkRX
+		usage.Network.TX += stats.NetworkTX
+		usage.Disk.Read += stats.DiskRead
+		usage.Disk.Write += stats.DiskWrite
+		memory += stats.Memory
+	}
+
+	usage.RAM = memory / (1024 * 1024)
+	usage.Updated = time.Now()
+
+	return usage, nil
+}
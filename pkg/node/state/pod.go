@@ -69,6 +69,49 @@ func (s *PodState) GetPods() map[string]*types.PodStatus {
 	return s.pods
 }
 
+// GetRunningPods returns copies of running pods statuses taken under state lock,
+// copies can be inspected without lock while pods are changed
+func (s *PodState) GetRunningPods() map[string]*types.PodStatus {
+	log.V(logLevel).Debugf("%s: get running pods", logPodPrefix)
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	pods := make(map[string]*types.PodStatus)
+	for key, pod := range s.pods {
+		if pod == nil || !pod.Running {
+			continue
+		}
+
+		status := *pod
+		status.Containers = make(map[string]*types.PodContainer, len(pod.Containers))
+		for id, c := range pod.Containers {
+			container := *c
+			status.Containers[id] = &container
+		}
+
+		pods[key] = &status
+	}
+
+	return pods
+}
+
+// SetUsage sets pod resources usage under state lock and notifies watchers
+func (s *PodState) SetUsage(key string, usage types.PodUsage) {
+	log.V(logLevel).Debugf("%s: set pod %s usage", logPodPrefix, key)
+
+	s.lock.Lock()
+	pod, ok := s.pods[key]
+	if !ok {
+		s.lock.Unlock()
+		return
+	}
+
+	pod.Usage = usage
+	s.lock.Unlock()
+	s.dispatch(key)
+}
+
 func (s *PodState) SetPods(pods map[string]*types.PodStatus) {
 	log.V(logLevel).Debugf("%s: set pods: %d", logPodPrefix, len(pods))
 	for key, pod := range pods {
@@ -135,6 +178,29 @@ func (s *PodState) SetPod(key string, pod *types.PodStatus) {
 	s.dispatch(key)
 }
 
+// UpdateContainer applies update to pod container under state lock,
+// pod state is recalculated and watchers are notified if update returns true
+func (s *PodState) UpdateContainer(key, id string, update func(c *types.PodContainer) bool) {
+	log.V(logLevel).Debugf("%s: update container %s in pod %s", logPodPrefix, id, key)
+
+	s.lock.Lock()
+	pod, ok := s.pods[key]
+	if !ok {
+		s.lock.Unlock()
+		return
+	}
+
+	c, ok := pod.Containers[id]
+	if !ok || !update(c) {
+		s.lock.Unlock()
+		return
+	}
+
+	state(pod)
+	s.lock.Unlock()
+	s.dispatch(key)
+}
+
 func (s *PodState) DelPod(key string) {
 	log.V(logLevel).Debugf("%s: del pod: %s", logPodPrefix, key)
 	s.lock.Lock()
@@ -202,6 +268,8 @@ func state(s *types.PodStatus) {
 	var sts = make(map[string]int)
 	var ems string
 
+	defer ready(s)
+
 	switch s.State {
 	case types.StateDestroyed:
 		return
@@ -248,3 +316,16 @@ func state(s *types.PodStatus) {
 		break
 	}
 }
+
+// ready marks pod as ready when it is running and all containers are ready
+func ready(s *types.PodStatus) {
+
+	s.Ready = s.Running && len(s.Containers) > 0
+
+	for _, cn := range s.Containers {
+		if !cn.Ready {
+			s.Ready = false
+			return
+		}
+	}
+}
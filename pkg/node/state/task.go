@@ -33,11 +33,15 @@ type TaskState struct {
 
 func (s *TaskState) AddTask(key string, task *types.NodeTask) {
 	log.V(logLevel).Debugf("%s add cancel func pod: %s", logTaskPrefix, key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	s.tasks[key] = *task
 }
 
 func (s *TaskState) GetTask(key string) *types.NodeTask {
 	log.V(logLevel).Debugf("%s get cancel func pod: %s", logTaskPrefix, key)
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
 	if _, ok := s.tasks[key]; ok {
 		t := s.tasks[key]
@@ -49,5 +53,20 @@ func (s *TaskState) GetTask(key string) *types.NodeTask {
 
 func (s *TaskState) DelTask(pod *types.Pod) {
 	log.V(logLevel).Debugf("%s del cancel func pod: %s", logTaskPrefix, pod.SelfLink())
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	delete(s.tasks, pod.Meta.Name)
 }
+
+// CancelTask cancels task by key and removes it from state
+func (s *TaskState) CancelTask(key string) {
+	log.V(logLevel).Debugf("%s cancel task: %s", logTaskPrefix, key)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if t, ok := s.tasks[key]; ok {
+		t.Cancel()
+		delete(s.tasks, key)
+	}
+}
@@ -208,6 +208,11 @@ func (p *Proxy) Update(ctx context.Context, state *types.EndpointState, spec *ty
 			if err := p.ipvs.NewService(svc); err != nil {
 				log.Errorf("%s can not create service: %s", logIPVSPrefix, err.Error())
 			}
+		} else if len(spec.Weights) > 0 {
+			// weighted scheduler is required for services created with round robin scheduler
+			if err := p.ipvs.UpdateService(svc); err != nil {
+				log.Errorf("%s can not update service: %s", logIPVSPrefix, err.Error())
+			}
 		}
 
 		// check service upstreams for removing
@@ -229,6 +234,15 @@ func (p *Proxy) Update(ctx context.Context, state *types.EndpointState, spec *ty
 				if err := p.ipvs.NewDestination(svc, dest); err != nil {
 					log.Errorf("%s can not add backend: %s", logIPVSPrefix, err.Error())
 				}
+				continue
+			}
+
+			// check service upstreams weight for updating
+			if cdest[did].Weight != dest.Weight {
+				log.Debugf("%s service %s backend weight update %s: %d", logIPVSPrefix, id, did, dest.Weight)
+				if err := p.ipvs.UpdateDestination(svc, dest); err != nil {
+					log.Errorf("%s can not update backend: %s", logIPVSPrefix, err.Error())
+				}
 			}
 		}
 	}
@@ -298,6 +312,7 @@ func (p *Proxy) getState(ctx context.Context) (map[string]*types.EndpointState,
 			endpoint.IP = host
 			endpoint.PortMap = make(map[uint16]string)
 			endpoint.Upstreams = make([]string, 0)
+			endpoint.Weights = make(map[string]int)
 		}
 
 		var prt uint16
@@ -332,6 +347,7 @@ func (p *Proxy) getState(ctx context.Context) (map[string]*types.EndpointState,
 
 			if !f {
 				endpoint.Upstreams = append(endpoint.Upstreams, dest.Address.String())
+				endpoint.Weights[dest.Address.String()] = dest.Weight
 			}
 		}
 
@@ -553,7 +569,7 @@ func specToServices(spec *types.EndpointManifest) (map[string]*libipvs.Service,
 			Address:       net.ParseIP(spec.IP),
 			Port:          ext,
 			AddressFamily: nl.FAMILY_V4,
-			SchedName:     "rr",
+			SchedName:     "wrr",
 		}
 
 		for _, host := range spec.Upstreams {
@@ -562,7 +578,7 @@ func specToServices(spec *types.EndpointManifest) (map[string]*libipvs.Service,
 
 			dest.Address = net.ParseIP(host)
 			dest.Port = port
-			dest.Weight = 1
+			dest.Weight = spec.Weight(host)
 			dests[fmt.Sprintf("%s_%d", dest.Address.String(), dest.Port)] = dest
 			log.Debugf("%s: added new destination %s_%d", logIPVSPrefix, dest.Address.String(), dest.Port)
 		}
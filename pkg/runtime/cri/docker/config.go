@@ -29,6 +29,11 @@ import (
 	"strconv"
 )
 
+const (
+	// cpuPeriod - CFS scheduler period in microseconds used for CPU limits
+	cpuPeriod = 100000
+)
+
 func GetConfig(manifest *types.ContainerManifest) *container.Config {
 
 	var (
@@ -67,6 +72,12 @@ func GetHostConfig(manifest *types.ContainerManifest) *container.HostConfig {
 		CPUShares: manifest.Resources.Request.CPU,
 	}
 
+	// CPU limit in shares is converted to CFS quota: 1024 shares limit is one core
+	if manifest.Resources.Limits.CPU > 0 {
+		resources.CPUPeriod = cpuPeriod
+		resources.CPUQuota = manifest.Resources.Limits.CPU * cpuPeriod / types.CPUSharesPerCore
+	}
+
 	var (
 		ports  = make(nat.PortMap, 0)
 		mounts []mount.Mount
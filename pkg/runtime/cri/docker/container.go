@@ -20,10 +20,15 @@ package docker
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	docker "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/log"
 	"io"
+	"io/ioutil"
 	"strconv"
 	"strings"
 	"time"
@@ -85,6 +90,24 @@ func (r *Runtime) Stop(ctx context.Context, ID string, timeout *time.Duration) e
 	return r.client.ContainerStop(ctx, ID, timeout)
 }
 
+// Wait blocks until container is stopped and returns its exit code
+func (r *Runtime) Wait(ctx context.Context, ID string) (int, error) {
+
+	res, errs := r.client.ContainerWait(ctx, ID, container.WaitConditionNotRunning)
+
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case err := <-errs:
+		return 0, err
+	case w := <-res:
+		if w.Error != nil {
+			return int(w.StatusCode), errors.New(w.Error.Message)
+		}
+		return int(w.StatusCode), nil
+	}
+}
+
 func (r *Runtime) Pause(ctx context.Context, ID string) error {
 	return r.client.ContainerPause(ctx, ID)
 }
@@ -110,6 +133,200 @@ func (r *Runtime) Logs(ctx context.Context, ID string, stdout, stderr, follow bo
 	})
 }
 
+// Exec runs command in container with attached streams, waits for it and returns exit code
+func (r *Runtime) Exec(ctx context.Context, ID string, opts *types.ContainerExecOptions, streams *types.ContainerStreams) (int, error) {
+
+	if streams == nil {
+		streams = new(types.ContainerStreams)
+	}
+
+	e, err := r.client.ContainerExecCreate(ctx, ID, docker.ExecConfig{
+		Cmd:          opts.Command,
+		Tty:          opts.TTY,
+		AttachStdin:  streams.Stdin != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := r.client.ContainerExecAttach(ctx, e.ID, docker.ExecStartCheck{Tty: opts.TTY})
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Close()
+
+	go resize(ctx, streams.Resize, func(size types.ContainerTerminalSize) error {
+		return r.client.ContainerExecResize(ctx, e.ID, docker.ResizeOptions{Width: size.Width, Height: size.Height})
+	})
+
+	if err := stream(ctx, resp, opts.TTY, streams); err != nil {
+		return 0, err
+	}
+
+	info, err := r.client.ContainerExecInspect(ctx, e.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.ExitCode, nil
+}
+
+// Attach attaches streams to container main process until container output is closed
+func (r *Runtime) Attach(ctx context.Context, ID string, streams *types.ContainerStreams) error {
+
+	if streams == nil {
+		streams = new(types.ContainerStreams)
+	}
+
+	info, err := r.client.ContainerInspect(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.ContainerAttach(ctx, ID, docker.ContainerAttachOptions{
+		Stream: true,
+		Stdin:  streams.Stdin != nil && info.Config.OpenStdin,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	go resize(ctx, streams.Resize, func(size types.ContainerTerminalSize) error {
+		return r.client.ContainerResize(ctx, ID, docker.ResizeOptions{Width: size.Width, Height: size.Height})
+	})
+
+	return stream(ctx, resp, info.Config.Tty, streams)
+}
+
+// stream copies hijacked connection to attached streams until output is closed,
+// output is multiplexed by docker if terminal is not allocated
+func stream(ctx context.Context, resp docker.HijackedResponse, tty bool, streams *types.ContainerStreams) error {
+
+	var (
+		stdout = streams.Stdout
+		stderr = streams.Stderr
+		done   = make(chan error, 1)
+	)
+
+	if stdout == nil {
+		stdout = ioutil.Discard
+	}
+
+	if stderr == nil {
+		stderr = ioutil.Discard
+	}
+
+	if streams.Stdin != nil {
+		go func() {
+			if _, err := io.Copy(resp.Conn, streams.Stdin); err != nil {
+				log.V(logLevel).Debugf("Docker: stdin copy err: %s", err)
+			}
+			resp.CloseWrite()
+		}()
+	}
+
+	go func() {
+		var err error
+		if tty {
+			_, err = io.Copy(stdout, resp.Reader)
+		} else {
+			_, err = stdcopy.StdCopy(stdout, stderr, resp.Reader)
+		}
+		done <- err
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
+
+// resize applies terminal size changes until sizes channel is closed
+func resize(ctx context.Context, sizes <-chan types.ContainerTerminalSize, fn func(size types.ContainerTerminalSize) error) {
+
+	if sizes == nil {
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case size, ok := <-sizes:
+			if !ok {
+				return
+			}
+			if err := fn(size); err != nil {
+				log.V(logLevel).Debugf("Docker: terminal resize err: %s", err)
+			}
+		}
+	}
+}
+
+// Stats returns container resources usage sampled by docker daemon
+func (r *Runtime) Stats(ctx context.Context, ID string) (*types.ContainerStats, error) {
+
+	resp, err := r.client.ContainerStats(ctx, ID, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var st docker.StatsJSON
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		return nil, err
+	}
+
+	stats := new(types.ContainerStats)
+	stats.Read = st.Read
+
+	var (
+		cpu    = float64(st.CPUStats.CPUUsage.TotalUsage) - float64(st.PreCPUStats.CPUUsage.TotalUsage)
+		system = float64(st.CPUStats.SystemUsage) - float64(st.PreCPUStats.SystemUsage)
+		cores  = float64(st.CPUStats.OnlineCPUs)
+	)
+
+	if cores == 0 {
+		cores = float64(len(st.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	if cpu > 0 && system > 0 {
+		stats.CPU = int64(cpu / system * cores * 1000)
+	}
+
+	// page cache is reclaimable and is not counted as used memory:
+	// cgroup v1 reports it as cache, cgroup v2 as inactive file
+	stats.Memory = int64(st.MemoryStats.Usage)
+	if v, ok := st.MemoryStats.Stats["cache"]; ok && v < st.MemoryStats.Usage {
+		stats.Memory -= int64(v)
+	} else if v, ok := st.MemoryStats.Stats["inactive_file"]; ok && v < st.MemoryStats.Usage {
+		stats.Memory -= int64(v)
+	}
+
+	for _, n := range st.Networks {
+		stats.NetworkRX += int64(n.RxBytes)
+		stats.NetworkTX += int64(n.TxBytes)
+	}
+
+	for _, b := range st.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(b.Op) {
+		case "read":
+			stats.DiskRead += int64(b.Value)
+		case "write":
+			stats.DiskWrite += int64(b.Value)
+		}
+	}
+
+	return stats, nil
+}
+
 func (r *Runtime) Inspect(ctx context.Context, ID string) (*types.Container, error) {
 
 	log.V(logLevel).Debug("Docker: Container Inspect")
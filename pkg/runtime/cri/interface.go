@@ -32,11 +32,15 @@ type CRI interface {
 	Start(ctx context.Context, ID string) error
 	Restart(ctx context.Context, ID string, timeout *time.Duration) error
 	Stop(ctx context.Context, ID string, timeout *time.Duration) error
+	Wait(ctx context.Context, ID string) (int, error)
 	Pause(ctx context.Context, ID string) error
 	Resume(ctx context.Context, ID string) error
 	Remove(ctx context.Context, ID string, clean bool, force bool) error
 	Inspect(ctx context.Context, ID string) (*types.Container, error)
 	Logs(ctx context.Context, ID string, stdout, stderr, follow bool) (io.ReadCloser, error)
 	Copy(ctx context.Context, ID, path string, content io.Reader) error
+	Exec(ctx context.Context, ID string, opts *types.ContainerExecOptions, streams *types.ContainerStreams) (int, error)
+	Attach(ctx context.Context, ID string, streams *types.ContainerStreams) error
+	Stats(ctx context.Context, ID string) (*types.ContainerStats, error)
 	Subscribe(ctx context.Context, container chan *types.Container) error
 }
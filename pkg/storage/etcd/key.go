@@ -86,3 +86,19 @@ func (Key) Route(namespace, name string) string {
 func (Key) Subnet(name string) string {
 	return fmt.Sprintf("%s", name)
 }
+
+func (Key) Account(name string) string {
+	return fmt.Sprintf("%s", name)
+}
+
+func (Key) Autoscaler(namespace, service string) string {
+	return fmt.Sprintf("%s:%s", namespace, service)
+}
+
+func (Key) Job(namespace, name string) string {
+	return fmt.Sprintf("%s:%s", namespace, name)
+}
+
+func (Key) CronJob(namespace, name string) string {
+	return fmt.Sprintf("%s:%s", namespace, name)
+}
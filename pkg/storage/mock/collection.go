@@ -44,6 +44,13 @@ const (
 	discoveryCollection = "discovery"
 	routeCollection   = "route"
 
+	accountCollection = "account"
+
+	autoscalerCollection = "autoscaler"
+
+	jobCollection     = "job"
+	cronJobCollection = "cronjob"
+
 	systemCollection  = "system"
 	testCollection    = "test"
 
@@ -130,6 +137,22 @@ func (Collection) Manifest() types.ManifestCollection {
 	return new(ManifestCollection)
 }
 
+func (Collection) Account() string {
+	return accountCollection
+}
+
+func (Collection) Autoscaler() string {
+	return autoscalerCollection
+}
+
+func (Collection) Job() string {
+	return jobCollection
+}
+
+func (Collection) CronJob() string {
+	return cronJobCollection
+}
+
 func (Collection) Test() string {
 	return testCollection
 }
@@ -62,6 +62,18 @@ func (Filter) Volume() types.VolumeFilter {
 	return new(VolumeFilter)
 }
 
+func (Filter) Autoscaler() types.AutoscalerFilter {
+	return new(AutoscalerFilter)
+}
+
+func (Filter) Job() types.JobFilter {
+	return new(JobFilter)
+}
+
+func (Filter) CronJob() types.CronJobFilter {
+	return new(CronJobFilter)
+}
+
 type NamespaceFilter struct{}
 
 type ServiceFilter struct{}
@@ -136,6 +148,24 @@ func (VolumeFilter) ByNamespace(namespace string) string {
 	return byNamespace(namespace)
 }
 
+type AutoscalerFilter struct{}
+
+func (AutoscalerFilter) ByNamespace(namespace string) string {
+	return byNamespace(namespace)
+}
+
+type JobFilter struct{}
+
+func (JobFilter) ByNamespace(namespace string) string {
+	return byNamespace(namespace)
+}
+
+type CronJobFilter struct{}
+
+func (CronJobFilter) ByNamespace(namespace string) string {
+	return byNamespace(namespace)
+}
+
 type TriggerFilter struct{}
 
 func (TriggerFilter) ByNamespace(namespace string) string {
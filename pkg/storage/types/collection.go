@@ -35,6 +35,10 @@ type Collection interface {
 	Endpoint() string
 	Network() string
 	Subnet() string
+	Account() string
+	Autoscaler() string
+	Job() string
+	CronJob() string
 	Manifest() ManifestCollection
 	Test() string
 }
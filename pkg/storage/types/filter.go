@@ -28,6 +28,9 @@ type Filter interface {
 	Route() RouteFilter
 	Secret() SecretFilter
 	Volume() VolumeFilter
+	Autoscaler() AutoscalerFilter
+	Job() JobFilter
+	CronJob() CronJobFilter
 }
 
 type NamespaceFilter interface {
@@ -67,3 +70,15 @@ type ConfigFilter interface {
 type VolumeFilter interface {
 	ByNamespace(namespace string) string
 }
+
+type AutoscalerFilter interface {
+	ByNamespace(namespace string) string
+}
+
+type JobFilter interface {
+	ByNamespace(namespace string) string
+}
+
+type CronJobFilter interface {
+	ByNamespace(namespace string) string
+}
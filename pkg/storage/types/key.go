@@ -34,4 +34,8 @@ type Key interface {
 	Node(name string) string
 	Route(namespace, name string) string
 	Subnet(name string) string
+	Account(name string) string
+	Autoscaler(namespace, service string) string
+	Job(namespace, name string) string
+	CronJob(namespace, name string) string
 }
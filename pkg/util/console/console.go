@@ -0,0 +1,274 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package console
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+// Websocket messages are prefixed with channel byte:
+// stdin and resize messages are sent by client, output and status are sent by server.
+// Empty stdin message closes process stdin.
+const (
+	StdinChannel  byte = 0
+	StdoutChannel byte = 1
+	StderrChannel byte = 2
+	StatusChannel byte = 3
+	ResizeChannel byte = 4
+
+	writeWait = 10 * time.Second
+)
+
+// Status - process exit status, sent to client when process is finished
+type Status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// Handler runs process with attached streams and returns exit code
+type Handler func(ctx context.Context, streams *types.ContainerStreams) (int, error)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// Upgrade upgrades http connection to websocket console connection
+func Upgrade(w http.ResponseWriter, r *http.Request) (*websocket.Conn, error) {
+	return upgrader.Upgrade(w, r, nil)
+}
+
+// Serve attaches websocket connection to process streams and sends exit status when process is finished,
+// process context is cancelled when connection is closed
+func Serve(ctx context.Context, conn *websocket.Conn, stdin bool, handler Handler) error {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		lock    = new(sync.Mutex)
+		resize  = make(chan types.ContainerTerminalSize, 1)
+		streams = &types.ContainerStreams{
+			Stdout: &writer{conn: conn, lock: lock, channel: StdoutChannel},
+			Stderr: &writer{conn: conn, lock: lock, channel: StderrChannel},
+			Resize: resize,
+		}
+		in *io.PipeWriter
+	)
+
+	if stdin {
+		var out *io.PipeReader
+		out, in = io.Pipe()
+		streams.Stdin = out
+		defer in.Close()
+	}
+
+	go func() {
+		defer cancel()
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				if in != nil {
+					in.Close()
+				}
+				return
+			}
+
+			if len(data) == 0 {
+				continue
+			}
+
+			switch data[0] {
+			case StdinChannel:
+				if in == nil {
+					continue
+				}
+				if len(data) == 1 {
+					in.Close()
+					continue
+				}
+				in.Write(data[1:])
+			case ResizeChannel:
+				var size types.ContainerTerminalSize
+				if err := json.Unmarshal(data[1:], &size); err != nil {
+					continue
+				}
+				select {
+				case resize <- size:
+				default:
+				}
+			}
+		}
+	}()
+
+	code, err := handler(ctx, streams)
+
+	status := Status{Code: code}
+	if err != nil {
+		status.Message = err.Error()
+	}
+
+	buf, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	w := &writer{conn: conn, lock: lock, channel: StatusChannel}
+	if _, err := w.Write(buf); err != nil {
+		return err
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	return conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(writeWait))
+}
+
+// Stream attaches streams to remote process console and returns process status,
+// stdin is closed on remote side when local stdin is finished
+func Stream(conn *websocket.Conn, streams *types.ContainerStreams) (*Status, error) {
+
+	var (
+		lock = new(sync.Mutex)
+		w    = &writer{conn: conn, lock: lock, channel: StdinChannel}
+		done = make(chan bool)
+	)
+
+	defer close(done)
+
+	if streams.Stdin != nil {
+		go func() {
+			if _, err := io.Copy(w, streams.Stdin); err != nil {
+				return
+			}
+			w.Write(nil)
+		}()
+	}
+
+	if streams.Resize != nil {
+		go func() {
+			r := &writer{conn: conn, lock: lock, channel: ResizeChannel}
+			for {
+				select {
+				case <-done:
+					return
+				case size, ok := <-streams.Resize:
+					if !ok {
+						return
+					}
+					buf, _ := json.Marshal(size)
+					r.Write(buf)
+				}
+			}
+		}()
+	}
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(data) == 0 {
+			continue
+		}
+
+		switch data[0] {
+		case StdoutChannel:
+			if streams.Stdout != nil {
+				streams.Stdout.Write(data[1:])
+			}
+		case StderrChannel:
+			if streams.Stderr != nil {
+				streams.Stderr.Write(data[1:])
+			}
+		case StatusChannel:
+			status := new(Status)
+			if err := json.Unmarshal(data[1:], status); err != nil {
+				return nil, err
+			}
+			return status, nil
+		}
+	}
+}
+
+// Proxy copies websocket messages between client and upstream connections until one of them is closed
+func Proxy(client, upstream *websocket.Conn) error {
+
+	errs := make(chan error, 2)
+
+	pipe := func(dst, src *websocket.Conn) {
+		for {
+			t, data, err := src.ReadMessage()
+			if err != nil {
+				if e, ok := err.(*websocket.CloseError); ok {
+					dst.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(e.Code, e.Text), time.Now().Add(writeWait))
+					err = nil
+				}
+				errs <- err
+				return
+			}
+
+			if err := dst.WriteMessage(t, data); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}
+
+	go pipe(upstream, client)
+	go pipe(client, upstream)
+
+	return <-errs
+}
+
+// writer writes data to websocket connection prefixed with channel byte
+type writer struct {
+	conn    *websocket.Conn
+	lock    *sync.Mutex
+	channel byte
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	buf := make([]byte, len(p)+1)
+	buf[0] = w.channel
+	copy(buf[1:], p)
+
+	if err := w.conn.WriteMessage(websocket.BinaryMessage, buf); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,168 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package console
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServe(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		stdin   string
+		handler Handler
+		stdout  string
+		stderr  string
+		status  Status
+	}{
+		{
+			name:  "stdin is copied to stdout",
+			stdin: "hello",
+			handler: func(ctx context.Context, streams *types.ContainerStreams) (int, error) {
+				data, err := ioutil.ReadAll(streams.Stdin)
+				if err != nil {
+					return 1, err
+				}
+				streams.Stdout.Write(data)
+				return 0, nil
+			},
+			stdout: "hello",
+			status: Status{Code: 0},
+		},
+		{
+			name: "stderr and exit code are sent",
+			handler: func(ctx context.Context, streams *types.ContainerStreams) (int, error) {
+				streams.Stderr.Write([]byte("failed"))
+				return 2, nil
+			},
+			stderr: "failed",
+			status: Status{Code: 2},
+		},
+		{
+			name: "process error is sent in status",
+			handler: func(ctx context.Context, streams *types.ContainerStreams) (int, error) {
+				return 0, errors.New("container not running")
+			},
+			status: Status{Code: 0, Message: "container not running"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conn, err := Upgrade(w, r)
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				Serve(r.Context(), conn, tc.stdin != "", tc.handler)
+			}))
+			defer srv.Close()
+
+			conn, err := dial(srv)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer conn.Close()
+
+			var stdout, stderr bytes.Buffer
+
+			streams := &types.ContainerStreams{Stdout: &stdout, Stderr: &stderr}
+			if tc.stdin != "" {
+				streams.Stdin = strings.NewReader(tc.stdin)
+			}
+
+			status, err := Stream(conn, streams)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, tc.status, *status, "status not equal")
+			assert.Equal(t, tc.stdout, stdout.String(), "stdout not equal")
+			assert.Equal(t, tc.stderr, stderr.String(), "stderr not equal")
+		})
+	}
+}
+
+func TestProxy(t *testing.T) {
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		Serve(r.Context(), conn, false, func(ctx context.Context, streams *types.ContainerStreams) (int, error) {
+			streams.Stdout.Write([]byte("proxied"))
+			return 3, nil
+		})
+	}))
+	defer upstream.Close()
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		up, err := dial(upstream)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer up.Close()
+
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		Proxy(conn, up)
+	}))
+	defer proxy.Close()
+
+	conn, err := dial(proxy)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	var stdout bytes.Buffer
+
+	status, err := Stream(conn, &types.ContainerStreams{Stdout: &stdout})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 3, status.Code, "exit code not equal")
+	assert.Equal(t, "proxied", stdout.String(), "stdout not equal")
+}
+
+func dial(srv *httptest.Server) (*websocket.Conn, error) {
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	return conn, err
+}
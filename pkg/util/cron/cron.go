@@ -0,0 +1,244 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchLimit - years count to search next schedule time in, schedules like `0 0 30 2 *` never match
+const searchLimit = 5
+
+type bounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minutes = bounds{0, 59, nil}
+	hours   = bounds{0, 23, nil}
+	dom     = bounds{1, 31, nil}
+	months  = bounds{1, 12, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// day of week 7 is treated as sunday
+	dow = bounds{0, 7, map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+
+	descriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// Schedule - parsed cron expression with minute, hour, day of month, month and day of week fields
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// day of month and day of week are matched with OR if both of them are restricted
+	domAny, dowAny bool
+}
+
+// Parse parses standard 5 fields cron expression or one of @yearly, @monthly, @weekly, @daily, @hourly descriptors
+func Parse(spec string) (*Schedule, error) {
+
+	spec = strings.TrimSpace(spec)
+	if d, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = d
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, found %d: %s", len(fields), spec)
+	}
+
+	var (
+		s   = new(Schedule)
+		err error
+	)
+
+	if s.minute, err = parseField(fields[0], minutes); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hours); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], dom); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], months); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dow); err != nil {
+		return nil, err
+	}
+
+	// sunday can be set as 0 or 7
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+
+	s.domAny = strings.HasPrefix(fields[2], "*") || fields[2] == "?"
+	s.dowAny = strings.HasPrefix(fields[4], "*") || fields[4] == "?"
+
+	return s, nil
+}
+
+// Next returns the first schedule time after provided time in its location,
+// zero time is returned if schedule can not be satisfied
+func (s *Schedule) Next(t time.Time) time.Time {
+
+	loc := t.Location()
+
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.Year() + searchLimit
+
+	for t.Year() <= limit {
+
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = forward(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc))
+			continue
+		}
+
+		if !s.dayMatch(t) {
+			t = forward(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc))
+			continue
+		}
+
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+			continue
+		}
+
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatch(t time.Time) bool {
+
+	var (
+		d = s.dom&(1<<uint(t.Day())) != 0
+		w = s.dow&(1<<uint(t.Weekday())) != 0
+	)
+
+	if s.domAny || s.dowAny {
+		return d && w
+	}
+
+	return d || w
+}
+
+// forward returns next time, midnight in daylight saving gap is normalized by time.Date
+// to the previous day, so the gap is stepped over to keep moving forward
+func forward(prev, next time.Time) time.Time {
+	if !next.After(prev) {
+		return next.Add(time.Hour)
+	}
+	return next
+}
+
+// parseField parses comma separated list of `*`, `n`, `n-m` values with optional `/step`
+func parseField(field string, b bounds) (uint64, error) {
+
+	var bits uint64
+
+	for _, expr := range strings.Split(field, ",") {
+
+		var (
+			rng  = expr
+			step = 1
+			err  error
+		)
+
+		if i := strings.Index(expr, "/"); i >= 0 {
+			rng = expr[:i]
+			if step, err = strconv.Atoi(expr[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %s", expr)
+			}
+		}
+
+		var start, end int
+
+		switch {
+		case rng == "*" || rng == "?":
+			start, end = b.min, b.max
+		case strings.Contains(rng, "-"):
+			parts := strings.SplitN(rng, "-", 2)
+			if start, err = parseValue(parts[0], b); err != nil {
+				return 0, err
+			}
+			if end, err = parseValue(parts[1], b); err != nil {
+				return 0, err
+			}
+		default:
+			if start, err = parseValue(rng, b); err != nil {
+				return 0, err
+			}
+			end = start
+			// `n/step` means range from n to max value
+			if rng != expr {
+				end = b.max
+			}
+		}
+
+		if start > end {
+			return 0, fmt.Errorf("invalid range in %s", expr)
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseValue(value string, b bounds) (int, error) {
+
+	if n, ok := b.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %s", value)
+	}
+
+	if n < b.min || n > b.max {
+		return 0, fmt.Errorf("value %d out of range [%d-%d]", n, b.min, b.max)
+	}
+
+	return n, nil
+}
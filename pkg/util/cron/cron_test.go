@@ -0,0 +1,150 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+	}{
+		{name: "every minute", spec: "* * * * *"},
+		{name: "lists, ranges and steps", spec: "0,30 8-18/2 1-15 */3 mon-fri"},
+		{name: "month and day names", spec: "0 0 1 jan,jul sun"},
+		{name: "sunday as 7", spec: "0 0 * * 7"},
+		{name: "descriptor", spec: "@daily"},
+		{name: "not enough fields", spec: "* * * *", wantErr: true},
+		{name: "minute out of range", spec: "60 * * * *", wantErr: true},
+		{name: "day of month out of range", spec: "0 0 0 * *", wantErr: true},
+		{name: "invalid step", spec: "*/0 * * * *", wantErr: true},
+		{name: "inverted range", spec: "0 18-8 * * *", wantErr: true},
+		{name: "unknown name", spec: "0 0 * * sun-fun", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.spec)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+
+	ny, err := time.LoadLocation("America/New_York")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	date := func(loc *time.Location, y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "every minute skips seconds",
+			spec: "* * * * *",
+			from: time.Date(2018, 5, 1, 10, 15, 30, 0, time.UTC),
+			want: date(time.UTC, 2018, 5, 1, 10, 16),
+		},
+		{
+			name: "every 15 minutes",
+			spec: "*/15 * * * *",
+			from: date(time.UTC, 2018, 5, 1, 10, 15),
+			want: date(time.UTC, 2018, 5, 1, 10, 30),
+		},
+		{
+			name: "daily at hour rolls to next day",
+			spec: "30 6 * * *",
+			from: date(time.UTC, 2018, 5, 1, 7, 0),
+			want: date(time.UTC, 2018, 5, 2, 6, 30),
+		},
+		{
+			name: "monthly rolls to next year",
+			spec: "@monthly",
+			from: date(time.UTC, 2018, 12, 15, 0, 0),
+			want: date(time.UTC, 2019, 1, 1, 0, 0),
+		},
+		{
+			name: "weekdays only",
+			spec: "0 9 * * mon-fri",
+			from: date(time.UTC, 2018, 5, 4, 10, 0),
+			want: date(time.UTC, 2018, 5, 7, 9, 0),
+		},
+		{
+			name: "day of month or day of week",
+			spec: "0 0 13 * fri",
+			from: date(time.UTC, 2018, 5, 1, 0, 0),
+			want: date(time.UTC, 2018, 5, 4, 0, 0),
+		},
+		{
+			name: "leap day",
+			spec: "0 0 29 2 *",
+			from: date(time.UTC, 2018, 1, 1, 0, 0),
+			want: date(time.UTC, 2020, 2, 29, 0, 0),
+		},
+		{
+			name: "schedule in location",
+			spec: "0 9 * * *",
+			from: date(ny, 2018, 5, 1, 10, 0),
+			want: date(ny, 2018, 5, 2, 9, 0),
+		},
+		{
+			name: "skipped daylight saving hour",
+			spec: "30 2 * * *",
+			from: date(ny, 2018, 3, 10, 3, 0),
+			want: date(ny, 2018, 3, 12, 2, 30),
+		},
+		{
+			name: "never matched schedule",
+			spec: "0 0 30 2 *",
+			from: date(time.UTC, 2018, 1, 1, 0, 0),
+			want: time.Time{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			s, err := Parse(tc.spec)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.True(t, tc.want.Equal(s.Next(tc.from)), "expected %s, got %s", tc.want, s.Next(tc.from))
+		})
+	}
+}
@@ -92,6 +92,25 @@ func ListenWithTLS(host string, port int, caFile, certFile, keyFile string, rout
 	return server.ListenAndServeTLS(certFile, keyFile)
 }
 
+// ListenWithClientCAs starts TLS server which verifies client certificates by provided pool if given,
+// requests without client certificate are passed to handlers to be authenticated by token
+func ListenWithClientCAs(host string, port int, certFile, keyFile string, pool *x509.CertPool, router http.Handler) error {
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", host, port),
+		Handler: router,
+	}
+
+	server.TLSConfig = &tls.Config{
+		ClientAuth:               tls.VerifyClientCertIfGiven,
+		ClientCAs:                pool,
+		PreferServerCipherSuites: true,
+		MinVersion:               tls.VersionTLS12,
+	}
+
+	return server.ListenAndServeTLS(certFile, keyFile)
+}
+
 func configTLS(caFile string) *tls.Config {
 
 	caCert, err := ioutil.ReadFile(caFile)
@@ -19,20 +19,57 @@
 package middleware
 
 import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
 	"github.com/lastbackend/lastbackend/pkg/distribution/errors"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/http/utils"
+	"github.com/lastbackend/lastbackend/pkg/util/pki"
 	"github.com/spf13/viper"
-	"net/http"
-	"strings"
 )
 
-// Auth - authentication middleware
+const (
+	accountContextKey     = "account"
+	certificateContextKey = "certificate"
+)
+
+// Authenticator returns account which issued provided token,
+// nil account should be returned for unknown token
+type Authenticator func(ctx context.Context, token string) (*types.Account, error)
+
+var authenticator Authenticator
+
+// SetAuthenticator sets accounts tokens authenticator,
+// only cluster token is accepted if authenticator is not set
+func SetAuthenticator(a Authenticator) {
+	authenticator = a
+}
+
+// Account returns authenticated account from request context
+func Account(r *http.Request) *types.Account {
+	if a, ok := r.Context().Value(accountContextKey).(*types.Account); ok {
+		return a
+	}
+	return nil
+}
+
+// Certified checks if request is authenticated by client certificate
+func Certified(r *http.Request) bool {
+	c, ok := r.Context().Value(certificateContextKey).(bool)
+	return ok && c
+}
+
 func Authenticate(h http.HandlerFunc) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
-		t := viper.GetString("security.token")
-		if t == "" {
-			h.ServeHTTP(w, r)
+
+		// Agents are authenticated by client certificates signed by cluster authority
+		if account := certificateAccount(r); account != nil {
+			r = utils.SetContext(r, certificateContextKey, true)
+			h.ServeHTTP(w, utils.SetContext(r, accountContextKey, account))
 			return
 		}
 
@@ -57,11 +94,131 @@ func Authenticate(h http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		if token != t {
+		if token == types.EmptyString {
+			errors.HTTP.Unauthorized(w)
+			return
+		}
+
+		// Cluster token grants cluster admin access, empty cluster token is never matched
+		if t := viper.GetString("token"); t != types.EmptyString && subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			h.ServeHTTP(w, utils.SetContext(r, accountContextKey, types.NewRootAccount()))
+			return
+		}
+
+		if authenticator == nil {
+			errors.HTTP.Unauthorized(w)
+			return
+		}
+
+		account, err := authenticator(r.Context(), token)
+		if err != nil {
+			errors.HTTP.InternalServerError(w)
+			return
+		}
+
+		if account == nil {
+			errors.HTTP.Unauthorized(w)
+			return
+		}
+
+		h.ServeHTTP(w, utils.SetContext(r, accountContextKey, account))
+	}
+}
+
+// Cluster authenticates requests issued by cluster components to agent API:
+// only client certificates verified by agent server authority or cluster token are accepted,
+// accounts tokens are never accepted
+func Cluster(h http.HandlerFunc) http.HandlerFunc {
+
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+			h.ServeHTTP(w, utils.SetContext(r, certificateContextKey, true))
+			return
+		}
+
+		var token string
+		if auth := strings.SplitN(r.Header.Get("Authorization"), " ", 2); len(auth) == 2 && auth[0] == "Bearer" {
+			token = auth[1]
+		} else {
+			token = r.URL.Query().Get("x-lastbackend-token")
+		}
+
+		t := viper.GetString("token")
+		if token == types.EmptyString || t == types.EmptyString || subtle.ConstantTimeCompare([]byte(token), []byte(t)) != 1 {
 			errors.HTTP.Unauthorized(w)
 			return
 		}
 
-		h.ServeHTTP(w, r)
+		h.ServeHTTP(w, utils.SetContext(r, accountContextKey, types.NewRootAccount()))
+	}
+}
+
+// Authorize authenticates request and checks that account role in requested namespace grants access,
+// cluster resources require cluster role binding, empty access allows any authenticated user account.
+// Agent accounts are allowed only for routes listed their kind
+// and only for own resource if route contains agent kind variable,
+// agents should use client certificates unless API is served without TLS
+func Authorize(access string, agents ...string) func(http.HandlerFunc) http.HandlerFunc {
+
+	return func(h http.HandlerFunc) http.HandlerFunc {
+		return Authenticate(func(w http.ResponseWriter, r *http.Request) {
+
+			if !allowed(Account(r), utils.Vars(r), access, agents) {
+				errors.HTTP.Forbidden(w)
+				return
+			}
+
+			if a := Account(r); a.Agent() && !Certified(r) && !viper.GetBool("api.tls.insecure") {
+				errors.HTTP.Forbidden(w, "client certificate required")
+				return
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+func allowed(a *types.Account, vars map[string]string, access string, agents []string) bool {
+
+	if a == nil {
+		return false
+	}
+
+	if a.Agent() {
+		for _, kind := range agents {
+			if kind != a.Spec.Kind {
+				continue
+			}
+
+			name, ok := vars[kind]
+			return !ok || name == a.Meta.Name
+		}
+		return false
+	}
+
+	if access == types.EmptyString {
+		return true
 	}
+
+	return a.Allowed(vars["namespace"], access)
+}
+
+func certificateAccount(r *http.Request) *types.Account {
+
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	kind, name := pki.Identity(r.TLS.VerifiedChains[0][0])
+
+	account := new(types.Account)
+	account.Meta.Name = name
+	account.Spec.Kind = kind
+
+	if name == types.EmptyString || !account.Agent() {
+		return nil
+	}
+
+	return account
 }
@@ -20,14 +20,21 @@ package middleware_test
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"github.com/gorilla/mux"
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
 	"github.com/lastbackend/lastbackend/pkg/util/http/middleware"
+	"github.com/lastbackend/lastbackend/pkg/util/pki"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 // GetTestHandler returns a http.HandlerFunc for testing http middleware
@@ -43,7 +50,8 @@ func TestAuthenticateMiddleware(t *testing.T) {
 
 	const token = "demotoken"
 
-	viper.Set("security.token", token)
+	viper.Set("token", token)
+	defer viper.Set("token", "")
 
 	tests := []struct {
 		description  string
@@ -95,3 +103,297 @@ func TestAuthenticateMiddleware(t *testing.T) {
 		assert.Equal(t, tc.expectedBody, string(b), tc.description)
 	}
 }
+
+func TestAuthenticateWithoutToken(t *testing.T) {
+
+	viper.Set("token", "")
+
+	tests := []struct {
+		description string
+		token       string
+	}{
+		{
+			description: "request without token",
+		},
+		{
+			description: "request with empty token",
+			token:       "Bearer ",
+		},
+		{
+			description: "request with any token",
+			token:       "Bearer demotoken",
+		},
+	}
+
+	handler := middleware.Authenticate(GetTestHandler())
+
+	for _, tc := range tests {
+
+		req := httptest.NewRequest("GET", "/", nil)
+		if len(tc.token) != 0 {
+			req.Header.Add("Authorization", tc.token)
+		}
+
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+
+		assert.Equal(t, http.StatusUnauthorized, res.Code, tc.description)
+	}
+}
+
+func TestAuthorizeMiddleware(t *testing.T) {
+
+	const token = "demotoken"
+
+	viper.Set("token", token)
+	defer viper.Set("token", "")
+
+	viewer := new(types.Account)
+	viewer.Meta.Name = "viewer"
+	viewer.Spec.Kind = types.AccountKindUser
+	viewer.Spec.Roles = map[string]string{"demo": types.RoleViewer}
+	_, vt, err := viewer.IssueToken()
+	assert.NoError(t, err)
+
+	agent := new(types.Account)
+	agent.Meta.Name = "node1"
+	agent.Spec.Kind = types.AccountKindNode
+	_, at, err := agent.IssueToken()
+	assert.NoError(t, err)
+
+	accounts := map[string]*types.Account{viewer.Meta.Name: viewer, agent.Meta.Name: agent}
+
+	ca, err := pki.NewCA("test")
+	assert.NoError(t, err)
+	key, err := pki.GenerateKey()
+	assert.NoError(t, err)
+	csr, err := pki.NewCSR(key, types.AccountKindNode, agent.Meta.Name)
+	assert.NoError(t, err)
+	pem, _, err := ca.Sign(csr, types.AccountKindNode, agent.Meta.Name, time.Hour)
+	assert.NoError(t, err)
+	cert, err := pki.ParseCertificate(pem)
+	assert.NoError(t, err)
+
+	defer viper.Set("api.tls.insecure", false)
+
+	middleware.SetAuthenticator(func(ctx context.Context, token string) (*types.Account, error) {
+		name, id, secret, err := types.ParseAccountToken(token)
+		if err != nil {
+			return nil, nil
+		}
+		a, ok := accounts[name]
+		if !ok || !a.VerifyToken(id, secret) {
+			return nil, nil
+		}
+		return a, nil
+	})
+	defer middleware.SetAuthenticator(nil)
+
+	tests := []struct {
+		description  string
+		path         string
+		url          string
+		token        string
+		access       string
+		agents       []string
+		insecure     bool
+		cert         *x509.Certificate
+		expectedCode int
+	}{
+		{
+			description:  "cluster token allows cluster admin access",
+			path:         "/cluster/node/{node}",
+			url:          "/cluster/node/node2",
+			token:        token,
+			access:       types.AccessAdmin,
+			expectedCode: http.StatusOK,
+		},
+		{
+			description:  "unknown token",
+			path:         "/namespace/{namespace}",
+			url:          "/namespace/demo",
+			token:        "viewer:unknown:token",
+			access:       types.AccessRead,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			description:  "viewer can read namespace",
+			path:         "/namespace/{namespace}",
+			url:          "/namespace/demo",
+			token:        vt,
+			access:       types.AccessRead,
+			expectedCode: http.StatusOK,
+		},
+		{
+			description:  "viewer can not write namespace",
+			path:         "/namespace/{namespace}",
+			url:          "/namespace/demo",
+			token:        vt,
+			access:       types.AccessWrite,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			description:  "viewer can not read other namespace",
+			path:         "/namespace/{namespace}",
+			url:          "/namespace/other",
+			token:        vt,
+			access:       types.AccessRead,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			description:  "viewer can not read cluster resources",
+			path:         "/cluster",
+			url:          "/cluster",
+			token:        vt,
+			access:       types.AccessRead,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			description:  "agent can access own resource",
+			path:         "/cluster/node/{node}",
+			url:          "/cluster/node/node1",
+			token:        at,
+			access:       types.AccessAdmin,
+			agents:       []string{types.AccountKindNode},
+			insecure:     true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			description:  "agent token rejected without client certificate",
+			path:         "/cluster/node/{node}",
+			url:          "/cluster/node/node1",
+			token:        at,
+			access:       types.AccessAdmin,
+			agents:       []string{types.AccountKindNode},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			description:  "agent client certificate accepted",
+			path:         "/cluster/node/{node}",
+			url:          "/cluster/node/node1",
+			access:       types.AccessAdmin,
+			agents:       []string{types.AccountKindNode},
+			cert:         cert,
+			expectedCode: http.StatusOK,
+		},
+		{
+			description:  "agent client certificate can not access other resource",
+			path:         "/cluster/node/{node}",
+			url:          "/cluster/node/node2",
+			access:       types.AccessAdmin,
+			agents:       []string{types.AccountKindNode},
+			cert:         cert,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			description:  "agent can not access other resource",
+			path:         "/cluster/node/{node}",
+			url:          "/cluster/node/node2",
+			token:        at,
+			access:       types.AccessAdmin,
+			agents:       []string{types.AccountKindNode},
+			insecure:     true,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			description:  "agent can not access routes out of scope",
+			path:         "/namespace/{namespace}",
+			url:          "/namespace/demo",
+			token:        at,
+			access:       types.AccessRead,
+			insecure:     true,
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+
+		viper.Set("api.tls.insecure", tc.insecure)
+
+		r := mux.NewRouter()
+		r.HandleFunc(tc.path, middleware.Authorize(tc.access, tc.agents...)(GetTestHandler()))
+
+		req := httptest.NewRequest("GET", tc.url, nil)
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tc.token))
+		if tc.cert != nil {
+			req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{tc.cert}}}
+		}
+
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.expectedCode, res.Code, tc.description)
+	}
+}
+
+func TestClusterMiddleware(t *testing.T) {
+
+	const token = "demotoken"
+
+	viewer := new(types.Account)
+	viewer.Meta.Name = "viewer"
+	viewer.Spec.Kind = types.AccountKindUser
+	viewer.Spec.Roles = map[string]string{"demo": types.RoleViewer}
+	_, vt, err := viewer.IssueToken()
+	assert.NoError(t, err)
+
+	middleware.SetAuthenticator(func(ctx context.Context, token string) (*types.Account, error) {
+		return viewer, nil
+	})
+	defer middleware.SetAuthenticator(nil)
+	defer viper.Set("token", "")
+
+	tests := []struct {
+		description  string
+		cluster      string
+		token        string
+		cert         bool
+		expectedCode int
+	}{
+		{
+			description:  "cluster token accepted",
+			cluster:      token,
+			token:        token,
+			expectedCode: http.StatusOK,
+		},
+		{
+			description:  "verified client certificate accepted",
+			cluster:      token,
+			cert:         true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			description:  "request without token rejected",
+			cluster:      token,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			description:  "account token rejected",
+			cluster:      token,
+			token:        vt,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			description:  "empty token rejected if cluster token is not configured",
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	handler := middleware.Cluster(GetTestHandler())
+
+	for _, tc := range tests {
+
+		viper.Set("token", tc.cluster)
+
+		req := httptest.NewRequest("GET", "/pod/demo/exec", nil)
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tc.token))
+		if tc.cert {
+			req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{new(x509.Certificate)}}}
+		}
+
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.expectedCode, res.Code, tc.description)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/gorilla/websocket"
 	"golang.org/x/net/http2"
 	"io"
 	"io/ioutil"
@@ -227,6 +228,56 @@ func (r *Request) Stream() (io.ReadCloser, error) {
 	}
 }
 
+// Websocket opens websocket connection with request url, headers and client tls config
+func (r *Request) Websocket() (*websocket.Conn, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	u := r.URL()
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: websocket.DefaultDialer.HandshakeTimeout,
+	}
+
+	if c, ok := r.client.(*http.Client); ok {
+		if t, ok := c.Transport.(*http.Transport); ok {
+			dialer.TLSClientConfig = t.TLSClientConfig
+		}
+	}
+
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	conn, res, err := dialer.DialContext(ctx, u.String(), r.headers)
+	if err != nil {
+		if res == nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+
+		var e struct {
+			Message string `json:"message"`
+		}
+
+		if decodeResponseJSON(res, &e) == nil && e.Message != "" {
+			return nil, fmt.Errorf("%s", e.Message)
+		}
+		return nil, fmt.Errorf("%d while accessing %v: %s", res.StatusCode, u, err.Error())
+	}
+
+	return conn, nil
+}
+
 func (r *Request) Param(name, value string) *Request {
 	if r.params == nil {
 		r.params = make(url.Values)
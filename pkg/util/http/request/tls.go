@@ -23,6 +23,9 @@ import (
 	"crypto/x509"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"sync"
+	"time"
 )
 
 func NewTLSConfig(c *Config) (*tls.Config, error) {
@@ -33,6 +36,10 @@ func NewTLSConfig(c *Config) (*tls.Config, error) {
 	if c.HasCA() && c.TLS.Insecure {
 		return nil, fmt.Errorf("certificates file with the insecure flag is not allowed")
 	}
+	// Client certificate loaded from files can be rotated on disk, so keep watching them
+	reload := len(c.TLS.CertData) == 0 && len(c.TLS.KeyData) == 0 &&
+		len(c.TLS.CertFile) > 0 && len(c.TLS.KeyFile) > 0
+
 	if err := loadTLSFiles(c); err != nil {
 		return nil, err
 	}
@@ -60,6 +67,15 @@ func NewTLSConfig(c *Config) (*tls.Config, error) {
 		staticCert = &cert
 	}
 
+	if c.HasCertAuth() && reload {
+		kp := &keyPair{certFile: c.TLS.CertFile, keyFile: c.TLS.KeyFile, cert: staticCert}
+		kp.modified, _ = kp.modTime()
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return kp.get()
+		}
+		return tlsConfig, nil
+	}
+
 	if c.HasCertAuth() {
 		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
 			if staticCert != nil {
@@ -72,6 +88,53 @@ func NewTLSConfig(c *Config) (*tls.Config, error) {
 	return tlsConfig, nil
 }
 
+// keyPair holds client certificate loaded from files
+// and reloads it when certificate files are changed
+type keyPair struct {
+	sync.Mutex
+	certFile string
+	keyFile  string
+	modified time.Time
+	cert     *tls.Certificate
+}
+
+func (kp *keyPair) get() (*tls.Certificate, error) {
+	kp.Lock()
+	defer kp.Unlock()
+
+	modified, err := kp.modTime()
+	if err != nil || !modified.After(kp.modified) {
+		return kp.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(kp.certFile, kp.keyFile)
+	if err != nil {
+		// files can be in the middle of rotation, use previous certificate
+		return kp.cert, nil
+	}
+
+	kp.cert = &cert
+	kp.modified = modified
+	return kp.cert, nil
+}
+
+func (kp *keyPair) modTime() (time.Time, error) {
+
+	var modified time.Time
+
+	for _, file := range []string{kp.certFile, kp.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return modified, err
+		}
+		if info.ModTime().After(modified) {
+			modified = info.ModTime()
+		}
+	}
+
+	return modified, nil
+}
+
 func loadTLSFiles(c *Config) (err error) {
 
 	c.TLS.CAData, err = getDataFromSliceOrFile(c.TLS.CAData, c.TLS.CAFile)
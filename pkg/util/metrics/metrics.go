@@ -0,0 +1,234 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lastbackend/lastbackend/pkg/log"
+)
+
+const (
+	logLevel  = 3
+	logPrefix = "metrics"
+
+	// ContentType - prometheus text exposition format
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	TypeGauge   = "gauge"
+	TypeCounter = "counter"
+)
+
+var (
+	started = time.Now()
+
+	collectors = struct {
+		sync.RWMutex
+		items map[string]Collector
+	}{items: make(map[string]Collector, 0)}
+)
+
+// Collector returns daemon metrics on each scrape
+type Collector func(ctx context.Context) ([]*Metric, error)
+
+// Labels - sample labels
+type Labels map[string]string
+
+// Metric - metric family with samples
+type Metric struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Sample - metric value with labels
+type Sample struct {
+	Labels Labels
+	Value  float64
+}
+
+// NewGauge returns gauge metric without samples
+func NewGauge(name, help string) *Metric {
+	return &Metric{Name: name, Help: help, Type: TypeGauge, Samples: make([]Sample, 0)}
+}
+
+// NewCounter returns counter metric without samples
+func NewCounter(name, help string) *Metric {
+	return &Metric{Name: name, Help: help, Type: TypeCounter, Samples: make([]Sample, 0)}
+}
+
+// Add appends sample to metric
+func (m *Metric) Add(value float64, labels Labels) *Metric {
+	m.Samples = append(m.Samples, Sample{Labels: labels, Value: value})
+	return m
+}
+
+// Register adds named collector, collector with the same name is replaced
+func Register(name string, c Collector) {
+	collectors.Lock()
+	defer collectors.Unlock()
+	collectors.items[name] = c
+}
+
+// Unregister removes named collector
+func Unregister(name string) {
+	collectors.Lock()
+	defer collectors.Unlock()
+	delete(collectors.items, name)
+}
+
+// Gather returns process metrics and metrics of all registered collectors sorted by name
+func Gather(ctx context.Context) ([]*Metric, error) {
+
+	collectors.RLock()
+	items := make([]Collector, 0, len(collectors.items))
+	for _, c := range collectors.items {
+		items = append(items, c)
+	}
+	collectors.RUnlock()
+
+	metrics := process()
+
+	for _, c := range items {
+		m, err := c(ctx)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m...)
+	}
+
+	sort.SliceStable(metrics, func(i, j int) bool {
+		return metrics[i].Name < metrics[j].Name
+	})
+
+	return metrics, nil
+}
+
+// Write writes metrics in prometheus text exposition format
+func Write(w io.Writer, metrics []*Metric) error {
+
+	b := bufio.NewWriter(w)
+
+	for _, m := range metrics {
+
+		if m.Help != "" {
+			fmt.Fprintf(b, "# HELP %s %s\n", m.Name, escape(m.Help, false))
+		}
+
+		if m.Type != "" {
+			fmt.Fprintf(b, "# TYPE %s %s\n", m.Name, m.Type)
+		}
+
+		for _, s := range m.Samples {
+			b.WriteString(m.Name)
+			b.WriteString(labels(s.Labels))
+			b.WriteByte(' ')
+			b.WriteString(strconv.FormatFloat(s.Value, 'g', -1, 64))
+			b.WriteByte('\n')
+		}
+	}
+
+	return b.Flush()
+}
+
+// Handler writes gathered metrics to response
+func Handler(w http.ResponseWriter, r *http.Request) {
+
+	metrics, err := Gather(r.Context())
+	if err != nil {
+		log.V(logLevel).Errorf("%s:> gather metrics err: %s", logPrefix, err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(http.StatusOK)
+
+	if err := Write(w, metrics); err != nil {
+		log.V(logLevel).Errorf("%s:> write metrics err: %s", logPrefix, err.Error())
+	}
+}
+
+// Listen serves metrics handler for daemons without http server
+func Listen(host string, port int) error {
+
+	log.V(logLevel).Debugf("%s:> listen metrics on %s:%d", logPrefix, host, port)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", Handler)
+
+	return http.ListenAndServe(fmt.Sprintf("%s:%d", host, port), mux)
+}
+
+func process() []*Metric {
+
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	return []*Metric{
+		NewGauge("go_goroutines", "Number of goroutines that currently exist.").
+			Add(float64(runtime.NumGoroutine()), nil),
+		NewGauge("go_memstats_alloc_bytes", "Number of bytes allocated and still in use.").
+			Add(float64(ms.Alloc), nil),
+		NewGauge("go_memstats_sys_bytes", "Number of bytes obtained from system.").
+			Add(float64(ms.Sys), nil),
+		NewGauge("process_start_time_seconds", "Start time of the process since unix epoch in seconds.").
+			Add(float64(started.Unix()), nil),
+	}
+}
+
+func labels(l Labels) string {
+
+	if len(l) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(l))
+	for k := range l {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", k, escape(l[k], true)))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escape(s string, quote bool) string {
+	s = strings.Replace(s, "\\", "\\\\", -1)
+	s = strings.Replace(s, "\n", "\\n", -1)
+	if quote {
+		s = strings.Replace(s, "\"", "\\\"", -1)
+	}
+	return s
+}
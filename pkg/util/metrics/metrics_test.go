@@ -0,0 +1,90 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		metrics []*Metric
+		want    string
+	}{
+		{
+			name:    "gauge without labels",
+			metrics: []*Metric{NewGauge("lb_test", "Test gauge.").Add(1.5, nil)},
+			want:    "# HELP lb_test Test gauge.\n# TYPE lb_test gauge\nlb_test 1.5\n",
+		},
+		{
+			name: "counter with sorted labels",
+			metrics: []*Metric{NewCounter("lb_test_total", "Test counter.").
+				Add(10, Labels{"service": "demo", "namespace": "test"}).
+				Add(20, Labels{"service": "web", "namespace": "test"})},
+			want: "# HELP lb_test_total Test counter.\n# TYPE lb_test_total counter\n" +
+				"lb_test_total{namespace=\"test\",service=\"demo\"} 10\n" +
+				"lb_test_total{namespace=\"test\",service=\"web\"} 20\n",
+		},
+		{
+			name:    "escaped label values",
+			metrics: []*Metric{NewGauge("lb_test", "").Add(0, Labels{"name": "a\"b\\c\nd"})},
+			want:    "# TYPE lb_test gauge\nlb_test{name=\"a\\\"b\\\\c\\nd\"} 0\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if !assert.NoError(t, Write(&b, tc.metrics)) {
+				return
+			}
+			assert.Equal(t, tc.want, b.String())
+		})
+	}
+}
+
+func TestGather(t *testing.T) {
+
+	Register("test", func(ctx context.Context) ([]*Metric, error) {
+		return []*Metric{NewGauge("aa_test", "Test gauge.").Add(1, nil)}, nil
+	})
+	defer Unregister("test")
+
+	metrics, err := Gather(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if !assert.NotEmpty(t, metrics) {
+		return
+	}
+
+	assert.Equal(t, "aa_test", metrics[0].Name, "metrics are not sorted")
+
+	var b bytes.Buffer
+	assert.NoError(t, Write(&b, metrics))
+	assert.True(t, strings.Contains(b.String(), "go_goroutines "), "process metrics not found")
+}
@@ -0,0 +1,63 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/lastbackend/lastbackend/pkg/distribution/types"
+)
+
+// UsageMetrics - resources usage metrics of pods or pods groups
+type UsageMetrics struct {
+	cpu       *Metric
+	memory    *Metric
+	networkRX *Metric
+	networkTX *Metric
+	diskRead  *Metric
+	diskWrite *Metric
+}
+
+// NewUsageMetrics returns usage metrics named with prefix,
+// subject is used in metrics descriptions
+func NewUsageMetrics(prefix, subject string) *UsageMetrics {
+	return &UsageMetrics{
+		cpu:       NewGauge(prefix+"_cpu_usage_millicores", fmt.Sprintf("CPU usage of %s in millicores.", subject)),
+		memory:    NewGauge(prefix+"_memory_usage_bytes", fmt.Sprintf("Memory usage of %s in bytes.", subject)),
+		networkRX: NewCounter(prefix+"_network_receive_bytes_total", fmt.Sprintf("Network bytes received by %s.", subject)),
+		networkTX: NewCounter(prefix+"_network_transmit_bytes_total", fmt.Sprintf("Network bytes transmitted by %s.", subject)),
+		diskRead:  NewCounter(prefix+"_disk_read_bytes_total", fmt.Sprintf("Disk bytes read by %s.", subject)),
+		diskWrite: NewCounter(prefix+"_disk_write_bytes_total", fmt.Sprintf("Disk bytes written by %s.", subject)),
+	}
+}
+
+// Add appends usage samples with provided labels
+func (u *UsageMetrics) Add(usage types.PodUsage, labels Labels) {
+	u.cpu.Add(float64(usage.CPU), labels)
+	u.memory.Add(float64(usage.RAM*1024*1024), labels)
+	u.networkRX.Add(float64(usage.Network.RX), labels)
+	u.networkTX.Add(float64(usage.Network.TX), labels)
+	u.diskRead.Add(float64(usage.Disk.Read), labels)
+	u.diskWrite.Add(float64(usage.Disk.Write), labels)
+}
+
+// Metrics returns usage metrics list
+func (u *UsageMetrics) Metrics() []*Metric {
+	return []*Metric{u.cpu, u.memory, u.networkRX, u.networkTX, u.diskRead, u.diskWrite}
+}
@@ -0,0 +1,257 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package pki
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"time"
+)
+
+const (
+	// DefaultCertificateTTL - lifetime of issued client certificates
+	DefaultCertificateTTL = 30 * 24 * time.Hour
+
+	authorityTTL = 10 * 365 * 24 * time.Hour
+
+	pemTypeCertificate = "CERTIFICATE"
+	pemTypeRequest     = "CERTIFICATE REQUEST"
+	pemTypeKey         = "EC PRIVATE KEY"
+)
+
+// CA - certificate authority used to sign agents client certificates
+type CA struct {
+	cert    *x509.Certificate
+	certPEM []byte
+	key     *ecdsa.PrivateKey
+}
+
+// Certificate returns PEM-encoded authority certificate
+func (ca *CA) Certificate() []byte {
+	return ca.certPEM
+}
+
+// Key returns PEM-encoded authority private key
+func (ca *CA) Key() ([]byte, error) {
+	return EncodeKey(ca.key)
+}
+
+// Pool returns certificates pool with authority certificate
+func (ca *CA) Pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+// Sign certificate request, subject is replaced by provided identity:
+// common name is set to name and organization is set to kind
+func (ca *CA) Sign(csrPEM []byte, kind, name string, ttl time.Duration) ([]byte, time.Time, error) {
+
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != pemTypeRequest {
+		return nil, time.Time{}, errors.New("invalid certificate request")
+	}
+
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if err := csr.CheckSignature(); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultCertificateTTL
+	}
+
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	now := time.Now().UTC()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   name,
+			Organization: []string{kind},
+		},
+		NotBefore:   now.Add(-time.Minute),
+		NotAfter:    now.Add(ttl),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, csr.PublicKey, ca.key)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: der}), tmpl.NotAfter, nil
+}
+
+// NewCA creates self-signed certificate authority
+func NewCA(name string) (*CA, error) {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(authorityTTL),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadCA(pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: der}), nil, key)
+}
+
+// LoadCA loads certificate authority from PEM-encoded certificate and key,
+// parsed key can be passed instead of PEM-encoded key
+func LoadCA(certPEM, keyPEM []byte, key ...*ecdsa.PrivateKey) (*CA, error) {
+
+	cert, err := ParseCertificate(certPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cert.IsCA {
+		return nil, errors.New("certificate is not authority")
+	}
+
+	ca := &CA{cert: cert, certPEM: certPEM}
+
+	if len(key) > 0 && key[0] != nil {
+		ca.key = key[0]
+		return ca, nil
+	}
+
+	ca.key, err = ParseKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return ca, nil
+}
+
+// GenerateKey generates private key for certificate request
+func GenerateKey() (crypto.Signer, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+// NewCSR creates PEM-encoded certificate request for identity
+func NewCSR(key crypto.Signer, kind, name string) ([]byte, error) {
+
+	tmpl := &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   name,
+			Organization: []string{kind},
+		},
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, tmpl, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeRequest, Bytes: der}), nil
+}
+
+// EncodeKey returns PEM-encoded private key
+func EncodeKey(key crypto.Signer) ([]byte, error) {
+
+	k, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("unsupported key type")
+	}
+
+	der, err := x509.MarshalECPrivateKey(k)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeKey, Bytes: der}), nil
+}
+
+// ParseKey parses PEM-encoded private key
+func ParseKey(keyPEM []byte) (*ecdsa.PrivateKey, error) {
+
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("invalid private key")
+	}
+
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+// ParseCertificate parses PEM-encoded certificate
+func ParseCertificate(certPEM []byte) (*x509.Certificate, error) {
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != pemTypeCertificate {
+		return nil, errors.New("invalid certificate")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// Identity returns identity kind and name from client certificate
+func Identity(cert *x509.Certificate) (string, string) {
+
+	if cert == nil || len(cert.Subject.Organization) == 0 {
+		return "", ""
+	}
+
+	return cert.Subject.Organization[0], cert.Subject.CommonName
+}
+
+// RenewAt returns time when certificate should be rotated:
+// after two thirds of certificate lifetime
+func RenewAt(cert *x509.Certificate) time.Time {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return cert.NotBefore.Add(lifetime * 2 / 3)
+}
+
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
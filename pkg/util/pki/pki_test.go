@@ -0,0 +1,127 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package pki
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCASign(t *testing.T) {
+
+	ca, err := NewCA("lastbackend")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	key, err := GenerateKey()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	csr, err := NewCSR(key, "node", "requested")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tests := []struct {
+		name    string
+		csr     []byte
+		ttl     time.Duration
+		wantTTL time.Duration
+		wantErr bool
+	}{
+		{
+			name:    "sign with default ttl",
+			csr:     csr,
+			wantTTL: DefaultCertificateTTL,
+		},
+		{
+			name:    "sign with custom ttl",
+			csr:     csr,
+			ttl:     time.Hour,
+			wantTTL: time.Hour,
+		},
+		{
+			name:    "sign invalid request",
+			csr:     []byte("invalid"),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			certPEM, expires, err := ca.Sign(tc.csr, "node", "node1", tc.ttl)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			cert, err := ParseCertificate(certPEM)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			_, err = cert.Verify(x509.VerifyOptions{
+				Roots:     ca.Pool(),
+				KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			})
+			assert.NoError(t, err, "certificate should be verified by authority")
+
+			kind, name := Identity(cert)
+			assert.Equal(t, "node", kind)
+			assert.Equal(t, "node1", name, "requested subject should be replaced")
+
+			assert.WithinDuration(t, time.Now().Add(tc.wantTTL), expires, time.Minute)
+			assert.True(t, RenewAt(cert).Before(cert.NotAfter))
+			assert.True(t, RenewAt(cert).After(cert.NotBefore))
+		})
+	}
+}
+
+func TestLoadCA(t *testing.T) {
+
+	ca, err := NewCA("lastbackend")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	key, err := ca.Key()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	loaded, err := LoadCA(ca.Certificate(), key)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, ca.Certificate(), loaded.Certificate())
+
+	_, err = LoadCA(ca.Certificate(), []byte("invalid"))
+	assert.Error(t, err)
+}
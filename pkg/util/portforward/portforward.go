@@ -0,0 +1,379 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package portforward
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gorilla/websocket"
+)
+
+// Websocket messages are prefixed with message type byte and stream id,
+// each forwarded connection is a stream, streams are created by client with connect message.
+// Close message means that sender finished writing to stream, error message aborts stream.
+const (
+	MessageConnect byte = 0
+	MessageData    byte = 1
+	MessageClose   byte = 2
+	MessageError   byte = 3
+
+	headerSize   = 5
+	streamBuffer = 32
+	readBuffer   = 32 * 1024
+)
+
+// Dialer opens connection to forwarded port
+type Dialer func(ctx context.Context, port uint16) (net.Conn, error)
+
+// Serve handles client streams: connects to allowed ports with dialer
+// and copies data until websocket connection is closed
+func Serve(ctx context.Context, conn *websocket.Conn, ports []uint16, dial Dialer) error {
+
+	s := newSession(conn)
+
+	allowed := make(map[uint16]bool, 0)
+	for _, p := range ports {
+		allowed[p] = true
+	}
+
+	return s.read(func(t byte, id uint32, payload []byte) {
+
+		if t != MessageConnect {
+			return
+		}
+
+		if len(payload) != 2 {
+			s.write(MessageError, id, []byte("invalid port"))
+			return
+		}
+
+		port := binary.BigEndian.Uint16(payload)
+		if !allowed[port] {
+			s.write(MessageError, id, []byte(fmt.Sprintf("port %d is not forwarded", port)))
+			return
+		}
+
+		st := s.add(id)
+		if st == nil {
+			s.write(MessageError, id, []byte("stream already exists"))
+			return
+		}
+
+		go func() {
+			c, err := dial(ctx, port)
+			if err != nil {
+				s.write(MessageError, id, []byte(err.Error()))
+				for range st.in {
+				}
+				s.remove(id)
+				return
+			}
+
+			if !s.attach(st, c) {
+				c.Close()
+				return
+			}
+
+			s.pipe(st, c)
+		}()
+	})
+}
+
+// Client forwards local connections to remote ports over websocket connection
+type Client struct {
+	session *session
+	next    uint32
+	done    chan struct{}
+	err     error
+}
+
+// NewClient starts reading streams from websocket connection
+func NewClient(conn *websocket.Conn) *Client {
+
+	c := &Client{session: newSession(conn), done: make(chan struct{})}
+
+	go func() {
+		c.err = c.session.read(func(t byte, id uint32, payload []byte) {})
+		close(c.done)
+	}()
+
+	return c
+}
+
+// Done returns channel closed when websocket connection is finished
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns websocket connection error after connection is finished
+func (c *Client) Err() error {
+	<-c.done
+	return c.err
+}
+
+// Forward copies local connection to remote port until both sides are finished
+func (c *Client) Forward(local net.Conn, port uint16) error {
+
+	id := atomic.AddUint32(&c.next, 1)
+
+	st := c.session.add(id)
+	if st == nil || !c.session.attach(st, local) {
+		local.Close()
+		return fmt.Errorf("stream %d can not be created", id)
+	}
+
+	buf := make([]byte, 2)
+	binary.BigEndian.PutUint16(buf, port)
+
+	if err := c.session.write(MessageConnect, id, buf); err != nil {
+		local.Close()
+		return err
+	}
+
+	c.session.pipe(st, local)
+	return nil
+}
+
+// Listen forwards accepted connections to remote port until context is done or connection is finished
+func (c *Client) Listen(ctx context.Context, l net.Listener, port uint16) error {
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-c.done:
+		}
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-c.done:
+				return nil
+			default:
+				return err
+			}
+		}
+
+		go c.Forward(conn, port)
+	}
+}
+
+type session struct {
+	conn *websocket.Conn
+
+	// websocket writes lock
+	wlock sync.Mutex
+
+	lock    sync.Mutex
+	streams map[uint32]*stream
+	closed  bool
+}
+
+type stream struct {
+	id uint32
+	// data received from websocket
+	in chan []byte
+	// in channel is closed
+	eof bool
+	// forwarded connection
+	conn net.Conn
+}
+
+func newSession(conn *websocket.Conn) *session {
+	return &session{conn: conn, streams: make(map[uint32]*stream, 0)}
+}
+
+func (s *session) write(t byte, id uint32, payload []byte) error {
+	s.wlock.Lock()
+	defer s.wlock.Unlock()
+
+	buf := make([]byte, headerSize+len(payload))
+	buf[0] = t
+	binary.BigEndian.PutUint32(buf[1:headerSize], id)
+	copy(buf[headerSize:], payload)
+
+	return s.conn.WriteMessage(websocket.BinaryMessage, buf)
+}
+
+// read dispatches websocket messages to streams until connection is closed,
+// messages not related to existing streams are passed to handler
+func (s *session) read(handler func(t byte, id uint32, payload []byte)) error {
+
+	defer s.close()
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				return nil
+			}
+			return err
+		}
+
+		if len(data) < headerSize {
+			continue
+		}
+
+		var (
+			t       = data[0]
+			id      = binary.BigEndian.Uint32(data[1:headerSize])
+			payload = data[headerSize:]
+		)
+
+		switch t {
+		case MessageData:
+			s.lock.Lock()
+			st, ok := s.streams[id]
+			s.lock.Unlock()
+			if ok && !st.eof {
+				st.in <- payload
+			}
+		case MessageClose:
+			s.eof(id, false)
+		case MessageError:
+			s.eof(id, true)
+		default:
+			handler(t, id, payload)
+		}
+	}
+}
+
+// add registers new stream, nil is returned if stream exists or session is closed
+func (s *session) add(id uint32) *stream {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.streams[id]; ok || s.closed {
+		return nil
+	}
+
+	st := &stream{id: id, in: make(chan []byte, streamBuffer)}
+	s.streams[id] = st
+	return st
+}
+
+// attach sets stream connection, false is returned if session is already closed
+func (s *session) attach(st *stream, conn net.Conn) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return false
+	}
+
+	st.conn = conn
+	return true
+}
+
+func (s *session) remove(id uint32) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.streams, id)
+}
+
+// eof closes stream input, connection is closed if stream is aborted
+func (s *session) eof(id uint32, abort bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	st, ok := s.streams[id]
+	if !ok {
+		return
+	}
+
+	if !st.eof {
+		st.eof = true
+		close(st.in)
+	}
+
+	if abort && st.conn != nil {
+		st.conn.Close()
+	}
+}
+
+// close aborts all streams when websocket connection is finished
+func (s *session) close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.closed = true
+
+	for _, st := range s.streams {
+		if !st.eof {
+			st.eof = true
+			close(st.in)
+		}
+		if st.conn != nil {
+			st.conn.Close()
+		}
+	}
+}
+
+// pipe copies data between stream and connection until both directions are finished
+func (s *session) pipe(st *stream, conn net.Conn) {
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		var failed bool
+		for data := range st.in {
+			if failed {
+				continue
+			}
+			if _, err := conn.Write(data); err != nil {
+				failed = true
+			}
+		}
+
+		if c, ok := conn.(interface{ CloseWrite() error }); ok {
+			c.CloseWrite()
+		}
+	}()
+
+	buf := make([]byte, readBuffer)
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			if err := s.write(MessageData, st.id, buf[:n]); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	s.write(MessageClose, st.id, nil)
+
+	wg.Wait()
+	conn.Close()
+	s.remove(st.id)
+}
@@ -0,0 +1,140 @@
+//
+// Last.Backend LLC CONFIDENTIAL
+// __________________
+//
+// [2014] - [2018] Last.Backend LLC
+// All Rights Reserved.
+//
+// NOTICE:  All information contained herein is, and remains
+// the property of Last.Backend LLC and its suppliers,
+// if any.  The intellectual and technical concepts contained
+// herein are proprietary to Last.Backend LLC
+// and its suppliers and may be covered by Russian Federation and Foreign Patents,
+// patents in process, and are protected by trade secret or copyright law.
+// Dissemination of this information or reproduction of this material
+// is strictly forbidden unless prior written permission is obtained
+// from Last.Backend LLC.
+//
+
+package portforward
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForward(t *testing.T) {
+
+	// echo server replies with received data prefixed with port
+	echo := func(prefix string) (net.Listener, uint16) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		go func() {
+			for {
+				c, err := l.Accept()
+				if err != nil {
+					return
+				}
+				go func(c net.Conn) {
+					defer c.Close()
+					data, _ := ioutil.ReadAll(c)
+					c.Write([]byte(prefix + string(data)))
+				}(c)
+			}
+		}()
+
+		return l, uint16(l.Addr().(*net.TCPAddr).Port)
+	}
+
+	e1, p1 := echo("first:")
+	defer e1.Close()
+
+	e2, p2 := echo("second:")
+	defer e2.Close()
+
+	upgrader := websocket.Upgrader{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		Serve(r.Context(), conn, []uint16{p1, p2}, func(ctx context.Context, port uint16) (net.Conn, error) {
+			return net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+		})
+	}))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	client := NewClient(conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tests := []struct {
+		name string
+		port uint16
+		data string
+		want string
+	}{
+		{name: "forward first port", port: p1, data: "ping", want: "first:ping"},
+		{name: "forward second port", port: p2, data: "pong", want: "second:pong"},
+		{name: "forward not allowed port", port: 1, data: "ping", want: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			go client.Listen(ctx, l, tc.port)
+
+			c, err := net.Dial("tcp", l.Addr().String())
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer c.Close()
+
+			c.SetDeadline(time.Now().Add(5 * time.Second))
+
+			_, err = io.WriteString(c, tc.data)
+			assert.NoError(t, err)
+			c.(*net.TCPConn).CloseWrite()
+
+			data, _ := ioutil.ReadAll(c)
+			assert.Equal(t, tc.want, string(data), "received data not equal")
+		})
+	}
+
+	conn.Close()
+
+	select {
+	case <-client.Done():
+	case <-time.After(5 * time.Second):
+		t.Error("client is not finished after connection is closed")
+	}
+}